@@ -24,6 +24,12 @@ type PatchStrategicMergeTransformerPlugin struct {
 	Patches       string                      `json:"patches,omitempty" yaml:"patches,omitempty"`
 
 	YAMLSupport bool `json:"yamlSupport,omitempty" yaml:"yamlSupport,omitempty"`
+
+	// As with kubectl, a field set to null in a patch is deleted from
+	// the target.  KeepNullValues instead sets the field to null, for
+	// resources where null is a legitimate value.  Only fields of maps
+	// are kept; null list elements are merged as usual.
+	KeepNullValues bool `json:"keepNullValues,omitempty" yaml:"keepNullValues,omitempty"`
 }
 
 func (p *PatchStrategicMergeTransformerPlugin) Config(
@@ -76,6 +82,11 @@ func (p *PatchStrategicMergeTransformerPlugin) Transform(m resmap.ResMap) error
 		if err != nil {
 			return err
 		}
+		var nulls [][]string
+		if p.KeepNullValues {
+			patch = patch.DeepCopy()
+			nulls = removeNullFields(patch.Map(), nil)
+		}
 		if !p.YAMLSupport {
 			err = target.Patch(patch.Kunstructured)
 			if err != nil {
@@ -97,12 +108,53 @@ func (p *PatchStrategicMergeTransformerPlugin) Transform(m resmap.ResMap) error
 			err = filtersutil.ApplyToJSON(patchstrategicmerge.Filter{
 				Patch: node,
 			}, target.Kunstructured)
+			if err != nil {
+				return err
+			}
+		}
+		if len(target.Map()) != 0 {
+			for _, path := range nulls {
+				setNullField(target.Map(), path)
+			}
 		}
 	}
 	return nil
 }
 
-//TODO: Remove this once the next version of kyaml is released which
+// removeNullFields removes the fields of m, and of its nested maps,
+// whose value is null.  Returns the paths to the removed fields.
+func removeNullFields(m map[string]interface{}, path []string) [][]string {
+	var nulls [][]string
+	for k, v := range m {
+		p := append(append([]string{}, path...), k)
+		switch typedV := v.(type) {
+		case nil:
+			delete(m, k)
+			nulls = append(nulls, p)
+		case map[string]interface{}:
+			nulls = append(nulls, removeNullFields(typedV, p)...)
+		}
+	}
+	return nulls
+}
+
+// setNullField sets the field at path in m to null, creating the
+// parent maps as needed.  Fields whose parent isn't a map are skipped.
+func setNullField(m map[string]interface{}, path []string) {
+	for _, k := range path[:len(path)-1] {
+		if m[k] == nil {
+			m[k] = map[string]interface{}{}
+		}
+		next, ok := m[k].(map[string]interface{})
+		if !ok {
+			return
+		}
+		m = next
+	}
+	m[path[len(path)-1]] = nil
+}
+
+// TODO: Remove this once the next version of kyaml is released which
 // exposes GetRNode from the filutersutil package.
 func getRNode(k json.Marshaler) (*kyaml.RNode, error) {
 	j, err := k.MarshalJSON()
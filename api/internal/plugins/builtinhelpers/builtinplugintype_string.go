@@ -10,24 +10,42 @@ func _() {
 	var x [1]struct{}
 	_ = x[Unknown-0]
 	_ = x[AnnotationsTransformer-1]
-	_ = x[ConfigMapGenerator-2]
-	_ = x[HashTransformer-3]
-	_ = x[ImageTagTransformer-4]
-	_ = x[LabelTransformer-5]
-	_ = x[LegacyOrderTransformer-6]
-	_ = x[NamespaceTransformer-7]
-	_ = x[PatchJson6902Transformer-8]
-	_ = x[PatchStrategicMergeTransformer-9]
-	_ = x[PatchTransformer-10]
-	_ = x[PrefixSuffixTransformer-11]
-	_ = x[ReplicaCountTransformer-12]
-	_ = x[SecretGenerator-13]
-	_ = x[ValueAddTransformer-14]
+	_ = x[ApiGroupNormalizeTransformer-2]
+	_ = x[ChecksumAnnotationTransformer-3]
+	_ = x[ConfigMapGenerator-4]
+	_ = x[ContainerFieldStripTransformer-5]
+	_ = x[CostLabelTransformer-6]
+	_ = x[DevModeTransformer-7]
+	_ = x[HPAGeneratorTransformer-8]
+	_ = x[HashTransformer-9]
+	_ = x[ImageAllowlistTransformer-10]
+	_ = x[ImageCanonicalizeTransformer-11]
+	_ = x[ImageTagTransformer-12]
+	_ = x[IngressHostTransformer-13]
+	_ = x[InitContainerInjectionTransformer-14]
+	_ = x[JSONArrayGenerator-15]
+	_ = x[LabelTransformer-16]
+	_ = x[LegacyOrderTransformer-17]
+	_ = x[NamespaceMergeTransformer-18]
+	_ = x[NamespaceTransformer-19]
+	_ = x[PatchJson6902Transformer-20]
+	_ = x[PatchLibraryTransformer-21]
+	_ = x[PatchStrategicMergeTransformer-22]
+	_ = x[PatchTransformer-23]
+	_ = x[PrefixSuffixTransformer-24]
+	_ = x[QuotaGeneratorTransformer-25]
+	_ = x[RBACConsolidateTransformer-26]
+	_ = x[ReplicaCountTransformer-27]
+	_ = x[SecretGenerator-28]
+	_ = x[StorageClassTransformer-29]
+	_ = x[TableGenerator-30]
+	_ = x[ValueAddTransformer-31]
+	_ = x[VolumeDedupeTransformer-32]
 }
 
-const _BuiltinPluginType_name = "UnknownAnnotationsTransformerConfigMapGeneratorHashTransformerImageTagTransformerLabelTransformerLegacyOrderTransformerNamespaceTransformerPatchJson6902TransformerPatchStrategicMergeTransformerPatchTransformerPrefixSuffixTransformerReplicaCountTransformerSecretGeneratorValueAddTransformer"
+const _BuiltinPluginType_name = "UnknownAnnotationsTransformerApiGroupNormalizeTransformerChecksumAnnotationTransformerConfigMapGeneratorContainerFieldStripTransformerCostLabelTransformerDevModeTransformerHPAGeneratorTransformerHashTransformerImageAllowlistTransformerImageCanonicalizeTransformerImageTagTransformerIngressHostTransformerInitContainerInjectionTransformerJSONArrayGeneratorLabelTransformerLegacyOrderTransformerNamespaceMergeTransformerNamespaceTransformerPatchJson6902TransformerPatchLibraryTransformerPatchStrategicMergeTransformerPatchTransformerPrefixSuffixTransformerQuotaGeneratorTransformerRBACConsolidateTransformerReplicaCountTransformerSecretGeneratorStorageClassTransformerTableGeneratorValueAddTransformerVolumeDedupeTransformer"
 
-var _BuiltinPluginType_index = [...]uint16{0, 7, 29, 47, 62, 81, 97, 119, 139, 163, 193, 209, 232, 255, 270, 289}
+var _BuiltinPluginType_index = [...]uint16{0, 7, 29, 57, 86, 104, 134, 154, 172, 195, 210, 235, 263, 282, 304, 337, 355, 371, 393, 418, 438, 462, 485, 515, 531, 554, 579, 605, 628, 643, 666, 680, 699, 722}
 
 func (i BuiltinPluginType) String() string {
 	if i < 0 || i >= BuiltinPluginType(len(_BuiltinPluginType_index)-1) {
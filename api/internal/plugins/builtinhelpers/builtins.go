@@ -14,19 +14,37 @@ type BuiltinPluginType int
 const (
 	Unknown BuiltinPluginType = iota
 	AnnotationsTransformer
+	ApiGroupNormalizeTransformer
+	ChecksumAnnotationTransformer
 	ConfigMapGenerator
+	ContainerFieldStripTransformer
+	CostLabelTransformer
+	DevModeTransformer
+	HPAGeneratorTransformer
 	HashTransformer
+	ImageAllowlistTransformer
+	ImageCanonicalizeTransformer
 	ImageTagTransformer
+	IngressHostTransformer
+	InitContainerInjectionTransformer
+	JSONArrayGenerator
 	LabelTransformer
 	LegacyOrderTransformer
+	NamespaceMergeTransformer
 	NamespaceTransformer
 	PatchJson6902Transformer
+	PatchLibraryTransformer
 	PatchStrategicMergeTransformer
 	PatchTransformer
 	PrefixSuffixTransformer
+	QuotaGeneratorTransformer
+	RBACConsolidateTransformer
 	ReplicaCountTransformer
 	SecretGenerator
+	StorageClassTransformer
+	TableGenerator
 	ValueAddTransformer
+	VolumeDedupeTransformer
 )
 
 var stringToBuiltinPluginTypeMap map[string]BuiltinPluginType
@@ -56,20 +74,38 @@ func GetBuiltinPluginType(n string) BuiltinPluginType {
 
 var GeneratorFactories = map[BuiltinPluginType]func() resmap.GeneratorPlugin{
 	ConfigMapGenerator: builtins.NewConfigMapGeneratorPlugin,
+	JSONArrayGenerator: builtins.NewJSONArrayGeneratorPlugin,
 	SecretGenerator:    builtins.NewSecretGeneratorPlugin,
+	TableGenerator:     builtins.NewTableGeneratorPlugin,
 }
 
 var TransformerFactories = map[BuiltinPluginType]func() resmap.TransformerPlugin{
-	AnnotationsTransformer:         builtins.NewAnnotationsTransformerPlugin,
-	HashTransformer:                builtins.NewHashTransformerPlugin,
-	ImageTagTransformer:            builtins.NewImageTagTransformerPlugin,
-	LabelTransformer:               builtins.NewLabelTransformerPlugin,
-	LegacyOrderTransformer:         builtins.NewLegacyOrderTransformerPlugin,
-	NamespaceTransformer:           builtins.NewNamespaceTransformerPlugin,
-	PatchJson6902Transformer:       builtins.NewPatchJson6902TransformerPlugin,
-	PatchStrategicMergeTransformer: builtins.NewPatchStrategicMergeTransformerPlugin,
-	PatchTransformer:               builtins.NewPatchTransformerPlugin,
-	PrefixSuffixTransformer:        builtins.NewPrefixSuffixTransformerPlugin,
-	ReplicaCountTransformer:        builtins.NewReplicaCountTransformerPlugin,
-	ValueAddTransformer:            builtins.NewValueAddTransformerPlugin,
+	AnnotationsTransformer:            builtins.NewAnnotationsTransformerPlugin,
+	ApiGroupNormalizeTransformer:      builtins.NewApiGroupNormalizeTransformerPlugin,
+	ChecksumAnnotationTransformer:     builtins.NewChecksumAnnotationTransformerPlugin,
+	ContainerFieldStripTransformer:    builtins.NewContainerFieldStripTransformerPlugin,
+	CostLabelTransformer:              builtins.NewCostLabelTransformerPlugin,
+	DevModeTransformer:                builtins.NewDevModeTransformerPlugin,
+	HPAGeneratorTransformer:           builtins.NewHPAGeneratorTransformerPlugin,
+	HashTransformer:                   builtins.NewHashTransformerPlugin,
+	ImageAllowlistTransformer:         builtins.NewImageAllowlistTransformerPlugin,
+	ImageCanonicalizeTransformer:      builtins.NewImageCanonicalizeTransformerPlugin,
+	ImageTagTransformer:               builtins.NewImageTagTransformerPlugin,
+	IngressHostTransformer:            builtins.NewIngressHostTransformerPlugin,
+	InitContainerInjectionTransformer: builtins.NewInitContainerInjectionTransformerPlugin,
+	LabelTransformer:                  builtins.NewLabelTransformerPlugin,
+	LegacyOrderTransformer:            builtins.NewLegacyOrderTransformerPlugin,
+	NamespaceMergeTransformer:         builtins.NewNamespaceMergeTransformerPlugin,
+	NamespaceTransformer:              builtins.NewNamespaceTransformerPlugin,
+	PatchJson6902Transformer:          builtins.NewPatchJson6902TransformerPlugin,
+	PatchLibraryTransformer:           builtins.NewPatchLibraryTransformerPlugin,
+	PatchStrategicMergeTransformer:    builtins.NewPatchStrategicMergeTransformerPlugin,
+	PatchTransformer:                  builtins.NewPatchTransformerPlugin,
+	PrefixSuffixTransformer:           builtins.NewPrefixSuffixTransformerPlugin,
+	QuotaGeneratorTransformer:         builtins.NewQuotaGeneratorTransformerPlugin,
+	RBACConsolidateTransformer:        builtins.NewRBACConsolidateTransformerPlugin,
+	ReplicaCountTransformer:           builtins.NewReplicaCountTransformerPlugin,
+	StorageClassTransformer:           builtins.NewStorageClassTransformerPlugin,
+	ValueAddTransformer:               builtins.NewValueAddTransformerPlugin,
+	VolumeDedupeTransformer:           builtins.NewVolumeDedupeTransformerPlugin,
 }
@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
-	"os/exec"
 	"strconv"
 	"strings"
 
@@ -44,6 +43,9 @@ type ExecPlugin struct {
 
 	// PluginHelpers
 	h *resmap.PluginHelpers
+
+	// Resource limits of each run of the executable.
+	limits types.PluginResourceLimits
 }
 
 func NewExecPlugin(p string) *ExecPlugin {
@@ -159,9 +161,10 @@ func (p *ExecPlugin) invokePlugin(input []byte) ([]byte, error) {
 		return nil, errors.Wrap(
 			err, "closing plugin config file "+f.Name())
 	}
-	//nolint:gosec
-	cmd := exec.Command(
-		p.path, append([]string{f.Name()}, p.args...)...)
+	cmd, err := p.command(append([]string{f.Name()}, p.args...))
+	if err != nil {
+		return nil, err
+	}
 	cmd.Env = p.getEnv()
 	cmd.Stdin = bytes.NewReader(input)
 	cmd.Stderr = os.Stderr
@@ -170,6 +173,7 @@ func (p *ExecPlugin) invokePlugin(input []byte) ([]byte, error) {
 	}
 	result, err := cmd.Output()
 	if err != nil {
+		err = p.killedError(err)
 		return nil, errors.Wrapf(
 			err, "failure in plugin configured via %s; %v",
 			f.Name(), err.Error())
@@ -0,0 +1,101 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package execplugin
+
+import (
+	"fmt"
+	"log"
+	"os/exec"
+	"runtime"
+	"syscall"
+
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+// killedExitCode is the exit code of a process killed by
+// SIGKILL, as reported by wrappers such as systemd-run.
+const killedExitCode = 128 + int(syscall.SIGKILL)
+
+// SetResourceLimits bounds the resources used by each run of
+// the plugin.
+func (p *ExecPlugin) SetResourceLimits(limits types.PluginResourceLimits) {
+	p.limits = limits
+}
+
+// command returns the command running the plugin with args.
+// If resource limits are set, the plugin is run in a cgroup
+// enforcing them, created with systemd-run.  Where that isn't
+// available, a warning is logged and the plugin is run
+// without limits.
+func (p *ExecPlugin) command(args []string) (*exec.Cmd, error) {
+	if !p.limits.IsSet() {
+		//nolint:gosec
+		return exec.Command(p.path, args...), nil
+	}
+	properties, err := cgroupProperties(p.limits)
+	if err != nil {
+		return nil, err
+	}
+	systemdRun, err := exec.LookPath("systemd-run")
+	if runtime.GOOS != "linux" || err != nil {
+		log.Printf(
+			"resource limits aren't enforced for plugin %s; cgroups aren't available",
+			p.path)
+		//nolint:gosec
+		return exec.Command(p.path, args...), nil
+	}
+	a := []string{"--user", "--scope", "--quiet"}
+	for _, property := range properties {
+		a = append(a, "--property", property)
+	}
+	a = append(append(a, "--", p.path), args...)
+	//nolint:gosec
+	return exec.Command(systemdRun, a...), nil
+}
+
+// cgroupProperties returns the systemd unit properties
+// enforcing the limits.
+func cgroupProperties(limits types.PluginResourceLimits) ([]string, error) {
+	var properties []string
+	memory, err := limits.MemoryBytes()
+	if err != nil {
+		return nil, err
+	}
+	if memory > 0 {
+		properties = append(properties,
+			fmt.Sprintf("MemoryMax=%d", memory),
+			// don't let the plugin swap instead of being killed
+			"MemorySwapMax=0")
+	}
+	cpus, err := limits.CPUs()
+	if err != nil {
+		return nil, err
+	}
+	if cpus > 0 {
+		properties = append(properties,
+			fmt.Sprintf("CPUQuota=%g%%", cpus*100))
+	}
+	return properties, nil
+}
+
+// killedError returns an error reporting that the plugin was
+// killed, likely for exceeding its resource limits, or err as
+// is if it wasn't.
+func (p *ExecPlugin) killedError(err error) error {
+	if !p.limits.IsSet() {
+		return err
+	}
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok {
+		return err
+	}
+	status, ok := exitErr.Sys().(syscall.WaitStatus)
+	signaled := ok && status.Signaled() && status.Signal() == syscall.SIGKILL
+	if !signaled && exitErr.ExitCode() != killedExitCode {
+		return err
+	}
+	return fmt.Errorf(
+		"plugin %s was killed, likely for exceeding its resource limits "+
+			"(memory %q, cpu %q): %v", p.path, p.limits.Memory, p.limits.CPU, err)
+}
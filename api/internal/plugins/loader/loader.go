@@ -171,6 +171,7 @@ func (l *Loader) loadPlugin(resId resid.ResId) (resmap.Configurable, error) {
 	p := execplugin.NewExecPlugin(l.absolutePluginPath(resId))
 	err := p.ErrIfNotExecutable()
 	if err == nil {
+		p.SetResourceLimits(l.pc.ResourceLimits)
 		return p, nil
 	}
 	if !os.IsNotExist(err) {
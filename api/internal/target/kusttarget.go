@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -31,6 +32,18 @@ type KustTarget struct {
 	rFactory      *resmap.Factory
 	tFactory      resmap.PatchFactory
 	pLdr          *loader.Loader
+	safeLabels    bool
+	setters       map[string]string
+	scope         string
+	subScopes     map[string]string
+	maxDepth      int
+	profile       *Profile
+	// tConfig is the transformer configuration the
+	// last customized ResMap was made with.
+	tConfig *builtinconfig.TransformerConfig
+	// roots are the roots of the kustomizations from the top
+	// level target down to this one, to report the nesting.
+	roots []string
 }
 
 // NewKustTarget returns a new instance of KustTarget.
@@ -83,6 +96,37 @@ func (kt *KustTarget) Kustomization() types.Kustomization {
 	return result
 }
 
+// Override applies fn to the internal kustomization object,
+// e.g. to apply ad-hoc overrides before the target is built.
+func (kt *KustTarget) Override(fn func(*types.Kustomization) error) error {
+	return fn(kt.kustomization)
+}
+
+// EnableSafeLabels makes the build fail if the commonLabels
+// of the target, or of any of its bases, would modify the
+// immutable selector of a workload.
+func (kt *KustTarget) EnableSafeLabels() {
+	kt.safeLabels = true
+}
+
+// SetMaxDepth makes the build fail if bases or components
+// are nested more than n levels below the target.  There is
+// no limit if n is zero.
+func (kt *KustTarget) SetMaxDepth(n int) {
+	kt.maxDepth = n
+}
+
+// nestedRoots returns the roots of the kustomizations from
+// the top level target down to the one at root, nested in
+// this target.
+func (kt *KustTarget) nestedRoots(root string) []string {
+	roots := kt.roots
+	if len(roots) == 0 {
+		roots = []string{kt.ldr.Root()}
+	}
+	return append(append([]string{}, roots...), root)
+}
+
 func loadKustFile(ldr ifc.Loader) ([]byte, error) {
 	var content []byte
 	match := 0
@@ -120,11 +164,20 @@ func (kt *KustTarget) MakeCustomizedResMap() (resmap.ResMap, error) {
 	return kt.makeCustomizedResMap()
 }
 
+// TransformerConfig returns the transformer configuration
+// the last customized ResMap was made with, i.e. the default
+// configuration merged with the configurations and CRDs of the
+// target and its bases, or nil if none was made yet.
+func (kt *KustTarget) TransformerConfig() *builtinconfig.TransformerConfig {
+	return kt.tConfig
+}
+
 func (kt *KustTarget) makeCustomizedResMap() (resmap.ResMap, error) {
 	ra, err := kt.AccumulateTarget()
 	if err != nil {
 		return nil, err
 	}
+	kt.tConfig = ra.GetTransformerConfig()
 
 	// The following steps must be done last, not as part of
 	// the recursion implicit in AccumulateTarget.
@@ -157,7 +210,7 @@ func (kt *KustTarget) addHashesToNames(
 	if err != nil {
 		return err
 	}
-	return ra.Transform(p)
+	return ra.Transform(kt.profiled(p))
 }
 
 // AccumulateTarget returns a new ResAccumulator,
@@ -173,11 +226,15 @@ func (kt *KustTarget) AccumulateTarget() (
 // (or empty if the Component does not have a parent).
 func (kt *KustTarget) accumulateTarget(ra *accumulator.ResAccumulator) (
 	resRa *accumulator.ResAccumulator, err error) {
-	ra, err = kt.accumulateResources(ra, kt.kustomization.Resources)
+	resources, components, err := kt.scopedPaths()
+	if err != nil {
+		return nil, err
+	}
+	ra, err = kt.accumulateResources(ra, resources)
 	if err != nil {
 		return nil, errors.Wrap(err, "accumulating resources")
 	}
-	ra, err = kt.accumulateComponents(ra, kt.kustomization.Components)
+	ra, err = kt.accumulateComponents(ra, components)
 	if err != nil {
 		return nil, errors.Wrap(err, "accumulating components")
 	}
@@ -231,7 +288,7 @@ func (kt *KustTarget) runGenerators(
 	}
 	generators = append(generators, gs...)
 	for _, g := range generators {
-		resMap, err := g.Generate()
+		resMap, err := kt.generate(g)
 		if err != nil {
 			return err
 		}
@@ -265,6 +322,9 @@ func (kt *KustTarget) runTransformers(ra *accumulator.ResAccumulator) error {
 		return err
 	}
 	r = append(r, lts...)
+	for i := range r {
+		r[i] = kt.profiled(r[i])
+	}
 	t := transform.NewMultiTransformer(r)
 	return ra.Transform(t)
 }
@@ -290,7 +350,7 @@ func (kt *KustTarget) accumulateResources(
 				return nil, fmt.Errorf("accumulateFile %q, loader.New %q", errF, errL)
 			}
 			var errD error
-			ra, errD = kt.accumulateDirectory(ra, ldr, false)
+			ra, errD = kt.accumulateDirectory(ra, ldr, false, kt.subScopes[path])
 			if errD != nil {
 				return nil, fmt.Errorf("accumulateFile %q, accumulateDirector: %q", errF, errD)
 			}
@@ -310,7 +370,7 @@ func (kt *KustTarget) accumulateComponents(
 			return nil, fmt.Errorf("loader.New %q", errL)
 		}
 		var errD error
-		ra, errD = kt.accumulateDirectory(ra, ldr, true)
+		ra, errD = kt.accumulateDirectory(ra, ldr, true, kt.subScopes[path])
 		if errD != nil {
 			return nil, fmt.Errorf("accumulateDirectory: %q", errD)
 		}
@@ -319,10 +379,21 @@ func (kt *KustTarget) accumulateComponents(
 }
 
 func (kt *KustTarget) accumulateDirectory(
-	ra *accumulator.ResAccumulator, ldr ifc.Loader, isComponent bool) (*accumulator.ResAccumulator, error) {
+	ra *accumulator.ResAccumulator, ldr ifc.Loader, isComponent bool,
+	scope string) (*accumulator.ResAccumulator, error) {
 	defer ldr.Cleanup()
 	subKt := NewKustTarget(
 		ldr, kt.validator, kt.rFactory, kt.tFactory, kt.pLdr)
+	subKt.safeLabels = kt.safeLabels
+	subKt.scope = scope
+	subKt.maxDepth = kt.maxDepth
+	subKt.profile = kt.profile
+	subKt.roots = kt.nestedRoots(ldr.Root())
+	if kt.maxDepth > 0 && len(subKt.roots)-1 > kt.maxDepth {
+		return nil, fmt.Errorf(
+			"nesting of bases exceeds the maximum depth of %d: %s",
+			kt.maxDepth, strings.Join(subKt.roots, " -> "))
+	}
 	err := subKt.Load()
 	if err != nil {
 		return nil, errors.Wrapf(
@@ -364,6 +435,9 @@ func (kt *KustTarget) accumulateFile(
 	if err != nil {
 		return errors.Wrapf(err, "accumulating resources from '%s'", path)
 	}
+	for _, r := range resources.Resources() {
+		r.SetOrigin(filepath.Join(kt.ldr.Root(), path))
+	}
 	err = ra.AppendAll(resources)
 	if err != nil {
 		return errors.Wrapf(err, "merging resources from '%s'", path)
@@ -79,11 +79,18 @@ var generatorConfigurators = map[builtinhelpers.BuiltinPluginType]func(
 			types.SecretArgs
 		}
 		for _, args := range kt.kustomization.SecretGenerator {
+			ok, err := kt.appliesWhen(args.AppliesWhen)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
 			c.SecretArgs = args
 			c.SecretArgs.Options = types.MergeGlobalOptionsIntoLocal(
 				c.SecretArgs.Options, kt.kustomization.GeneratorOptions)
 			p := f()
-			err := kt.configureBuiltinPlugin(p, c, bpt)
+			err = kt.configureBuiltinPlugin(p, c, bpt)
 			if err != nil {
 				return nil, err
 			}
@@ -98,11 +105,18 @@ var generatorConfigurators = map[builtinhelpers.BuiltinPluginType]func(
 			types.ConfigMapArgs
 		}
 		for _, args := range kt.kustomization.ConfigMapGenerator {
+			ok, err := kt.appliesWhen(args.AppliesWhen)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
 			c.ConfigMapArgs = args
 			c.ConfigMapArgs.Options = types.MergeGlobalOptionsIntoLocal(
 				c.ConfigMapArgs.Options, kt.kustomization.GeneratorOptions)
 			p := f()
-			err := kt.configureBuiltinPlugin(p, c, bpt)
+			err = kt.configureBuiltinPlugin(p, c, bpt)
 			if err != nil {
 				return nil, err
 			}
@@ -210,6 +224,10 @@ var transformerConfigurators = map[builtinhelpers.BuiltinPluginType]func(
 		}
 		c.Labels = kt.kustomization.CommonLabels
 		c.FieldSpecs = tc.CommonLabels
+		if kt.safeLabels {
+			result = append(result, &selectorGuard{
+				labels: c.Labels, fieldSpecs: c.FieldSpecs})
+		}
 		p := f()
 		err = kt.configureBuiltinPlugin(p, c, bpt)
 		if err != nil {
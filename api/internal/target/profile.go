@@ -0,0 +1,112 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+)
+
+// Profile records the wall-clock time taken by each
+// generator and transformer of a build, to find the
+// expensive ones.
+type Profile struct {
+	Stages []Stage
+}
+
+// Stage is the timing of one run of a generator or
+// transformer.
+type Stage struct {
+	// Kind is either "generator" or "transformer".
+	Kind string
+
+	// Name of the plugin, e.g. ConfigMapGenerator, or the
+	// file name of an exec plugin.
+	Name string
+
+	// Root of the kustomization running the plugin.
+	Root string
+
+	// Duration is the wall-clock time the plugin took.
+	Duration time.Duration
+
+	// Resources is the number of resources generated, or
+	// transformed.
+	Resources int
+}
+
+// EnableProfiling makes the target, and its bases and
+// components, record the timing of each generator and
+// transformer in p.
+func (kt *KustTarget) EnableProfiling(p *Profile) {
+	kt.profile = p
+}
+
+// generate runs the generator, recording its timing if
+// profiling is enabled.
+func (kt *KustTarget) generate(g resmap.Generator) (resmap.ResMap, error) {
+	if kt.profile == nil {
+		return g.Generate()
+	}
+	start := time.Now()
+	m, err := g.Generate()
+	if err != nil {
+		return nil, err
+	}
+	kt.profile.add("generator", g, kt.ldr.Root(), time.Since(start), m.Size())
+	return m, nil
+}
+
+// profiledTransformer records the timing of each run of a
+// transformer.
+type profiledTransformer struct {
+	t       resmap.Transformer
+	root    string
+	profile *Profile
+}
+
+func (p profiledTransformer) Transform(m resmap.ResMap) error {
+	start := time.Now()
+	if err := p.t.Transform(m); err != nil {
+		return err
+	}
+	p.profile.add("transformer", p.t, p.root, time.Since(start), m.Size())
+	return nil
+}
+
+// profiled returns the transformer, recording its timing
+// if profiling is enabled.
+func (kt *KustTarget) profiled(t resmap.Transformer) resmap.Transformer {
+	if kt.profile == nil {
+		return t
+	}
+	return profiledTransformer{t: t, root: kt.ldr.Root(), profile: kt.profile}
+}
+
+func (p *Profile) add(
+	kind string, plugin interface{}, root string, d time.Duration, n int) {
+	p.Stages = append(p.Stages, Stage{
+		Kind:      kind,
+		Name:      pluginName(plugin),
+		Root:      root,
+		Duration:  d,
+		Resources: n,
+	})
+}
+
+// pluginName returns the name of a plugin for a profile,
+// e.g. ConfigMapGenerator for the builtin generator of
+// ConfigMaps, or the file name of an exec plugin.
+func pluginName(plugin interface{}) string {
+	if p, ok := plugin.(interface{ Path() string }); ok {
+		return filepath.Base(p.Path())
+	}
+	name := fmt.Sprintf("%T", plugin)
+	name = name[strings.LastIndex(name, ".")+1:]
+	return strings.TrimSuffix(name, "Plugin")
+}
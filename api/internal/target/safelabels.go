@@ -0,0 +1,76 @@
+// Copyright 2020 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+// immutableSelectorKinds are the kinds of workloads whose
+// selector may not be changed once they are created.
+var immutableSelectorKinds = map[string]bool{
+	"DaemonSet":   true,
+	"Deployment":  true,
+	"StatefulSet": true,
+}
+
+// immutableSelectorPath is the path to the immutable selector.
+const immutableSelectorPath = "spec/selector/matchLabels"
+
+// selectorGuard is a transformer which fails if adding
+// labels to the given field specs would modify the
+// immutable selector of a workload.  It is run before
+// the label transformer when safe labels are enabled.
+type selectorGuard struct {
+	labels     map[string]string
+	fieldSpecs []types.FieldSpec
+}
+
+var _ resmap.Transformer = &selectorGuard{}
+
+func (g *selectorGuard) Transform(m resmap.ResMap) error {
+	if len(g.labels) == 0 {
+		return nil
+	}
+	var errs []string
+	for _, r := range m.Resources() {
+		if !immutableSelectorKinds[r.GetKind()] {
+			continue
+		}
+		for _, fs := range g.fieldSpecs {
+			if fs.Path != immutableSelectorPath ||
+				!r.OrgId().IsSelected(&fs.Gvk) {
+				continue
+			}
+			selector, err := r.GetStringMap(
+				strings.ReplaceAll(immutableSelectorPath, "/", "."))
+			if err != nil && !fs.CreateIfNotPresent {
+				continue
+			}
+			var changes []string
+			for k, v := range g.labels {
+				if current, ok := selector[k]; !ok || current != v {
+					changes = append(changes, k+"="+v)
+				}
+			}
+			if len(changes) > 0 {
+				sort.Strings(changes)
+				errs = append(errs, fmt.Sprintf("%s would have %s set to %s",
+					r.CurId(), immutableSelectorPath, strings.Join(changes, ", ")))
+			}
+			break
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf(
+			"commonLabels would modify immutable selectors:\n  %s",
+			strings.Join(errs, "\n  "))
+	}
+	return nil
+}
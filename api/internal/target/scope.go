@@ -0,0 +1,56 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// ScopeTo limits the build to the contribution of the
+// resources or components at the given path, relative to
+// the kustomization root.  The generators and transformers
+// of the kustomization, and of any kustomization between
+// it and the path, are still applied.
+func (kt *KustTarget) ScopeTo(p string) {
+	kt.scope = path.Clean(p)
+}
+
+// scopedPaths returns the resources and components of the
+// kustomization which are within its scope, recording the
+// scope of those which contain it.
+func (kt *KustTarget) scopedPaths() (
+	resources []string, components []string, err error) {
+	if kt.scope == "" {
+		return kt.kustomization.Resources, kt.kustomization.Components, nil
+	}
+	kt.subScopes = map[string]string{}
+	resources = kt.inScope(kt.kustomization.Resources)
+	components = kt.inScope(kt.kustomization.Components)
+	if len(resources) == 0 && len(components) == 0 {
+		return nil, nil, fmt.Errorf(
+			"no resources or components of '%s' are within '%s'",
+			kt.ldr.Root(), kt.scope)
+	}
+	return resources, components, nil
+}
+
+// inScope returns the entries of paths which are within the
+// scope, or which contain it.
+func (kt *KustTarget) inScope(paths []string) []string {
+	var result []string
+	for _, entry := range paths {
+		p := path.Clean(entry)
+		switch {
+		case p == kt.scope || strings.HasPrefix(p, kt.scope+"/"):
+			result = append(result, entry)
+		case strings.HasPrefix(kt.scope, p+"/"):
+			// only part of this entry is within the scope
+			kt.subScopes[entry] = strings.TrimPrefix(kt.scope, p+"/")
+			result = append(result, entry)
+		}
+	}
+	return result
+}
@@ -0,0 +1,110 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/ifc"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+)
+
+// The api module depends on a kyaml release which predates
+// the krmfile and fieldmeta packages, so their constants are
+// repeated here.
+const (
+	// krmfileName is the name of the file holding the
+	// OpenAPI setter definitions of a package, as
+	// krmfile.KrmfileName.
+	krmfileName = "Krmfile"
+
+	// setterDefinitionPrefix is the prefix of the OpenAPI
+	// definitions of setters, as
+	// fieldmeta.SetterDefinitionPrefix.
+	setterDefinitionPrefix = "io.k8s.cli.setters."
+)
+
+// appliesWhen returns true if the condition is nil or holds
+// for the setters defined next to the kustomization file.
+func (kt *KustTarget) appliesWhen(c *types.SetterCondition) (bool, error) {
+	if c == nil {
+		return true, nil
+	}
+	if c.Setter == "" {
+		return false, fmt.Errorf("appliesWhen requires a setter")
+	}
+	if kt.setters == nil {
+		setters, err := loadSetterValues(kt.ldr)
+		if err != nil {
+			return false, err
+		}
+		kt.setters = setters
+	}
+	value, ok := kt.setters[c.Setter]
+	if !ok {
+		return false, fmt.Errorf(
+			"appliesWhen refers to setter %s, which is not defined in %s",
+			c.Setter, krmfileName)
+	}
+	return value == c.Value, nil
+}
+
+// loadSetterValues returns the values of the setters
+// defined in the setter file, keyed by setter name.
+func loadSetterValues(ldr ifc.Loader) (map[string]string, error) {
+	content, err := ldr.Load(krmfileName)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"appliesWhen requires setters defined in %s: %v",
+			krmfileName, err)
+	}
+	var f struct {
+		OpenAPI struct {
+			Definitions map[string]struct {
+				Cli struct {
+					Setter struct {
+						Name  string      `json:"name"`
+						Value interface{} `json:"value"`
+					} `json:"setter"`
+				} `json:"x-k8s-cli"`
+			} `json:"definitions"`
+		} `json:"openAPI"`
+	}
+	if err := yaml.Unmarshal(content, &f); err != nil {
+		return nil, fmt.Errorf(
+			"unable to parse %s: %v", krmfileName, err)
+	}
+	result := map[string]string{}
+	for key, def := range f.OpenAPI.Definitions {
+		if !strings.HasPrefix(key, setterDefinitionPrefix) {
+			continue
+		}
+		name := def.Cli.Setter.Name
+		if name == "" {
+			name = strings.TrimPrefix(key, setterDefinitionPrefix)
+		}
+		result[name] = formatSetterValue(def.Cli.Setter.Value)
+	}
+	return result, nil
+}
+
+// formatSetterValue returns the string form of a setter value,
+// which may have been written as a number or bool rather than
+// a string -- e.g. 3 rather than "3".
+func formatSetterValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		// numbers are decoded from JSON, format them as written
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
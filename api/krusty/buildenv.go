@@ -0,0 +1,110 @@
+// Copyright 2020 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+)
+
+// envVar is an env var injected into containers.
+type envVar struct {
+	name  string
+	value string
+}
+
+// injectBuildEnv adds the env vars, each given as NAME=VALUE,
+// to every container and init container of the resources in m,
+// e.g. to stamp them with the git sha they were built from.
+// Containers already holding an env var of the same name are
+// left untouched, so injecting into the output of an earlier
+// build doesn't duplicate it.
+func injectBuildEnv(m resmap.ResMap, env []string) error {
+	var vars []envVar
+	for _, e := range env {
+		kv := strings.SplitN(e, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return fmt.Errorf(
+				"invalid build env %q; expected NAME=VALUE", e)
+		}
+		vars = append(vars, envVar{name: kv[0], value: kv[1]})
+	}
+	for _, res := range m.Resources() {
+		if err := injectEnvInContainers(res.Map(), vars); err != nil {
+			return fmt.Errorf("%s: %v", res.CurId(), err)
+		}
+	}
+	return nil
+}
+
+// injectEnvInContainers searches obj for containers and init
+// containers at any depth, e.g. spec/template/spec/containers,
+// and adds the env vars to each of them.
+func injectEnvInContainers(obj map[string]interface{}, vars []envVar) error {
+	for key, value := range obj {
+		switch typedV := value.(type) {
+		case map[string]interface{}:
+			if err := injectEnvInContainers(typedV, vars); err != nil {
+				return err
+			}
+		case []interface{}:
+			isContainers := key == "containers" || key == "initContainers"
+			for _, item := range typedV {
+				typedItem, ok := item.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if isContainers {
+					if err := injectEnv(typedItem, vars); err != nil {
+						return err
+					}
+					continue
+				}
+				if err := injectEnvInContainers(typedItem, vars); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// injectEnv appends the env vars to the env of container,
+// skipping those it already has.
+func injectEnv(container map[string]interface{}, vars []envVar) error {
+	var env []interface{}
+	if e, found := container["env"]; found && e != nil {
+		var ok bool
+		if env, ok = e.([]interface{}); !ok {
+			return fmt.Errorf(
+				"env of container %v is not of type []interface{} but %T",
+				container["name"], e)
+		}
+	}
+	for _, v := range vars {
+		if hasEnvVar(env, v.name) {
+			continue
+		}
+		env = append(env, map[string]interface{}{
+			"name":  v.name,
+			"value": v.value,
+		})
+	}
+	if len(env) > 0 {
+		container["env"] = env
+	}
+	return nil
+}
+
+// hasEnvVar returns true if env holds an env var named name.
+func hasEnvVar(env []interface{}, name string) bool {
+	for _, e := range env {
+		if m, ok := e.(map[string]interface{}); ok && m["name"] == name {
+			return true
+		}
+	}
+	return false
+}
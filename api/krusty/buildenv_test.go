@@ -0,0 +1,132 @@
+// Copyright 2020 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+const buildEnvExpected = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - env:
+        - name: LOG_LEVEL
+          value: debug
+        - name: BUILD_SHA
+          value: 1a2b3c
+        image: app:v1
+        name: app
+      - env:
+        - name: BUILD_SHA
+          value: 1a2b3c
+        image: sidecar:v1
+        name: sidecar
+      initContainers:
+      - env:
+        - name: BUILD_SHA
+          value: 1a2b3c
+        image: init:v1
+        name: init
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: debug
+spec:
+  containers:
+  - env:
+    - name: BUILD_SHA
+      value: 1a2b3c
+    image: busybox
+    name: debug
+`
+
+func TestInjectBuildEnv(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteF("/app/resources.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: init:v1
+      containers:
+      - name: app
+        image: app:v1
+        env:
+        - name: LOG_LEVEL
+          value: debug
+      - name: sidecar
+        image: sidecar:v1
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: debug
+spec:
+  containers:
+  - name: debug
+    image: busybox
+`)
+	th.WriteK("/app", `
+resources:
+- resources.yaml
+`)
+	options := th.MakeDefaultOptions()
+	options.InjectBuildEnv = []string{"BUILD_SHA=1a2b3c"}
+	m := th.Run("/app", options)
+	th.AssertActualEqualsExpected(m, buildEnvExpected)
+
+	// injecting into the output of the build doesn't
+	// duplicate the env var
+	out, err := m.AsYaml()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	th.WriteF("/rerun/resources.yaml", string(out))
+	th.WriteK("/rerun", `
+resources:
+- resources.yaml
+`)
+	m = th.Run("/rerun", options)
+	th.AssertActualEqualsExpected(m, buildEnvExpected)
+}
+
+func TestInjectBuildEnvInvalid(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteF("/app/resources.yaml", `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: debug
+spec:
+  containers:
+  - name: debug
+    image: busybox
+`)
+	th.WriteK("/app", `
+resources:
+- resources.yaml
+`)
+	options := th.MakeDefaultOptions()
+	options.InjectBuildEnv = []string{"BUILD_SHA"}
+	err := th.RunWithErr("/app", options)
+	if err == nil || !strings.Contains(err.Error(),
+		`invalid build env "BUILD_SHA"; expected NAME=VALUE`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
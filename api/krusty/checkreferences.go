@@ -0,0 +1,187 @@
+// Copyright 2020 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/internal/plugins/builtinconfig"
+	"sigs.k8s.io/kustomize/api/resid"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/api/transform"
+)
+
+// checkReferences returns an error listing each name reference
+// in m which doesn't resolve to a resource in m.
+//
+// The name reference fields are those of the nameReference
+// configuration of tc, e.g. a Deployment's
+// envFrom/configMapRef/name refers to a ConfigMap.
+// References are compared against the current (transformed)
+// names, since the name reference transformer has already
+// rewritten the references that it could resolve.
+func checkReferences(
+	m resmap.ResMap, tc *builtinconfig.TransformerConfig) error {
+	var dangling []string
+	for _, referrer := range m.Resources() {
+		refs, err := findReferences(m, tc, referrer)
+		if err != nil {
+			return err
+		}
+		for _, fr := range refs {
+			if len(fr.targets) == 0 && !fr.isDefaultServiceAccount() {
+				dangling = append(dangling, fmt.Sprintf(
+					"%s field %s refers to missing %s %s",
+					referrer.CurId(), fr.path,
+					strings.Join(fr.kinds, " or "), fr.name))
+			}
+		}
+	}
+	if len(dangling) > 0 {
+		return fmt.Errorf(
+			"found %d dangling references:\n  %s",
+			len(dangling), strings.Join(dangling, "\n  "))
+	}
+	return nil
+}
+
+// fieldRef is a name reference held by a field of
+// a referrer, the kinds it may refer to, and the
+// resources it resolves to.
+type fieldRef struct {
+	nameRef
+	path    string
+	kinds   []string
+	targets []*resource.Resource
+}
+
+// defaultServiceAccount is the name of the ServiceAccount
+// which Kubernetes creates in every namespace.
+const defaultServiceAccount = "default"
+
+// isDefaultServiceAccount returns true if fr may refer to
+// the default ServiceAccount, which needn't be a resource
+// of the build.
+func (fr *fieldRef) isDefaultServiceAccount() bool {
+	if fr.name != defaultServiceAccount {
+		return false
+	}
+	for _, k := range fr.kinds {
+		if k == "ServiceAccount" {
+			return true
+		}
+	}
+	return false
+}
+
+// findReferences returns the name references held by the
+// fields of referrer, per the nameReference configuration
+// of tc.
+func findReferences(
+	m resmap.ResMap, tc *builtinconfig.TransformerConfig,
+	referrer *resource.Resource) ([]*fieldRef, error) {
+	// Some fields may refer to more than one kind,
+	// e.g. a RoleBinding's roleRef/name may refer to a
+	// Role or a ClusterRole, so references held by the
+	// same field are combined.
+	var refs []*fieldRef
+	byField := map[string]*fieldRef{}
+	for _, target := range tc.NameReference {
+		for _, fSpec := range target.FieldSpecs {
+			if !referrer.OrgId().IsSelected(&fSpec.Gvk) {
+				continue
+			}
+			path := fSpec.Path
+			gvk := target.Gvk
+			err := transform.MutateField(
+				referrer.Map(), fSpec.PathSlice(), false,
+				func(in interface{}) (interface{}, error) {
+					for _, ref := range namesReferenced(in, gvk.Kind) {
+						key := path + "|" + ref.namespace + "|" + ref.name
+						fr, ok := byField[key]
+						if !ok {
+							fr = &fieldRef{path: path, nameRef: ref}
+							byField[key] = fr
+							refs = append(refs, fr)
+						}
+						fr.kinds = append(fr.kinds, gvk.Kind)
+						fr.targets = append(fr.targets,
+							referencedResources(m, referrer, gvk, ref)...)
+					}
+					return in, nil
+				})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return refs, nil
+}
+
+// nameRef is the name, and optionally the namespace,
+// held by a name reference field.
+type nameRef struct {
+	name      string
+	namespace string
+}
+
+// namesReferenced returns the names held by the value of
+// a name reference field to a resource of the given kind.
+// The value may be a name, a map with name and namespace
+// fields (e.g. RoleBinding subjects) or a list of either.
+// Maps with a kind field other than kind are skipped.
+func namesReferenced(in interface{}, kind string) []nameRef {
+	switch thing := in.(type) {
+	case string:
+		return []nameRef{{name: thing}}
+	case map[string]interface{}:
+		if k, ok := thing["kind"].(string); ok && k != kind {
+			return nil
+		}
+		name, ok := thing["name"].(string)
+		if !ok {
+			return nil
+		}
+		ns, _ := thing["namespace"].(string)
+		return []nameRef{{name: name, namespace: ns}}
+	case []interface{}:
+		var result []nameRef
+		for _, item := range thing {
+			result = append(result, namesReferenced(item, kind)...)
+		}
+		return result
+	}
+	return nil
+}
+
+// referencedResources returns the resources in m selected
+// by target with the referenced name and namespace.
+// Without an explicit namespace, a namespaced referrer may
+// only refer to resources in its own namespace.
+func referencedResources(
+	m resmap.ResMap, referrer *resource.Resource,
+	target resid.Gvk, ref nameRef) []*resource.Resource {
+	var result []*resource.Resource
+	for _, res := range m.Resources() {
+		id := res.CurId()
+		if !id.IsSelected(&target) || res.GetName() != ref.name {
+			continue
+		}
+		switch {
+		case !id.IsNamespaceableKind():
+			result = append(result, res)
+		case ref.namespace != "":
+			if id.EffectiveNamespace() == resid.NewResIdWithNamespace(
+				id.Gvk, ref.name, ref.namespace).EffectiveNamespace() {
+				result = append(result, res)
+			}
+		case !referrer.CurId().IsNamespaceableKind() ||
+			id.IsNsEquals(referrer.CurId()):
+			result = append(result, res)
+		}
+	}
+	return result
+}
@@ -0,0 +1,157 @@
+// Copyright 2020 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func writeCheckReferencesDeployment(th kusttest_test.Harness, configMap string) {
+	th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app
+        envFrom:
+        - configMapRef:
+            name: `+configMap+`
+`)
+	th.WriteF("/app/configmap.yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+`)
+	th.WriteK("/app", `
+namePrefix: p-
+resources:
+- configmap.yaml
+- deployment.yaml
+`)
+}
+
+func TestCheckReferencesDangling(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeCheckReferencesDeployment(th, "missing")
+	options := th.MakeDefaultOptions()
+	options.CheckReferences = true
+	err := th.RunWithErr("/app", options)
+	if !strings.Contains(err.Error(),
+		"apps_v1_Deployment|~X|p-app field "+
+			"spec/template/spec/containers/envFrom/configMapRef/name "+
+			"refers to missing ConfigMap missing") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCheckReferencesResolved(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeCheckReferencesDeployment(th, "config")
+	options := th.MakeDefaultOptions()
+	options.CheckReferences = true
+	m := th.Run("/app", options)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: p-config
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: p-app
+spec:
+  template:
+    spec:
+      containers:
+      - envFrom:
+        - configMapRef:
+            name: p-config
+        image: app
+        name: app
+`)
+}
+
+func TestCheckReferencesConfigurations(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteF("/app/job.yaml", `
+apiVersion: example.com/v1
+kind: Job
+metadata:
+  name: job
+spec:
+  configName: missing
+`)
+	th.WriteF("/app/nameref.yaml", `
+nameReference:
+- kind: ConfigMap
+  fieldSpecs:
+  - kind: Job
+    group: example.com
+    path: spec/configName
+`)
+	th.WriteK("/app", `
+resources:
+- job.yaml
+configurations:
+- nameref.yaml
+`)
+	options := th.MakeDefaultOptions()
+	options.CheckReferences = true
+	err := th.RunWithErr("/app", options)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"example.com_v1_Job|~X|job field spec/configName "+
+			"refers to missing ConfigMap missing") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCheckReferencesDefaultServiceAccount(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      serviceAccountName: default
+      containers:
+      - name: app
+        image: app
+`)
+	th.WriteK("/app", `
+resources:
+- deployment.yaml
+`)
+	options := th.MakeDefaultOptions()
+	options.CheckReferences = true
+	m := th.Run("/app", options)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - image: app
+        name: app
+      serviceAccountName: default
+`)
+}
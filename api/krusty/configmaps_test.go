@@ -288,3 +288,58 @@ metadata:
   name: cm-o2-gfcc59fg5m
 `)
 }
+
+// The data keys of generated ConfigMaps and Secrets are
+// emitted in sorted order, regardless of the order in which
+// they were added, so that the output is stable.
+func TestGeneratorDataKeysSorted(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+generatorOptions:
+  disableNameSuffixHash: true
+configMapGenerator:
+- name: cm
+  literals:
+  - zebra=stripes
+  - apple=red
+  - mango=yellow
+  envs:
+  - b.env
+  files:
+  - c.txt
+secretGenerator:
+- name: secret
+  literals:
+  - zebra=stripes
+  - apple=red
+`)
+	th.WriteF("/app/b.env", `
+YAK=hairy
+BEE=buzzy
+`)
+	th.WriteF("/app/c.txt", "cherry")
+
+	m := th.Run("/app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  BEE: buzzy
+  YAK: hairy
+  apple: red
+  c.txt: cherry
+  mango: yellow
+  zebra: stripes
+kind: ConfigMap
+metadata:
+  name: cm
+---
+apiVersion: v1
+data:
+  apple: cmVk
+  zebra: c3RyaXBlcw==
+kind: Secret
+metadata:
+  name: secret
+type: Opaque
+`)
+}
@@ -0,0 +1,48 @@
+// Copyright 2020 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty
+
+import (
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+)
+
+// groupByOrigin reorders the resources in m so that those
+// read from the same file are contiguous, in the position
+// of the first of them.  Otherwise the order is kept.
+// Resources without an origin, e.g. generated ones, keep
+// their position relative to the groups.
+func groupByOrigin(m resmap.ResMap) error {
+	var order []string
+	groups := map[string][]*resource.Resource{}
+	var resources []*resource.Resource
+	for _, r := range m.Resources() {
+		origin := r.GetOrigin()
+		if origin == "" {
+			resources = append(resources, r)
+			continue
+		}
+		if _, found := groups[origin]; !found {
+			order = append(order, origin)
+			// placeholder for the group
+			resources = append(resources, nil)
+		}
+		groups[origin] = append(groups[origin], r)
+	}
+	m.Clear()
+	next := 0
+	for _, r := range resources {
+		group := []*resource.Resource{r}
+		if r == nil {
+			group = groups[order[next]]
+			next++
+		}
+		for _, res := range group {
+			if err := m.Append(res); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
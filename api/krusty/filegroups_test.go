@@ -0,0 +1,82 @@
+// Copyright 2020 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestPreserveFileGroups(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteF("/app/a.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: a
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: a
+`)
+	th.WriteF("/app/b.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: b
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: b
+`)
+	th.WriteK("/app", `
+resources:
+- a.yaml
+- b.yaml
+configMapGenerator:
+- name: cm
+  literals:
+  - foo=bar
+generatorOptions:
+  disableNameSuffixHash: true
+`)
+	options := th.MakeDefaultOptions()
+	options.DoLegacyResourceSort = true
+	options.PreserveFileGroups = true
+	m := th.Run("/app", options)
+	// the legacy sort emits the services before the
+	// deployments, but the resources of each file stay
+	// together
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  foo: bar
+kind: ConfigMap
+metadata:
+  name: cm
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: a
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: a
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: b
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: b
+`)
+}
@@ -0,0 +1,141 @@
+// Copyright 2020 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/internal/plugins/builtinconfig"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+)
+
+// ResourceGraph is a graph of resources and the references
+// between them, e.g. for visualizing the output of a build.
+type ResourceGraph struct {
+	// Nodes identify the resources, e.g. Deployment/app or,
+	// for namespaced resources, prod/Deployment/app.
+	Nodes []string `json:"nodes"`
+
+	// Edges are the references between the resources.
+	Edges []ResourceGraphEdge `json:"edges"`
+}
+
+// ResourceGraphEdge is a reference from one resource to another.
+type ResourceGraphEdge struct {
+	// From is the node of the referring resource.
+	From string `json:"from"`
+
+	// To is the node of the referenced resource.
+	To string `json:"to"`
+
+	// Field is the path of the field holding the reference.
+	Field string `json:"field"`
+}
+
+// serviceSelectorPath is the path of a Service's selector.
+const serviceSelectorPath = "spec/selector"
+
+// MakeResourceGraph returns the graph of the resources in m.
+// Edges are derived from the name reference fields of the
+// default nameReference configuration, e.g. a Deployment's
+// configMapRef, and from the selectors of Services, which
+// refer to the workloads whose pod templates they match.
+func MakeResourceGraph(m resmap.ResMap) (*ResourceGraph, error) {
+	g := &ResourceGraph{Nodes: []string{}, Edges: []ResourceGraphEdge{}}
+	for _, r := range m.Resources() {
+		g.Nodes = append(g.Nodes, graphNode(r))
+	}
+	tc := builtinconfig.MakeDefaultConfig()
+	for _, referrer := range m.Resources() {
+		refs, err := findReferences(m, tc, referrer)
+		if err != nil {
+			return nil, err
+		}
+		for _, fr := range refs {
+			for _, target := range fr.targets {
+				g.Edges = append(g.Edges, ResourceGraphEdge{
+					From:  graphNode(referrer),
+					To:    graphNode(target),
+					Field: fr.path,
+				})
+			}
+		}
+		if referrer.GetKind() == "Service" {
+			for _, target := range selectedWorkloads(m, referrer) {
+				g.Edges = append(g.Edges, ResourceGraphEdge{
+					From:  graphNode(referrer),
+					To:    graphNode(target),
+					Field: serviceSelectorPath,
+				})
+			}
+		}
+	}
+	return g, nil
+}
+
+// selectedWorkloads returns the resources in the namespace
+// of the service whose pod template labels match its selector.
+func selectedWorkloads(
+	m resmap.ResMap, service *resource.Resource) []*resource.Resource {
+	selector, err := service.GetStringMap(
+		strings.ReplaceAll(serviceSelectorPath, "/", "."))
+	if err != nil || len(selector) == 0 {
+		return nil
+	}
+	var result []*resource.Resource
+	for _, r := range m.Resources() {
+		if !r.CurId().IsNsEquals(service.CurId()) {
+			continue
+		}
+		labels, err := r.GetStringMap("spec.template.metadata.labels")
+		if err != nil {
+			continue
+		}
+		matched := true
+		for k, v := range selector {
+			if labels[k] != v {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+func graphNode(r *resource.Resource) string {
+	node := r.GetKind() + "/" + r.GetName()
+	if ns := r.GetNamespace(); ns != "" {
+		node = ns + "/" + node
+	}
+	return node
+}
+
+// Dot returns the graph in the Graphviz DOT language.
+func (g *ResourceGraph) Dot() string {
+	var b strings.Builder
+	b.WriteString("digraph resources {\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&b, "  %q;\n", n)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %q -> %q [label=%q];\n", e.From, e.To, e.Field)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// JSON returns the graph as indented JSON.
+func (g *ResourceGraph) JSON() ([]byte, error) {
+	b, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2020 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/krusty"
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestResourceGraph(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteF("/app/resources.yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  selector:
+    app: web
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: nginx
+        envFrom:
+        - configMapRef:
+            name: config
+`)
+	th.WriteK("/app", `
+resources:
+- resources.yaml
+`)
+	m := th.Run("/app", th.MakeDefaultOptions())
+	g, err := krusty.MakeResourceGraph(m)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `digraph resources {
+  "ConfigMap/config";
+  "Service/web";
+  "Deployment/web";
+  "Service/web" -> "Deployment/web" [label="spec/selector"];
+  "Deployment/web" -> "ConfigMap/config" [label="spec/template/spec/containers/envFrom/configMapRef/name"];
+}
+`
+	if actual := g.Dot(); actual != expected {
+		t.Fatalf("expected:\n%s\nactual:\n%s", expected, actual)
+	}
+
+	b, err := g.JSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = `{
+  "nodes": [
+    "ConfigMap/config",
+    "Service/web",
+    "Deployment/web"
+  ],
+  "edges": [
+    {
+      "from": "Service/web",
+      "to": "Deployment/web",
+      "field": "spec/selector"
+    },
+    {
+      "from": "Deployment/web",
+      "to": "ConfigMap/config",
+      "field": "spec/template/spec/containers/envFrom/configMapRef/name"
+    }
+  ]
+}
+`
+	if actual := string(b); actual != expected {
+		t.Fatalf("expected:\n%s\nactual:\n%s", expected, actual)
+	}
+}
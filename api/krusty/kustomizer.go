@@ -5,6 +5,8 @@ package krusty
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"sigs.k8s.io/kustomize/api/builtins"
 	"sigs.k8s.io/kustomize/api/filesys"
@@ -28,8 +30,10 @@ import (
 // number of overlays and bases), then make a Kustomizer
 // injected with the given fileystem, then call Run.
 type Kustomizer struct {
-	fSys    filesys.FileSystem
-	options *Options
+	fSys     filesys.FileSystem
+	options  *Options
+	warnings []string
+	profile  *Profile
 }
 
 // MakeKustomizer returns an instance of Kustomizer.
@@ -49,6 +53,9 @@ func MakeKustomizer(fSys filesys.FileSystem, o *Options) *Kustomizer {
 // on any number of internal paths (e.g. the filesystem may contain
 // multiple overlays, and Run can be called on each of them).
 func (b *Kustomizer) Run(path string) (resmap.ResMap, error) {
+	b.warnings = nil
+	b.profile = nil
+	start := time.Now()
 	pf := transformer.NewFactoryImpl()
 	rf := resmap.NewFactory(
 		resource.NewFactory(
@@ -70,10 +77,32 @@ func (b *Kustomizer) Run(path string) (resmap.ResMap, error) {
 		pf,
 		pLdr.NewLoader(b.options.PluginConfig, rf),
 	)
+	if b.options.SafeLabels {
+		kt.EnableSafeLabels()
+	}
+	if b.options.Only != "" {
+		kt.ScopeTo(b.options.Only)
+	}
+	if b.options.MaxDepth > 0 {
+		kt.SetMaxDepth(b.options.MaxDepth)
+	}
+	var p *target.Profile
+	if b.options.Profile {
+		p = &target.Profile{}
+		kt.EnableProfiling(p)
+	}
 	err = kt.Load()
 	if err != nil {
 		return nil, err
 	}
+	if len(b.options.Overrides) > 0 {
+		err = kt.Override(func(k *types.Kustomization) error {
+			return applyOverrides(k, b.options.Overrides)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
 	var m resmap.ResMap
 	m, err = kt.MakeCustomizedResMap()
 	if err != nil {
@@ -82,6 +111,11 @@ func (b *Kustomizer) Run(path string) (resmap.ResMap, error) {
 	if b.options.DoLegacyResourceSort {
 		builtins.NewLegacyOrderTransformerPlugin().Transform(m)
 	}
+	if b.options.PreserveFileGroups {
+		if err = groupByOrigin(m); err != nil {
+			return nil, err
+		}
+	}
 	if b.options.AddManagedbyLabel {
 		t := builtins.LabelTransformerPlugin{
 			Labels: map[string]string{konfig.ManagedbyLabelKey: fmt.Sprintf("kustomize-%s", provenance.GetProvenance().Version)},
@@ -92,5 +126,45 @@ func (b *Kustomizer) Run(path string) (resmap.ResMap, error) {
 		}
 		t.Transform(m)
 	}
+	if len(b.options.InjectBuildEnv) > 0 {
+		if err = injectBuildEnv(m, b.options.InjectBuildEnv); err != nil {
+			return nil, err
+		}
+	}
+	if b.options.MaxResources > 0 && m.Size() > b.options.MaxResources {
+		return nil, fmt.Errorf(
+			"build emits %d resources, exceeding the maximum of %d",
+			m.Size(), b.options.MaxResources)
+	}
+	if b.options.CheckReferences {
+		if err = checkReferences(m, kt.TransformerConfig()); err != nil {
+			return nil, err
+		}
+	}
+	if len(b.options.WarnMissingLabels) > 0 {
+		b.warnings = append(b.warnings,
+			missingLabels(m, b.options.WarnMissingLabels)...)
+	}
+	if b.options.Strict && len(b.warnings) > 0 {
+		return nil, fmt.Errorf(
+			"found %d warnings in strict mode:\n  %s",
+			len(b.warnings), strings.Join(b.warnings, "\n  "))
+	}
+	if p != nil {
+		b.profile = makeProfile(p, time.Since(start), m.Size())
+	}
 	return m, nil
 }
+
+// Warnings returns the warnings about the resources
+// emitted by the last Run, e.g. about resources lacking
+// recommended labels.
+func (b *Kustomizer) Warnings() []string {
+	return b.warnings
+}
+
+// Profile returns the timing of the last Run, or nil if
+// Options.Profile isn't set.
+func (b *Kustomizer) Profile() *Profile {
+	return b.profile
+}
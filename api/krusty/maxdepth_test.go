@@ -0,0 +1,66 @@
+// Copyright 2020 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+// writeMaxDepthOverlay writes an overlay with a base nested
+// two levels deep: overlay -> mid -> base.
+func writeMaxDepthOverlay(th kusttest_test.Harness) {
+	th.WriteF("/app/base/cm.yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+`)
+	th.WriteK("/app/base", `
+resources:
+- cm.yaml
+`)
+	th.WriteK("/app/mid", `
+resources:
+- ../base
+namePrefix: mid-
+`)
+	th.WriteK("/app/overlay", `
+resources:
+- ../mid
+namePrefix: overlay-
+`)
+}
+
+func TestMaxDepthExceeded(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeMaxDepthOverlay(th)
+	options := th.MakeDefaultOptions()
+	options.MaxDepth = 1
+	err := th.RunWithErr("/app/overlay", options)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(err.Error(),
+		"nesting of bases exceeds the maximum depth of 1: "+
+			"/app/overlay -> /app/mid -> /app/base") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestMaxDepthNotExceeded(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeMaxDepthOverlay(th)
+	options := th.MakeDefaultOptions()
+	options.MaxDepth = 2
+	m := th.Run("/app/overlay", options)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: overlay-mid-cm
+`)
+}
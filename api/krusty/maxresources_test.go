@@ -0,0 +1,73 @@
+// Copyright 2020 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func writeMaxResourcesBase(th kusttest_test.Harness) {
+	th.WriteF("/app/resources.yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm2
+`)
+	th.WriteK("/app", `
+resources:
+- resources.yaml
+configMapGenerator:
+- name: generated
+  literals:
+  - a=b
+generatorOptions:
+  disableNameSuffixHash: true
+`)
+}
+
+func TestMaxResourcesExceeded(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeMaxResourcesBase(th)
+	options := th.MakeDefaultOptions()
+	options.MaxResources = 2
+	err := th.RunWithErr("/app", options)
+	if !strings.Contains(err.Error(),
+		"build emits 3 resources, exceeding the maximum of 2") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestMaxResourcesNotExceeded(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeMaxResourcesBase(th)
+	options := th.MakeDefaultOptions()
+	options.MaxResources = 3
+	m := th.Run("/app", options)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm2
+---
+apiVersion: v1
+data:
+  a: b
+kind: ConfigMap
+metadata:
+  name: generated
+`)
+}
@@ -0,0 +1,33 @@
+// Copyright 2020 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+)
+
+// missingLabels returns a warning for each resource in m
+// which lacks any of the given labels, listing the labels
+// it lacks.
+func missingLabels(m resmap.ResMap, labels []string) []string {
+	var warnings []string
+	for _, r := range m.Resources() {
+		present := r.GetLabels()
+		var missing []string
+		for _, l := range labels {
+			if _, ok := present[l]; !ok {
+				missing = append(missing, l)
+			}
+		}
+		if len(missing) > 0 {
+			warnings = append(warnings, fmt.Sprintf(
+				"%s is missing recommended labels: %s",
+				r.CurId(), strings.Join(missing, ", ")))
+		}
+	}
+	return warnings
+}
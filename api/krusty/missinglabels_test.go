@@ -0,0 +1,97 @@
+// Copyright 2020 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/krusty"
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func writeMissingLabelsBase(th kusttest_test.Harness) {
+	th.WriteF("/app/resources.yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: labelled
+  labels:
+    app.kubernetes.io/name: app
+    app.kubernetes.io/version: v1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: partial
+  labels:
+    app.kubernetes.io/name: app
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unlabelled
+`)
+	th.WriteK("/app", `
+resources:
+- resources.yaml
+`)
+}
+
+func TestWarnMissingLabels(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeMissingLabelsBase(th)
+	options := th.MakeDefaultOptions()
+	options.WarnMissingLabels = []string{
+		"app.kubernetes.io/name", "app.kubernetes.io/version"}
+	k := krusty.MakeKustomizer(th.GetFSys(), &options)
+	m, err := k.Run("/app")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// warnings don't fail the build
+	if m.Size() != 3 {
+		t.Fatalf("expected 3 resources, got %d", m.Size())
+	}
+	expected := []string{
+		"~G_v1_ConfigMap|~X|partial is missing recommended labels: " +
+			"app.kubernetes.io/version",
+		"~G_v1_ConfigMap|~X|unlabelled is missing recommended labels: " +
+			"app.kubernetes.io/name, app.kubernetes.io/version",
+	}
+	if !reflect.DeepEqual(k.Warnings(), expected) {
+		t.Fatalf("expected warnings %v, got %v", expected, k.Warnings())
+	}
+}
+
+func TestWarnMissingLabelsStrict(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeMissingLabelsBase(th)
+	options := th.MakeDefaultOptions()
+	options.WarnMissingLabels = []string{"app.kubernetes.io/name"}
+	options.Strict = true
+	err := th.RunWithErr("/app", options)
+	if err == nil || !strings.Contains(err.Error(),
+		"found 1 warnings in strict mode") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	options.WarnMissingLabels = []string{"app.kubernetes.io/name"}
+	th.WriteF("/app/resources.yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: labelled
+  labels:
+    app.kubernetes.io/name: app
+`)
+	k := krusty.MakeKustomizer(th.GetFSys(), &options)
+	if _, err := k.Run("/app"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(k.Warnings()) != 0 {
+		t.Fatalf("unexpected warnings: %v", k.Warnings())
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func writeTwoComponents(th kusttest_test.Harness) {
+	th.WriteK("/app", `
+namePrefix: prod-
+resources:
+- components/web
+- components/db
+`)
+	th.WriteK("/app/components/web", `
+resources:
+- deployment.yaml
+- service.yaml
+`)
+	th.WriteF("/app/components/web/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+`)
+	th.WriteF("/app/components/web/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`)
+	th.WriteK("/app/components/db", `
+resources:
+- statefulset.yaml
+`)
+	th.WriteF("/app/components/db/statefulset.yaml", `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+`)
+}
+
+func TestOnly(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeTwoComponents(th)
+	opts := th.MakeDefaultOptions()
+	opts.Only = "components/web"
+	m := th.Run("/app", opts)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: prod-web
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: prod-web
+`)
+}
+
+func TestOnlyWithinSubtree(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeTwoComponents(th)
+	opts := th.MakeDefaultOptions()
+	opts.Only = "components/web/deployment.yaml"
+	m := th.Run("/app", opts)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: prod-web
+`)
+}
+
+func TestOnlyNoMatch(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeTwoComponents(th)
+	opts := th.MakeDefaultOptions()
+	opts.Only = "components/cache"
+	err := th.RunWithErr("/app", opts)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"no resources or components of '/app' are within 'components/cache'") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -30,6 +30,61 @@ type Options struct {
 	// Create an inventory object for pruning.
 	DoPrune bool
 
+	// When greater than zero, fail the build if it would
+	// emit more than this many resources.  Guards against
+	// runaway generators or accidentally large globs.
+	MaxResources int
+
+	// When greater than zero, fail the build if bases or
+	// components are nested more than this many levels
+	// below the kustomization being built.
+	MaxDepth int
+
+	// When true, fail the build if a name reference, e.g.
+	// a Deployment's configMapRef, refers to a resource
+	// that isn't present in the build output.
+	CheckReferences bool
+
+	// Overrides applied to the top level kustomization after
+	// it is loaded, e.g. "namespace=test" or
+	// "images.nginx=nginx:2".  The files are left untouched.
+	Overrides []string
+
+	// When true, fail the build if commonLabels would modify
+	// the selector of a Deployment, StatefulSet or DaemonSet,
+	// since selectors are immutable once created.
+	SafeLabels bool
+
+	// If set, only the resources and components at this path,
+	// relative to the kustomization root, are accumulated,
+	// e.g. "components/web".  The generators and transformers
+	// of the kustomizations containing the path still apply.
+	Only string
+
+	// Warn about each resource in the build output lacking
+	// any of these labels, e.g. app.kubernetes.io/name.
+	WarnMissingLabels []string
+
+	// Env vars, each given as NAME=VALUE, added to every
+	// container in the build output, e.g. BUILD_SHA=1a2b3c.
+	// Containers already holding a var of the same name
+	// are left untouched.
+	InjectBuildEnv []string
+
+	// When true, the resources read from the same file are
+	// emitted contiguously, in the position of the first of
+	// them, e.g. after the legacy resource sort.  Resources
+	// made by generators keep their position.
+	PreserveFileGroups bool
+
+	// When true, fail the build if there are any warnings,
+	// e.g. about resources lacking recommended labels.
+	Strict bool
+
+	// When true, record the wall-clock time taken by each
+	// generator and transformer.  See Kustomizer.Profile.
+	Profile bool
+
 	// Options related to kustomize plugins.
 	PluginConfig *types.PluginConfig
 }
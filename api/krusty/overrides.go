@@ -0,0 +1,93 @@
+// Copyright 2020 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+// applyOverrides applies each override, of the form key=value,
+// to the kustomization.  The supported keys are
+//
+//   namespace=<namespace>
+//   namePrefix=<prefix>
+//   images.<name>=<newName>[:<newTag>|@<digest>]
+//   replicas.<name>=<count>
+//
+// Image and replica overrides replace any entry in the
+// kustomization with the same name.
+func applyOverrides(k *types.Kustomization, overrides []string) error {
+	for _, o := range overrides {
+		kv := strings.SplitN(o, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return fmt.Errorf(
+				"invalid override %q; expected key=value", o)
+		}
+		key, value := kv[0], kv[1]
+		switch {
+		case key == "namespace":
+			k.Namespace = value
+		case key == "namePrefix":
+			k.NamePrefix = value
+		case strings.HasPrefix(key, "images."):
+			image := parseImageOverride(
+				strings.TrimPrefix(key, "images."), value)
+			k.Images = overrideImage(k.Images, image)
+		case strings.HasPrefix(key, "replicas."):
+			count, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return fmt.Errorf(
+					"invalid override %q; replicas must be an integer", o)
+			}
+			k.Replicas = overrideReplica(k.Replicas, types.Replica{
+				Name:  strings.TrimPrefix(key, "replicas."),
+				Count: count,
+			})
+		default:
+			return fmt.Errorf(
+				"unsupported override key %q; must be one of "+
+					"namespace, namePrefix, images.<name>, replicas.<name>", key)
+		}
+	}
+	return nil
+}
+
+// parseImageOverride returns the Image replacing name per value,
+// e.g. nginx:2, my-registry/nginx or nginx@sha256:abc.
+func parseImageOverride(name, value string) types.Image {
+	image := types.Image{Name: name}
+	if d := strings.SplitN(value, "@", 2); len(d) == 2 {
+		value, image.Digest = d[0], d[1]
+	} else if i := strings.LastIndex(value, ":"); i > strings.LastIndex(value, "/") {
+		value, image.NewTag = value[:i], value[i+1:]
+	}
+	if value != "" && value != name {
+		image.NewName = value
+	}
+	return image
+}
+
+func overrideImage(images []types.Image, image types.Image) []types.Image {
+	for i := range images {
+		if images[i].Name == image.Name {
+			images[i] = image
+			return images
+		}
+	}
+	return append(images, image)
+}
+
+func overrideReplica(replicas []types.Replica, replica types.Replica) []types.Replica {
+	for i := range replicas {
+		if replicas[i].Name == replica.Name {
+			replicas[i] = replica
+			return replicas
+		}
+	}
+	return append(replicas, replica)
+}
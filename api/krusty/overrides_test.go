@@ -0,0 +1,70 @@
+// Copyright 2020 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func writeOverridesBase(th kusttest_test.Harness) {
+	th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: nginx:1
+`)
+	th.WriteK("/app", `
+namespace: dev
+resources:
+- deployment.yaml
+images:
+- name: nginx
+  newTag: "1.1"
+`)
+}
+
+func TestOverridesNamespaceAndImage(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeOverridesBase(th)
+	options := th.MakeDefaultOptions()
+	options.Overrides = []string{
+		"namespace=test",
+		"images.nginx=my-registry/nginx:2",
+	}
+	m := th.Run("/app", options)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: test
+spec:
+  template:
+    spec:
+      containers:
+      - image: my-registry/nginx:2
+        name: app
+`)
+}
+
+func TestOverridesUnsupportedKey(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeOverridesBase(th)
+	options := th.MakeDefaultOptions()
+	options.Overrides = []string{"commonLabels.app=x"}
+	err := th.RunWithErr("/app", options)
+	if !strings.Contains(err.Error(),
+		`unsupported override key "commonLabels.app"`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty
+
+import (
+	"time"
+
+	"sigs.k8s.io/kustomize/api/internal/target"
+)
+
+// Profile is the timing of a build, recorded when
+// Options.Profile is set, to find expensive generators
+// and transformers.
+type Profile struct {
+	// Stages are the runs of the generators and
+	// transformers, in the order they ran.
+	Stages []StageTiming
+
+	// Duration is the wall-clock time of the whole build.
+	Duration time.Duration
+
+	// Resources is the number of resources emitted.
+	Resources int
+}
+
+// StageTiming is the timing of one run of a generator or
+// transformer.
+type StageTiming struct {
+	// Kind is either "generator" or "transformer".
+	Kind string
+
+	// Name of the plugin, e.g. ConfigMapGenerator, or the
+	// file name of an exec plugin.
+	Name string
+
+	// Root of the kustomization running the plugin.
+	Root string
+
+	// Duration is the wall-clock time the plugin took.
+	Duration time.Duration
+
+	// Resources is the number of resources generated, or
+	// transformed.
+	Resources int
+}
+
+func makeProfile(
+	p *target.Profile, d time.Duration, resources int) *Profile {
+	result := &Profile{Duration: d, Resources: resources}
+	for _, s := range p.Stages {
+		result.Stages = append(result.Stages, StageTiming{
+			Kind:      s.Kind,
+			Name:      s.Name,
+			Root:      s.Root,
+			Duration:  s.Duration,
+			Resources: s.Resources,
+		})
+	}
+	return result
+}
@@ -0,0 +1,99 @@
+// Copyright 2020 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/krusty"
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestProfile(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteF("/app/base/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`)
+	th.WriteK("/app/base", `
+resources:
+- service.yaml
+configMapGenerator:
+- name: config
+  literals:
+  - color=blue
+`)
+	th.WriteK("/app/overlay", `
+namePrefix: prod-
+resources:
+- ../base
+`)
+	options := th.MakeDefaultOptions()
+	options.Profile = true
+	k := krusty.MakeKustomizer(th.GetFSys(), &options)
+	if _, err := k.Run("/app/overlay"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p := k.Profile()
+	if p == nil {
+		t.Fatalf("expected a profile")
+	}
+	if p.Resources != 2 {
+		t.Fatalf("expected 2 resources, got %d", p.Resources)
+	}
+	// each stage is timed, in the order it ran
+	found := map[string]krusty.StageTiming{}
+	var order []string
+	for _, s := range p.Stages {
+		if s.Duration < 0 || s.Duration > p.Duration {
+			t.Fatalf("unexpected duration of %s: %v", s.Name, s.Duration)
+		}
+		key := s.Kind + " " + s.Name + " " + s.Root
+		if _, ok := found[key]; !ok {
+			order = append(order, key)
+		}
+		found[key] = s
+	}
+	for _, stage := range []struct {
+		kind      string
+		name      string
+		root      string
+		resources int
+	}{
+		{"generator", "ConfigMapGenerator", "/app/base", 1},
+		{"transformer", "PrefixSuffixTransformer", "/app/base", 2},
+		{"transformer", "PrefixSuffixTransformer", "/app/overlay", 2},
+		{"transformer", "HashTransformer", "/app/overlay", 2},
+	} {
+		s, ok := found[stage.kind+" "+stage.name+" "+stage.root]
+		if !ok {
+			t.Fatalf("expected a %s %s stage in %s, got %v",
+				stage.kind, stage.name, stage.root, order)
+		}
+		if s.Resources != stage.resources {
+			t.Fatalf("expected %d resources for %s in %s, got %d",
+				stage.resources, stage.name, stage.root, s.Resources)
+		}
+	}
+}
+
+func TestProfileDisabled(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+configMapGenerator:
+- name: config
+  literals:
+  - color=blue
+`)
+	options := th.MakeDefaultOptions()
+	k := krusty.MakeKustomizer(th.GetFSys(), &options)
+	if _, err := k.Run("/app"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if k.Profile() != nil {
+		t.Fatalf("expected no profile, got %v", k.Profile())
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2020 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func writeSafeLabelsBase(th kusttest_test.Harness) {
+	th.WriteF("/app/base/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: nginx
+`)
+	th.WriteK("/app/base", `
+resources:
+- deployment.yaml
+`)
+}
+
+func TestSafeLabelsSelectorMutated(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeSafeLabelsBase(th)
+	th.WriteK("/app/overlay", `
+resources:
+- ../base
+commonLabels:
+  team: payments
+`)
+	options := th.MakeDefaultOptions()
+	options.SafeLabels = true
+	err := th.RunWithErr("/app/overlay", options)
+	if !strings.Contains(err.Error(),
+		"apps_v1_Deployment|~X|web would have spec/selector/matchLabels "+
+			"set to team=payments") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSafeLabelsSelectorUnchanged(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeSafeLabelsBase(th)
+	th.WriteK("/app/overlay", `
+resources:
+- ../base
+commonLabels:
+  app: web
+`)
+	options := th.MakeDefaultOptions()
+	options.SafeLabels = true
+	m := th.Run("/app/overlay", options)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    app: web
+  name: web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - image: nginx
+        name: web
+`)
+}
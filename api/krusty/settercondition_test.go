@@ -0,0 +1,148 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func writeConditionalGenerators(th kusttest_test.Harness, tls string) {
+	th.WriteK("/app", `
+generatorOptions:
+  disableNameSuffixHash: true
+configMapGenerator:
+- name: config
+  literals:
+  - color=blue
+secretGenerator:
+- name: tls
+  literals:
+  - key=secret
+  appliesWhen:
+    setter: tls
+    value: "true"
+`)
+	th.WriteF("/app/Krmfile", `
+apiVersion: krm.dev/v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.tls:
+      x-k8s-cli:
+        setter:
+          name: tls
+          value: "`+tls+`"
+`)
+}
+
+func TestConditionalGeneratorEnabled(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeConditionalGenerators(th, "true")
+	m := th.Run("/app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  color: blue
+kind: ConfigMap
+metadata:
+  name: config
+---
+apiVersion: v1
+data:
+  key: c2VjcmV0
+kind: Secret
+metadata:
+  name: tls
+type: Opaque
+`)
+}
+
+func TestConditionalGeneratorDisabled(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeConditionalGenerators(th, "false")
+	m := th.Run("/app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  color: blue
+kind: ConfigMap
+metadata:
+  name: config
+`)
+}
+
+func TestConditionalGeneratorUnknownSetter(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+secretGenerator:
+- name: tls
+  literals:
+  - key=secret
+  appliesWhen:
+    setter: tls
+    value: "true"
+`)
+	th.WriteF("/app/Krmfile", `
+apiVersion: krm.dev/v1alpha1
+kind: Krmfile
+`)
+	err := th.RunWithErr("/app", th.MakeDefaultOptions())
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"appliesWhen refers to setter tls, which is not defined in Krmfile") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestConditionalGeneratorNonStringValues(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+generatorOptions:
+  disableNameSuffixHash: true
+configMapGenerator:
+- name: replicas
+  literals:
+  - count=3
+  appliesWhen:
+    setter: replicas
+    value: "3"
+secretGenerator:
+- name: tls
+  literals:
+  - key=secret
+  appliesWhen:
+    setter: tls
+    value: "false"
+`)
+	th.WriteF("/app/Krmfile", `
+apiVersion: krm.dev/v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: 3
+    io.k8s.cli.setters.tls:
+      x-k8s-cli:
+        setter:
+          name: tls
+          value: true
+`)
+	m := th.Run("/app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  count: "3"
+kind: ConfigMap
+metadata:
+  name: replicas
+`)
+}
@@ -718,62 +718,113 @@ func anchorRegex(pattern string) string {
 // Select returns a list of resources that
 // are selected by a Selector
 func (m *resWrangler) Select(s types.Selector) ([]*resource.Resource, error) {
-	ns := regexp.MustCompile(anchorRegex(s.Namespace))
-	nm := regexp.MustCompile(anchorRegex(s.Name))
+	sel, err := compileSelector(s)
+	if err != nil {
+		return nil, err
+	}
 	var result []*resource.Resource
 	for _, r := range m.Resources() {
-		curId := r.CurId()
-		orgId := r.OrgId()
-
-		// matches the namespace when namespace is not empty in the selector
-		// It first tries to match with the original namespace
-		// then matches with the current namespace
-		if r.GetNamespace() != "" {
-			matched := ns.MatchString(orgId.EffectiveNamespace())
-			if !matched {
-				matched = ns.MatchString(curId.EffectiveNamespace())
-				if !matched {
-					continue
-				}
-			}
+		matched, err := sel.isSelected(r)
+		if err != nil {
+			return nil, err
 		}
-
-		// matches the name when name is not empty in the selector
-		// It first tries to match with the original name
-		// then matches with the current name
-		if r.GetName() != "" {
-			matched := nm.MatchString(orgId.Name)
-			if !matched {
-				matched = nm.MatchString(curId.Name)
-				if !matched {
-					continue
-				}
-			}
+		if matched {
+			result = append(result, r)
 		}
+	}
+	return result, nil
+}
 
-		// matches the GVK
-		if !r.GetGvk().IsSelected(&s.Gvk) {
-			continue
-		}
+// compiledSelector is a Selector with its namespace and
+// name patterns compiled, and those of its AnyOf selectors.
+type compiledSelector struct {
+	types.Selector
+	ns    *regexp.Regexp
+	nm    *regexp.Regexp
+	anyOf []*compiledSelector
+}
 
-		// matches the label selector
-		matched, err := r.MatchesLabelSelector(s.LabelSelector)
+// compileSelector compiles the patterns of the selector and
+// of its AnyOf selectors.
+func compileSelector(s types.Selector) (*compiledSelector, error) {
+	ns, err := regexp.Compile(anchorRegex(s.Namespace))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid namespace %q in selector", s.Namespace)
+	}
+	nm, err := regexp.Compile(anchorRegex(s.Name))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid name %q in selector", s.Name)
+	}
+	result := &compiledSelector{Selector: s, ns: ns, nm: nm}
+	for i := range s.AnyOf {
+		c, err := compileSelector(s.AnyOf[i])
 		if err != nil {
 			return nil, err
 		}
+		result.anyOf = append(result.anyOf, c)
+	}
+	return result, nil
+}
+
+// isSelected returns true if the resource matches all the
+// conditions of the selector and, if the selector has any,
+// at least one of its AnyOf selectors.
+func (s *compiledSelector) isSelected(r *resource.Resource) (bool, error) {
+	curId := r.CurId()
+	orgId := r.OrgId()
+
+	// matches the namespace when namespace is not empty in the selector
+	// It first tries to match with the original namespace
+	// then matches with the current namespace
+	if r.GetNamespace() != "" {
+		matched := s.ns.MatchString(orgId.EffectiveNamespace())
 		if !matched {
-			continue
+			matched = s.ns.MatchString(curId.EffectiveNamespace())
+			if !matched {
+				return false, nil
+			}
 		}
+	}
 
-		// matches the annotation selector
-		matched, err = r.MatchesAnnotationSelector(s.AnnotationSelector)
-		if err != nil {
-			return nil, err
-		}
+	// matches the name when name is not empty in the selector
+	// It first tries to match with the original name
+	// then matches with the current name
+	if r.GetName() != "" {
+		matched := s.nm.MatchString(orgId.Name)
 		if !matched {
-			continue
+			matched = s.nm.MatchString(curId.Name)
+			if !matched {
+				return false, nil
+			}
 		}
-		result = append(result, r)
 	}
-	return result, nil
+
+	// matches the GVK
+	if !r.GetGvk().IsSelected(&s.Gvk) {
+		return false, nil
+	}
+
+	// matches the label selector
+	matched, err := r.MatchesLabelSelector(s.LabelSelector)
+	if err != nil || !matched {
+		return false, err
+	}
+
+	// matches the annotation selector
+	matched, err = r.MatchesAnnotationSelector(s.AnnotationSelector)
+	if err != nil || !matched {
+		return false, err
+	}
+
+	// matches any of the alternative selectors
+	if len(s.anyOf) == 0 {
+		return true, nil
+	}
+	for i := range s.anyOf {
+		matched, err = s.anyOf[i].isSelected(r)
+		if err != nil || matched {
+			return matched, err
+		}
+	}
+	return false, nil
 }
@@ -164,6 +164,36 @@ func TestFindPatchTargets(t *testing.T) {
 			},
 			count: 2,
 		},
+		{
+			target: types.Selector{
+				AnyOf: []types.Selector{
+					{LabelSelector: "app=name1"},
+					{LabelSelector: "app=name3"},
+				},
+			},
+			count: 2,
+		},
+		{
+			target: types.Selector{
+				Gvk: resid.Gvk{
+					Kind: "Kind1",
+				},
+				AnyOf: []types.Selector{
+					{LabelSelector: "app=name1"},
+					{LabelSelector: "app=name3"},
+				},
+			},
+			count: 1,
+		},
+		{
+			target: types.Selector{
+				AnyOf: []types.Selector{
+					{LabelSelector: "app=name1", AnnotationSelector: "bar=baz"},
+					{LabelSelector: "app=name3", AnnotationSelector: "bar=baz"},
+				},
+			},
+			count: 1,
+		},
 	}
 	for _, testcase := range testcases {
 		actual, err := rm.Select(testcase.target)
@@ -176,3 +206,16 @@ func TestFindPatchTargets(t *testing.T) {
 	}
 
 }
+
+func TestSelectInvalidPattern(t *testing.T) {
+	rm := setupRMForPatchTargets(t)
+	for _, target := range []types.Selector{
+		{Name: "name("},
+		{Namespace: "[ns"},
+		{AnyOf: []types.Selector{{Name: "name1"}, {Name: "name("}}},
+	} {
+		if _, err := rm.Select(target); err == nil {
+			t.Errorf("expected an error selecting %v", target)
+		}
+	}
+}
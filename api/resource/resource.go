@@ -25,6 +25,7 @@ type Resource struct {
 	refVarNames  []string
 	namePrefixes []string
 	nameSuffixes []string
+	origin       string
 }
 
 // ResCtx is an interface describing the contextual added
@@ -70,6 +71,7 @@ func (r *Resource) copyOtherFields(other *Resource) {
 	r.refVarNames = copyStringSlice(other.refVarNames)
 	r.namePrefixes = copyStringSlice(other.namePrefixes)
 	r.nameSuffixes = copyStringSlice(other.nameSuffixes)
+	r.origin = other.origin
 }
 
 func (r *Resource) Equals(o *Resource) bool {
@@ -223,6 +225,18 @@ func (r *Resource) setOriginalNs(n string) *Resource {
 	return r
 }
 
+// GetOrigin returns the path to the file the resource was
+// read from, or the empty string if it was generated.
+func (r *Resource) GetOrigin() string {
+	return r.origin
+}
+
+// SetOrigin records the path to the file the resource was
+// read from.
+func (r *Resource) SetOrigin(path string) {
+	r.origin = path
+}
+
 // String returns resource as JSON.
 func (r *Resource) String() string {
 	bs, err := r.MarshalJSON()
@@ -24,4 +24,9 @@ type GeneratorArgs struct {
 
 	// Local overrides to global generatorOptions field.
 	Options *GeneratorOptions `json:"options,omitempty" yaml:"options,omitempty"`
+
+	// AppliesWhen, if set, skips the generator unless the
+	// condition holds -- e.g. to only generate the resources
+	// of a feature which is enabled by a setter.
+	AppliesWhen *SetterCondition `json:"appliesWhen,omitempty" yaml:"appliesWhen,omitempty"`
 }
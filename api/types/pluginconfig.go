@@ -29,4 +29,7 @@ type PluginConfig struct {
 
 	// BpLoadingOptions distinguishes builtin plugin behaviors.
 	BpLoadingOptions BuiltinPluginLoadingOptions
+
+	// ResourceLimits bounds the resources used by exec plugins.
+	ResourceLimits PluginResourceLimits
 }
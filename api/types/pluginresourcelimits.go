@@ -0,0 +1,81 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PluginResourceLimits bounds the resources used by each run
+// of an exec plugin, so that a runaway plugin can't exhaust
+// the host.  Empty values are unlimited.
+type PluginResourceLimits struct {
+	// Memory is the maximum memory of the plugin, as a number
+	// of bytes with an optional k, m or g suffix -- e.g. 512m.
+	// The plugin is killed if it exceeds it.
+	Memory string
+
+	// CPU is the number of CPUs the plugin may use -- e.g. 0.5.
+	CPU string
+}
+
+var memoryPattern = regexp.MustCompile(`^([0-9]+)([bkmg]?)$`)
+
+var memoryUnits = map[string]int64{
+	"":  1,
+	"b": 1,
+	"k": 1 << 10,
+	"m": 1 << 20,
+	"g": 1 << 30,
+}
+
+// IsSet returns true if any of the limits is set.
+func (l PluginResourceLimits) IsSet() bool {
+	return l.Memory != "" || l.CPU != ""
+}
+
+// Validate returns an error if a limit is malformed.
+func (l PluginResourceLimits) Validate() error {
+	if _, err := l.MemoryBytes(); err != nil {
+		return err
+	}
+	_, err := l.CPUs()
+	return err
+}
+
+// MemoryBytes returns the memory limit in bytes, or 0 if
+// memory is unlimited.
+func (l PluginResourceLimits) MemoryBytes() (int64, error) {
+	if l.Memory == "" {
+		return 0, nil
+	}
+	m := memoryPattern.FindStringSubmatch(strings.ToLower(l.Memory))
+	if m == nil {
+		return 0, fmt.Errorf(
+			"invalid memory limit %q; must be a number of bytes "+
+				"with an optional k, m or g suffix", l.Memory)
+	}
+	n, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil || n == 0 {
+		return 0, fmt.Errorf(
+			"invalid memory limit %q; must be greater than zero", l.Memory)
+	}
+	return n * memoryUnits[m[2]], nil
+}
+
+// CPUs returns the CPU limit, or 0 if CPU is unlimited.
+func (l PluginResourceLimits) CPUs() (float64, error) {
+	if l.CPU == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseFloat(l.CPU, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf(
+			"invalid cpu limit %q; must be a number of CPUs greater than zero", l.CPU)
+	}
+	return n, nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types_test
+
+import (
+	"testing"
+
+	. "sigs.k8s.io/kustomize/api/types"
+)
+
+func TestPluginResourceLimits_MemoryBytes(t *testing.T) {
+	tests := []struct {
+		memory   string
+		expected int64
+		err      bool
+	}{
+		{memory: "", expected: 0},
+		{memory: "1024", expected: 1024},
+		{memory: "512m", expected: 512 << 20},
+		{memory: "2G", expected: 2 << 30},
+		{memory: "0", err: true},
+		{memory: "-1m", err: true},
+		{memory: "lots", err: true},
+	}
+	for _, test := range tests {
+		actual, err := PluginResourceLimits{Memory: test.memory}.MemoryBytes()
+		if test.err {
+			if err == nil {
+				t.Fatalf("expected error for %q", test.memory)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", test.memory, err)
+		}
+		if actual != test.expected {
+			t.Fatalf("expected %d for %q, got %d", test.expected, test.memory, actual)
+		}
+	}
+}
+
+func TestPluginResourceLimits_CPUs(t *testing.T) {
+	tests := []struct {
+		cpu      string
+		expected float64
+		err      bool
+	}{
+		{cpu: "", expected: 0},
+		{cpu: "0.5", expected: 0.5},
+		{cpu: "2", expected: 2},
+		{cpu: "0", err: true},
+		{cpu: "half", err: true},
+	}
+	for _, test := range tests {
+		actual, err := PluginResourceLimits{CPU: test.cpu}.CPUs()
+		if test.err {
+			if err == nil {
+				t.Fatalf("expected error for %q", test.cpu)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", test.cpu, err)
+		}
+		if actual != test.expected {
+			t.Fatalf("expected %g for %q, got %g", test.expected, test.cpu, actual)
+		}
+	}
+}
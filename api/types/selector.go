@@ -9,7 +9,9 @@ import (
 
 // Selector specifies a set of resources.
 // Any resource that matches intersection of all conditions
-// is included in this set.
+// is included in this set.  If AnyOf is not empty, the
+// resource must additionally match at least one of the
+// selectors it holds.
 type Selector struct {
 	resid.Gvk `json:",inline,omitempty" yaml:",inline,omitempty"`
 	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
@@ -24,4 +26,10 @@ type Selector struct {
 	// https://kubernetes.io/docs/concepts/overview/working-with-objects/labels/#api
 	// It matches with the resource labels.
 	LabelSelector string `json:"labelSelector,omitempty" yaml:"labelSelector,omitempty"`
+
+	// AnyOf is a list of selectors combined with OR, e.g. to
+	// select the resources matching either of two disjoint
+	// sets of labels.  Each selector in the list still
+	// matches the intersection of its own conditions.
+	AnyOf []Selector `json:"anyOf,omitempty" yaml:"anyOf,omitempty"`
 }
@@ -0,0 +1,16 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// SetterCondition holds when an OpenAPI setter has a given value.
+// The setters are those defined in the Krmfile next to the
+// kustomization file, as created by 'kustomize cfg create-setter'
+// and changed by 'kustomize cfg set'.
+type SetterCondition struct {
+	// Setter is the name of the setter.
+	Setter string `json:"setter,omitempty" yaml:"setter,omitempty"`
+
+	// Value is the value the setter must have for the condition to hold.
+	Value string `json:"value" yaml:"value"`
+}
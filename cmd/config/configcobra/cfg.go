@@ -14,19 +14,37 @@ func GetCfg(name string) *cobra.Command {
 		Short: "Commands for reading and writing configuration.",
 	}
 
+	cmd.AddCommand(commands.AddConstraintCommand(name))
 	cmd.AddCommand(commands.AnnotateCommand(name))
 	cmd.AddCommand(commands.CatCommand(name))
+	cmd.AddCommand(commands.CheckSettersCommand(name))
+	cmd.AddCommand(commands.CopySetterCommand(name))
 	cmd.AddCommand(commands.CountCommand(name))
 	cmd.AddCommand(commands.CreateSetterCommand(name))
 	cmd.AddCommand(commands.CreateSubstitutionCommand(name))
+	cmd.AddCommand(commands.DeleteSetterCommand(name))
+	cmd.AddCommand(commands.ExportSettersCommand(name))
+	cmd.AddCommand(commands.FixSubstitutionsCommand(name))
 	cmd.AddCommand(commands.FmtCommand(name))
 	cmd.AddCommand(commands.GrepCommand(name))
+	cmd.AddCommand(commands.ImportSettersCommand(name))
 	cmd.AddCommand(commands.InitCommand(name))
+	cmd.AddCommand(commands.ListSetterRefsCommand(name))
 	cmd.AddCommand(commands.ListSettersCommand(name))
 	cmd.AddCommand(commands.MergeCommand(name))
 	cmd.AddCommand(commands.Merge3Command(name))
+	cmd.AddCommand(commands.PromoteSetterCommand(name))
+	cmd.AddCommand(commands.PruneCommand(name))
+	cmd.AddCommand(commands.ReleaseCheckCommand(name))
+	cmd.AddCommand(commands.RenameResourcesCommand(name))
+	cmd.AddCommand(commands.RenameSetterCommand(name))
 	cmd.AddCommand(commands.SetCommand(name))
+	cmd.AddCommand(commands.SetImpactCommand(name))
+	cmd.AddCommand(commands.SettersStatusCommand(name))
+	cmd.AddCommand(commands.SplitCommand(name))
 	cmd.AddCommand(commands.TreeCommand(name))
+	cmd.AddCommand(commands.UnusedSettersCommand(name))
+	cmd.AddCommand(commands.ValuesSchemaCommand(name))
 
 	return cmd
 }
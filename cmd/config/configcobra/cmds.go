@@ -14,22 +14,33 @@ import (
 
 // Export commands publicly for composition
 var (
+	AddConstraint      = commands.AddConstraintCommand
 	Annotate           = commands.AnnotateCommand
 	Cat                = commands.CatCommand
+	CheckSetters       = commands.CheckSettersCommand
 	Count              = commands.CountCommand
 	CreateSetter       = commands.CreateSetterCommand
 	CreateSubstitution = commands.CreateSubstitutionCommand
+	ExportSetters      = commands.ExportSettersCommand
+	FixSubstitutions   = commands.FixSubstitutionsCommand
 	Fmt                = commands.FmtCommand
 	Grep               = commands.GrepCommand
+	ImportSetters      = commands.ImportSettersCommand
 	Init               = commands.InitCommand
 	ListSetters        = commands.ListSettersCommand
 	Merge              = commands.MergeCommand
 	Merge3             = commands.Merge3Command
+	PromoteSetter      = commands.PromoteSetterCommand
+	RenameResources    = commands.RenameResourcesCommand
 	RunFn              = commands.RunCommand
 	Set                = commands.SetCommand
+	SetImpact          = commands.SetImpactCommand
 	Sink               = commands.SinkCommand
+	Split              = commands.SplitCommand
 	Source             = commands.SourceCommand
 	Tree               = commands.TreeCommand
+	UnusedSetters      = commands.UnusedSettersCommand
+	ValuesSchema       = commands.ValuesSchemaCommand
 	Wrap               = commands.WrapCommand
 	XArgs              = commands.XArgsCommand
 
@@ -4,11 +4,77 @@
 package ext
 
 import (
-	"path/filepath"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/krmfile"
 )
 
 // GetOpenAPIFile returns the path to the file containing supplementary OpenAPI definitions.
+// Defaults to the Krmfile of the package, or the file named by its
+// config.kubernetes.io/openapi-file annotation, so each subpackage may name its own.
 // Maybe be overridden to configure which file to read OpenAPI definitions from.
 var GetOpenAPIFile = func(args []string) (string, error) {
-	return filepath.Join(args[0], "Krmfile"), nil
+	return krmfile.OpenAPIFile(args[0])
+}
+
+// GetDefaultSetBy returns who set a setter value when it isn't specified with --set-by.
+// Defaults to the git user.email, if configured, or else the current OS username,
+// or "" if neither is known -- e.g. for a container user without a passwd entry.
+// Maybe be overridden to record a different identity -- e.g. a service identity.
+var GetDefaultSetBy = func() (string, error) {
+	if email, err := exec.Command("git", "config", "user.email").Output(); err == nil {
+		if email := strings.TrimSpace(string(email)); email != "" {
+			return email, nil
+		}
+	}
+	u, err := user.Current()
+	if err != nil {
+		// the default only saves passing --set-by, so it is best-effort
+		return "", nil
+	}
+	return u.Username, nil
+}
+
+// ValueFetcher fetches setter values from an external store -- e.g. a secret
+// manager -- so that they needn't be passed on the command line.
+type ValueFetcher interface {
+	// Fetch returns the value referenced by ref -- e.g. secret/data/db#password.
+	// Implementations must not include the value in returned errors.
+	Fetch(ref string) (string, error)
+}
+
+// ValueFetchers are the backends `cfg set` may fetch setter values from, keyed
+// by the name of their flag -- e.g. vault for --from-vault.  May be extended to
+// register additional backends, or overridden to replace the stub backends.
+var ValueFetchers = map[string]ValueFetcher{
+	"env":   EnvFetcher{},
+	"vault": UnregisteredFetcher{Backend: "vault"},
+}
+
+// EnvFetcher fetches values from the environment variable named by ref.
+type EnvFetcher struct{}
+
+// Fetch returns the value of the environment variable ref.
+func (EnvFetcher) Fetch(ref string) (string, error) {
+	value, found := os.LookupEnv(ref)
+	if !found {
+		return "", fmt.Errorf("environment variable %s is not set", ref)
+	}
+	return value, nil
+}
+
+// UnregisteredFetcher is a stub for a backend which hasn't been registered.
+type UnregisteredFetcher struct {
+	Backend string
+}
+
+// Fetch returns an error.
+func (f UnregisteredFetcher) Fetch(ref string) (string, error) {
+	return "", fmt.Errorf(
+		"unable to fetch %s: no %s backend is registered in ext.ValueFetchers",
+		ref, f.Backend)
 }
@@ -49,6 +49,12 @@ func GetCatRunner(name string) *CatRunner {
 		"if true, exclude non-local-config in the output.")
 	c.Flags().StringVar(&r.OutputDest, "dest", "",
 		"if specified, write output to a file rather than stdout")
+	c.Flags().StringVar(&r.Kind, "kind", "",
+		"if specified, only print resources of this kind.")
+	c.Flags().StringVar(&r.Name, "name", "",
+		"if specified, only print resources with this name.")
+	c.Flags().StringVar(&r.Namespace, "namespace", "",
+		"if specified, only print resources in this namespace.")
 	r.Command = c
 	return r
 }
@@ -70,6 +76,9 @@ type CatRunner struct {
 	StripComments      bool
 	IncludeLocal       bool
 	ExcludeNonLocal    bool
+	Kind               string
+	Name               string
+	Namespace          string
 	Command            *cobra.Command
 }
 
@@ -104,6 +113,7 @@ func (r *CatRunner) runE(c *cobra.Command, args []string) error {
 		IncludeLocalConfig:    r.IncludeLocal,
 		ExcludeNonLocalConfig: r.ExcludeNonLocal,
 	})
+	fltr = append(fltr, resourceSelectors(r.Kind, r.Name, r.Namespace)...)
 	if r.Format {
 		fltr = append(fltr, filters.FormatFilter{})
 	}
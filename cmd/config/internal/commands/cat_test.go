@@ -540,3 +540,98 @@ spec:
 		return
 	}
 }
+
+func TestCmd_selectKind(t *testing.T) {
+	d, err := ioutil.TempDir("", "kustomize-cat-test")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(d)
+
+	err = ioutil.WriteFile(filepath.Join(d, "f1.yaml"), []byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo
+  namespace: default
+spec:
+  replicas: 1 # keep this comment
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: foo
+  namespace: default
+spec:
+  selector:
+    app: nginx
+`), 0600)
+	if !assert.NoError(t, err) {
+		return
+	}
+	err = ioutil.WriteFile(filepath.Join(d, "f2.yaml"), []byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: foo
+  namespace: default
+data:
+  a: b
+---
+# the bar deployment
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: bar
+  namespace: other
+spec:
+  replicas: 3
+`), 0600)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	b := &bytes.Buffer{}
+	r := commands.GetCatRunner("")
+	r.Command.SetArgs([]string{d, "--kind", "Deployment"})
+	r.Command.SetOut(b)
+	if !assert.NoError(t, r.Command.Execute()) {
+		return
+	}
+	if !assert.Equal(t, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo
+  namespace: default
+spec:
+  replicas: 1 # keep this comment
+---
+# the bar deployment
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: bar
+  namespace: other
+spec:
+  replicas: 3
+`, b.String()) {
+		return
+	}
+
+	b = &bytes.Buffer{}
+	r = commands.GetCatRunner("")
+	r.Command.SetArgs([]string{d, "--name", "foo", "--namespace", "default", "--kind", "Service"})
+	r.Command.SetOut(b)
+	if !assert.NoError(t, r.Command.Execute()) {
+		return
+	}
+	assert.Equal(t, `apiVersion: v1
+kind: Service
+metadata:
+  name: foo
+  namespace: default
+spec:
+  selector:
+    app: nginx
+`, b.String())
+}
@@ -0,0 +1,83 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/cmd/config/ext"
+	"sigs.k8s.io/kustomize/cmd/config/internal/generateddocs/commands"
+	"sigs.k8s.io/kustomize/kyaml/setters2"
+)
+
+// NewAddConstraintRunner returns a command runner.
+func NewAddConstraintRunner(parent string) *AddConstraintRunner {
+	r := &AddConstraintRunner{}
+	c := &cobra.Command{
+		Use:     "add-constraint DIR EXPRESSION",
+		Args:    cobra.ExactArgs(2),
+		Short:   commands.AddConstraintShort,
+		Long:    commands.AddConstraintLong,
+		Example: commands.AddConstraintExamples,
+		PreRunE: r.preRunE,
+		RunE:    r.runE,
+	}
+	fixDocs(parent, c)
+	c.Flags().StringVar(&r.Constraint.Name, "name", "",
+		"name of the constraint.  defaults to a name derived from the expression -- "+
+			"e.g. min-replicas-le-max-replicas.")
+	r.Command = c
+	return r
+}
+
+func AddConstraintCommand(parent string) *cobra.Command {
+	return NewAddConstraintRunner(parent).Command
+}
+
+type AddConstraintRunner struct {
+	Command    *cobra.Command
+	Constraint setters2.ConstraintDefinition
+}
+
+// operatorNames are used to derive constraint names from their expressions.
+var operatorNames = map[string]string{
+	"<":  "lt",
+	"<=": "le",
+	">":  "gt",
+	">=": "ge",
+	"==": "eq",
+	"!=": "ne",
+}
+
+func (r *AddConstraintRunner) preRunE(c *cobra.Command, args []string) error {
+	constraint, err := setters2.ParseConstraint(args[1])
+	if err != nil {
+		return err
+	}
+	r.Constraint.Expression = constraint.String()
+	if r.Constraint.Name == "" {
+		r.Constraint.Name = fmt.Sprintf("%s-%s-%s",
+			constraint.Left, operatorNames[constraint.Operator], constraint.Right)
+	}
+	return nil
+}
+
+func (r *AddConstraintRunner) runE(c *cobra.Command, args []string) error {
+	openAPIFile, err := ext.GetOpenAPIFile(args)
+	if err != nil {
+		return handleError(c, err)
+	}
+	if err := r.Constraint.AddToFile(openAPIFile); err != nil {
+		return handleError(c, err)
+	}
+	// report, rather than reject, constraints which the current values violate
+	// so that they may be fixed with set
+	if _, err := setters2.CheckConstraints(openAPIFile, nil); err != nil {
+		fmt.Fprintf(c.ErrOrStderr(), "warning: %v\n", err)
+	}
+	fmt.Fprintf(c.OutOrStdout(), "added constraint %s: %s\n",
+		r.Constraint.Name, r.Constraint.Expression)
+	return nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/cmd/config/ext"
+	"sigs.k8s.io/kustomize/cmd/config/internal/commands"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+)
+
+func TestAddConstraintCommand(t *testing.T) {
+	// reset the openAPI afterward
+	openapi.ResetOpenAPI()
+	defer openapi.ResetOpenAPI()
+
+	d, err := ioutil.TempDir("", "kustomize-constraint-test")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(d)
+
+	openAPIFile := filepath.Join(d, "Krmfile")
+	err = ioutil.WriteFile(openAPIFile, []byte(`apiVersion: v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.min-replicas:
+      x-k8s-cli:
+        setter:
+          name: min-replicas
+          value: "1"
+    io.k8s.cli.setters.max-replicas:
+      x-k8s-cli:
+        setter:
+          name: max-replicas
+          value: "3"
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	old := ext.GetOpenAPIFile
+	defer func() { ext.GetOpenAPIFile = old }()
+	ext.GetOpenAPIFile = func(args []string) (s string, err error) {
+		return openAPIFile, nil
+	}
+
+	err = ioutil.WriteFile(filepath.Join(d, "hpa.yaml"), []byte(`apiVersion: autoscaling/v2beta2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: app
+spec:
+  minReplicas: 1 # {"$openapi":"min-replicas"}
+  maxReplicas: 3 # {"$openapi":"max-replicas"}
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	// add the constraint
+	add := commands.NewAddConstraintRunner("")
+	out := &bytes.Buffer{}
+	add.Command.SetOut(out)
+	add.Command.SetArgs([]string{d, "min-replicas<=max-replicas"})
+	if !assert.NoError(t, add.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t,
+		"added constraint min-replicas-le-max-replicas: min-replicas <= max-replicas\n",
+		out.String())
+
+	actualOpenAPI, err := ioutil.ReadFile(openAPIFile)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `apiVersion: v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.min-replicas:
+      x-k8s-cli:
+        setter:
+          name: min-replicas
+          value: "1"
+    io.k8s.cli.setters.max-replicas:
+      x-k8s-cli:
+        setter:
+          name: max-replicas
+          value: "3"
+    io.k8s.cli.constraints.min-replicas-le-max-replicas:
+      x-k8s-cli:
+        constraint:
+          name: min-replicas-le-max-replicas
+          expression: min-replicas <= max-replicas
+`, string(actualOpenAPI))
+
+	// a set satisfying the constraint succeeds
+	set := commands.NewSetRunner("")
+	out = &bytes.Buffer{}
+	set.Command.SetOut(out)
+	set.Command.SetArgs([]string{d, "min-replicas", "2", "--no-set-by"})
+	if !assert.NoError(t, set.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, "set 1 fields\n", out.String())
+
+	// a set violating the constraint is rejected
+	set = commands.NewSetRunner("")
+	set.Command.SetOut(&bytes.Buffer{})
+	set.Command.SetErr(&bytes.Buffer{})
+	set.Command.SetArgs([]string{d, "max-replicas", "1", "--no-set-by"})
+	err = set.Command.Execute()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(),
+			"setter values violate constraints: min-replicas <= max-replicas")
+	}
+
+	actualResources, err := ioutil.ReadFile(filepath.Join(d, "hpa.yaml"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `apiVersion: autoscaling/v2beta2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: app
+spec:
+  minReplicas: 2 # {"$openapi":"min-replicas"}
+  maxReplicas: 3 # {"$openapi":"max-replicas"}
+`, string(actualResources))
+
+	// the current values satisfy the constraint
+	check := commands.NewCheckSettersRunner("")
+	out = &bytes.Buffer{}
+	check.Command.SetOut(out)
+	check.Command.SetArgs([]string{d})
+	if !assert.NoError(t, check.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, "1 constraints satisfied\n", out.String())
+}
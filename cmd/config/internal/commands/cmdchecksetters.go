@@ -0,0 +1,84 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/cmd/config/ext"
+	"sigs.k8s.io/kustomize/cmd/config/internal/generateddocs/commands"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/setters2"
+)
+
+// NewCheckSettersRunner returns a command runner.
+func NewCheckSettersRunner(parent string) *CheckSettersRunner {
+	r := &CheckSettersRunner{}
+	c := &cobra.Command{
+		Use:     "check-setters DIR",
+		Args:    cobra.ExactArgs(1),
+		Short:   commands.CheckSettersShort,
+		Long:    commands.CheckSettersLong,
+		Example: commands.CheckSettersExamples,
+		RunE:    r.runE,
+	}
+	fixDocs(parent, c)
+	r.Command = c
+	return r
+}
+
+func CheckSettersCommand(parent string) *cobra.Command {
+	return NewCheckSettersRunner(parent).Command
+}
+
+type CheckSettersRunner struct {
+	Command *cobra.Command
+}
+
+func (r *CheckSettersRunner) runE(c *cobra.Command, args []string) error {
+	openAPIFile, err := ext.GetOpenAPIFile(args)
+	if err != nil {
+		return handleError(c, err)
+	}
+	if err := checkRequiredSetters(c, openAPIFile, args[0]); err != nil {
+		return handleError(c, err)
+	}
+	count, err := setters2.CheckConstraints(openAPIFile, nil)
+	if err != nil {
+		return handleError(c, err)
+	}
+	fmt.Fprintf(c.OutOrStdout(), "%d constraints satisfied\n", count)
+	return nil
+}
+
+// checkRequiredSetters prints each required setter which hasn't been set --
+// whose value is empty or still its default placeholder -- and returns an
+// error if there are any.
+func checkRequiredSetters(c *cobra.Command, openAPIFile, resourcesPath string) error {
+	l := setters2.List{}
+	if err := l.ListSetters(openAPIFile, resourcesPath); err != nil {
+		return err
+	}
+	var unset int
+	for _, s := range l.Setters {
+		if !s.Required {
+			continue
+		}
+		switch unfilledReason(s) {
+		case reasonNoValue:
+			fmt.Fprintf(c.OutOrStdout(), "required setter %s has no value\n", s.Name)
+		case reasonPlaceholder:
+			fmt.Fprintf(c.OutOrStdout(),
+				"required setter %s has its placeholder value %q\n", s.Name, s.Value)
+		default:
+			continue
+		}
+		unset++
+	}
+	if unset > 0 {
+		return errors.Errorf("%d required setters must be set", unset)
+	}
+	return nil
+}
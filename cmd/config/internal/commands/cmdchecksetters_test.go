@@ -0,0 +1,100 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/cmd/config/internal/commands"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+)
+
+func TestCheckSettersCommand_required(t *testing.T) {
+	// reset the openAPI afterward
+	openapi.ResetOpenAPI()
+	defer openapi.ResetOpenAPI()
+
+	d, err := ioutil.TempDir("", "kustomize-check-setters-test")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(d)
+
+	err = ioutil.WriteFile(filepath.Join(d, "Krmfile"), []byte(`apiVersion: v1alpha1
+kind: Krmfile
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	err = ioutil.WriteFile(filepath.Join(d, "deploy.yaml"), []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: NAMESPACE
+spec:
+  replicas: 3
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	create := commands.NewCreateSetterRunner("")
+	create.Command.SetOut(&bytes.Buffer{})
+	create.Command.SetArgs([]string{d, "namespace", "NAMESPACE", "--required", "--no-set-by"})
+	if !assert.NoError(t, create.Command.Execute()) {
+		t.FailNow()
+	}
+	actualOpenAPI, err := ioutil.ReadFile(filepath.Join(d, "Krmfile"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `apiVersion: v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.namespace:
+      x-k8s-cli:
+        setter:
+          name: namespace
+          value: NAMESPACE
+          required: true
+          default: NAMESPACE
+`, string(actualOpenAPI))
+
+	// the placeholder value fails the check
+	check := commands.NewCheckSettersRunner("")
+	out := &bytes.Buffer{}
+	check.Command.SetOut(out)
+	check.Command.SilenceUsage = true
+	check.Command.SilenceErrors = true
+	check.Command.SetArgs([]string{d})
+	err = check.Command.Execute()
+	if assert.Error(t, err) {
+		assert.Equal(t, "1 required setters must be set", err.Error())
+	}
+	assert.Equal(t, "required setter namespace has its placeholder value \"NAMESPACE\"\n",
+		out.String())
+
+	set := commands.NewSetRunner("")
+	set.Command.SetOut(&bytes.Buffer{})
+	set.Command.SetArgs([]string{d, "namespace", "prod", "--no-set-by"})
+	if !assert.NoError(t, set.Command.Execute()) {
+		t.FailNow()
+	}
+
+	// once set, the check passes
+	check = commands.NewCheckSettersRunner("")
+	out = &bytes.Buffer{}
+	check.Command.SetOut(out)
+	check.Command.SetArgs([]string{d})
+	if !assert.NoError(t, check.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, "0 constraints satisfied\n", out.String())
+}
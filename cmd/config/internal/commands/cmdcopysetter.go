@@ -0,0 +1,55 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/cmd/config/ext"
+	"sigs.k8s.io/kustomize/cmd/config/internal/generateddocs/commands"
+	"sigs.k8s.io/kustomize/kyaml/setters2"
+)
+
+// NewCopySetterRunner returns a command runner.
+func NewCopySetterRunner(parent string) *CopySetterRunner {
+	r := &CopySetterRunner{}
+	c := &cobra.Command{
+		Use:     "copy-setter DIR NAME NEW_NAME",
+		Args:    cobra.ExactArgs(3),
+		Short:   commands.CopySetterShort,
+		Long:    commands.CopySetterLong,
+		Example: commands.CopySetterExamples,
+		RunE:    r.runE,
+	}
+	fixDocs(parent, c)
+	c.Flags().StringVar(&r.Value, "value", "",
+		"value of the copy, rather than that of the setter.  validated against the schema of the setter.")
+	r.Command = c
+	return r
+}
+
+func CopySetterCommand(parent string) *cobra.Command {
+	return NewCopySetterRunner(parent).Command
+}
+
+type CopySetterRunner struct {
+	Command *cobra.Command
+
+	// Value is the value of the copy, if not that of the setter.
+	Value string
+}
+
+func (r *CopySetterRunner) runE(c *cobra.Command, args []string) error {
+	openAPIFile, err := ext.GetOpenAPIFile(args)
+	if err != nil {
+		return handleError(c, err)
+	}
+	cd := setters2.CopyDefinition{Name: args[1], NewName: args[2], Value: r.Value}
+	if err := cd.CopyInFile(openAPIFile); err != nil {
+		return handleError(c, err)
+	}
+	fmt.Fprintf(c.OutOrStdout(), "copied setter %s to %s\n", args[1], args[2])
+	return nil
+}
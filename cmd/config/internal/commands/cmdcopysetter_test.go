@@ -0,0 +1,135 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/cmd/config/internal/commands"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+)
+
+func TestCopySetterCommand(t *testing.T) {
+	krmfile := `apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      description: number of replicas
+      type: integer
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+`
+	deployment := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+`
+	var tests = []struct {
+		name     string
+		args     []string
+		out      string
+		expected string
+		err      string
+	}{
+		{
+			name: "copy",
+			args: []string{"replicas", "us-east-replicas"},
+			out:  "copied setter replicas to us-east-replicas\n",
+			expected: krmfile + `    io.k8s.cli.setters.us-east-replicas:
+      description: number of replicas
+      type: integer
+      x-k8s-cli:
+        setter:
+          name: us-east-replicas
+          value: "3"
+`,
+		},
+		{
+			name: "copy with value",
+			args: []string{"replicas", "us-east-replicas", "--value", "5"},
+			out:  "copied setter replicas to us-east-replicas\n",
+			expected: krmfile + `    io.k8s.cli.setters.us-east-replicas:
+      description: number of replicas
+      type: integer
+      x-k8s-cli:
+        setter:
+          name: us-east-replicas
+          value: "5"
+`,
+		},
+		{
+			name:     "invalid value",
+			args:     []string{"replicas", "us-east-replicas", "--value", "five"},
+			err:      "us-east-replicas in body must be of type integer",
+			expected: krmfile,
+		},
+		{
+			name:     "setter exists",
+			args:     []string{"replicas", "replicas"},
+			err:      "setter replicas already exists",
+			expected: krmfile,
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			openapi.ResetOpenAPI()
+			defer openapi.ResetOpenAPI()
+
+			d, err := ioutil.TempDir("", "kustomize-copy-setter-test")
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			defer os.RemoveAll(d)
+			files := map[string]string{"Krmfile": krmfile, "deployment.yaml": deployment}
+			for name, data := range files {
+				err := ioutil.WriteFile(filepath.Join(d, name), []byte(data), 0600)
+				if !assert.NoError(t, err) {
+					t.FailNow()
+				}
+			}
+
+			out := &bytes.Buffer{}
+			r := commands.NewCopySetterRunner("")
+			r.Command.SetOut(out)
+			r.Command.SetErr(&bytes.Buffer{})
+			r.Command.SilenceUsage = true
+			r.Command.SetArgs(append([]string{d}, test.args...))
+			err = r.Command.Execute()
+			if test.err != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), test.err)
+				}
+			} else {
+				if !assert.NoError(t, err) {
+					t.FailNow()
+				}
+				assert.Equal(t, test.out, out.String())
+			}
+
+			actual, err := ioutil.ReadFile(filepath.Join(d, "Krmfile"))
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, test.expected, string(actual))
+
+			// the fields referencing the setter are unchanged
+			actual, err = ioutil.ReadFile(filepath.Join(d, "deployment.yaml"))
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, deployment, string(actual))
+		})
+	}
+}
@@ -4,15 +4,16 @@
 package commands
 
 import (
-	"github.com/go-openapi/spec"
+	"fmt"
+	"os"
+	"regexp"
+
 	"github.com/spf13/cobra"
-	"sigs.k8s.io/kustomize/cmd/config/ext"
 	"sigs.k8s.io/kustomize/cmd/config/internal/generateddocs/commands"
 	"sigs.k8s.io/kustomize/kyaml/errors"
-	"sigs.k8s.io/kustomize/kyaml/fieldmeta"
 	"sigs.k8s.io/kustomize/kyaml/kio"
-	"sigs.k8s.io/kustomize/kyaml/openapi"
 	"sigs.k8s.io/kustomize/kyaml/setters"
+	"sigs.k8s.io/kustomize/kyaml/setters2"
 	"sigs.k8s.io/kustomize/kyaml/setters2/settersutil"
 )
 
@@ -21,7 +22,7 @@ func NewCreateSetterRunner(parent string) *CreateSetterRunner {
 	r := &CreateSetterRunner{}
 	set := &cobra.Command{
 		Use:     "create-setter DIR NAME VALUE",
-		Args:    cobra.RangeArgs(2, 3),
+		Args:    r.validateArgs,
 		Short:   commands.CreateSetterShort,
 		Long:    commands.CreateSetterLong,
 		Example: commands.CreateSetterExamples,
@@ -30,30 +31,67 @@ func NewCreateSetterRunner(parent string) *CreateSetterRunner {
 	}
 	set.Flags().StringVar(&r.Set.SetPartialField.Setter.Value, "value", "",
 		"optional flag, alternative to specifying the value as an argument. e.g. used to specify values that start with '-'")
+	set.Flags().StringVar(&r.ValueFromEnv, "value-from-env", "",
+		"name of an environment variable holding the value -- e.g. REPLICAS.  "+
+			"VALUE or --value is used if the variable is unset.")
 	set.Flags().StringVar(&r.Set.SetPartialField.SetBy, "set-by", "",
-		"record who the field was default by.")
+		"record who the field was default by.  defaults to the git user.email, or the current OS user.")
+	set.Flags().BoolVar(&r.NoSetBy, "no-set-by", false,
+		"don't record who the field was default by.")
 	set.Flags().StringVar(&r.Set.SetPartialField.Description, "description", "",
 		"record a description for the current setter value.")
 	set.Flags().StringVar(&r.Set.SetPartialField.Field, "field", "",
 		"name of the field to set -- e.g. --field port.  defaults to all fields match"+
-			"VALUE.  maybe be the field name, field path, or partial field path (suffix)")
+			"VALUE.  maybe be the field name, or the full path to the field -- e.g. spec.replicas or "+
+			"spec.containers[name=nginx].image -- which matches only the fields at that path, whatever their value.")
 	set.Flags().StringVar(&r.Set.ResourceMeta.Name, "name", "",
 		"name of the Resource on which to create the setter.")
 	set.Flags().MarkHidden("name")
-	set.Flags().StringVar(&r.Set.ResourceMeta.Kind, "kind", "",
-		"kind of the Resource on which to create the setter.")
-	set.Flags().MarkHidden("kind")
+	set.Flags().StringSliceVar(&r.CreateSetter.Kinds, "kind", nil,
+		"only reference the fields of resources of this kind -- e.g. Deployment.  may be repeated.  "+
+			"recorded in the setter definition, so that set only sets the fields of resources of the kinds.")
+	set.Flags().StringVar(&r.CreateSetter.APIVersion, "api-version", "",
+		"only reference the fields of resources with this apiVersion -- e.g. apps/v1.  "+
+			"recorded in the setter definition like --kind.")
 	set.Flags().StringVar(&r.Set.SetPartialField.Type, "type", "",
-		"OpenAPI field type for the setter -- e.g. integer,boolean,string.")
+		"OpenAPI field type for the setter -- e.g. integer,boolean,string.  defaults to the type of the matching field values.  "+
+			"percentage setters have string values of at most 100% -- e.g. 25%.")
 	set.Flags().BoolVar(&r.Set.SetPartialField.Partial, "partial", false,
-		"create a partial setter for only part of the field value.")
-	set.Flags().MarkHidden("partial")
+		"create the setter for only the part of the --field values which is VALUE -- e.g. the tag of an image -- "+
+			"and a substitution of it into the rest of their values.")
 	set.Flags().StringVar(&setterVersion, "version", "",
 		"use this version of the setter format")
 	set.Flags().StringVar(&r.CreateSetter.SchemaPath, "schema-path", "",
 		`openAPI schema file path for setter constraints -- file content `+
 			`e.g. {"type": "string", "maxLength": 15, "enum": ["allowedValue1", "allowedValue2"]}`)
+	set.Flags().StringVar(&r.CreateSetter.Pattern, "pattern", "",
+		"regular expression which values of the setter must match -- e.g. '^v[0-9]+\\.[0-9]+$'.")
+	set.Flags().StringSliceVar(&r.CreateSetter.Enum, "values", nil,
+		"comma separated values which the setter may have -- e.g. dev,staging,prod.")
+	set.Flags().BoolVar(&r.CreateSetter.UniqueItems, "unique-items", false,
+		"require the values of an array type setter to be unique.")
+	set.Flags().BoolVar(&r.CreateSetter.Unbounded, "unbounded", false,
+		"allow the values of a percentage type setter to exceed 100%.")
+	set.Flags().BoolVar(&r.CreateSetter.Required, "required", false,
+		"mark the setter as required -- its VALUE is a placeholder which users of the package must set.  see check-setters.")
+	set.Flags().BoolVar(&r.CreateSetter.Force, "force", false,
+		"update the definition of the setter if it already exists, keeping its description and set-by unless given.")
 	set.Flags().MarkHidden("version")
+	set.Flags().BoolVar(&r.InlineOpenAPI, "inline-openapi", false,
+		"read and write the setter definitions in an '# openapi:' comment block at the top of the file, rather than the Krmfile.")
+	set.Flags().StringVar(&r.OpenAPIPath, "openapi-path", "",
+		"read and write the setter definitions in this file rather than the Krmfile of DIR -- e.g. a central file shared by several packages.")
+	set.Flags().BoolVar(&r.Verbose, "verbose", false,
+		"list each file and field path which was added a reference to the setter.")
+	set.Flags().BoolVarP(&r.RecurseSubPackages, "recurse-subpackages", "R", false,
+		"also create the setter in the subpackages of DIR -- directories containing their own Krmfile -- "+
+			"each in its own definitions.")
+	set.Flags().BoolVar(&r.Stdin, "stdin", false,
+		"read the resources from stdin and write them to stdout, rather than DIR, which is omitted.  "+
+			"the setter definitions are read from and written to --openapi-path, or else a Krmfile document "+
+			"in the resources, which is added if there is none.")
+	set.Flags().BoolVar(&r.DryRun, "dry-run", false,
+		"print the unified diff of the resources and the OpenAPI file rather than changing them.")
 	fixDocs(parent, set)
 	r.Command = set
 	return r
@@ -64,17 +102,56 @@ func CreateSetterCommand(parent string) *cobra.Command {
 }
 
 type CreateSetterRunner struct {
-	Command      *cobra.Command
-	Set          setters.CreateSetter
-	CreateSetter settersutil.SetterCreator
-	OpenAPIFile  string
+	Command       *cobra.Command
+	Set           setters.CreateSetter
+	CreateSetter  settersutil.SetterCreator
+	OpenAPIFile   string
+	InlineOpenAPI bool
+
+	// OpenAPIPath is the file holding the setter definitions, if not the
+	// Krmfile of the package.
+	OpenAPIPath string
+
+	// Verbose lists the fields which were added a reference to the setter.
+	Verbose bool
+
+	// DryRun prints the diff of the files rather than changing them.
+	DryRun bool
+
+	// Stdin reads the resources from stdin and writes them to stdout.
+	Stdin bool
+
+	// RecurseSubPackages also creates the setter in the subpackages of DIR.
+	RecurseSubPackages bool
+
+	// ValueFromEnv is the name of an environment variable holding the value.
+	ValueFromEnv string
+
+	// NoSetBy doesn't default setBy to the current user.
+	NoSetBy bool
 }
 
 func (r *CreateSetterRunner) runE(c *cobra.Command, args []string) error {
 	return handleError(c, r.set(c, args))
 }
 
+// validateArgs checks the number of args, which don't include DIR with --stdin.
+func (r *CreateSetterRunner) validateArgs(c *cobra.Command, args []string) error {
+	if r.Stdin {
+		return cobra.RangeArgs(1, 2)(c, args)
+	}
+	return cobra.RangeArgs(2, 3)(c, args)
+}
+
 func (r *CreateSetterRunner) preRunE(c *cobra.Command, args []string) error {
+	if r.Stdin {
+		if r.InlineOpenAPI || r.RecurseSubPackages || r.DryRun {
+			return errors.Errorf(
+				"--stdin may not be specified with --inline-openapi, --recurse-subpackages or --dry-run")
+		}
+		// the resources are read from stdin rather than DIR
+		args = append([]string{""}, args...)
+	}
 	valueSetFromFlag := c.Flag("value").Changed
 	var err error
 	r.Set.SetPartialField.Setter.Name = args[1]
@@ -85,42 +162,58 @@ func (r *CreateSetterRunner) preRunE(c *cobra.Command, args []string) error {
 		r.Set.SetPartialField.Setter.Value = args[2]
 		r.CreateSetter.FieldValue = args[2]
 	}
+	hasValue := valueSetFromFlag || len(args) > 2
+	if r.ValueFromEnv != "" {
+		if err := r.readValueFromEnv(hasValue); err != nil {
+			return err
+		}
+		hasValue = true
+	}
 	r.CreateSetter.FieldName, err = c.Flags().GetString("field")
 	if err != nil {
 		return err
 	}
+	if len(r.CreateSetter.Kinds) == 1 {
+		// v1 setters may only be scoped to a single kind
+		r.Set.ResourceMeta.Kind = r.CreateSetter.Kinds[0]
+	}
 
+	if r.InlineOpenAPI && r.OpenAPIPath != "" {
+		return errors.Errorf("--inline-openapi and --openapi-path may not both be specified")
+	}
+	if err := r.defaultSetBy(c); err != nil {
+		return err
+	}
+	if r.RecurseSubPackages {
+		if err := r.preRunPackages(args); err != nil {
+			return err
+		}
+	}
+	if r.InlineOpenAPI || r.OpenAPIPath != "" || r.Stdin {
+		setterVersion = "v2"
+	}
 	if setterVersion == "" {
 		if len(args) == 2 && r.Set.SetPartialField.Type == "array" && c.Flag("field").Changed {
 			setterVersion = "v2"
-		} else if len(args) < 2 || !c.Flag("value").Changed && len(args) < 3 {
+		} else if len(args) < 2 || !hasValue {
 			setterVersion = "v1"
 		} else if err := initSetterVersion(c, args); err != nil {
 			return err
 		}
 	}
+	if r.DryRun && setterVersion != "v2" {
+		return errors.Errorf("--dry-run is not supported for v1 setters")
+	}
+	if (len(r.CreateSetter.Kinds) > 1 || r.CreateSetter.APIVersion != "") && setterVersion != "v2" {
+		return errors.Errorf("--api-version and more than one --kind are not supported for v1 setters")
+	}
 	if setterVersion == "v2" {
-		var err error
-		r.OpenAPIFile, err = ext.GetOpenAPIFile(args)
-		if err != nil {
-			return err
-		}
-
-		if err := openapi.AddSchemaFromFile(r.OpenAPIFile); err != nil {
-			return err
-		}
-
-		// check if substitution with same name exists and throw error
-		ref, err := spec.NewRef(fieldmeta.DefinitionsPrefix + fieldmeta.SubstitutionDefinitionPrefix + r.CreateSetter.Name)
-		if err != nil {
-			return err
-		}
-
-		subst, _ := openapi.Resolve(&ref)
-		// if substitution already exists with the input setter name, throw error
-		if subst != nil {
-			return errors.Errorf("substitution with name %s already exists, "+
-				"substitution and setter can't have same name", r.CreateSetter.Name)
+		if !r.Stdin {
+			var err error
+			r.OpenAPIFile, err = getOpenAPIFile(args, r.InlineOpenAPI, r.OpenAPIPath)
+			if err != nil {
+				return err
+			}
 		}
 
 		r.CreateSetter.Description = r.Set.SetPartialField.Description
@@ -132,13 +225,135 @@ func (r *CreateSetterRunner) preRunE(c *cobra.Command, args []string) error {
 				return errors.Errorf("field flag must be set for array type setters")
 			}
 		}
+		if err := r.validatePartial(c); err != nil {
+			return err
+		}
+		if err := r.validatePattern(); err != nil {
+			return err
+		}
+		if r.CreateSetter.Type == "array" && len(r.CreateSetter.Enum) > 0 {
+			return errors.Errorf("--values is not supported for array type setters")
+		}
+		if r.CreateSetter.Type != "array" && r.CreateSetter.UniqueItems {
+			return errors.Errorf("--unique-items is only supported for array type setters")
+		}
+		if err := r.validatePercentage(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// defaultSetBy records the current user as who set the setter, unless
+// --set-by or --no-set-by is specified.  An existing setter updated with
+// --force keeps its set-by.
+func (r *CreateSetterRunner) defaultSetBy(c *cobra.Command) error {
+	if c.Flag("set-by").Changed && r.NoSetBy {
+		return errors.Errorf("--set-by and --no-set-by may not both be specified")
+	}
+	if c.Flag("set-by").Changed || r.NoSetBy || r.CreateSetter.Force {
+		return nil
+	}
+	r.Set.SetPartialField.SetBy = lookupSetBy()
+	return nil
+}
+
+// validatePercentage checks the value of a percentage setter is a percentage,
+// of at most 100% unless --unbounded is set.
+func (r *CreateSetterRunner) validatePercentage() error {
+	if r.CreateSetter.Type != setters2.PercentageType {
+		if r.CreateSetter.Unbounded {
+			return errors.Errorf("--unbounded is only supported for percentage type setters")
+		}
+		return nil
+	}
+	var max *float64
+	if !r.CreateSetter.Unbounded {
+		m := float64(100)
+		max = &m
+	}
+	return setters2.ValidatePercentage(r.CreateSetter.Name, r.CreateSetter.FieldValue, max)
+}
+
+// readValueFromEnv sets the value of the setter to the value of the
+// --value-from-env environment variable.  If the variable is unset, the value
+// is left as given by VALUE or --value, which hasValue reports.
+func (r *CreateSetterRunner) readValueFromEnv(hasValue bool) error {
+	value, found := os.LookupEnv(r.ValueFromEnv)
+	if !found {
+		if !hasValue {
+			return errors.Errorf("environment variable %s is unset, and no VALUE was given", r.ValueFromEnv)
+		}
+		return nil
+	}
+	r.Set.SetPartialField.Setter.Value = value
+	r.CreateSetter.FieldValue = value
+	return nil
+}
+
+// validatePartial checks a partial setter is created for the part of the
+// values of --field, which aren't arrays.
+func (r *CreateSetterRunner) validatePartial(c *cobra.Command) error {
+	r.CreateSetter.Partial = r.Set.SetPartialField.Partial
+	if !r.CreateSetter.Partial {
+		return nil
+	}
+	if !c.Flag("field").Changed {
+		return errors.Errorf("--partial requires --field")
+	}
+	if r.CreateSetter.Type == "array" {
+		return errors.Errorf("--partial is not supported for array type setters")
+	}
+	return nil
+}
+
+// validatePattern checks the --pattern is a valid regular expression, and that
+// the setter value matches it.
+func (r *CreateSetterRunner) validatePattern() error {
+	if r.CreateSetter.Pattern == "" {
+		return nil
+	}
+	p, err := regexp.Compile(r.CreateSetter.Pattern)
+	if err != nil {
+		return errors.WrapPrefixf(err, "invalid --pattern %s", r.CreateSetter.Pattern)
+	}
+	if r.CreateSetter.Type == "array" {
+		return errors.Errorf("--pattern is not supported for array type setters")
+	}
+	if !p.MatchString(r.CreateSetter.FieldValue) {
+		return errors.Errorf("value %q of setter %s doesn't match pattern %s",
+			r.CreateSetter.FieldValue, r.CreateSetter.Name, r.CreateSetter.Pattern)
 	}
 	return nil
 }
 
 func (r *CreateSetterRunner) set(c *cobra.Command, args []string) error {
+	if r.Stdin {
+		return r.createStdin(c)
+	}
+	if setterVersion == "v2" && r.DryRun {
+		return r.dryRun(c, args)
+	}
+	if setterVersion == "v2" && r.RecurseSubPackages {
+		return r.createPackages(c, args)
+	}
+	if setterVersion == "v2" && r.InlineOpenAPI {
+		defer os.Remove(r.OpenAPIFile)
+		if err := r.CreateSetter.Create(r.OpenAPIFile, args[0]); err != nil {
+			return err
+		}
+		if err := writeInlineOpenAPI(args[0], r.OpenAPIFile); err != nil {
+			return err
+		}
+		r.printSummary(c)
+		return nil
+	}
 	if setterVersion == "v2" {
-		return r.CreateSetter.Create(r.OpenAPIFile, args[0])
+		if err := r.CreateSetter.Create(r.OpenAPIFile, args[0]); err != nil {
+			return err
+		}
+		r.printSummary(c)
+		return nil
 	}
 
 	rw := &kio.LocalPackageReadWriter{PackagePath: args[0]}
@@ -151,3 +366,28 @@ func (r *CreateSetterRunner) set(c *cobra.Command, args []string) error {
 	}
 	return nil
 }
+
+// printSummary prints the number of fields and files which were added a
+// reference to the setter, and if Verbose is set, each of the fields.
+func (r *CreateSetterRunner) printSummary(c *cobra.Command) {
+	out := c.OutOrStdout()
+	if r.Stdin {
+		// the resources are written to stdout
+		out = c.ErrOrStderr()
+	}
+	files := map[string]bool{}
+	for _, ref := range r.CreateSetter.References {
+		files[ref.File] = true
+		if r.Verbose {
+			fmt.Fprintf(out, "%s: %s\n", ref.File, ref.Field)
+		}
+	}
+	if r.CreateSetter.Partial {
+		fmt.Fprintf(out, "setter %s: added reference to substitution %s to %d fields in %d files\n",
+			r.CreateSetter.Name, setters2.PartialSubstitutionName(r.CreateSetter.Name, r.CreateSetter.FieldName),
+			len(r.CreateSetter.References), len(files))
+		return
+	}
+	fmt.Fprintf(out, "setter %s: added reference to %d fields in %d files\n",
+		r.CreateSetter.Name, len(r.CreateSetter.References), len(files))
+}
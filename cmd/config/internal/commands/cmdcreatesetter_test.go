@@ -5,8 +5,10 @@ package commands_test
 
 import (
 	"bytes"
+	"errors"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -31,6 +33,7 @@ func TestCreateSetterCommand(t *testing.T) {
 		{
 			name: "add replicas",
 			args: []string{"replicas", "3", "--description", "hello world", "--set-by", "me"},
+			out:  "setter replicas: added reference to 1 fields in 1 files\n",
 			input: `
 apiVersion: apps/v1
 kind: Deployment
@@ -50,6 +53,7 @@ openAPI:
   definitions:
     io.k8s.cli.setters.replicas:
       description: hello world
+      type: integer
       x-k8s-cli:
         setter:
           name: replicas
@@ -90,6 +94,7 @@ openAPI:
 		{
 			name:   "add replicas with schema",
 			args:   []string{"replicas", "3", "--description", "hello world", "--set-by", "me"},
+			out:    "setter replicas: added reference to 1 fields in 1 files\n",
 			schema: `{"maximum": 10, "type": "integer"}`,
 			input: `
 apiVersion: apps/v1
@@ -128,9 +133,89 @@ spec:
  `,
 		},
 
+		{
+			name: "add enum values",
+			args: []string{"env", "dev", "--field", "metadata.labels.env", "--values", "dev,staging,prod"},
+			out:  "setter env: added reference to 1 fields in 1 files\n",
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+  labels:
+    env: dev
+ `,
+			inputOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+`,
+			expectedOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.env:
+      enum:
+      - dev
+      - staging
+      - prod
+      type: string
+      x-k8s-cli:
+        setter:
+          name: env
+          value: dev
+ `,
+			expectedResources: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+  labels:
+    env: dev # {"$openapi":"env"}
+ `,
+		},
+
+		{
+			name: "error value not in enum values",
+			args: []string{"env", "test", "--values", "dev,staging,prod"},
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+  labels:
+    env: test
+ `,
+			inputOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+`,
+			err: `value "test" of setter env isn't one of the allowed values: dev, staging, prod`,
+		},
+
+		{
+			name:   "error value exceeding schema maximum",
+			args:   []string{"replicas", "20"},
+			schema: `{"maximum": 10, "type": "integer"}`,
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 20
+ `,
+			inputOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+`,
+			err: "value 20 exceeds maximum 10 for setter replicas",
+		},
+
 		{
 			name:   "list values with schema",
 			args:   []string{"list", "--description", "hello world", "--set-by", "me", "--type", "array", "--field", "spec.list"},
+			out:    "setter list: added reference to 2 fields in 1 files\n",
 			schema: `{"maxItems": 3, "type": "array", "items": {"type": "string"}}`,
 			input: `
 apiVersion: example.com/v1beta1
@@ -223,6 +308,60 @@ spec:
  `,
 		},
 
+		{
+			name:   "list of objects",
+			args:   []string{"env", "--description", "hello world", "--set-by", "me", "--type", "array", "--field", "spec.env"},
+			out:    "setter env: added reference to 1 fields in 1 files\n",
+			schema: `{"type": "array", "items": {"type": "object", "required": ["name"]}}`,
+			input: `
+apiVersion: example.com/v1beta1
+kind: Example
+spec:
+  env:
+  - name: FOO
+    value: foo
+  - name: BAR
+    value: bar
+ `,
+			inputOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+`,
+			expectedOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.env:
+      items:
+        required:
+        - name
+        type: object
+      type: array
+      description: hello world
+      x-k8s-cli:
+        setter:
+          name: env
+          value: ""
+          listValues:
+          - name: FOO
+            value: foo
+          - name: BAR
+            value: bar
+          setBy: me
+ `,
+			expectedResources: `
+apiVersion: example.com/v1beta1
+kind: Example
+spec:
+  env: # {"$openapi":"env"}
+  - name: FOO
+    value: foo
+  - name: BAR
+    value: bar
+ `,
+		},
+
 		{
 			name:   "error list path with different values",
 			args:   []string{"list", "--description", "hello world", "--set-by", "me", "--type", "array", "--field", "spec.list"},
@@ -251,6 +390,81 @@ kind: Example
 				`array values for specified field path: [c d], [a b c]`,
 		},
 
+		{
+			name: "add list with unique items",
+			args: []string{"list", "--type", "array", "--field", "spec.list", "--unique-items"},
+			out:  "setter list: added reference to 1 fields in 1 files\n",
+			input: `
+apiVersion: example.com/v1beta1
+kind: Example
+spec:
+  list:
+  - "a"
+  - "b"
+ `,
+			inputOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+`,
+			expectedOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.list:
+      type: array
+      uniqueItems: true
+      x-k8s-cli:
+        setter:
+          name: list
+          value: ""
+          listValues:
+          - a
+          - b
+ `,
+			expectedResources: `
+apiVersion: example.com/v1beta1
+kind: Example
+spec:
+  list: # {"$openapi":"list"}
+  - "a"
+  - "b"
+ `,
+		},
+		{
+			name: "error duplicated list values with unique items",
+			args: []string{"list", "--type", "array", "--field", "spec.list", "--unique-items"},
+			input: `
+apiVersion: example.com/v1beta1
+kind: Example
+spec:
+  list:
+  - "a"
+  - "b"
+  - "a"
+ `,
+			inputOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+`,
+			err: `values of setter list must be unique, found duplicates: a`,
+		},
+		{
+			name: "error unique items of non-array setter",
+			args: []string{"replicas", "3", "--unique-items"},
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  replicas: 3
+ `,
+			inputOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+`,
+			err: `--unique-items is only supported for array type setters`,
+		},
+
 		{
 			name:   "list values error if field not set",
 			args:   []string{"list", "a", "--description", "hello world", "--set-by", "me", "--type", "array"},
@@ -300,15 +514,73 @@ spec:
 			err: `field flag must be set for array type setters`,
 		},
 		{
-			name: "add replicas with value set by flag",
-			args: []string{"replicas", "--value", "3", "--description", "hello world", "--set-by", "me"},
+			name: "add tag with pattern",
+			args: []string{"tag", "v1.7", "--pattern", `^v[0-9]+\.[0-9]+$`},
+			out:  "setter tag: added reference to 1 fields in 1 files\n",
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+  labels:
+    version: v1.7
+ `,
+			inputOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+`,
+			expectedOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.tag:
+      type: string
+      pattern: ^v[0-9]+\.[0-9]+$
+      x-k8s-cli:
+        setter:
+          name: tag
+          value: v1.7
+ `,
+			expectedResources: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+  labels:
+    version: v1.7 # {"$openapi":"tag"}
+ `,
+		},
+		{
+			name: "error if value doesn't match pattern",
+			args: []string{"tag", "latest", "--pattern", `^v[0-9]+\.[0-9]+$`},
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+  labels:
+    version: latest
+ `,
+			inputOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+`,
+			err: `value "latest" of setter tag doesn't match pattern ^v[0-9]+\.[0-9]+$`,
+		},
+		{
+			name: "add percentage",
+			args: []string{"max-surge", "25%", "--type", "percentage"},
+			out:  "setter max-surge: added reference to 1 fields in 1 files\n",
 			input: `
 apiVersion: apps/v1
 kind: Deployment
 metadata:
   name: nginx-deployment
 spec:
-  replicas: 3
+  strategy:
+    rollingUpdate:
+      maxSurge: 25%
  `,
 			inputOpenAPI: `
 apiVersion: v1alpha1
@@ -319,13 +591,14 @@ apiVersion: v1alpha1
 kind: Example
 openAPI:
   definitions:
-    io.k8s.cli.setters.replicas:
-      description: hello world
+    io.k8s.cli.setters.max-surge:
+      format: percentage
+      maximum: 100
+      type: string
       x-k8s-cli:
         setter:
-          name: replicas
-          value: "3"
-          setBy: me
+          name: max-surge
+          value: 25%
  `,
 			expectedResources: `
 apiVersion: apps/v1
@@ -333,66 +606,444 @@ kind: Deployment
 metadata:
   name: nginx-deployment
 spec:
-  replicas: 3 # {"$openapi":"replicas"}
+  strategy:
+    rollingUpdate:
+      maxSurge: 25% # {"$openapi":"max-surge"}
  `,
 		},
-	}
-	for i := range tests {
-		test := tests[i]
-		t.Run(test.name, func(t *testing.T) {
-			// reset the openAPI afterward
-			openapi.ResetOpenAPI()
-			defer openapi.ResetOpenAPI()
-
-			f, err := ioutil.TempFile("", "k8s-cli-")
-			if !assert.NoError(t, err) {
-				t.FailNow()
-			}
-			defer os.Remove(f.Name())
-
-			err = ioutil.WriteFile(f.Name(), []byte(test.inputOpenAPI), 0600)
-			if !assert.NoError(t, err) {
-				t.FailNow()
-			}
-
-			if test.schema != "" {
-				sch, err := ioutil.TempFile("", "schema.json")
-				if !assert.NoError(t, err) {
-					t.FailNow()
-				}
-				defer os.Remove(sch.Name())
-
-				err = ioutil.WriteFile(sch.Name(), []byte(test.schema), 0600)
-				if !assert.NoError(t, err) {
-					t.FailNow()
-				}
-
-				test.args = append(test.args, "--schema-path", sch.Name())
-			}
-
-			old := ext.GetOpenAPIFile
-			defer func() { ext.GetOpenAPIFile = old }()
-			ext.GetOpenAPIFile = func(args []string) (s string, err error) {
-				return f.Name(), nil
-			}
-
-			r, err := ioutil.TempFile("", "k8s-cli-*.yaml")
-			if !assert.NoError(t, err) {
-				t.FailNow()
-			}
-			defer os.Remove(r.Name())
-			err = ioutil.WriteFile(r.Name(), []byte(test.input), 0600)
-			if !assert.NoError(t, err) {
-				t.FailNow()
-			}
-
-			runner := commands.NewCreateSetterRunner("")
-			out := &bytes.Buffer{}
-			runner.Command.SetOut(out)
-			runner.Command.SetArgs(append([]string{r.Name()}, test.args...))
-			err = runner.Command.Execute()
-			if test.err != "" {
-				if !assert.NotNil(t, err) {
+		{
+			name: "add unbounded percentage",
+			args: []string{"scale", "150%", "--type", "percentage", "--unbounded"},
+			out:  "setter scale: added reference to 1 fields in 1 files\n",
+			input: `
+apiVersion: example.com/v1
+kind: Rollout
+metadata:
+  name: nginx
+spec:
+  scale: 150%
+ `,
+			inputOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+`,
+			expectedOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.scale:
+      format: percentage
+      type: string
+      x-k8s-cli:
+        setter:
+          name: scale
+          value: 150%
+ `,
+			expectedResources: `
+apiVersion: example.com/v1
+kind: Rollout
+metadata:
+  name: nginx
+spec:
+  scale: 150% # {"$openapi":"scale"}
+ `,
+		},
+		{
+			name: "error if percentage exceeds 100%",
+			args: []string{"max-surge", "110%", "--type", "percentage"},
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  strategy:
+    rollingUpdate:
+      maxSurge: 110%
+ `,
+			inputOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+`,
+			err: `value "110%" of setter max-surge exceeds 100%`,
+		},
+		{
+			name: "error if not a percentage",
+			args: []string{"max-surge", "25", "--type", "percentage"},
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  strategy:
+    rollingUpdate:
+      maxSurge: 25
+ `,
+			inputOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+`,
+			err: `value "25" of setter max-surge isn't a percentage -- e.g. 25%`,
+		},
+		{
+			name: "add replicas by full field path with another value",
+			args: []string{"replicas", "5", "--field", "spec.replicas"},
+			out:  "setter replicas: added reference to 1 fields in 1 files\n",
+			input: `
+apiVersion: example.com/v1
+kind: Example
+metadata:
+  name: example
+spec:
+  replicas: 3
+  worker:
+    replicas: 3
+ `,
+			inputOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+`,
+			expectedOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      type: integer
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "5"
+ `,
+			expectedResources: `
+apiVersion: example.com/v1
+kind: Example
+metadata:
+  name: example
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+  worker:
+    replicas: 3
+ `,
+		},
+		{
+			name: "add image by field path",
+			args: []string{"image", "nginx:1.8", "--field", "spec.template.spec.containers[name=nginx].image"},
+			out:  "setter image: added reference to 1 fields in 1 files\n",
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.7
+      - name: sidecar
+        image: nginx:1.7
+ `,
+			inputOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+`,
+			expectedOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.image:
+      type: string
+      x-k8s-cli:
+        setter:
+          name: image
+          value: nginx:1.8
+ `,
+			expectedResources: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.7 # {"$openapi":"image"}
+      - name: sidecar
+        image: nginx:1.7
+ `,
+		},
+		{
+			name: "add partial setter",
+			args: []string{"tag", "1.7.9", "--field", "image", "--partial"},
+			out:  "setter tag: added reference to substitution image-tag to 2 fields in 1 files\n",
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.7.9
+      - name: sidecar
+        image: sidecar:0.1
+      initContainers:
+      - name: init
+        image: nginx:1.7.9
+ `,
+			inputOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+`,
+			expectedOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.tag:
+      x-k8s-cli:
+        setter:
+          name: tag
+          value: 1.7.9
+    io.k8s.cli.substitutions.image-tag:
+      x-k8s-cli:
+        substitution:
+          name: image-tag
+          pattern: nginx:${tag}
+          values:
+          - marker: ${tag}
+            ref: '#/definitions/io.k8s.cli.setters.tag'
+ `,
+			expectedResources: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.7.9 # {"$openapi":"image-tag"}
+      - name: sidecar
+        image: sidecar:0.1
+      initContainers:
+      - name: init
+        image: nginx:1.7.9 # {"$openapi":"image-tag"}
+ `,
+		},
+		{
+			name: "error partial setter of fields with different values",
+			args: []string{"tag", "1.7.9", "--field", "image", "--partial"},
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.7.9
+      - name: redis
+        image: redis:1.7.9
+ `,
+			inputOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+`,
+			err: "partial setters can only be created for fields with the same value, " +
+				"encountered different values of fields image containing 1.7.9: nginx:1.7.9, redis:1.7.9",
+		},
+		{
+			name: "error partial setter without field",
+			args: []string{"tag", "1.7.9", "--partial"},
+			inputOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+`,
+			err: "--partial requires --field",
+		},
+		{
+			name: "add replicas scoped to kind and apiVersion",
+			args: []string{"replicas", "3", "--kind", "Deployment", "--kind", "ReplicaSet", "--api-version", "apps/v1"},
+			out:  "setter replicas: added reference to 1 fields in 1 files\n",
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 3
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: nginx-statefulset
+spec:
+  replicas: 3
+---
+apiVersion: extensions/v1beta1
+kind: ReplicaSet
+metadata:
+  name: nginx-replicaset
+spec:
+  replicas: 3
+ `,
+			inputOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+`,
+			expectedOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      type: integer
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+          kinds:
+          - Deployment
+          - ReplicaSet
+          apiVersion: apps/v1
+ `,
+			expectedResources: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: nginx-statefulset
+spec:
+  replicas: 3
+---
+apiVersion: extensions/v1beta1
+kind: ReplicaSet
+metadata:
+  name: nginx-replicaset
+spec:
+  replicas: 3
+ `,
+		},
+		{
+			name: "add replicas with value set by flag",
+			args: []string{"replicas", "--value", "3", "--description", "hello world", "--set-by", "me"},
+			out:  "setter replicas: added reference to 1 fields in 1 files\n",
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 3
+ `,
+			inputOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+`,
+			expectedOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      description: hello world
+      type: integer
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+          setBy: me
+ `,
+			expectedResources: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+ `,
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			// reset the openAPI afterward
+			openapi.ResetOpenAPI()
+			defer openapi.ResetOpenAPI()
+
+			f, err := ioutil.TempFile("", "k8s-cli-")
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			defer os.Remove(f.Name())
+
+			err = ioutil.WriteFile(f.Name(), []byte(test.inputOpenAPI), 0600)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+
+			if test.schema != "" {
+				sch, err := ioutil.TempFile("", "schema.json")
+				if !assert.NoError(t, err) {
+					t.FailNow()
+				}
+				defer os.Remove(sch.Name())
+
+				err = ioutil.WriteFile(sch.Name(), []byte(test.schema), 0600)
+				if !assert.NoError(t, err) {
+					t.FailNow()
+				}
+
+				test.args = append(test.args, "--schema-path", sch.Name())
+			}
+
+			old := ext.GetOpenAPIFile
+			defer func() { ext.GetOpenAPIFile = old }()
+			ext.GetOpenAPIFile = func(args []string) (s string, err error) {
+				return f.Name(), nil
+			}
+
+			// don't default setBy to the current user
+			oldSetBy := ext.GetDefaultSetBy
+			defer func() { ext.GetDefaultSetBy = oldSetBy }()
+			ext.GetDefaultSetBy = func() (string, error) {
+				return "", nil
+			}
+
+			r, err := ioutil.TempFile("", "k8s-cli-*.yaml")
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			defer os.Remove(r.Name())
+			err = ioutil.WriteFile(r.Name(), []byte(test.input), 0600)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+
+			runner := commands.NewCreateSetterRunner("")
+			out := &bytes.Buffer{}
+			runner.Command.SetOut(out)
+			runner.Command.SetArgs(append([]string{r.Name()}, test.args...))
+			err = runner.Command.Execute()
+			if test.err != "" {
+				if !assert.NotNil(t, err) {
 					t.FailNow()
 				} else {
 					assert.Equal(t, err.Error(), test.err)
@@ -403,29 +1054,856 @@ spec:
 				t.FailNow()
 			}
 
-			if !assert.Equal(t, test.out, out.String()) {
-				t.FailNow()
-			}
+			if !assert.Equal(t, test.out, out.String()) {
+				t.FailNow()
+			}
+
+			actualResources, err := ioutil.ReadFile(r.Name())
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			if !assert.Equal(t,
+				strings.TrimSpace(test.expectedResources),
+				strings.TrimSpace(string(actualResources))) {
+				t.FailNow()
+			}
+
+			actualOpenAPI, err := ioutil.ReadFile(f.Name())
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			if !assert.Equal(t,
+				strings.TrimSpace(test.expectedOpenAPI),
+				strings.TrimSpace(string(actualOpenAPI))) {
+				t.FailNow()
+			}
+		})
+	}
+}
+
+func TestCreateSetterCommand_inlineOpenAPI(t *testing.T) {
+	// reset the openAPI afterward
+	openapi.ResetOpenAPI()
+	defer openapi.ResetOpenAPI()
+
+	r, err := ioutil.TempFile("", "k8s-cli-*.yaml")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.Remove(r.Name())
+	err = ioutil.WriteFile(r.Name(), []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 3
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	// create the setter with its definition inline
+	runner := commands.NewCreateSetterRunner("")
+	runner.Command.SetOut(&bytes.Buffer{})
+	runner.Command.SetArgs([]string{r.Name(), "replicas", "3", "--inline-openapi", "--no-set-by"})
+	if !assert.NoError(t, runner.Command.Execute()) {
+		t.FailNow()
+	}
+	actual, err := ioutil.ReadFile(r.Name())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `# openapi:
+#   definitions:
+#     io.k8s.cli.setters.replicas:
+#       type: integer
+#       x-k8s-cli:
+#         setter:
+#           name: replicas
+#           value: "3"
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+`, string(actual))
+
+	// set the setter from the inline definition
+	openapi.ResetOpenAPI()
+	set := commands.NewSetRunner("")
+	out := &bytes.Buffer{}
+	set.Command.SetOut(out)
+	set.Command.SetArgs([]string{r.Name(), "replicas", "5", "--inline-openapi", "--no-set-by"})
+	if !assert.NoError(t, set.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, "set 1 fields\n", out.String())
+	actual, err = ioutil.ReadFile(r.Name())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `# openapi:
+#   definitions:
+#     io.k8s.cli.setters.replicas:
+#       type: integer
+#       x-k8s-cli:
+#         setter:
+#           name: replicas
+#           value: "5"
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 5 # {"$openapi":"replicas"}
+`, string(actual))
+}
+
+func TestCreateSetterCommand_verbose(t *testing.T) {
+	// reset the openAPI afterward
+	openapi.ResetOpenAPI()
+	defer openapi.ResetOpenAPI()
+
+	d, err := ioutil.TempDir("", "kustomize-create-setter-test")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(d)
+	files := map[string]string{
+		"Krmfile": `apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+`,
+		"deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  namespace: myspace
+spec:
+  replicas: 3
+`,
+		"service.yaml": `apiVersion: v1
+kind: Service
+metadata:
+  name: nginx
+  namespace: myspace
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: nginx
+  namespace: myspace
+data:
+  namespace: myspace
+`,
+	}
+	for name, data := range files {
+		if !assert.NoError(t, ioutil.WriteFile(filepath.Join(d, name), []byte(data), 0600)) {
+			t.FailNow()
+		}
+	}
+
+	runner := commands.NewCreateSetterRunner("")
+	out := &bytes.Buffer{}
+	runner.Command.SetOut(out)
+	runner.Command.SetArgs([]string{d, "namespace", "myspace", "--field", "metadata.namespace", "--verbose", "--no-set-by"})
+	if !assert.NoError(t, runner.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, `deployment.yaml: metadata.namespace
+service.yaml: metadata.namespace
+service.yaml: metadata.namespace
+setter namespace: added reference to 3 fields in 2 files
+`, out.String())
+}
 
-			actualResources, err := ioutil.ReadFile(r.Name())
-			if !assert.NoError(t, err) {
-				t.FailNow()
-			}
-			if !assert.Equal(t,
-				strings.TrimSpace(test.expectedResources),
-				strings.TrimSpace(string(actualResources))) {
-				t.FailNow()
-			}
+func TestCreateSetterCommand_dryRun(t *testing.T) {
+	// reset the openAPI afterward
+	openapi.ResetOpenAPI()
+	defer openapi.ResetOpenAPI()
 
-			actualOpenAPI, err := ioutil.ReadFile(f.Name())
-			if !assert.NoError(t, err) {
-				t.FailNow()
-			}
-			if !assert.Equal(t,
-				strings.TrimSpace(test.expectedOpenAPI),
-				strings.TrimSpace(string(actualOpenAPI))) {
-				t.FailNow()
-			}
-		})
+	d, err := ioutil.TempDir("", "kustomize-create-setter-test")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(d)
+	files := map[string]string{
+		"Krmfile": `apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+`,
+		"deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3
+`,
+	}
+	for name, data := range files {
+		if !assert.NoError(t, ioutil.WriteFile(filepath.Join(d, name), []byte(data), 0600)) {
+			t.FailNow()
+		}
+	}
+
+	runner := commands.NewCreateSetterRunner("")
+	out := &bytes.Buffer{}
+	runner.Command.SetOut(out)
+	runner.Command.SetArgs([]string{d, "replicas", "3", "--dry-run", "--no-set-by"})
+	if !assert.NoError(t, runner.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, `--- a/Krmfile
++++ b/Krmfile
+@@ -1,2 +1,10 @@
+ apiVersion: config.k8s.io/v1alpha1
+ kind: Krmfile
++openAPI:
++  definitions:
++    io.k8s.cli.setters.replicas:
++      type: integer
++      x-k8s-cli:
++        setter:
++          name: replicas
++          value: "3"
+--- a/deployment.yaml
++++ b/deployment.yaml
+@@ -3,4 +3,4 @@
+ metadata:
+   name: nginx
+ spec:
+-  replicas: 3
++  replicas: 3 # {"$openapi":"replicas"}
+`, out.String())
+
+	// the files are left unchanged
+	for name, data := range files {
+		actual, err := ioutil.ReadFile(filepath.Join(d, name))
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.Equal(t, data, string(actual))
+	}
+}
+
+func TestCreateSetterCommand_valueFromEnv(t *testing.T) {
+	// reset the openAPI afterward
+	openapi.ResetOpenAPI()
+	defer openapi.ResetOpenAPI()
+
+	d, err := ioutil.TempDir("", "kustomize-create-setter-test")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(d)
+	files := map[string]string{
+		"Krmfile": `apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+`,
+		"deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3
+`,
+	}
+	for name, data := range files {
+		if !assert.NoError(t, ioutil.WriteFile(filepath.Join(d, name), []byte(data), 0600)) {
+			t.FailNow()
+		}
+	}
+
+	// the variable is unset, and there is no value
+	runner := commands.NewCreateSetterRunner("")
+	runner.Command.SetOut(&bytes.Buffer{})
+	runner.Command.SetErr(&bytes.Buffer{})
+	runner.Command.SilenceUsage = true
+	runner.Command.SetArgs([]string{d, "replicas", "--value-from-env", "KUSTOMIZE_TEST_REPLICAS", "--no-set-by"})
+	err = runner.Command.Execute()
+	if assert.Error(t, err) {
+		assert.Equal(t, "environment variable KUSTOMIZE_TEST_REPLICAS is unset, and no VALUE was given", err.Error())
+	}
+
+	// the value from the variable is validated
+	os.Setenv("KUSTOMIZE_TEST_REPLICAS", "three")
+	defer os.Unsetenv("KUSTOMIZE_TEST_REPLICAS")
+	runner = commands.NewCreateSetterRunner("")
+	runner.Command.SetOut(&bytes.Buffer{})
+	runner.Command.SetErr(&bytes.Buffer{})
+	runner.Command.SilenceUsage = true
+	runner.Command.SetArgs([]string{d, "replicas", "--value-from-env", "KUSTOMIZE_TEST_REPLICAS",
+		"--pattern", "^[0-9]+$"})
+	err = runner.Command.Execute()
+	if assert.Error(t, err) {
+		assert.Equal(t, `value "three" of setter replicas doesn't match pattern ^[0-9]+$`, err.Error())
+	}
+
+	os.Setenv("KUSTOMIZE_TEST_REPLICAS", "3")
+	openapi.ResetOpenAPI()
+	runner = commands.NewCreateSetterRunner("")
+	runner.Command.SetOut(&bytes.Buffer{})
+	runner.Command.SetArgs([]string{d, "replicas", "--value-from-env", "KUSTOMIZE_TEST_REPLICAS", "--no-set-by"})
+	if !assert.NoError(t, runner.Command.Execute()) {
+		t.FailNow()
+	}
+	actual, err := ioutil.ReadFile(filepath.Join(d, "Krmfile"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      type: integer
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+`, string(actual))
+	actual, err = ioutil.ReadFile(filepath.Join(d, "deployment.yaml"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+`, string(actual))
+}
+
+func TestCreateSetterCommand_openAPIPath(t *testing.T) {
+	// reset the openAPI afterward
+	openapi.ResetOpenAPI()
+	defer openapi.ResetOpenAPI()
+
+	d, err := ioutil.TempDir("", "kustomize-create-setter-test")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(d)
+	// the definitions are kept in a central file, outside of the package
+	openAPIFile := filepath.Join(d, "setters.yaml")
+	pkg := filepath.Join(d, "app")
+	if !assert.NoError(t, os.Mkdir(pkg, 0700)) {
+		t.FailNow()
+	}
+	err = ioutil.WriteFile(openAPIFile, []byte(`apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	err = ioutil.WriteFile(filepath.Join(pkg, "deployment.yaml"), []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	runner := commands.NewCreateSetterRunner("")
+	runner.Command.SetOut(&bytes.Buffer{})
+	runner.Command.SetArgs([]string{pkg, "replicas", "3", "--openapi-path", openAPIFile, "--no-set-by"})
+	if !assert.NoError(t, runner.Command.Execute()) {
+		t.FailNow()
+	}
+	_, err = os.Stat(filepath.Join(pkg, "Krmfile"))
+	assert.True(t, os.IsNotExist(err))
+
+	openapi.ResetOpenAPI()
+	out := &bytes.Buffer{}
+	list := commands.NewListSettersRunner("")
+	list.Command.SetOut(out)
+	list.Command.SetArgs([]string{pkg, "--openapi-path", openAPIFile})
+	if !assert.NoError(t, list.Command.Execute()) {
+		t.FailNow()
+	}
+	lines := strings.Split(out.String(), "\n")
+	if assert.True(t, len(lines) > 1) {
+		assert.Equal(t, []string{"replicas", "3", "1"}, strings.Fields(lines[1]))
+	}
+
+	openapi.ResetOpenAPI()
+	set := commands.NewSetRunner("")
+	set.Command.SetOut(&bytes.Buffer{})
+	set.Command.SetArgs([]string{pkg, "replicas", "4", "--set-by", "me", "--openapi-path", openAPIFile})
+	if !assert.NoError(t, set.Command.Execute()) {
+		t.FailNow()
+	}
+
+	actual, err := ioutil.ReadFile(openAPIFile)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      type: integer
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "4"
+          setBy: me
+`, string(actual))
+	actual, err = ioutil.ReadFile(filepath.Join(pkg, "deployment.yaml"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 4 # {"$openapi":"replicas"}
+`, string(actual))
+
+	runner = commands.NewCreateSetterRunner("")
+	runner.Command.SetOut(&bytes.Buffer{})
+	runner.Command.SetErr(&bytes.Buffer{})
+	runner.Command.SilenceUsage = true
+	runner.Command.SetArgs([]string{filepath.Join(pkg, "deployment.yaml"), "name", "nginx",
+		"--openapi-path", openAPIFile, "--inline-openapi"})
+	err = runner.Command.Execute()
+	if assert.Error(t, err) {
+		assert.Equal(t, "--inline-openapi and --openapi-path may not both be specified", err.Error())
+	}
+}
+
+func TestCreateSetterCommand_force(t *testing.T) {
+	// reset the openAPI afterward
+	openapi.ResetOpenAPI()
+	defer openapi.ResetOpenAPI()
+
+	d, err := ioutil.TempDir("", "kustomize-create-setter-test")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(d)
+	err = ioutil.WriteFile(filepath.Join(d, "Krmfile"), []byte(`apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      description: hello world
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+          setBy: me
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	err = ioutil.WriteFile(filepath.Join(d, "deployment.yaml"), []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	schema := filepath.Join(d, "schema.json")
+	err = ioutil.WriteFile(schema, []byte(`{"maximum": 10, "type": "integer"}`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	// re-creating the setter is an error without --force
+	runner := commands.NewCreateSetterRunner("")
+	runner.Command.SetOut(&bytes.Buffer{})
+	runner.Command.SetErr(&bytes.Buffer{})
+	runner.Command.SilenceUsage = true
+	runner.Command.SetArgs([]string{d, "replicas", "3", "--schema-path", schema, "--no-set-by"})
+	err = runner.Command.Execute()
+	if assert.Error(t, err) {
+		assert.Equal(t, "setter with name replicas already exists, use --force to update it", err.Error())
+	}
+
+	// the definition is updated in place with --force, keeping setBy
+	for i := 0; i < 2; i++ {
+		openapi.ResetOpenAPI()
+		runner = commands.NewCreateSetterRunner("")
+		runner.Command.SetOut(&bytes.Buffer{})
+		runner.Command.SetArgs([]string{d, "replicas", "3", "--schema-path", schema,
+			"--description", "number of replicas", "--force"})
+		if !assert.NoError(t, runner.Command.Execute()) {
+			t.FailNow()
+		}
+		actual, err := ioutil.ReadFile(filepath.Join(d, "Krmfile"))
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.Equal(t, `apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      maximum: 10
+      type: integer
+      description: number of replicas
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+          setBy: me
+`, string(actual))
+		actual, err = ioutil.ReadFile(filepath.Join(d, "deployment.yaml"))
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.Equal(t, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+`, string(actual))
+	}
+}
+
+func TestCreateSetterCommand_recurseSubPackages(t *testing.T) {
+	// reset the openAPI afterward
+	openapi.ResetOpenAPI()
+	defer openapi.ResetOpenAPI()
+
+	d, err := ioutil.TempDir("", "kustomize-create-setter-test")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(d)
+	krmfile := `apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+`
+	deployment := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3
+`
+	files := map[string]string{
+		"Krmfile":                    krmfile,
+		"deployment.yaml":            deployment,
+		"base/deployment.yaml":       deployment,
+		"app/Krmfile":                krmfile,
+		"app/deployment.yaml":        deployment,
+		"config/Krmfile":             krmfile,
+		"config/configmap.yaml":      "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\n",
+		"app/nested/deployment.yaml": deployment,
+	}
+	for name, data := range files {
+		if !assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(d, name)), 0700)) {
+			t.FailNow()
+		}
+		if !assert.NoError(t, ioutil.WriteFile(filepath.Join(d, name), []byte(data), 0600)) {
+			t.FailNow()
+		}
+	}
+
+	out := &bytes.Buffer{}
+	runner := commands.NewCreateSetterRunner("")
+	runner.Command.SetOut(out)
+	runner.Command.SetArgs([]string{d, "replicas", "3", "--field", "replicas", "-R", "--no-set-by"})
+	if !assert.NoError(t, runner.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, "setter replicas: added reference to 4 fields in 4 files\n", out.String())
+
+	definition := `apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      type: integer
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+`
+	expected := map[string]string{
+		"Krmfile":        definition,
+		"app/Krmfile":    definition,
+		"config/Krmfile": krmfile,
+	}
+	for _, name := range []string{
+		"deployment.yaml", "base/deployment.yaml", "app/deployment.yaml", "app/nested/deployment.yaml"} {
+		expected[name] = strings.Replace(deployment,
+			"replicas: 3", `replicas: 3 # {"$openapi":"replicas"}`, 1)
+	}
+	for name, data := range expected {
+		actual, err := ioutil.ReadFile(filepath.Join(d, name))
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.Equal(t, data, string(actual), name)
+	}
+}
+
+func TestCreateSetterCommand_defaultSetBy(t *testing.T) {
+	// reset the openAPI afterward
+	openapi.ResetOpenAPI()
+	defer openapi.ResetOpenAPI()
+
+	oldSetBy := ext.GetDefaultSetBy
+	defer func() { ext.GetDefaultSetBy = oldSetBy }()
+	ext.GetDefaultSetBy = func() (string, error) {
+		return "jane@example.com", nil
+	}
+
+	d, err := ioutil.TempDir("", "kustomize-create-setter-test")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(d)
+	files := map[string]string{
+		"Krmfile": `apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+`,
+		"deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  namespace: default
+spec:
+  replicas: 3
+`,
+	}
+	for name, data := range files {
+		if !assert.NoError(t, ioutil.WriteFile(filepath.Join(d, name), []byte(data), 0600)) {
+			t.FailNow()
+		}
+	}
+
+	// --set-by and --no-set-by conflict
+	runner := commands.NewCreateSetterRunner("")
+	runner.Command.SetOut(&bytes.Buffer{})
+	runner.Command.SetErr(&bytes.Buffer{})
+	runner.Command.SilenceUsage = true
+	runner.Command.SetArgs([]string{d, "replicas", "3", "--set-by", "me", "--no-set-by"})
+	err = runner.Command.Execute()
+	if assert.Error(t, err) {
+		assert.Equal(t, "--set-by and --no-set-by may not both be specified", err.Error())
+	}
+
+	// --no-set-by leaves setBy unset
+	runner = commands.NewCreateSetterRunner("")
+	runner.Command.SetOut(&bytes.Buffer{})
+	runner.Command.SetArgs([]string{d, "namespace", "default", "--no-set-by"})
+	if !assert.NoError(t, runner.Command.Execute()) {
+		t.FailNow()
+	}
+
+	// setBy defaults to the current user
+	openapi.ResetOpenAPI()
+	runner = commands.NewCreateSetterRunner("")
+	runner.Command.SetOut(&bytes.Buffer{})
+	runner.Command.SetArgs([]string{d, "replicas", "3"})
+	if !assert.NoError(t, runner.Command.Execute()) {
+		t.FailNow()
+	}
+
+	actual, err := ioutil.ReadFile(filepath.Join(d, "Krmfile"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.namespace:
+      type: string
+      x-k8s-cli:
+        setter:
+          name: namespace
+          value: default
+    io.k8s.cli.setters.replicas:
+      type: integer
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+          setBy: jane@example.com
+`, string(actual))
+
+	// a failed lookup leaves setBy unset rather than failing the command
+	ext.GetDefaultSetBy = func() (string, error) {
+		return "", errors.New("user: unknown userid 1000")
+	}
+	openapi.ResetOpenAPI()
+	runner = commands.NewCreateSetterRunner("")
+	runner.Command.SetOut(&bytes.Buffer{})
+	runner.Command.SetArgs([]string{d, "name", "nginx"})
+	if !assert.NoError(t, runner.Command.Execute()) {
+		t.FailNow()
+	}
+	actual, err = ioutil.ReadFile(filepath.Join(d, "Krmfile"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.True(t, strings.HasSuffix(string(actual), `
+    io.k8s.cli.setters.name:
+      type: string
+      x-k8s-cli:
+        setter:
+          name: name
+          value: nginx
+`), string(actual))
+}
+
+func TestCreateSetterCommand_listOfObjectsSchema(t *testing.T) {
+	// reset the openAPI afterward
+	openapi.ResetOpenAPI()
+	defer openapi.ResetOpenAPI()
+
+	d, err := ioutil.TempDir("", "kustomize-create-setter-test")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(d)
+	files := map[string]string{
+		"Krmfile": `apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+`,
+		"example.yaml": `apiVersion: example.com/v1beta1
+kind: Example
+spec:
+  env:
+  - name: FOO
+    value: foo
+  - value: bar
+`,
+		"schema.json": `{"type": "array", "items": {"type": "object", "required": ["name"]}}`,
+	}
+	for name, data := range files {
+		if !assert.NoError(t, ioutil.WriteFile(filepath.Join(d, name), []byte(data), 0600)) {
+			t.FailNow()
+		}
+	}
+
+	// the items are validated against the items schema
+	runner := commands.NewCreateSetterRunner("")
+	runner.Command.SetOut(&bytes.Buffer{})
+	runner.Command.SetErr(&bytes.Buffer{})
+	runner.Command.SilenceUsage = true
+	runner.Command.SetArgs([]string{d, "env", "--type", "array", "--field", "spec.env",
+		"--schema-path", filepath.Join(d, "schema.json"), "--no-set-by"})
+	err = runner.Command.Execute()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "name in body is required")
+	}
+
+	// the resources are left unchanged
+	actual, err := ioutil.ReadFile(filepath.Join(d, "example.yaml"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, files["example.yaml"], string(actual))
+}
+
+func TestCreateSetterCommand_inferType(t *testing.T) {
+	// reset the openAPI afterward
+	openapi.ResetOpenAPI()
+	defer openapi.ResetOpenAPI()
+
+	d, err := ioutil.TempDir("", "kustomize-create-setter-test")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(d)
+	files := map[string]string{
+		"Krmfile": `apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+`,
+		"deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  labels:
+    version: "5"
+spec:
+  replicas: 1
+  minReadySeconds: 5
+  paused: false
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx
+`,
+	}
+	for name, data := range files {
+		if !assert.NoError(t, ioutil.WriteFile(filepath.Join(d, name), []byte(data), 0600)) {
+			t.FailNow()
+		}
+	}
+
+	for _, args := range [][]string{
+		// the fields with value 5 are an integer and a string
+		{"five", "5"},
+		{"image", "nginx", "--field", "image", "--type", "string"},
+		{"paused", "false"},
+		{"replicas", "1"},
+	} {
+		openapi.ResetOpenAPI()
+		runner := commands.NewCreateSetterRunner("")
+		runner.Command.SetOut(&bytes.Buffer{})
+		runner.Command.SetArgs(append([]string{d}, append(args, "--no-set-by")...))
+		if !assert.NoError(t, runner.Command.Execute()) {
+			t.FailNow()
+		}
+	}
+	actual, err := ioutil.ReadFile(filepath.Join(d, "Krmfile"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.five:
+      x-k8s-cli:
+        setter:
+          name: five
+          value: "5"
+    io.k8s.cli.setters.image:
+      type: string
+      x-k8s-cli:
+        setter:
+          name: image
+          value: nginx
+    io.k8s.cli.setters.paused:
+      type: boolean
+      x-k8s-cli:
+        setter:
+          name: paused
+          value: "false"
+    io.k8s.cli.setters.replicas:
+      type: integer
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "1"
+`, string(actual))
+
+	// values of the wrong type are rejected by set
+	openapi.ResetOpenAPI()
+	set := commands.NewSetRunner("")
+	set.Command.SetOut(&bytes.Buffer{})
+	set.Command.SetErr(&bytes.Buffer{})
+	set.Command.SilenceUsage = true
+	set.Command.SetArgs([]string{d, "replicas", "abc", "--no-set-by"})
+	err = set.Command.Execute()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "replicas in body must be of type integer")
 	}
 }
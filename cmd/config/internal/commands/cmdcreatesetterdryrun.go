@@ -0,0 +1,137 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/kyaml/copyutil"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+)
+
+// dryRunFile is a file which create-setter may change, and its copy in which
+// the setter is created.
+type dryRunFile struct {
+	// name is the name of the file in the diff, relative to the package
+	name string
+	// path is the path to the file
+	path string
+	// copy is the path to the copy of the file
+	copy string
+}
+
+// dryRun creates the setter in a copy of the package, and prints the unified
+// diff of the files which would be changed, rather than changing them.
+func (r *CreateSetterRunner) dryRun(c *cobra.Command, args []string) error {
+	if r.InlineOpenAPI {
+		defer os.Remove(r.OpenAPIFile)
+	}
+	tmp, err := ioutil.TempDir("", "kustomize-create-setter-")
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	files, pkgCopy, err := copyPackage(args[0], tmp)
+	if err != nil {
+		return err
+	}
+	openAPIFile := r.OpenAPIFile
+	if !r.InlineOpenAPI {
+		openAPIFile = ""
+		for _, f := range files {
+			if filepath.Clean(f.path) == filepath.Clean(r.OpenAPIFile) {
+				openAPIFile = f.copy
+			}
+		}
+		if openAPIFile == "" {
+			// the OpenAPI file is outside of the package
+			openAPIFile = filepath.Join(tmp, "openapi")
+			if err := copyutil.SyncFile(r.OpenAPIFile, openAPIFile); err != nil {
+				return errors.Wrap(err)
+			}
+			name, err := filepath.Rel(args[0], r.OpenAPIFile)
+			if err != nil {
+				name = r.OpenAPIFile
+			}
+			files = append(files, dryRunFile{
+				name: filepath.ToSlash(name), path: r.OpenAPIFile, copy: openAPIFile})
+		}
+	}
+
+	if err := r.CreateSetter.Create(openAPIFile, pkgCopy); err != nil {
+		return err
+	}
+	if r.InlineOpenAPI {
+		if err := writeInlineOpenAPI(pkgCopy, openAPIFile); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range files {
+		before, err := readFileIfExists(f.path)
+		if err != nil {
+			return err
+		}
+		after, err := readFileIfExists(f.copy)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(c.OutOrStdout(), copyutil.UnifiedDiff(f.name, before, after))
+	}
+	return nil
+}
+
+// copyPackage copies the package at pkg, a directory or a single file, into
+// dir.  Returns the files of the package, and the path to its copy.
+func copyPackage(pkg, dir string) ([]dryRunFile, string, error) {
+	fi, err := os.Stat(pkg)
+	if err != nil {
+		return nil, "", errors.Wrap(err)
+	}
+	if !fi.IsDir() {
+		f := dryRunFile{
+			name: filepath.Base(pkg), path: pkg, copy: filepath.Join(dir, filepath.Base(pkg))}
+		if err := copyutil.SyncFile(f.path, f.copy); err != nil {
+			return nil, "", errors.Wrap(err)
+		}
+		return []dryRunFile{f}, f.copy, nil
+	}
+
+	pkgCopy := filepath.Join(dir, "package")
+	if err := copyutil.CopyDir(pkg, pkgCopy); err != nil {
+		return nil, "", errors.Wrap(err)
+	}
+	var files []dryRunFile
+	err = filepath.Walk(pkgCopy, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(pkgCopy, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, dryRunFile{
+			name: filepath.ToSlash(rel),
+			path: filepath.Join(pkg, rel),
+			copy: path,
+		})
+		return nil
+	})
+	return files, pkgCopy, errors.Wrap(err)
+}
+
+// readFileIfExists returns the content of the file at path, or the empty
+// string if it doesn't exist.
+func readFileIfExists(path string) (string, error) {
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	return string(b), errors.Wrap(err)
+}
@@ -0,0 +1,92 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/cmd/config/ext"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/krmfile"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+	"sigs.k8s.io/kustomize/kyaml/setters2/settersutil"
+)
+
+// preRunPackages validates the flags for creating the setter in the
+// subpackages of DIR.
+func (r *CreateSetterRunner) preRunPackages(args []string) error {
+	if r.InlineOpenAPI || r.OpenAPIPath != "" || r.DryRun {
+		return errors.Errorf("--recurse-subpackages may not be specified " +
+			"with --inline-openapi, --openapi-path or --dry-run")
+	}
+	info, err := os.Stat(args[0])
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if !info.IsDir() {
+		return errors.Errorf("--recurse-subpackages requires DIR to be a directory")
+	}
+	// subpackages are only supported by setters created with create-setter
+	setterVersion = "v2"
+	return nil
+}
+
+// createPackages creates the setter in DIR, and in each of its subpackages,
+// each with its own OpenAPI file.  The fields of a subpackage are only added
+// a reference to the setter defined by the subpackage, which is left
+// unchanged if none of its fields match.
+func (r *CreateSetterRunner) createPackages(c *cobra.Command, args []string) error {
+	dirs, err := packageDirs(args[0], true)
+	if err != nil {
+		return err
+	}
+	var references []settersutil.SetterReference
+	for _, dir := range dirs {
+		openAPIFile := r.OpenAPIFile
+		var previous []byte
+		if dir != args[0] {
+			openAPIFile, err = ext.GetOpenAPIFile([]string{dir})
+			if err != nil {
+				return err
+			}
+			if previous, err = ioutil.ReadFile(openAPIFile); err != nil {
+				if os.IsNotExist(err) {
+					// not a package -- its fields belong to the enclosing package
+					continue
+				}
+				return errors.Wrap(err)
+			}
+		}
+
+		// each package has its own setter definitions
+		openapi.ResetOpenAPI()
+		cs := r.CreateSetter
+		cs.PackageFileName = krmfile.KrmfileName
+		if err := cs.Create(openAPIFile, dir); err != nil {
+			return errors.WrapPrefixf(err, dir)
+		}
+		if dir != args[0] && len(cs.References) == 0 {
+			// don't define the setter in subpackages which don't use it
+			if err := ioutil.WriteFile(openAPIFile, previous, 0600); err != nil {
+				return errors.Wrap(err)
+			}
+			continue
+		}
+
+		rel, err := filepath.Rel(args[0], dir)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		for _, ref := range cs.References {
+			ref.File = filepath.Join(rel, ref.File)
+			references = append(references, ref)
+		}
+	}
+	r.CreateSetter.References = references
+	r.printSummary(c)
+	return nil
+}
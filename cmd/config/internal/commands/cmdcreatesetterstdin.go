@@ -0,0 +1,87 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// krmfileDocument is the Krmfile document added to the resources written to
+// stdout if the input has none.
+const krmfileDocument = `apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+`
+
+// createStdin creates the setter in the resources read from stdin, and writes
+// them to stdout.  Unless --openapi-path is set, the setter definitions are
+// read from the Krmfile document of the resources, if they have one, and
+// written to it after the other resources -- so that the definitions of
+// earlier create-setter commands in a pipeline are kept.
+func (r *CreateSetterRunner) createStdin(c *cobra.Command) error {
+	rw := &kio.ByteReadWriter{Reader: c.InOrStdin(), Writer: c.OutOrStdout()}
+	nodes, err := rw.Read()
+	if err != nil {
+		return err
+	}
+
+	openAPIFile := r.OpenAPIPath
+	if openAPIFile == "" {
+		var content string
+		nodes, content, err = splitKrmfile(nodes)
+		if err != nil {
+			return err
+		}
+		f, err := ioutil.TempFile("", "kustomize-create-setter-*.yaml")
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		defer os.Remove(f.Name())
+		_, err = f.WriteString(content)
+		f.Close()
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		openAPIFile = f.Name()
+	}
+
+	nodes, err = r.CreateSetter.CreateFilter(openAPIFile).Filter(nodes)
+	if err != nil {
+		return err
+	}
+	if r.OpenAPIPath == "" {
+		krmfile, err := yaml.ReadFile(openAPIFile)
+		if err != nil {
+			return err
+		}
+		nodes = append(nodes, krmfile)
+	}
+	if err := rw.Write(nodes); err != nil {
+		return err
+	}
+	r.printSummary(c)
+	return nil
+}
+
+// splitKrmfile removes the Krmfile document from nodes, and returns its
+// content, or that of a new Krmfile if there is none.
+func splitKrmfile(nodes []*yaml.RNode) ([]*yaml.RNode, string, error) {
+	for i := range nodes {
+		meta, err := nodes[i].GetMeta()
+		if err != nil || meta.Kind != "Krmfile" {
+			continue
+		}
+		content, err := nodes[i].String()
+		if err != nil {
+			return nil, "", err
+		}
+		return append(nodes[:i], nodes[i+1:]...), content, nil
+	}
+	return nodes, krmfileDocument, nil
+}
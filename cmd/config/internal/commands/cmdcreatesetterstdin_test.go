@@ -0,0 +1,166 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/cmd/config/ext"
+	"sigs.k8s.io/kustomize/cmd/config/internal/commands"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+)
+
+func TestCreateSetterCommand_stdin(t *testing.T) {
+	defer openapi.ResetOpenAPI()
+	oldSetBy := ext.GetDefaultSetBy
+	defer func() { ext.GetDefaultSetBy = oldSetBy }()
+	ext.GetDefaultSetBy = func() (string, error) {
+		return "", nil
+	}
+
+	input := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3
+`
+	// the definitions are written as a Krmfile document after the resources
+	r := commands.NewCreateSetterRunner("")
+	out := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	r.Command.SetIn(strings.NewReader(input))
+	r.Command.SetOut(out)
+	r.Command.SetErr(stderr)
+	r.Command.SetArgs([]string{"--stdin", "replicas", "3"})
+	if !assert.NoError(t, r.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+---
+apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      type: integer
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+`, out.String())
+	assert.Equal(t, "setter replicas: added reference to 1 fields in 1 files\n", stderr.String())
+
+	// the definitions of the Krmfile document are kept by the next command
+	openapi.ResetOpenAPI()
+	r = commands.NewCreateSetterRunner("")
+	r.Command.SetIn(strings.NewReader(out.String()))
+	out = &bytes.Buffer{}
+	r.Command.SetOut(out)
+	r.Command.SetErr(&bytes.Buffer{})
+	r.Command.SetArgs([]string{"--stdin", "app", "nginx", "--field", "metadata.name"})
+	if !assert.NoError(t, r.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx # {"$openapi":"app"}
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+---
+apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      type: integer
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+    io.k8s.cli.setters.app:
+      type: string
+      x-k8s-cli:
+        setter:
+          name: app
+          value: nginx
+`, out.String())
+}
+
+func TestCreateSetterCommand_stdinOpenAPIPath(t *testing.T) {
+	d, err := ioutil.TempDir("", "kustomize-create-setter-test")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(d)
+	defer openapi.ResetOpenAPI()
+
+	path := filepath.Join(d, "setters.yaml")
+	err = ioutil.WriteFile(path, []byte(`apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	// the definitions are written to --openapi-path rather than stdout
+	r := commands.NewCreateSetterRunner("")
+	out := &bytes.Buffer{}
+	r.Command.SetIn(strings.NewReader(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3
+`))
+	r.Command.SetOut(out)
+	r.Command.SetErr(&bytes.Buffer{})
+	r.Command.SetArgs([]string{"--stdin", "replicas", "3", "--openapi-path", path, "--no-set-by"})
+	if !assert.NoError(t, r.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+`, out.String())
+
+	b, err := ioutil.ReadFile(path)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      type: integer
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+`, string(b))
+
+	// DIR is omitted, and may not be given with --stdin
+	r = commands.NewCreateSetterRunner("")
+	r.Command.SetIn(strings.NewReader(""))
+	r.Command.SetOut(&bytes.Buffer{})
+	r.Command.SetErr(&bytes.Buffer{})
+	r.Command.SetArgs([]string{"--stdin", d, "replicas", "3"})
+	assert.Error(t, r.Command.Execute())
+}
@@ -33,6 +33,8 @@ func NewCreateSubstitutionRunner(parent string) *CreateSubstitutionRunner {
 		"value of the field to create substitution for -- e.g. --field-value nginx:0.1.0")
 	cs.Flags().StringVar(&r.CreateSubstitution.Pattern, "pattern", "",
 		`substitution pattern -- e.g. --pattern \${my-image-setter}:\${my-tag-setter}`)
+	cs.Flags().BoolVar(&r.CreateSubstitution.CreateMissingSetters, "create-missing-setters", false,
+		"create the setters missing for markers in the pattern even if their values can't be derived from --field-value, with empty values, and print their names.")
 	_ = cs.MarkFlagRequired("pattern")
 	_ = cs.MarkFlagRequired("field-value")
 	fixDocs(parent, cs)
@@ -52,7 +54,21 @@ type CreateSubstitutionRunner struct {
 }
 
 func (r *CreateSubstitutionRunner) runE(c *cobra.Command, args []string) error {
-	return handleError(c, r.CreateSubstitution.Create(r.OpenAPIFile, args[0]))
+	var created []string
+	if r.CreateSubstitution.CreateMissingSetters {
+		var err error
+		created, err = r.CreateSubstitution.MissingSetters(r.OpenAPIFile)
+		if err != nil {
+			return handleError(c, err)
+		}
+	}
+	if err := r.CreateSubstitution.Create(r.OpenAPIFile, args[0]); err != nil {
+		return handleError(c, err)
+	}
+	for _, name := range created {
+		fmt.Fprintf(c.OutOrStdout(), "created setter %s\n", name)
+	}
+	return nil
 }
 
 func (r *CreateSubstitutionRunner) preRunE(c *cobra.Command, args []string) error {
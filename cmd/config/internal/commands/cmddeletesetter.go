@@ -0,0 +1,117 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/cmd/config/ext"
+	"sigs.k8s.io/kustomize/cmd/config/internal/generateddocs/commands"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/krmfile"
+	"sigs.k8s.io/kustomize/kyaml/setters2"
+	"sigs.k8s.io/kustomize/kyaml/setters2/settersutil"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// NewDeleteSetterRunner returns a command runner.
+func NewDeleteSetterRunner(parent string) *DeleteSetterRunner {
+	r := &DeleteSetterRunner{}
+	c := &cobra.Command{
+		Use:     "delete-setter DIR NAME",
+		Args:    cobra.ExactArgs(2),
+		Short:   commands.DeleteSetterShort,
+		Long:    commands.DeleteSetterLong,
+		Example: commands.DeleteSetterExamples,
+		RunE:    r.runE,
+	}
+	fixDocs(parent, c)
+	c.Flags().BoolVarP(&r.RecurseSubPackages, "recurse-subpackages", "R", false,
+		"also delete the setter from the subpackages of DIR -- directories containing their own Krmfile.")
+	r.Command = c
+	return r
+}
+
+func DeleteSetterCommand(parent string) *cobra.Command {
+	return NewDeleteSetterRunner(parent).Command
+}
+
+type DeleteSetterRunner struct {
+	Command *cobra.Command
+
+	// RecurseSubPackages also deletes the setter from the subpackages of DIR.
+	RecurseSubPackages bool
+}
+
+func (r *DeleteSetterRunner) runE(c *cobra.Command, args []string) error {
+	return handleError(c, r.delete(c, args))
+}
+
+// delete deletes the setter from DIR, and from each of its subpackages which
+// defines it if RecurseSubPackages is set.  The definitions of all of the
+// packages are checked before anything is deleted.
+func (r *DeleteSetterRunner) delete(c *cobra.Command, args []string) error {
+	name := args[1]
+	dirs, err := packageDirs(args[0], r.RecurseSubPackages)
+	if err != nil {
+		return err
+	}
+	openAPIFiles := map[string]string{}
+	var packages []string
+	for _, dir := range dirs {
+		openAPIFile, err := ext.GetOpenAPIFile([]string{dir})
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(openAPIFile); err != nil {
+			if dir == args[0] && !r.RecurseSubPackages {
+				return errors.Wrap(err)
+			}
+			// not a package -- it defines no setters
+			continue
+		}
+		if r.RecurseSubPackages {
+			// only the packages defining the setter are changed
+			d := settersutil.SetterDeleter{Name: name}
+			defined, err := d.IsDefined(openAPIFile)
+			if err != nil {
+				return errors.WrapPrefixf(err, dir)
+			}
+			if !defined {
+				continue
+			}
+		}
+		// check the setter may be deleted, without writing the definitions
+		object, err := yaml.ReadFile(openAPIFile)
+		if err != nil {
+			return errors.WrapPrefixf(err, dir)
+		}
+		if _, err := (setters2.DeleteDefinition{Name: name}).Filter(object); err != nil {
+			return errors.WrapPrefixf(err, dir)
+		}
+		openAPIFiles[dir] = openAPIFile
+		packages = append(packages, dir)
+	}
+
+	if len(packages) == 0 {
+		return errors.Errorf("setter %s is not defined", name)
+	}
+
+	var count int
+	for _, dir := range packages {
+		d := settersutil.SetterDeleter{Name: name}
+		if r.RecurseSubPackages {
+			d.PackageFileName = krmfile.KrmfileName
+		}
+		if err := d.Delete(openAPIFiles[dir], dir); err != nil {
+			return errors.WrapPrefixf(err, dir)
+		}
+		count += d.Count
+	}
+	fmt.Fprintf(c.OutOrStdout(), "deleted setter %s: removed reference from %d fields in %d packages\n",
+		name, count, len(packages))
+	return nil
+}
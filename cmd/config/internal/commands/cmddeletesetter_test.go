@@ -0,0 +1,158 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/cmd/config/internal/commands"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+)
+
+func TestDeleteSetterCommand(t *testing.T) {
+	krmfile := `apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+`
+	deployment := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+`
+	deleted := map[string]string{
+		"Krmfile": `apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+openAPI:
+  definitions: {}
+`,
+		"deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3
+`,
+	}
+	var tests = []struct {
+		name     string
+		args     []string
+		files    map[string]string
+		out      string
+		expected map[string]string
+		err      string
+	}{
+		{
+			name: "delete",
+			args: []string{"replicas"},
+			files: map[string]string{
+				"Krmfile":         krmfile,
+				"deployment.yaml": deployment,
+			},
+			out: "deleted setter replicas: removed reference from 1 fields in 1 packages\n",
+			expected: map[string]string{
+				"Krmfile":         deleted["Krmfile"],
+				"deployment.yaml": deleted["deployment.yaml"],
+			},
+		},
+		{
+			name: "recurse subpackages",
+			args: []string{"replicas", "-R"},
+			files: map[string]string{
+				"Krmfile":             krmfile,
+				"deployment.yaml":     deployment,
+				"app/Krmfile":         krmfile,
+				"app/deployment.yaml": deployment,
+			},
+			out: "deleted setter replicas: removed reference from 2 fields in 2 packages\n",
+			expected: map[string]string{
+				"Krmfile":             deleted["Krmfile"],
+				"deployment.yaml":     deleted["deployment.yaml"],
+				"app/Krmfile":         deleted["Krmfile"],
+				"app/deployment.yaml": deleted["deployment.yaml"],
+			},
+		},
+		{
+			name: "referenced by a substitution",
+			args: []string{"replicas"},
+			files: map[string]string{
+				"Krmfile": krmfile + `    io.k8s.cli.substitutions.name:
+      x-k8s-cli:
+        substitution:
+          name: name
+          pattern: nginx-REPLICAS
+          values:
+          - marker: REPLICAS
+            ref: '#/definitions/io.k8s.cli.setters.replicas'
+`,
+				"deployment.yaml": deployment,
+			},
+			err: "setter replicas is referenced by substitution name",
+			expected: map[string]string{
+				"deployment.yaml": deployment,
+			},
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			// reset the openAPI afterward
+			openapi.ResetOpenAPI()
+			defer openapi.ResetOpenAPI()
+
+			d, err := ioutil.TempDir("", "kustomize-delete-setter-test")
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			defer os.RemoveAll(d)
+			for name, data := range test.files {
+				if !assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(d, name)), 0700)) {
+					t.FailNow()
+				}
+				err := ioutil.WriteFile(filepath.Join(d, name), []byte(data), 0600)
+				if !assert.NoError(t, err) {
+					t.FailNow()
+				}
+			}
+
+			out := &bytes.Buffer{}
+			r := commands.NewDeleteSetterRunner("")
+			r.Command.SetOut(out)
+			r.Command.SetErr(&bytes.Buffer{})
+			r.Command.SilenceUsage = true
+			r.Command.SetArgs(append([]string{d}, test.args...))
+			err = r.Command.Execute()
+			if test.err != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), test.err)
+				}
+			} else {
+				if !assert.NoError(t, err) {
+					t.FailNow()
+				}
+				assert.Equal(t, test.out, out.String())
+			}
+
+			for name, data := range test.expected {
+				actual, err := ioutil.ReadFile(filepath.Join(d, name))
+				if !assert.NoError(t, err) {
+					t.FailNow()
+				}
+				assert.Equal(t, data, string(actual), name)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/cmd/config/ext"
+	"sigs.k8s.io/kustomize/cmd/config/internal/generateddocs/commands"
+	"sigs.k8s.io/kustomize/kyaml/setters2/settersutil"
+)
+
+// NewExportSettersRunner returns a command runner.
+func NewExportSettersRunner(parent string) *ExportSettersRunner {
+	r := &ExportSettersRunner{}
+	c := &cobra.Command{
+		Use:     "export-setters DIR FILE",
+		Args:    cobra.ExactArgs(2),
+		Short:   commands.ExportSettersShort,
+		Long:    commands.ExportSettersLong,
+		Example: commands.ExportSettersExamples,
+		RunE:    r.runE,
+	}
+	fixDocs(parent, c)
+	r.Command = c
+	return r
+}
+
+func ExportSettersCommand(parent string) *cobra.Command {
+	return NewExportSettersRunner(parent).Command
+}
+
+type ExportSettersRunner struct {
+	Command *cobra.Command
+}
+
+func (r *ExportSettersRunner) runE(c *cobra.Command, args []string) error {
+	openAPIFile, err := ext.GetOpenAPIFile(args)
+	if err != nil {
+		return handleError(c, err)
+	}
+	b, err := settersutil.ExportSetters(openAPIFile, args[0])
+	if err != nil {
+		return handleError(c, err)
+	}
+	if err := b.WriteFile(args[1]); err != nil {
+		return handleError(c, err)
+	}
+	fmt.Fprintf(c.OutOrStdout(), "exported %d definitions, %d markers\n",
+		b.DefinitionCount(), len(b.Markers))
+	return nil
+}
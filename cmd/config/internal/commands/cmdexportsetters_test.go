@@ -0,0 +1,143 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/cmd/config/internal/commands"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+)
+
+func TestExportImportSettersCommand(t *testing.T) {
+	// reset the openAPI afterward
+	openapi.ResetOpenAPI()
+	defer openapi.ResetOpenAPI()
+
+	srcOpenAPI := `apiVersion: v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+    io.k8s.cli.setters.image:
+      x-k8s-cli:
+        setter:
+          name: image
+          value: nginx
+`
+	srcResources := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx # {"$openapi":"image"}
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: other-deployment
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+`
+	// the target package doesn't contain other-deployment
+	dstResources := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx
+`
+	expectedResources := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx # {"$openapi":"image"}
+`
+
+	src, err := ioutil.TempDir("", "kustomize-export-setters-test")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(src)
+	dst, err := ioutil.TempDir("", "kustomize-import-setters-test")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(dst)
+	bundle := filepath.Join(src, "bundle.yml.out")
+
+	files := map[string]string{
+		filepath.Join(src, "Krmfile"):         srcOpenAPI,
+		filepath.Join(src, "deployment.yaml"): srcResources,
+		filepath.Join(dst, "Krmfile"):         "apiVersion: v1alpha1\nkind: Krmfile\n",
+		filepath.Join(dst, "deployment.yaml"): dstResources,
+	}
+	for path, content := range files {
+		if !assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0600)) {
+			t.FailNow()
+		}
+	}
+
+	// export the setters from the source package
+	export := commands.NewExportSettersRunner("")
+	out := &bytes.Buffer{}
+	export.Command.SetOut(out)
+	export.Command.SetArgs([]string{src, bundle})
+	if !assert.NoError(t, export.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, "exported 2 definitions, 3 markers\n", out.String())
+
+	// import the setters into the target package
+	openapi.ResetOpenAPI()
+	imp := commands.NewImportSettersRunner("")
+	out = &bytes.Buffer{}
+	errOut := &bytes.Buffer{}
+	imp.Command.SetOut(out)
+	imp.Command.SetErr(errOut)
+	imp.Command.SetArgs([]string{dst, bundle})
+	if !assert.NoError(t, imp.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, "imported 2 definitions, applied 2 of 3 markers\n", out.String())
+	assert.Equal(t, "warning: unable to apply #/definitions/io.k8s.cli.setters.replicas "+
+		"to Deployment other-deployment field spec.replicas\n", errOut.String())
+
+	actualResources, err := ioutil.ReadFile(filepath.Join(dst, "deployment.yaml"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, expectedResources, string(actualResources))
+
+	actualOpenAPI, err := ioutil.ReadFile(filepath.Join(dst, "Krmfile"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, srcOpenAPI, string(actualOpenAPI))
+}
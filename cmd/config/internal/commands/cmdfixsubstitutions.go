@@ -0,0 +1,285 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/cmd/config/ext"
+	"sigs.k8s.io/kustomize/cmd/config/internal/generateddocs/commands"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/fieldmeta"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+	"sigs.k8s.io/kustomize/kyaml/setters2"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// NewFixSubstitutionsRunner returns a command runner.
+func NewFixSubstitutionsRunner(parent string) *FixSubstitutionsRunner {
+	r := &FixSubstitutionsRunner{}
+	c := &cobra.Command{
+		Use:     "fix-substitutions DIR",
+		Args:    cobra.ExactArgs(1),
+		Short:   commands.FixSubstitutionsShort,
+		Long:    commands.FixSubstitutionsLong,
+		Example: commands.FixSubstitutionsExamples,
+		RunE:    r.runE,
+	}
+	fixDocs(parent, c)
+	c.Flags().BoolVar(&r.Interactive, "interactive", false,
+		"prompt to relink each dangling reference to the setter it was most likely renamed to.")
+	r.Command = c
+	return r
+}
+
+func FixSubstitutionsCommand(parent string) *cobra.Command {
+	return NewFixSubstitutionsRunner(parent).Command
+}
+
+type FixSubstitutionsRunner struct {
+	Command *cobra.Command
+
+	// Interactive prompts to relink the dangling references.
+	Interactive bool
+
+	// Dangling are the substitution references which refer to missing definitions.
+	// References relinked interactively are not included.
+	Dangling []danglingReference
+
+	// Relinked is the number of references which were relinked.
+	Relinked int
+}
+
+// danglingReference is a substitution value whose ref refers to a missing
+// setter or substitution definition.
+type danglingReference struct {
+	// Substitution is the name of the substitution.
+	Substitution string
+
+	// Marker is the marker of the value -- e.g. ${tag}.
+	Marker string
+
+	// Definition is the missing definition -- e.g. io.k8s.cli.setters.tag.
+	Definition string
+
+	// ref is the node holding the reference, so that it may be relinked.
+	ref *yaml.RNode
+}
+
+func (r *FixSubstitutionsRunner) runE(c *cobra.Command, args []string) error {
+	openAPIFile, err := ext.GetOpenAPIFile(args)
+	if err != nil {
+		return handleError(c, err)
+	}
+	if !r.Interactive {
+		object, err := yaml.ReadFile(openAPIFile)
+		if err != nil {
+			return handleError(c, err)
+		}
+		r.Dangling, _, err = findDanglingReferences(object)
+		if err != nil {
+			return handleError(c, err)
+		}
+		return handleError(c, r.print(c))
+	}
+
+	in := c.InOrStdin()
+	if f, ok := in.(*os.File); ok && !isTerminal(f) {
+		// don't hang waiting for input which will never come
+		return handleError(c, errors.Errorf("--interactive requires a terminal on stdin"))
+	}
+	err = yaml.UpdateFile(yaml.FilterFunc(func(object *yaml.RNode) (*yaml.RNode, error) {
+		return object, r.relink(c, object)
+	}), openAPIFile)
+	if err != nil {
+		return handleError(c, err)
+	}
+	return handleError(c, r.print(c))
+}
+
+// relink prompts to relink each dangling reference in object to the setter it
+// was most likely renamed to.  References without a likely setter, or which
+// the user declines to relink, are recorded as dangling.
+func (r *FixSubstitutionsRunner) relink(c *cobra.Command, object *yaml.RNode) error {
+	dangling, unreferenced, err := findDanglingReferences(object)
+	if err != nil {
+		return err
+	}
+	reader := bufio.NewReader(c.InOrStdin())
+	out := c.OutOrStdout()
+	for _, d := range dangling {
+		var name string
+		if strings.HasPrefix(d.Definition, fieldmeta.SetterDefinitionPrefix) {
+			name = renameCandidate(strings.TrimPrefix(
+				d.Definition, fieldmeta.SetterDefinitionPrefix), unreferenced)
+		}
+		if name == "" {
+			r.Dangling = append(r.Dangling, d)
+			continue
+		}
+		fmt.Fprintf(out, "%s -- relink to setter %s? [y/N]: ", d, name)
+		line, readErr := reader.ReadString('\n')
+		if readErr != nil && readErr != io.EOF {
+			return readErr
+		}
+		if answer := strings.ToLower(strings.TrimSpace(line)); answer != "y" && answer != "yes" {
+			r.Dangling = append(r.Dangling, d)
+			continue
+		}
+		d.ref.YNode().Value = fieldmeta.DefinitionsPrefix + fieldmeta.SetterDefinitionPrefix + name
+		r.Relinked++
+	}
+	return nil
+}
+
+func (r *FixSubstitutionsRunner) print(c *cobra.Command) error {
+	if r.Relinked > 0 {
+		fmt.Fprintf(c.OutOrStdout(), "relinked %d references\n", r.Relinked)
+	}
+	if len(r.Dangling) == 0 {
+		fmt.Fprintf(c.OutOrStdout(), "no dangling substitution references\n")
+		return nil
+	}
+	for _, d := range r.Dangling {
+		fmt.Fprintf(c.OutOrStdout(), "%s\n", d)
+	}
+	return errors.Errorf("found %d dangling substitution references", len(r.Dangling))
+}
+
+func (d danglingReference) String() string {
+	return fmt.Sprintf("substitution %s value %s refers to missing definition %s",
+		d.Substitution, d.Marker, d.Definition)
+}
+
+// findDanglingReferences returns the substitution values in the OpenAPI
+// object which refer to missing definitions, and the names of the setters
+// which aren't referenced by any substitution.
+func findDanglingReferences(object *yaml.RNode) ([]danglingReference, []string, error) {
+	definitions, err := object.Pipe(yaml.Lookup(
+		openapi.SupplementaryOpenAPIFieldName, "definitions"))
+	if err != nil || definitions == nil {
+		return nil, nil, err
+	}
+	keys, err := definitions.Fields()
+	if err != nil {
+		return nil, nil, err
+	}
+	defined := map[string]bool{}
+	for _, k := range keys {
+		defined[k] = true
+	}
+
+	var dangling []danglingReference
+	referenced := map[string]bool{}
+	for _, k := range keys {
+		if !strings.HasPrefix(k, fieldmeta.SubstitutionDefinitionPrefix) {
+			continue
+		}
+		values, err := definitions.Pipe(yaml.Lookup(
+			k, setters2.K8sCliExtensionKey, "substitution", "values"))
+		if err != nil {
+			return nil, nil, err
+		}
+		if values == nil {
+			continue
+		}
+		err = values.VisitElements(func(node *yaml.RNode) error {
+			ref := node.Field("ref")
+			if ref == nil {
+				return nil
+			}
+			definition := strings.TrimPrefix(ref.Value.YNode().Value, fieldmeta.DefinitionsPrefix)
+			referenced[definition] = true
+			if defined[definition] {
+				return nil
+			}
+			var marker string
+			if m := node.Field("marker"); m != nil {
+				marker = m.Value.YNode().Value
+			}
+			dangling = append(dangling, danglingReference{
+				Substitution: strings.TrimPrefix(k, fieldmeta.SubstitutionDefinitionPrefix),
+				Marker:       marker,
+				Definition:   definition,
+				ref:          ref.Value,
+			})
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var unreferenced []string
+	for _, k := range keys {
+		if strings.HasPrefix(k, fieldmeta.SetterDefinitionPrefix) && !referenced[k] {
+			unreferenced = append(unreferenced, strings.TrimPrefix(k, fieldmeta.SetterDefinitionPrefix))
+		}
+	}
+	return dangling, unreferenced, nil
+}
+
+// renameCandidate returns the setter which name was most likely renamed to:
+// the setter in setters closest to name by edit distance, if it is closer than
+// every other setter and keeps at least half of the characters of name -- e.g.
+// tag renamed to image-tag.  Returns the empty string if there is no such setter.
+func renameCandidate(name string, setters []string) string {
+	best, bestDistance, tied := "", -1, false
+	for _, s := range setters {
+		d := editDistance(name, s)
+		switch {
+		case bestDistance < 0 || d < bestDistance:
+			best, bestDistance, tied = s, d, false
+		case d == bestDistance:
+			tied = true
+		}
+	}
+	if best == "" || tied {
+		return ""
+	}
+	longest := len(name)
+	if len(best) > longest {
+		longest = len(best)
+	}
+	if 2*(longest-bestDistance) < len(name) {
+		return ""
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
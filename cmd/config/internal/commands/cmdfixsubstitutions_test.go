@@ -0,0 +1,121 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/cmd/config/internal/commands"
+)
+
+// the tag setter was renamed to image-tag after the image substitution
+// was created
+const danglingSubstitutionKrmfile = `apiVersion: v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.image-tag:
+      x-k8s-cli:
+        setter:
+          name: image-tag
+          value: 1.7.9
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+    io.k8s.cli.substitutions.image:
+      x-k8s-cli:
+        substitution:
+          name: image
+          pattern: nginx:${tag}
+          values:
+          - marker: ${tag}
+            ref: '#/definitions/io.k8s.cli.setters.tag'
+`
+
+func TestFixSubstitutionsCommand(t *testing.T) {
+	var tests = []struct {
+		name        string
+		args        []string
+		input       string
+		expectedErr string
+		expectedOut string
+		expected    string
+	}{
+		{
+			name:        "detect dangling reference",
+			expectedErr: "found 1 dangling substitution references",
+			expectedOut: "substitution image value ${tag} refers to missing " +
+				"definition io.k8s.cli.setters.tag\n",
+			expected: danglingSubstitutionKrmfile,
+		},
+		{
+			name:  "relink dangling reference",
+			args:  []string{"--interactive"},
+			input: "y\n",
+			expectedOut: "substitution image value ${tag} refers to missing " +
+				"definition io.k8s.cli.setters.tag -- relink to setter image-tag? [y/N]: " +
+				"relinked 1 references\n" +
+				"no dangling substitution references\n",
+			expected: strings.Replace(danglingSubstitutionKrmfile,
+				"setters.tag'", "setters.image-tag'", 1),
+		},
+		{
+			name:        "decline to relink dangling reference",
+			args:        []string{"--interactive"},
+			input:       "n\n",
+			expectedErr: "found 1 dangling substitution references",
+			expectedOut: "substitution image value ${tag} refers to missing " +
+				"definition io.k8s.cli.setters.tag -- relink to setter image-tag? [y/N]: " +
+				"substitution image value ${tag} refers to missing " +
+				"definition io.k8s.cli.setters.tag\n",
+			expected: danglingSubstitutionKrmfile,
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			d, err := ioutil.TempDir("", "kustomize-fix-substitutions-test")
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			defer os.RemoveAll(d)
+			err = ioutil.WriteFile(filepath.Join(d, "Krmfile"),
+				[]byte(danglingSubstitutionKrmfile), 0600)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+
+			r := commands.NewFixSubstitutionsRunner("")
+			out := &bytes.Buffer{}
+			r.Command.SetOut(out)
+			r.Command.SilenceUsage = true
+			r.Command.SilenceErrors = true
+			r.Command.SetIn(bytes.NewBufferString(test.input))
+			r.Command.SetArgs(append([]string{d}, test.args...))
+			err = r.Command.Execute()
+			if test.expectedErr != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), test.expectedErr)
+				}
+			} else if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, test.expectedOut, out.String())
+
+			actual, err := ioutil.ReadFile(filepath.Join(d, "Krmfile"))
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, test.expected, string(actual))
+		})
+	}
+}
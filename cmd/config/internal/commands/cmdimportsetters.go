@@ -0,0 +1,60 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/cmd/config/ext"
+	"sigs.k8s.io/kustomize/cmd/config/internal/generateddocs/commands"
+	"sigs.k8s.io/kustomize/kyaml/setters2/settersutil"
+)
+
+// NewImportSettersRunner returns a command runner.
+func NewImportSettersRunner(parent string) *ImportSettersRunner {
+	r := &ImportSettersRunner{}
+	c := &cobra.Command{
+		Use:     "import-setters DIR FILE",
+		Args:    cobra.ExactArgs(2),
+		Short:   commands.ImportSettersShort,
+		Long:    commands.ImportSettersLong,
+		Example: commands.ImportSettersExamples,
+		RunE:    r.runE,
+	}
+	fixDocs(parent, c)
+	r.Command = c
+	return r
+}
+
+func ImportSettersCommand(parent string) *cobra.Command {
+	return NewImportSettersRunner(parent).Command
+}
+
+type ImportSettersRunner struct {
+	Command *cobra.Command
+}
+
+func (r *ImportSettersRunner) runE(c *cobra.Command, args []string) error {
+	openAPIFile, err := ext.GetOpenAPIFile(args)
+	if err != nil {
+		return handleError(c, err)
+	}
+	b, err := settersutil.ReadSetterBundle(args[1])
+	if err != nil {
+		return handleError(c, err)
+	}
+	applied, unapplied, err := b.Import(openAPIFile, args[0])
+	if err != nil {
+		return handleError(c, err)
+	}
+	for _, m := range unapplied {
+		fmt.Fprintf(c.ErrOrStderr(), "warning: unable to apply %s to %s %s field %s\n",
+			m.Ref, m.Kind, m.Name, strings.Join(m.Path, "."))
+	}
+	fmt.Fprintf(c.OutOrStdout(), "imported %d definitions, applied %d of %d markers\n",
+		b.DefinitionCount(), applied, len(b.Markers))
+	return nil
+}
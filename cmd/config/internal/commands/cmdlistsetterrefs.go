@@ -0,0 +1,191 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/cmd/config/ext"
+	"sigs.k8s.io/kustomize/cmd/config/internal/generateddocs/commands"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+	"sigs.k8s.io/kustomize/kyaml/setters2"
+	"sigs.k8s.io/kustomize/kyaml/setters2/settersutil"
+)
+
+// NewListSetterRefsRunner returns a command runner.
+func NewListSetterRefsRunner(parent string) *ListSetterRefsRunner {
+	r := &ListSetterRefsRunner{}
+	c := &cobra.Command{
+		Use:     "list-setter-refs DIR NAME",
+		Args:    cobra.ExactArgs(2),
+		Short:   commands.ListSetterRefsShort,
+		Long:    commands.ListSetterRefsLong,
+		Example: commands.ListSetterRefsExamples,
+		PreRunE: r.preRunE,
+		RunE:    r.runE,
+	}
+	fixDocs(parent, c)
+	c.Flags().BoolVar(&r.RecurseSubPackages, "recurse-subpackages", false,
+		"include the subpackages of DIR -- directories containing their own Krmfile.")
+	c.Flags().StringVar(&r.Output, "output", "table",
+		"output format -- one of table or json.")
+	r.Command = c
+	return r
+}
+
+func ListSetterRefsCommand(parent string) *cobra.Command {
+	return NewListSetterRefsRunner(parent).Command
+}
+
+type ListSetterRefsRunner struct {
+	Command *cobra.Command
+
+	// RecurseSubPackages includes the subpackages of the directory.
+	RecurseSubPackages bool
+
+	// Output is the output format -- table or json.
+	Output string
+
+	// Refs are the fields referencing the setter.
+	Refs []setterRef
+}
+
+// setterRef is a field referencing the setter, its current value, and the
+// package containing it.
+type setterRef struct {
+	// Package is the path to the package, relative to DIR.
+	Package string `json:"package"`
+
+	settersutil.SetterReference
+
+	// Current is the current value of the field -- a list of the values of
+	// its items for list fields.
+	Current interface{} `json:"value"`
+
+	// Drift is true if the field references the setter directly, and its
+	// value differs from the value of the setter -- e.g. it was edited by hand.
+	Drift bool `json:"drift"`
+}
+
+func (r *ListSetterRefsRunner) preRunE(c *cobra.Command, args []string) error {
+	if r.Output != "table" && r.Output != "json" {
+		return errors.Errorf("--output must be one of table or json, was %s", r.Output)
+	}
+	return nil
+}
+
+func (r *ListSetterRefsRunner) runE(c *cobra.Command, args []string) error {
+	packages, err := packageDirs(args[0], r.RecurseSubPackages)
+	if err != nil {
+		return handleError(c, err)
+	}
+	for _, pkg := range packages {
+		openAPIFile, err := ext.GetOpenAPIFile([]string{pkg})
+		if err != nil {
+			return handleError(c, err)
+		}
+		if _, err := os.Stat(openAPIFile); err != nil {
+			// not a package -- it defines no setters
+			continue
+		}
+		if err := r.listRefs(args[0], pkg, openAPIFile, args[1]); err != nil {
+			return handleError(c, err)
+		}
+	}
+	return handleError(c, r.print(c, args[1]))
+}
+
+// listRefs records the fields of pkg referencing the setter name, and whether
+// each is out of sync with the value of the setter.
+func (r *ListSetterRefsRunner) listRefs(dir, pkg, openAPIFile, name string) error {
+	// each package has its own setter definitions
+	openapi.ResetOpenAPI()
+	refs, err := settersutil.SetterReferences(openAPIFile, pkg, name)
+	if err != nil {
+		return err
+	}
+	l := setters2.List{Name: name}
+	if err := l.ListSetters(openAPIFile, pkg); err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(dir, pkg)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	for i := range refs {
+		ref := setterRef{Package: rel, SetterReference: refs[i], Current: refs[i].Value}
+		if refs[i].ListValues != nil {
+			ref.Current = refs[i].ListValues
+		}
+		if len(l.Setters) > 0 {
+			ref.Drift = drifted(refs[i], l.Setters[0])
+		}
+		r.Refs = append(r.Refs, ref)
+	}
+	return nil
+}
+
+// drifted returns true if the field ref references the setter s directly, and
+// its value differs from the value the setter sets -- mapped by its enumValues.
+func drifted(ref settersutil.SetterReference, s setters2.SetterDefinition) bool {
+	if ref.Substitution != "" {
+		return false
+	}
+	if ref.ListValues != nil {
+		return !reflect.DeepEqual(ref.ListValues, []string(s.ListValues))
+	}
+	if v, found := s.EnumValues[s.Value]; found {
+		return ref.Value != v
+	}
+	return ref.Value != s.Value
+}
+
+func (r *ListSetterRefsRunner) print(c *cobra.Command, name string) error {
+	if r.Output == "json" {
+		refs := r.Refs
+		if refs == nil {
+			refs = []setterRef{}
+		}
+		b, err := json.MarshalIndent(refs, "", "  ")
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		fmt.Fprintf(c.OutOrStdout(), "%s\n", b)
+		return nil
+	}
+
+	if len(r.Refs) == 0 {
+		fmt.Fprintf(c.OutOrStdout(), "no fields reference setter %s\n", name)
+		return nil
+	}
+	table := newTable(c.OutOrStdout(), false)
+	table.SetHeader([]string{"PACKAGE", "FILE", "RESOURCE", "FIELD", "VALUE", "SUBSTITUTION", "DRIFT"})
+	var drift int
+	for _, ref := range r.Refs {
+		value := ref.Value
+		if ref.ListValues != nil {
+			value = "[" + strings.Join(ref.ListValues, ",") + "]"
+		}
+		var d string
+		if ref.Drift {
+			d = "yes"
+			drift++
+		}
+		table.Append([]string{
+			ref.Package, ref.File, ref.Kind + "/" + ref.Name, ref.Field, value, ref.Substitution, d})
+	}
+	table.Render()
+	if drift > 0 {
+		fmt.Fprintf(c.OutOrStdout(), "%d of %d fields are out of sync with setter %s\n",
+			drift, len(r.Refs), name)
+	}
+	return nil
+}
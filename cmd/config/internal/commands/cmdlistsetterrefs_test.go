@@ -0,0 +1,161 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/cmd/config/internal/commands"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+)
+
+func TestListSetterRefsCommand(t *testing.T) {
+	d, err := ioutil.TempDir("", "kustomize-list-setter-refs-test")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(d)
+	defer openapi.ResetOpenAPI()
+
+	files := map[string]string{
+		"Krmfile": `apiVersion: v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+    io.k8s.cli.setters.args:
+      type: array
+      x-k8s-cli:
+        setter:
+          name: args
+          listValues:
+          - a
+          - b
+`,
+		"deploy.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: frontend
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+  args: # {"$openapi":"args"}
+  - a
+  - b
+`,
+		// edited by hand
+		"worker.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: worker
+spec:
+  replicas: 5 # {"$openapi":"replicas"}
+  args: # {"$openapi":"args"}
+  - a
+`,
+	}
+	for name, content := range files {
+		if !assert.NoError(t, ioutil.WriteFile(filepath.Join(d, name), []byte(content), 0600)) {
+			t.FailNow()
+		}
+	}
+
+	r := commands.NewListSetterRefsRunner("")
+	out := &bytes.Buffer{}
+	r.Command.SetOut(out)
+	r.Command.SetArgs([]string{d, "replicas", "--output", "json"})
+	if !assert.NoError(t, r.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, strings.TrimSpace(`
+[
+  {
+    "package": ".",
+    "file": "deploy.yaml",
+    "kind": "Deployment",
+    "name": "frontend",
+    "field": "spec.replicas",
+    "value": "3",
+    "drift": false
+  },
+  {
+    "package": ".",
+    "file": "worker.yaml",
+    "kind": "Deployment",
+    "name": "worker",
+    "field": "spec.replicas",
+    "value": "5",
+    "drift": true
+  }
+]`), strings.TrimSpace(out.String()))
+
+	// list fields
+	openapi.ResetOpenAPI()
+	r = commands.NewListSetterRefsRunner("")
+	out = &bytes.Buffer{}
+	r.Command.SetOut(out)
+	r.Command.SetArgs([]string{d, "args", "--output", "json"})
+	if !assert.NoError(t, r.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, strings.TrimSpace(`
+[
+  {
+    "package": ".",
+    "file": "deploy.yaml",
+    "kind": "Deployment",
+    "name": "frontend",
+    "field": "spec.args",
+    "value": [
+      "a",
+      "b"
+    ],
+    "drift": false
+  },
+  {
+    "package": ".",
+    "file": "worker.yaml",
+    "kind": "Deployment",
+    "name": "worker",
+    "field": "spec.args",
+    "value": [
+      "a"
+    ],
+    "drift": true
+  }
+]`), strings.TrimSpace(out.String()))
+
+	// the table counts the fields which are out of sync
+	openapi.ResetOpenAPI()
+	r = commands.NewListSetterRefsRunner("")
+	out = &bytes.Buffer{}
+	r.Command.SetOut(out)
+	r.Command.SetArgs([]string{d, "replicas"})
+	if !assert.NoError(t, r.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Contains(t, out.String(), "spec.replicas")
+	assert.True(t, strings.HasSuffix(out.String(),
+		"1 of 2 fields are out of sync with setter replicas\n"), out.String())
+
+	// no fields reference the setter
+	openapi.ResetOpenAPI()
+	r = commands.NewListSetterRefsRunner("")
+	out = &bytes.Buffer{}
+	r.Command.SetOut(out)
+	r.Command.SetArgs([]string{d, "image"})
+	if !assert.NoError(t, r.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, "no fields reference setter image\n", out.String())
+}
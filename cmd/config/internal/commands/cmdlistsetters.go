@@ -4,6 +4,7 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -11,11 +12,12 @@ import (
 
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
-	"sigs.k8s.io/kustomize/cmd/config/ext"
 	"sigs.k8s.io/kustomize/cmd/config/internal/generateddocs/commands"
+	"sigs.k8s.io/kustomize/kyaml/errors"
 	"sigs.k8s.io/kustomize/kyaml/fieldmeta"
 	"sigs.k8s.io/kustomize/kyaml/setters"
 	"sigs.k8s.io/kustomize/kyaml/setters2"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
 // NewListSettersRunner returns a command runner.
@@ -32,6 +34,12 @@ func NewListSettersRunner(parent string) *ListSettersRunner {
 	}
 	c.Flags().BoolVar(&r.Markdown, "markdown", false,
 		"output as github markdown")
+	c.Flags().BoolVar(&r.Unused, "unused", false,
+		"only list the setters which no field references.")
+	c.Flags().StringVar(&r.Output, "output", "table",
+		"output format -- one of table, json or yaml.")
+	c.Flags().StringVar(&r.OpenAPIPath, "openapi-path", "",
+		"read the setter definitions from this file rather than the Krmfile of DIR -- e.g. a central file shared by several packages.")
 	fixDocs(parent, c)
 	r.Command = c
 	return r
@@ -46,40 +54,117 @@ type ListSettersRunner struct {
 	Lookup   setters.LookupSetters
 	List     setters2.List
 	Markdown bool
+
+	// Output is the output format -- table, json or yaml.
+	Output string
+
+	// Unused only lists the setters which no field references.
+	Unused bool
+
+	// OpenAPIPath is the file holding the setter definitions, if not the
+	// Krmfile of the package.
+	OpenAPIPath string
+}
+
+// listedSetter is the json and yaml output for a setter.  Its fields are
+// always present so that scripts may rely on them.
+type listedSetter struct {
+	Name        string   `json:"name" yaml:"name"`
+	Value       string   `json:"value" yaml:"value"`
+	ListValues  []string `json:"listValues" yaml:"listValues"`
+	SetBy       string   `json:"setBy" yaml:"setBy"`
+	Description string   `json:"description" yaml:"description"`
+	Type        string   `json:"type" yaml:"type"`
+	Count       int      `json:"count" yaml:"count"`
 }
 
 func (r *ListSettersRunner) preRunE(c *cobra.Command, args []string) error {
+	switch r.Output {
+	case "table":
+	case "json", "yaml":
+		if r.Markdown {
+			return errors.Errorf("--markdown may only be specified with --output table")
+		}
+		// structured output is only supported by setters created with create-setter
+		setterVersion = "v2"
+	default:
+		return errors.Errorf("--output must be one of table, json or yaml, was %s", r.Output)
+	}
+	if r.Unused {
+		// references are only counted for setters created with create-setter
+		setterVersion = "v2"
+	}
+
 	if len(args) > 1 {
 		r.Lookup.Name = args[1]
 		r.List.Name = args[1]
 	}
 
+	if r.OpenAPIPath != "" {
+		setterVersion = "v2"
+		return nil
+	}
 	initSetterVersion(c, args)
 	return nil
 }
 
 func (r *ListSettersRunner) runE(c *cobra.Command, args []string) error {
+	if r.Output != "table" {
+		return handleError(c, r.printSetters(c, args))
+	}
 	if setterVersion == "v2" {
 		if err := r.ListSetters(c, args); err != nil {
 			return err
 		}
+		if r.Unused {
+			return nil
+		}
 		return r.ListSubstitutions(c, args)
 	}
 
 	return handleError(c, lookup(r.Lookup, c, args))
 }
 
-func (r *ListSettersRunner) ListSetters(c *cobra.Command, args []string) error {
-	// use setters v2
-	path, err := ext.GetOpenAPIFile(args)
+// listSetters initializes r.List.Setters with the setters of the package,
+// and the number of fields referencing each.  With --unused only the setters
+// which no field references are kept.
+func (r *ListSettersRunner) listSetters(args []string) error {
+	path, err := getOpenAPIFile(args, false, r.OpenAPIPath)
 	if err != nil {
 		return err
 	}
 	if err := r.List.ListSetters(path, args[0]); err != nil {
 		return err
 	}
+	if !r.Unused {
+		return nil
+	}
+	var unused []setters2.SetterDefinition
+	for _, s := range r.List.Setters {
+		if s.Count == 0 {
+			unused = append(unused, s)
+		}
+	}
+	r.List.Setters = unused
+	return nil
+}
+
+func (r *ListSettersRunner) ListSetters(c *cobra.Command, args []string) error {
+	// use setters v2
+	if err := r.listSetters(args); err != nil {
+		return err
+	}
+	// only list the allowed values if some setter restricts them
+	var enum bool
+	for i := range r.List.Setters {
+		enum = enum || len(r.List.Setters[i].Enum) > 0
+	}
 	table := newTable(c.OutOrStdout(), r.Markdown)
-	table.SetHeader([]string{"NAME", "VALUE", "SET BY", "DESCRIPTION", "COUNT"})
+	header := []string{"NAME", "VALUE", "SET BY", "DESCRIPTION", "COUNT"}
+	if enum {
+		header = append(header, "ALLOWED VALUES")
+	}
+	table.SetHeader(header)
 	for i := range r.List.Setters {
 		s := r.List.Setters[i]
 		v := s.Value
@@ -89,8 +174,16 @@ func (r *ListSettersRunner) ListSetters(c *cobra.Command, args []string) error {
 			v = strings.Join(s.ListValues, ",")
 			v = fmt.Sprintf("[%s]", v)
 		}
-		table.Append([]string{
-			s.Name, v, s.SetBy, s.Description, fmt.Sprintf("%d", s.Count)})
+		count := fmt.Sprintf("%d", s.Count)
+		if s.Count == 0 {
+			// flag setters which are defined but not wired to any field
+			count += " (unused)"
+		}
+		row := []string{s.Name, v, s.SetBy, s.Description, count}
+		if enum {
+			row = append(row, strings.Join(s.Enum, ","))
+		}
+		table.Append(row)
 	}
 	table.Render()
 
@@ -103,9 +196,42 @@ func (r *ListSettersRunner) ListSetters(c *cobra.Command, args []string) error {
 	return nil
 }
 
+// printSetters prints the setters as a json or yaml list.
+func (r *ListSettersRunner) printSetters(c *cobra.Command, args []string) error {
+	if err := r.listSetters(args); err != nil {
+		return err
+	}
+	setters := []listedSetter{}
+	for _, s := range r.List.Setters {
+		ls := listedSetter{
+			Name:        s.Name,
+			Value:       s.Value,
+			ListValues:  s.ListValues,
+			SetBy:       s.SetBy,
+			Description: s.Description,
+			Type:        s.Type,
+			Count:       s.Count,
+		}
+		if ls.ListValues == nil {
+			ls.ListValues = []string{}
+		}
+		setters = append(setters, ls)
+	}
+
+	if r.Output == "yaml" {
+		return errors.Wrap(yaml.NewEncoder(c.OutOrStdout()).Encode(setters))
+	}
+	b, err := json.MarshalIndent(setters, "", "  ")
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	fmt.Fprintf(c.OutOrStdout(), "%s\n", b)
+	return nil
+}
+
 func (r *ListSettersRunner) ListSubstitutions(c *cobra.Command, args []string) error {
 	// use setters v2
-	path, err := ext.GetOpenAPIFile(args)
+	path, err := getOpenAPIFile(args, false, r.OpenAPIPath)
 	if err != nil {
 		return err
 	}
@@ -46,6 +46,142 @@ spec:
  `,
 			expected: `    NAME     VALUE   SET BY   DESCRIPTION   COUNT  
   replicas   3       me       hello world   1      
+`,
+		},
+		{
+			name: "list-json",
+			openapi: `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.image:
+      x-k8s-cli:
+        setter:
+          name: image
+          value: nginx
+    io.k8s.cli.setters.replicas:
+      type: integer
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+          setBy: me
+      description: "hello world"
+ `,
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 3 # {"$ref": "#/definitions/io.k8s.cli.setters.replicas"}
+ `,
+			args: []string{"--output", "json"},
+			expected: `[
+  {
+    "name": "image",
+    "value": "nginx",
+    "listValues": [],
+    "setBy": "",
+    "description": "",
+    "type": "",
+    "count": 0
+  },
+  {
+    "name": "replicas",
+    "value": "3",
+    "listValues": [],
+    "setBy": "me",
+    "description": "hello world",
+    "type": "integer",
+    "count": 1
+  }
+]
+`,
+		},
+		{
+			name: "list-yaml",
+			openapi: `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.env:
+      type: string
+      x-k8s-cli:
+        setter:
+          name: env
+          value: dev
+          setBy: me
+ `,
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+  namespace: dev # {"$ref": "#/definitions/io.k8s.cli.setters.env"}
+ `,
+			args: []string{"--output", "yaml"},
+			expected: `- name: env
+  value: dev
+  listValues: []
+  setBy: me
+  description: ""
+  type: string
+  count: 1
+`,
+		},
+		{
+			name: "list-unused",
+			openapi: `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.image:
+      x-k8s-cli:
+        setter:
+          name: image
+          value: nginx
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+ `,
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+ `,
+			args: []string{"--unused"},
+			expected: `  NAME    VALUE   SET BY   DESCRIPTION     COUNT     
+  image   nginx                          0 (unused)  
+`,
+		},
+		{
+			name: "list-enum",
+			openapi: `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.env:
+      enum:
+      - dev
+      - staging
+      - prod
+      x-k8s-cli:
+        setter:
+          name: env
+          value: dev
+ `,
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+  labels:
+    env: dev # {"$ref": "#/definitions/io.k8s.cli.setters.env"}
+ `,
+			expected: `  NAME   VALUE   SET BY   DESCRIPTION   COUNT    ALLOWED VALUES   
+  env    dev                            1       dev,staging,prod  
 `,
 		},
 		{
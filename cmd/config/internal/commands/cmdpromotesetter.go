@@ -0,0 +1,65 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/cmd/config/ext"
+	"sigs.k8s.io/kustomize/cmd/config/internal/generateddocs/commands"
+	"sigs.k8s.io/kustomize/kyaml/setters2/settersutil"
+)
+
+// NewPromoteSetterRunner returns a command runner.
+func NewPromoteSetterRunner(parent string) *PromoteSetterRunner {
+	r := &PromoteSetterRunner{}
+	c := &cobra.Command{
+		Use:     "promote-setter DIR SUBSTITUTION MARKER",
+		Args:    cobra.ExactArgs(3),
+		Short:   commands.PromoteSetterShort,
+		Long:    commands.PromoteSetterLong,
+		Example: commands.PromoteSetterExamples,
+		RunE:    r.runE,
+	}
+	fixDocs(parent, c)
+	c.Flags().StringVar(&r.Promote.Name, "name", "",
+		"name of the setter.  defaults to the marker without its delimiters -- e.g. tag for '${tag}'.")
+	c.Flags().StringVar(&r.Promote.Value, "value", "",
+		"value of the setter if it is created.  defaults to the value of the setter the marker references.")
+	c.Flags().StringVar(&r.Promote.Description, "description", "",
+		"description of the setter if it is created.")
+	c.Flags().StringVar(&r.Promote.Type, "type", "",
+		"OpenAPI field type of the setter if it is created -- e.g. integer,boolean,string.")
+	r.Command = c
+	return r
+}
+
+func PromoteSetterCommand(parent string) *cobra.Command {
+	return NewPromoteSetterRunner(parent).Command
+}
+
+type PromoteSetterRunner struct {
+	Command *cobra.Command
+	Promote settersutil.SetterPromoter
+}
+
+func (r *PromoteSetterRunner) runE(c *cobra.Command, args []string) error {
+	openAPIFile, err := ext.GetOpenAPIFile(args)
+	if err != nil {
+		return handleError(c, err)
+	}
+	r.Promote.Substitution = args[1]
+	r.Promote.Marker = args[2]
+	if err := r.Promote.Promote(openAPIFile); err != nil {
+		return handleError(c, err)
+	}
+	if r.Promote.Created {
+		fmt.Fprintf(c.OutOrStdout(), "created setter %s with value %q\n",
+			r.Promote.Name, r.Promote.Value)
+	}
+	fmt.Fprintf(c.OutOrStdout(), "substitution %s marker %s references setter %s\n",
+		args[1], args[2], r.Promote.Name)
+	return nil
+}
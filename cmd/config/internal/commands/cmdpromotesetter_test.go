@@ -0,0 +1,99 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/cmd/config/internal/commands"
+)
+
+func TestPromoteSetterCommand(t *testing.T) {
+	d, err := ioutil.TempDir("", "kustomize-promote-setter-test")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(d)
+
+	err = ioutil.WriteFile(filepath.Join(d, "Krmfile"), []byte(`apiVersion: v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.repo:
+      x-k8s-cli:
+        setter:
+          name: repo
+          value: nginx
+    io.k8s.cli.setters.version:
+      x-k8s-cli:
+        setter:
+          name: version
+          value: 1.7.9
+    io.k8s.cli.substitutions.image:
+      x-k8s-cli:
+        substitution:
+          name: image
+          pattern: ${repo}:${tag}
+          values:
+          - marker: ${repo}
+            ref: '#/definitions/io.k8s.cli.setters.repo'
+          - marker: ${tag}
+            ref: '#/definitions/io.k8s.cli.setters.version'
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	r := commands.NewPromoteSetterRunner("")
+	out := &bytes.Buffer{}
+	r.Command.SetOut(out)
+	r.Command.SetArgs([]string{d, "image", "${tag}", "--description", "release tag"})
+	if !assert.NoError(t, r.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, `created setter tag with value "1.7.9"
+substitution image marker ${tag} references setter tag
+`, out.String())
+
+	actual, err := ioutil.ReadFile(filepath.Join(d, "Krmfile"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `apiVersion: v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.repo:
+      x-k8s-cli:
+        setter:
+          name: repo
+          value: nginx
+    io.k8s.cli.setters.version:
+      x-k8s-cli:
+        setter:
+          name: version
+          value: 1.7.9
+    io.k8s.cli.substitutions.image:
+      x-k8s-cli:
+        substitution:
+          name: image
+          pattern: ${repo}:${tag}
+          values:
+          - marker: ${repo}
+            ref: '#/definitions/io.k8s.cli.setters.repo'
+          - marker: ${tag}
+            ref: '#/definitions/io.k8s.cli.setters.tag'
+    io.k8s.cli.setters.tag:
+      description: release tag
+      x-k8s-cli:
+        setter:
+          name: tag
+          value: 1.7.9
+`, string(actual))
+}
@@ -0,0 +1,80 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/cmd/config/ext"
+	"sigs.k8s.io/kustomize/cmd/config/internal/generateddocs/commands"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/kio/kioutil"
+	"sigs.k8s.io/kustomize/kyaml/krmfile"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+	"sigs.k8s.io/kustomize/kyaml/setters2"
+)
+
+// NewPruneRunner returns a command runner.
+func NewPruneRunner(parent string) *PruneRunner {
+	r := &PruneRunner{}
+	c := &cobra.Command{
+		Use:     "prune DIR",
+		Args:    cobra.ExactArgs(1),
+		Short:   commands.PruneShort,
+		Long:    commands.PruneLong,
+		Example: commands.PruneExamples,
+		RunE:    r.runE,
+	}
+	fixDocs(parent, c)
+	c.Flags().StringVar(&r.OutputDest, "dest", "",
+		"if specified, write output to a file rather than stdout")
+	r.Command = c
+	return r
+}
+
+func PruneCommand(parent string) *cobra.Command {
+	return NewPruneRunner(parent).Command
+}
+
+type PruneRunner struct {
+	Command *cobra.Command
+
+	// OutputDest is the file to write the Resources to, if not stdout.
+	OutputDest string
+}
+
+func (r *PruneRunner) runE(c *cobra.Command, args []string) error {
+	openAPIFile, err := ext.GetOpenAPIFile(args)
+	if err != nil {
+		return handleError(c, err)
+	}
+	if _, err := os.Stat(openAPIFile); err == nil {
+		if err := openapi.AddSchemaFromFile(openAPIFile); err != nil {
+			return handleError(c, err)
+		}
+	}
+
+	out := c.OutOrStdout()
+	if r.OutputDest != "" {
+		o, err := os.Create(r.OutputDest)
+		if err != nil {
+			return handleError(c, errors.Wrap(err))
+		}
+		defer o.Close()
+		out = o
+	}
+	return handleError(c, kio.Pipeline{
+		Inputs: []kio.Reader{kio.LocalPackageReader{
+			PackagePath:     args[0],
+			PackageFileName: krmfile.KrmfileName,
+		}},
+		Filters: []kio.Filter{&setters2.Prune{}},
+		Outputs: []kio.Writer{kio.ByteWriter{
+			Writer:           out,
+			ClearAnnotations: []string{kioutil.PathAnnotation},
+		}},
+	}.Execute())
+}
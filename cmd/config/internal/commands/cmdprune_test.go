@@ -0,0 +1,123 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/cmd/config/internal/commands"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+)
+
+func TestPruneCommand(t *testing.T) {
+	var tests = []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{
+			name:  "excluded",
+			value: "false",
+			expected: `apiVersion: networking.k8s.io/v1beta1
+kind: Ingress
+metadata:
+  name: web
+spec:
+  rules:
+  - host: example.com
+`,
+		},
+		{
+			name:  "included",
+			value: "true",
+			expected: `apiVersion: networking.k8s.io/v1beta1
+kind: Ingress
+metadata:
+  name: web
+spec:
+  tls: # {"$openapi-include":"tls_enabled"}
+  - secretName: tls
+  rules:
+  - host: example.com
+---
+# {"$openapi-include":"tls_enabled"}
+apiVersion: v1
+kind: Secret
+metadata:
+  name: tls
+`,
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			// reset the openAPI afterward
+			openapi.ResetOpenAPI()
+			defer openapi.ResetOpenAPI()
+
+			d, err := ioutil.TempDir("", "kustomize-prune-test")
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			defer os.RemoveAll(d)
+			files := map[string]string{
+				"Krmfile": `apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.tls_enabled:
+      x-k8s-cli:
+        setter:
+          name: tls_enabled
+          value: "` + test.value + `"
+`,
+				"ingress.yaml": `apiVersion: networking.k8s.io/v1beta1
+kind: Ingress
+metadata:
+  name: web
+spec:
+  tls: # {"$openapi-include":"tls_enabled"}
+  - secretName: tls
+  rules:
+  - host: example.com
+`,
+				"secret.yaml": `# {"$openapi-include":"tls_enabled"}
+apiVersion: v1
+kind: Secret
+metadata:
+  name: tls
+`,
+			}
+			for name, data := range files {
+				err := ioutil.WriteFile(filepath.Join(d, name), []byte(data), 0600)
+				if !assert.NoError(t, err) {
+					t.FailNow()
+				}
+			}
+
+			out := &bytes.Buffer{}
+			r := commands.NewPruneRunner("")
+			r.Command.SetOut(out)
+			r.Command.SetArgs([]string{d})
+			if !assert.NoError(t, r.Command.Execute()) {
+				t.FailNow()
+			}
+			assert.Equal(t, test.expected, out.String())
+
+			// the package is left unchanged
+			for name, data := range files {
+				actual, err := ioutil.ReadFile(filepath.Join(d, name))
+				if !assert.NoError(t, err) {
+					t.FailNow()
+				}
+				assert.Equal(t, data, string(actual))
+			}
+		})
+	}
+}
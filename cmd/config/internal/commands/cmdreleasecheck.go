@@ -0,0 +1,175 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/cmd/config/ext"
+	"sigs.k8s.io/kustomize/cmd/config/internal/generateddocs/commands"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+	"sigs.k8s.io/kustomize/kyaml/setters2"
+)
+
+// NewReleaseCheckRunner returns a command runner.
+func NewReleaseCheckRunner(parent string) *ReleaseCheckRunner {
+	r := &ReleaseCheckRunner{}
+	c := &cobra.Command{
+		Use:     "release-check DIR",
+		Args:    cobra.ExactArgs(1),
+		Short:   commands.ReleaseCheckShort,
+		Long:    commands.ReleaseCheckLong,
+		Example: commands.ReleaseCheckExamples,
+		PreRunE: r.preRunE,
+		RunE:    r.runE,
+	}
+	fixDocs(parent, c)
+	c.Flags().StringVar(&r.Output, "output", "table",
+		"output format -- one of table or json.")
+	r.Command = c
+	return r
+}
+
+func ReleaseCheckCommand(parent string) *cobra.Command {
+	return NewReleaseCheckRunner(parent).Command
+}
+
+type ReleaseCheckRunner struct {
+	Command *cobra.Command
+
+	// Output is the output format -- table or json.
+	Output string
+
+	// Packages is the number of packages checked.
+	Packages int
+
+	// Unfilled are the setters which must be set before a release.
+	Unfilled []unfilledSetter
+}
+
+// unfilledSetter is a setter which hasn't been set, and the json output for it.
+type unfilledSetter struct {
+	Package string `json:"package"`
+	Name    string `json:"name"`
+	Value   string `json:"value,omitempty"`
+	Reason  string `json:"reason"`
+}
+
+const (
+	// reasonNoValue is the reason of a required setter without a value.
+	reasonNoValue = "required, no value"
+
+	// reasonPlaceholder is the reason of a setter holding its default.
+	reasonPlaceholder = "placeholder value"
+)
+
+// unfilledReason returns why the setter must be set before the package is
+// released -- it is required and has no value, or it still holds its default
+// placeholder -- or "" if it needn't be.
+func unfilledReason(s setters2.SetterDefinition) string {
+	if len(s.ListValues) > 0 {
+		return ""
+	}
+	switch {
+	case s.Value == "" && s.Required:
+		return reasonNoValue
+	case s.Value != "" && s.Value == s.Default:
+		return reasonPlaceholder
+	}
+	return ""
+}
+
+func (r *ReleaseCheckRunner) preRunE(c *cobra.Command, args []string) error {
+	if r.Output != "table" && r.Output != "json" {
+		return errors.Errorf("--output must be one of table or json, was %s", r.Output)
+	}
+	return nil
+}
+
+func (r *ReleaseCheckRunner) runE(c *cobra.Command, args []string) error {
+	if err := r.check(args[0]); err != nil {
+		return handleError(c, err)
+	}
+	if err := r.print(c); err != nil {
+		return handleError(c, err)
+	}
+	if len(r.Unfilled) > 0 {
+		return handleError(c, errors.Errorf(
+			"%d setters must be set before release", len(r.Unfilled)))
+	}
+	return nil
+}
+
+// check records the unfilled setters of DIR and of each of its subpackages
+// -- directories containing their own Krmfile.
+func (r *ReleaseCheckRunner) check(dir string) error {
+	dirs, err := packageDirs(dir, true)
+	if err != nil {
+		return err
+	}
+	for _, d := range dirs {
+		openAPIFile, err := ext.GetOpenAPIFile([]string{d})
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(openAPIFile); err != nil {
+			if os.IsNotExist(err) {
+				// not a package -- it defines no setters
+				continue
+			}
+			return errors.Wrap(err)
+		}
+		rel, err := filepath.Rel(dir, d)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+
+		// each package has its own setter definitions
+		openapi.ResetOpenAPI()
+		l := setters2.List{}
+		if err := l.ListSetters(openAPIFile, d); err != nil {
+			return errors.WrapPrefixf(err, d)
+		}
+		r.Packages++
+		for _, s := range l.Setters {
+			if reason := unfilledReason(s); reason != "" {
+				r.Unfilled = append(r.Unfilled, unfilledSetter{
+					Package: rel, Name: s.Name, Value: s.Value, Reason: reason})
+			}
+		}
+	}
+	return nil
+}
+
+func (r *ReleaseCheckRunner) print(c *cobra.Command) error {
+	if r.Output == "json" {
+		unfilled := r.Unfilled
+		if unfilled == nil {
+			unfilled = []unfilledSetter{}
+		}
+		b, err := json.MarshalIndent(unfilled, "", "  ")
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		fmt.Fprintf(c.OutOrStdout(), "%s\n", b)
+		return nil
+	}
+
+	if len(r.Unfilled) == 0 {
+		fmt.Fprintf(c.OutOrStdout(), "all setters are set in %d packages\n", r.Packages)
+		return nil
+	}
+	table := newTable(c.OutOrStdout(), false)
+	table.SetHeader([]string{"PACKAGE", "SETTER", "VALUE", "REASON"})
+	for _, s := range r.Unfilled {
+		table.Append([]string{s.Package, s.Name, s.Value, s.Reason})
+	}
+	table.Render()
+	return nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/cmd/config/internal/commands"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+)
+
+func TestReleaseCheckCommand(t *testing.T) {
+	// reset the openAPI afterward
+	openapi.ResetOpenAPI()
+	defer openapi.ResetOpenAPI()
+
+	d, err := ioutil.TempDir("", "kustomize-release-check-test")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(d)
+
+	// the replicas setter of the package is set, the namespace setter of the
+	// subpackage still holds its placeholder
+	err = ioutil.WriteFile(filepath.Join(d, "Krmfile"), []byte(`apiVersion: v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+          required: true
+          default: "1"
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	err = ioutil.WriteFile(filepath.Join(d, "deploy.yaml"), []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	if !assert.NoError(t, os.Mkdir(filepath.Join(d, "sub"), 0700)) {
+		t.FailNow()
+	}
+	err = ioutil.WriteFile(filepath.Join(d, "sub", "Krmfile"), []byte(`apiVersion: v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.namespace:
+      x-k8s-cli:
+        setter:
+          name: namespace
+          value: NAMESPACE
+          required: true
+          default: NAMESPACE
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	err = ioutil.WriteFile(filepath.Join(d, "sub", "service.yaml"), []byte(`apiVersion: v1
+kind: Service
+metadata:
+  name: app
+  namespace: NAMESPACE # {"$openapi":"namespace"}
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	r := commands.NewReleaseCheckRunner("")
+	out := &bytes.Buffer{}
+	r.Command.SetOut(out)
+	r.Command.SilenceErrors = true
+	r.Command.SilenceUsage = true
+	r.Command.SetArgs([]string{d})
+	err = r.Command.Execute()
+	if assert.Error(t, err) {
+		assert.Equal(t, "1 setters must be set before release", err.Error())
+	}
+	assert.Contains(t, out.String(), "PACKAGE")
+	assert.Contains(t, out.String(), "placeholder value")
+	assert.NotContains(t, out.String(), "replicas")
+
+	r = commands.NewReleaseCheckRunner("")
+	out = &bytes.Buffer{}
+	r.Command.SetOut(out)
+	r.Command.SilenceErrors = true
+	r.Command.SilenceUsage = true
+	r.Command.SetArgs([]string{d, "--output", "json"})
+	err = r.Command.Execute()
+	if assert.Error(t, err) {
+		assert.Equal(t, "1 setters must be set before release", err.Error())
+	}
+	assert.Equal(t, `[
+  {
+    "package": "sub",
+    "name": "namespace",
+    "value": "NAMESPACE",
+    "reason": "placeholder value"
+  }
+]
+`, out.String())
+
+	set := commands.NewSetRunner("")
+	set.Command.SetOut(&bytes.Buffer{})
+	set.Command.SetArgs([]string{filepath.Join(d, "sub"), "namespace", "prod", "--no-set-by"})
+	if !assert.NoError(t, set.Command.Execute()) {
+		t.FailNow()
+	}
+
+	// once set, the check passes
+	r = commands.NewReleaseCheckRunner("")
+	out = &bytes.Buffer{}
+	r.Command.SetOut(out)
+	r.Command.SetArgs([]string{d})
+	if !assert.NoError(t, r.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, "all setters are set in 2 packages\n", out.String())
+}
@@ -0,0 +1,158 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/cmd/config/internal/generateddocs/commands"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// NewRenameResourcesRunner returns a command runner.
+func NewRenameResourcesRunner(parent string) *RenameResourcesRunner {
+	r := &RenameResourcesRunner{}
+	c := &cobra.Command{
+		Use:     "rename-resources DIR",
+		Args:    cobra.ExactArgs(1),
+		Short:   commands.RenameResourcesShort,
+		Long:    commands.RenameResourcesLong,
+		Example: commands.RenameResourcesExamples,
+		PreRunE: r.preRunE,
+		RunE:    r.runE,
+	}
+	fixDocs(parent, c)
+	c.Flags().StringVar(&r.Match, "match", "",
+		"regular expression matching the names of the Resources to rename.")
+	c.Flags().StringVar(&r.Replace, "replace", "",
+		"replacement for matching names.  may contain capture group references -- e.g. 'new-$1'.")
+	_ = c.MarkFlagRequired("match")
+	_ = c.MarkFlagRequired("replace")
+	r.Command = c
+	return r
+}
+
+func RenameResourcesCommand(parent string) *cobra.Command {
+	return NewRenameResourcesRunner(parent).Command
+}
+
+type RenameResourcesRunner struct {
+	Command *cobra.Command
+
+	// Match is the regular expression matched against Resource names.
+	Match string
+
+	// Replace is the replacement for matching names.
+	Replace string
+
+	// Renamed is the number of Resources which were renamed.
+	Renamed int
+
+	// References is the number of name references which were updated.
+	References int
+
+	// Warnings contains the name references which matched Match, but could
+	// not be resolved to a Resource in the package.
+	Warnings []string
+
+	match *regexp.Regexp
+}
+
+func (r *RenameResourcesRunner) preRunE(c *cobra.Command, args []string) error {
+	var err error
+	r.match, err = regexp.Compile(r.Match)
+	if err != nil {
+		return errors.WrapPrefixf(err, "invalid --match")
+	}
+	return nil
+}
+
+func (r *RenameResourcesRunner) runE(c *cobra.Command, args []string) error {
+	rw := &kio.LocalPackageReadWriter{PackagePath: args[0], NoDeleteFiles: true}
+	err := kio.Pipeline{
+		Inputs:  []kio.Reader{rw},
+		Filters: []kio.Filter{r},
+		Outputs: []kio.Writer{rw},
+	}.Execute()
+	if err != nil {
+		return handleError(c, err)
+	}
+	for i := range r.Warnings {
+		fmt.Fprintf(c.ErrOrStderr(), "warning: %s\n", r.Warnings[i])
+	}
+	fmt.Fprintf(c.OutOrStdout(), "renamed %d resources, updated %d references\n",
+		r.Renamed, r.References)
+	return nil
+}
+
+// Filter renames the matching Resources and updates the name references to them.
+func (r *RenameResourcesRunner) Filter(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
+	if r.match == nil {
+		var err error
+		if r.match, err = regexp.Compile(r.Match); err != nil {
+			return nil, errors.Wrap(err)
+		}
+	}
+
+	// rename the Resources, recording the new names by kind and namespace
+	// so references may be updated
+	renamed := map[string]string{}
+	for i := range nodes {
+		meta, err := nodes[i].GetMeta()
+		if err != nil {
+			return nil, err
+		}
+		if !r.match.MatchString(meta.Name) {
+			continue
+		}
+		name, err := nodes[i].Pipe(yaml.Lookup(yaml.MetadataField, yaml.NameField))
+		if err != nil {
+			return nil, err
+		}
+		newName := r.match.ReplaceAllString(meta.Name, r.Replace)
+		name.YNode().Value = newName
+		renamed[renamedKey(meta.Kind, meta.Namespace, meta.Name)] = newName
+		r.Renamed++
+	}
+
+	// update the references to the renamed Resources
+	for i := range nodes {
+		meta, err := nodes[i].GetMeta()
+		if err != nil {
+			return nil, err
+		}
+		err = visitNameReferences(nodes[i], func(kind string, field *yaml.RNode, p string) error {
+			value := field.YNode().Value
+			// references are resolved within the namespace of the referring
+			// Resource, falling back to cluster-scoped Resources
+			newName, found := renamed[renamedKey(kind, meta.Namespace, value)]
+			if !found {
+				newName, found = renamed[renamedKey(kind, "", value)]
+			}
+			if found {
+				field.YNode().Value = newName
+				r.References++
+				return nil
+			}
+			if r.match.MatchString(value) {
+				r.Warnings = append(r.Warnings, fmt.Sprintf(
+					"unable to resolve reference to %s %s from %s %s field %s",
+					kind, value, meta.Kind, meta.Name, p))
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+func renamedKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
@@ -0,0 +1,260 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/cmd/config/internal/commands"
+)
+
+func TestRenameResourcesCommand(t *testing.T) {
+	var tests = []struct {
+		name             string
+		args             []string
+		input            string
+		expected         string
+		expectedOut      string
+		expectedWarnings string
+	}{
+		{
+			name: "rename with capture groups",
+			args: []string{"--match", "^old-(.*)$", "--replace", "new-$1"},
+			input: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: old-config
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: old-secret
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        envFrom:
+        - configMapRef:
+            name: old-config
+        env:
+        - name: PASSWORD
+          valueFrom:
+            secretKeyRef:
+              name: old-secret
+              key: password
+      volumes:
+      - name: config
+        configMap:
+          name: old-config
+`,
+			expected: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: new-config
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: new-secret
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        envFrom:
+        - configMapRef:
+            name: new-config
+        env:
+        - name: PASSWORD
+          valueFrom:
+            secretKeyRef:
+              name: new-secret
+              key: password
+      volumes:
+      - name: config
+        configMap:
+          name: new-config
+`,
+			expectedOut: "renamed 2 resources, updated 3 references\n",
+		},
+		{
+			name: "warn on unresolved references",
+			args: []string{"--match", "^old-(.*)$", "--replace", "new-$1"},
+			input: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: old-config
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      serviceAccountName: old-account
+      containers:
+      - name: app
+        envFrom:
+        - configMapRef:
+            name: old-config
+`,
+			expected: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: new-config
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      serviceAccountName: old-account
+      containers:
+      - name: app
+        envFrom:
+        - configMapRef:
+            name: new-config
+`,
+			expectedOut: "renamed 1 resources, updated 1 references\n",
+			expectedWarnings: "warning: unable to resolve reference to ServiceAccount old-account " +
+				"from Deployment app field spec.template.spec.serviceAccountName\n",
+		},
+		{
+			name: "resolve references within their namespace",
+			args: []string{"--match", "^old-(.*)$", "--replace", "new-$1"},
+			input: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: old-config
+  namespace: a
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: a
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        envFrom:
+        - configMapRef:
+            name: old-config
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: b
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        envFrom:
+        - configMapRef:
+            name: old-config
+`,
+			expected: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: new-config
+  namespace: a
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: a
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        envFrom:
+        - configMapRef:
+            name: new-config
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: b
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        envFrom:
+        - configMapRef:
+            name: old-config
+`,
+			expectedOut: "renamed 1 resources, updated 1 references\n",
+			expectedWarnings: "warning: unable to resolve reference to ConfigMap old-config " +
+				"from Deployment app field spec.template.spec.containers.envFrom.configMapRef.name\n",
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			d, err := ioutil.TempDir("", "kustomize-rename-test")
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			defer os.RemoveAll(d)
+
+			err = ioutil.WriteFile(filepath.Join(d, "resources.yaml"), []byte(test.input), 0600)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+
+			r := commands.NewRenameResourcesRunner("")
+			out := &bytes.Buffer{}
+			errOut := &bytes.Buffer{}
+			r.Command.SetOut(out)
+			r.Command.SetErr(errOut)
+			r.Command.SetArgs(append([]string{d}, test.args...))
+			if !assert.NoError(t, r.Command.Execute()) {
+				t.FailNow()
+			}
+			assert.Equal(t, test.expectedOut, out.String())
+			assert.Equal(t, test.expectedWarnings, errOut.String())
+
+			actual, err := ioutil.ReadFile(filepath.Join(d, "resources.yaml"))
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t,
+				strings.TrimSpace(test.expected),
+				strings.TrimSpace(string(actual)))
+		})
+	}
+}
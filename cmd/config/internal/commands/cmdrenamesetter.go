@@ -0,0 +1,114 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/cmd/config/ext"
+	"sigs.k8s.io/kustomize/cmd/config/internal/generateddocs/commands"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/krmfile"
+	"sigs.k8s.io/kustomize/kyaml/setters2"
+	"sigs.k8s.io/kustomize/kyaml/setters2/settersutil"
+)
+
+// NewRenameSetterRunner returns a command runner.
+func NewRenameSetterRunner(parent string) *RenameSetterRunner {
+	r := &RenameSetterRunner{}
+	c := &cobra.Command{
+		Use:     "rename-setter DIR NAME NEW_NAME",
+		Args:    cobra.ExactArgs(3),
+		Short:   commands.RenameSetterShort,
+		Long:    commands.RenameSetterLong,
+		Example: commands.RenameSetterExamples,
+		RunE:    r.runE,
+	}
+	fixDocs(parent, c)
+	c.Flags().BoolVarP(&r.RecurseSubPackages, "recurse-subpackages", "R", false,
+		"also rename the setter in the subpackages of DIR -- directories containing their own Krmfile.")
+	r.Command = c
+	return r
+}
+
+func RenameSetterCommand(parent string) *cobra.Command {
+	return NewRenameSetterRunner(parent).Command
+}
+
+type RenameSetterRunner struct {
+	Command *cobra.Command
+
+	// RecurseSubPackages also renames the setter in the subpackages of DIR.
+	RecurseSubPackages bool
+}
+
+func (r *RenameSetterRunner) runE(c *cobra.Command, args []string) error {
+	return handleError(c, r.rename(c, args))
+}
+
+// rename renames the setter in DIR, and in each of its subpackages which
+// defines it if RecurseSubPackages is set.  The definitions of all of the
+// packages are checked before anything is written.
+func (r *RenameSetterRunner) rename(c *cobra.Command, args []string) error {
+	name, newName := args[1], args[2]
+	if err := setters2.ValidateName(newName); err != nil {
+		return err
+	}
+	dirs, err := packageDirs(args[0], r.RecurseSubPackages)
+	if err != nil {
+		return err
+	}
+	openAPIFiles := map[string]string{}
+	var packages []string
+	for _, dir := range dirs {
+		openAPIFile, err := ext.GetOpenAPIFile([]string{dir})
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(openAPIFile); err != nil {
+			if dir == args[0] && !r.RecurseSubPackages {
+				return errors.Wrap(err)
+			}
+			// not a package -- it defines no setters
+			continue
+		}
+		sr := settersutil.SetterRenamer{Name: name, NewName: newName}
+		if r.RecurseSubPackages {
+			// only the packages defining the setter are changed
+			defined, err := sr.IsDefined(openAPIFile)
+			if err != nil {
+				return errors.WrapPrefixf(err, dir)
+			}
+			if !defined {
+				continue
+			}
+		}
+		if err := sr.Check(openAPIFile); err != nil {
+			return errors.WrapPrefixf(err, dir)
+		}
+		openAPIFiles[dir] = openAPIFile
+		packages = append(packages, dir)
+	}
+
+	if len(packages) == 0 {
+		return errors.Errorf("setter %s is not defined", name)
+	}
+
+	var count int
+	for _, dir := range packages {
+		sr := settersutil.SetterRenamer{Name: name, NewName: newName}
+		if r.RecurseSubPackages {
+			sr.PackageFileName = krmfile.KrmfileName
+		}
+		if err := sr.Rename(openAPIFiles[dir], dir); err != nil {
+			return errors.WrapPrefixf(err, dir)
+		}
+		count += sr.Count
+	}
+	fmt.Fprintf(c.OutOrStdout(), "renamed setter %s to %s: updated %d references in %d packages\n",
+		name, newName, count, len(packages))
+	return nil
+}
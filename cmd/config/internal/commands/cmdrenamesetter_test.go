@@ -0,0 +1,174 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/cmd/config/internal/commands"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+)
+
+func TestRenameSetterCommand(t *testing.T) {
+	krmfile := `apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+`
+	deployment := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+`
+	renamed := map[string]string{
+		"Krmfile": `apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replica-count:
+      x-k8s-cli:
+        setter:
+          name: replica-count
+          value: "3"
+`,
+		"deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3 # {"$openapi":"replica-count"}
+`,
+	}
+	var tests = []struct {
+		name     string
+		args     []string
+		files    map[string]string
+		out      string
+		expected map[string]string
+		err      string
+	}{
+		{
+			name: "rename",
+			args: []string{"replicas", "replica-count"},
+			files: map[string]string{
+				"Krmfile":         krmfile,
+				"deployment.yaml": deployment,
+			},
+			out:      "renamed setter replicas to replica-count: updated 1 references in 1 packages\n",
+			expected: renamed,
+		},
+		{
+			name: "recurse subpackages",
+			args: []string{"replicas", "replica-count", "-R"},
+			files: map[string]string{
+				"Krmfile":             krmfile,
+				"deployment.yaml":     deployment,
+				"app/Krmfile":         krmfile,
+				"app/deployment.yaml": deployment,
+			},
+			out: "renamed setter replicas to replica-count: updated 2 references in 2 packages\n",
+			expected: map[string]string{
+				"Krmfile":             renamed["Krmfile"],
+				"deployment.yaml":     renamed["deployment.yaml"],
+				"app/Krmfile":         renamed["Krmfile"],
+				"app/deployment.yaml": renamed["deployment.yaml"],
+			},
+		},
+		{
+			name: "collision in a subpackage",
+			args: []string{"replicas", "replica-count", "-R"},
+			files: map[string]string{
+				"Krmfile":         krmfile,
+				"deployment.yaml": deployment,
+				"app/Krmfile": krmfile + `    io.k8s.cli.setters.replica-count:
+      x-k8s-cli:
+        setter:
+          name: replica-count
+          value: "1"
+`,
+				"app/deployment.yaml": deployment,
+			},
+			err: "setter replica-count already exists",
+			expected: map[string]string{
+				"Krmfile":             krmfile,
+				"deployment.yaml":     deployment,
+				"app/deployment.yaml": deployment,
+			},
+		},
+		{
+			name: "invalid name",
+			args: []string{"replicas", "replica count"},
+			files: map[string]string{
+				"Krmfile":         krmfile,
+				"deployment.yaml": deployment,
+			},
+			err: `invalid setter name "replica count"`,
+			expected: map[string]string{
+				"Krmfile":         krmfile,
+				"deployment.yaml": deployment,
+			},
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			// reset the openAPI afterward
+			openapi.ResetOpenAPI()
+			defer openapi.ResetOpenAPI()
+
+			d, err := ioutil.TempDir("", "kustomize-rename-setter-test")
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			defer os.RemoveAll(d)
+			for name, data := range test.files {
+				if !assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(d, name)), 0700)) {
+					t.FailNow()
+				}
+				err := ioutil.WriteFile(filepath.Join(d, name), []byte(data), 0600)
+				if !assert.NoError(t, err) {
+					t.FailNow()
+				}
+			}
+
+			out := &bytes.Buffer{}
+			r := commands.NewRenameSetterRunner("")
+			r.Command.SetOut(out)
+			r.Command.SetErr(&bytes.Buffer{})
+			r.Command.SilenceUsage = true
+			r.Command.SetArgs(append([]string{d}, test.args...))
+			err = r.Command.Execute()
+			if test.err != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), test.err)
+				}
+			} else {
+				if !assert.NoError(t, err) {
+					t.FailNow()
+				}
+				assert.Equal(t, test.out, out.String())
+			}
+
+			for name, data := range test.expected {
+				actual, err := ioutil.ReadFile(filepath.Join(d, name))
+				if !assert.NoError(t, err) {
+					t.FailNow()
+				}
+				assert.Equal(t, data, string(actual), name)
+			}
+		})
+	}
+}
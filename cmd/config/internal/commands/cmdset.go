@@ -5,7 +5,9 @@ package commands
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
+	"strings"
 
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
@@ -14,6 +16,7 @@ import (
 	"sigs.k8s.io/kustomize/kyaml/errors"
 	"sigs.k8s.io/kustomize/kyaml/kio"
 	"sigs.k8s.io/kustomize/kyaml/setters"
+	"sigs.k8s.io/kustomize/kyaml/setters2"
 	"sigs.k8s.io/kustomize/kyaml/setters2/settersutil"
 )
 
@@ -22,7 +25,7 @@ func NewSetRunner(parent string) *SetRunner {
 	r := &SetRunner{}
 	c := &cobra.Command{
 		Use:     "set DIR NAME --values [VALUE]",
-		Args:    cobra.MinimumNArgs(2),
+		Args:    r.args,
 		Short:   commands.SetShort,
 		Long:    commands.SetLong,
 		Example: commands.SetExamples,
@@ -34,9 +37,49 @@ func NewSetRunner(parent string) *SetRunner {
 	c.Flags().StringArrayVar(&r.Values, "values", []string{},
 		"optional flag, the values of the setter to be set to")
 	c.Flags().StringVar(&r.Perform.SetBy, "set-by", "",
-		"annotate the field with who set it")
+		"annotate the field with who set it.  defaults to the git user.email, or the current OS user.")
+	c.Flags().BoolVar(&r.NoSetBy, "no-set-by", false,
+		"don't annotate the field with who set it.")
 	c.Flags().StringVar(&r.Perform.Description, "description", "",
 		"annotate the field with a description of its value")
+	c.Flags().BoolVar(&r.Interactive, "interactive", false,
+		"prompt on stdin for the value of each setter which is required or has no value.")
+	c.Flags().StringVar(&r.FromFile, "from-file", "",
+		"set the value to the contents of this file, e.g. a script or certificate.")
+	r.addFetcherFlags(c)
+	c.Flags().StringVar(&r.ValuesFile, "values-file", "",
+		"set each of the setters named in this YAML or JSON file to its value -- a list for list setters.")
+	c.Flags().BoolVar(&r.IgnoreUnknown, "ignore-unknown", false,
+		"with --values-file, skip the names in the file which aren't setters rather than failing.")
+	c.Flags().BoolVar(&r.Unset, "unset", false,
+		"clear the value of the setter, reverting the fields to its default if it has one.")
+	c.Flags().BoolVar(&r.InlineOpenAPI, "inline-openapi", false,
+		"read and write the setter definitions in an '# openapi:' comment block at the top of the file, rather than the Krmfile.")
+	c.Flags().StringVar(&r.OpenAPIPath, "openapi-path", "",
+		"read and write the setter definitions in this file rather than the Krmfile of DIR -- e.g. a central file shared by several packages.")
+	c.Flags().StringVar(&r.Set.Style, "style", "",
+		"write the fields in this style -- one of "+strings.Join(setters2.StyleNames(), ", ")+
+			".  defaults to the style of each field.")
+	c.Flags().StringVar(&r.Set.FieldFilter, "field-filter", "",
+		"only set the fields whose path matches this glob -- e.g. spec.template.**.image.  the setter value is still changed.")
+	c.Flags().StringVar(&r.Path, "path", "",
+		"set the field at this JSON pointer -- e.g. /spec/replicas -- rather than the fields of a setter.")
+	c.Flags().BoolVar(&r.Create, "create", false,
+		"with --path, create the field and its parents if they don't exist.")
+	c.Flags().StringVar(&r.Kind, "kind", "",
+		"with --path, only set the field in Resources of this kind.")
+	c.Flags().StringVar(&r.Name, "name", "",
+		"with --path, only set the field in Resources with this name.")
+	c.Flags().BoolVar(&r.Set.Record, "record", false,
+		"append the change of value, when and by whom it was made, to the history of the setter.")
+	c.Flags().IntVar(&r.Set.MaxHistory, "max-history", 0,
+		"with --record, keep at most this many entries in the history of the setter.  0 keeps all.")
+	c.Flags().BoolVar(&r.DryRun, "dry-run", false,
+		"print the number of fields which would change in each package, without writing.")
+	c.Flags().BoolVar(&r.RecurseSubPackages, "recurse-subpackages", false,
+		"also set the setter on the subpackages of DIR -- directories containing their own Krmfile.")
+	c.Flags().StringVar(&r.Set.OutputDir, "output-dir", "",
+		"write the resources to this directory, at their paths relative to DIR, leaving DIR unchanged.  the setter definitions are still updated.")
 	c.Flags().StringVar(&setterVersion, "version", "",
 		"use this version of the setter format")
 	c.Flags().MarkHidden("version")
@@ -51,12 +94,64 @@ func SetCommand(parent string) *cobra.Command {
 }
 
 type SetRunner struct {
-	Command     *cobra.Command
-	Lookup      setters.LookupSetters
-	Perform     setters.PerformSetters
-	Set         settersutil.FieldSetter
-	OpenAPIFile string
-	Values      []string
+	Command       *cobra.Command
+	Lookup        setters.LookupSetters
+	Perform       setters.PerformSetters
+	Set           settersutil.FieldSetter
+	OpenAPIFile   string
+	Values        []string
+	NoSetBy       bool
+	Interactive   bool
+	Unset         bool
+	FromFile      string
+	InlineOpenAPI bool
+	Path          string
+	Create        bool
+	Kind          string
+	Name          string
+
+	// OpenAPIPath is the file holding the setter definitions, if not the
+	// Krmfile of the package.
+	OpenAPIPath string
+
+	// DryRun reports the fields which would change without writing.
+	DryRun bool
+
+	// RecurseSubPackages also sets the setter on the subpackages of DIR.
+	RecurseSubPackages bool
+
+	// ValuesFile is a file mapping the names of setters to their values.
+	ValuesFile string
+
+	// IgnoreUnknown skips the names in ValuesFile which aren't setters.
+	IgnoreUnknown bool
+
+	pointer []string
+
+	// fileValues are the values read from ValuesFile, keyed by setter name.
+	fileValues map[string][]string
+
+	// fetchRefs are the references given to the --from-<name> flags, keyed
+	// by the name of the backend to fetch them from.
+	fetchRefs map[string]*string
+
+	// globMatches are the files matching the glob given in place of DIR, and
+	// packageDir is the package enclosing them.
+	globMatches []string
+	packageDir  string
+}
+
+func (r *SetRunner) args(c *cobra.Command, args []string) error {
+	if r.Path != "" {
+		return cobra.RangeArgs(1, 2)(c, args)
+	}
+	if r.Interactive || r.ValuesFile != "" {
+		return cobra.ExactArgs(1)(c, args)
+	}
+	if r.Unset {
+		return cobra.ExactArgs(2)(c, args)
+	}
+	return cobra.MinimumNArgs(2)(c, args)
 }
 
 func initSetterVersion(c *cobra.Command, args []string) error {
@@ -78,12 +173,119 @@ func initSetterVersion(c *cobra.Command, args []string) error {
 	return nil
 }
 
+// lookupSetBy returns who set a setter value when --set-by isn't specified,
+// or "" if it can't be looked up -- the default only saves passing --set-by,
+// so it never fails the command.
+func lookupSetBy() string {
+	setBy, err := ext.GetDefaultSetBy()
+	if err != nil {
+		return ""
+	}
+	return setBy
+}
+
 func (r *SetRunner) preRunE(c *cobra.Command, args []string) error {
 	valueFlagSet := c.Flag("values").Changed
 
+	if r.Set.Style != "" {
+		if _, found := setters2.ScalarStyles[r.Set.Style]; !found {
+			return errors.Errorf("--style must be one of %s",
+				strings.Join(setters2.StyleNames(), ", "))
+		}
+		if r.Path != "" {
+			return errors.Errorf("--style may not be specified with --path")
+		}
+	}
+
+	if r.Set.MaxHistory < 0 {
+		return errors.Errorf("--max-history must not be negative")
+	}
+	if c.Flag("max-history").Changed && !r.Set.Record {
+		return errors.Errorf("--max-history may only be specified with --record")
+	}
+	if r.Set.Record && (r.Path != "" || r.Unset) {
+		return errors.Errorf("--record may not be specified with --path or --unset")
+	}
+
+	if r.Set.FieldFilter != "" {
+		if r.Path != "" || r.Interactive || r.ValuesFile != "" || r.Unset {
+			return errors.Errorf(
+				"--field-filter may not be specified with --path, --interactive, --values-file or --unset")
+		}
+		if _, err := setters2.MatchFieldPath(r.Set.FieldFilter, ""); err != nil {
+			return err
+		}
+	}
+
+	if r.Set.OutputDir != "" && (isGlob(args[0]) || r.Path != "" || r.Interactive ||
+		r.ValuesFile != "" || r.Unset || r.DryRun || r.RecurseSubPackages || r.InlineOpenAPI) {
+		return errors.Errorf("--output-dir may not be specified with a glob, --path, --interactive, " +
+			"--values-file, --unset, --dry-run, --recurse-subpackages or --inline-openapi")
+	}
+
+	if r.IgnoreUnknown && r.ValuesFile == "" {
+		return errors.Errorf("--ignore-unknown may only be specified with --values-file")
+	}
+	if r.ValuesFile != "" {
+		return r.preRunValuesFile(c, args)
+	}
+
+	if r.OpenAPIPath != "" {
+		if r.InlineOpenAPI {
+			return errors.Errorf("--inline-openapi and --openapi-path may not both be specified")
+		}
+		if r.DryRun || r.RecurseSubPackages || r.Path != "" {
+			return errors.Errorf(
+				"--openapi-path may not be specified with --dry-run, --recurse-subpackages or --path")
+		}
+		setterVersion = "v2"
+	}
+	if r.DryRun || r.RecurseSubPackages {
+		if err := r.preRunPackages(args); err != nil {
+			return err
+		}
+	}
+	if r.Path != "" {
+		return r.preRunPath(c, args)
+	}
+	if isGlob(args[0]) {
+		if err := r.preRunGlob(args); err != nil {
+			return err
+		}
+	}
+	if r.InlineOpenAPI {
+		if r.Interactive {
+			return errors.Errorf("--inline-openapi may not be specified with --interactive")
+		}
+		setterVersion = "v2"
+	}
+	if r.Interactive {
+		return r.preRunInteractive(c, args)
+	}
+	if r.Unset {
+		if valueFlagSet {
+			return errors.Errorf("--unset and --values may not both be specified")
+		}
+		r.Set.Name = args[1]
+		var err error
+		r.OpenAPIFile, err = getOpenAPIFile(args, r.InlineOpenAPI, r.OpenAPIPath)
+		return err
+	}
+
 	if valueFlagSet && len(args) > 2 {
 		return errors.Errorf("value should set either from flag or arg")
 	}
+	fromFile := r.FromFile != ""
+	if fromFile && (valueFlagSet || len(args) > 2) {
+		return errors.Errorf("value should set either from flag, arg or file")
+	}
+	fetched, fromFetcher, err := r.fetchValue(c)
+	if err != nil {
+		return err
+	}
+	if fromFetcher && (valueFlagSet || len(args) > 2 || fromFile) {
+		return errors.Errorf("value should set either from flag, arg, file or backend")
+	}
 
 	if len(args) > 1 {
 		r.Perform.Name = args[1]
@@ -94,20 +296,52 @@ func (r *SetRunner) preRunE(c *cobra.Command, args []string) error {
 		r.Perform.Value = r.Values[0]
 	} else if len(args) > 2 {
 		r.Perform.Value = args[2]
+	} else if fromFile {
+		b, err := ioutil.ReadFile(r.FromFile)
+		if err != nil {
+			return err
+		}
+		r.Perform.Value = string(b)
+	} else if fromFetcher {
+		r.Perform.Value = fetched
+	}
+
+	if c.Flag("set-by").Changed && r.NoSetBy {
+		return errors.Errorf("--set-by and --no-set-by may not both be specified")
+	}
+	if (valueFlagSet || len(args) > 2 || fromFile || fromFetcher) && !c.Flag("set-by").Changed && !r.NoSetBy {
+		// record who set the value by default
+		r.Perform.SetBy = lookupSetBy()
 	}
 
 	if setterVersion == "" {
-		if len(args) < 2 || len(args) < 3 && !valueFlagSet {
+		if len(args) < 2 || len(args) < 3 && !valueFlagSet && !fromFile && !fromFetcher {
 			setterVersion = "v1"
 		} else if err := initSetterVersion(c, args); err != nil {
 			return err
 		}
 	}
+	if fromFile && setterVersion != "v2" {
+		return errors.Errorf("--from-file is only supported by setters created with create-setter")
+	}
+	if fromFetcher && setterVersion != "v2" {
+		return errors.Errorf("--from-<backend> is only supported by setters created with create-setter")
+	}
+	if r.Set.Record && setterVersion != "v2" {
+		return errors.Errorf("--record is only supported by setters created with create-setter")
+	}
+	if r.Set.FieldFilter != "" && setterVersion != "v2" {
+		return errors.Errorf("--field-filter is only supported by setters created with create-setter")
+	}
+	if r.Set.OutputDir != "" && setterVersion != "v2" {
+		return errors.Errorf("--output-dir is only supported by setters created with create-setter")
+	}
 	if setterVersion == "v2" {
-		var err error
 		r.Set.Name = args[1]
 		if valueFlagSet {
 			r.Set.Value = r.Values[0]
+		} else if fromFile || fromFetcher {
+			r.Set.Value = r.Perform.Value
 		} else {
 			r.Set.Value = args[2]
 		}
@@ -121,7 +355,7 @@ func (r *SetRunner) preRunE(c *cobra.Command, args []string) error {
 
 		r.Set.Description = r.Perform.Description
 		r.Set.SetBy = r.Perform.SetBy
-		r.OpenAPIFile, err = ext.GetOpenAPIFile(args)
+		r.OpenAPIFile, err = getOpenAPIFile(r.openAPIArgs(args), r.InlineOpenAPI, r.OpenAPIPath)
 		if err != nil {
 			return err
 		}
@@ -131,8 +365,42 @@ func (r *SetRunner) preRunE(c *cobra.Command, args []string) error {
 }
 
 func (r *SetRunner) runE(c *cobra.Command, args []string) error {
+	if r.Path != "" {
+		return handleError(c, r.setPath(c, args))
+	}
+	if r.Interactive {
+		return handleError(c, r.interactive(c, args))
+	}
+	if r.ValuesFile != "" {
+		return handleError(c, r.setValuesFile(c, args))
+	}
+	if r.InlineOpenAPI {
+		defer os.Remove(r.OpenAPIFile)
+	}
+	if r.Unset {
+		return handleError(c, r.unset(c, args))
+	}
+	if r.DryRun || r.RecurseSubPackages {
+		return handleError(c, r.setPackages(c, args))
+	}
 	if setterVersion == "v2" {
-		count, err := r.Set.Set(r.OpenAPIFile, args[0])
+		count, err := r.setAll(args)
+		if err == nil && r.Set.Changed && r.InlineOpenAPI {
+			err = writeInlineOpenAPI(args[0], r.OpenAPIFile)
+		}
+		if err == nil {
+			r.warnNoFieldMatch(c, count)
+		}
+		if err == nil && len(r.Set.DivergentValues) > 0 {
+			// the fields had drifted apart before being set
+			fmt.Fprintf(c.ErrOrStderr(),
+				"warning: fields set by %s had divergent values which were overwritten: %s\n",
+				r.Set.Name, strings.Join(r.Set.DivergentValues, ", "))
+		}
+		if err == nil && !r.Set.Changed {
+			fmt.Fprintf(c.OutOrStdout(), "no change\n")
+			return nil
+		}
 		fmt.Fprintf(c.OutOrStdout(), "set %d fields\n", count)
 		return handleError(c, err)
 	}
@@ -142,6 +410,41 @@ func (r *SetRunner) runE(c *cobra.Command, args []string) error {
 	return handleError(c, lookup(r.Lookup, c, args))
 }
 
+// warnNoFieldMatch warns if --field-filter was specified and none of the count
+// fields set matched it.
+func (r *SetRunner) warnNoFieldMatch(c *cobra.Command, count int) {
+	if r.Set.FieldFilter == "" || count > 0 {
+		return
+	}
+	fmt.Fprintf(c.ErrOrStderr(), "warning: no fields of setter %s match --field-filter %s\n",
+		r.Set.Name, r.Set.FieldFilter)
+}
+
+// unset clears the value of the setter, reverting its fields to its default.
+func (r *SetRunner) unset(c *cobra.Command, args []string) error {
+	def, count, err := r.Set.Unset(r.OpenAPIFile, args[0])
+	if err != nil {
+		return err
+	}
+	if r.InlineOpenAPI {
+		if err := writeInlineOpenAPI(args[0], r.OpenAPIFile); err != nil {
+			return err
+		}
+	}
+	if def.Required {
+		fmt.Fprintf(c.ErrOrStderr(),
+			"warning: setter %s is required and must be set before the package is used\n",
+			def.Name)
+	}
+	if def.Default != "" {
+		fmt.Fprintf(c.OutOrStdout(), "unset %s, reverted %d fields to %q\n",
+			def.Name, count, def.Default)
+		return nil
+	}
+	fmt.Fprintf(c.OutOrStdout(), "unset %s\n", def.Name)
+	return nil
+}
+
 func lookup(l setters.LookupSetters, c *cobra.Command, args []string) error {
 	// lookup the setters
 	err := kio.Pipeline{
@@ -5,8 +5,10 @@ package commands_test
 
 import (
 	"bytes"
+	"errors"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -359,6 +361,252 @@ spec:
 			errMsg: "name in body should be at most 5 chars long",
 		},
 
+		{
+			name: "validate openAPI string pattern",
+			args: []string{"tag", "latest"},
+			inputOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.tag:
+      type: string
+      pattern: ^v[0-9]+\.[0-9]+$
+      x-k8s-cli:
+        setter:
+          name: tag
+          value: v1.7
+ `,
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  labels:
+    version: v1.7 # {"$openapi":"tag"}
+ `,
+			expectedOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.tag:
+      type: string
+      pattern: ^v[0-9]+\.[0-9]+$
+      x-k8s-cli:
+        setter:
+          name: tag
+          value: v1.7
+ `,
+			expectedResources: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  labels:
+    version: v1.7 # {"$openapi":"tag"}
+ `,
+			errMsg: `value "latest" of setter tag doesn't match pattern ^v[0-9]+\.[0-9]+$`,
+		},
+		{
+			name: "set with style",
+			args: []string{"app", "web", "--set-by", "me", "--style", "single"},
+			out:  "set 1 fields\n",
+			inputOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.app:
+      type: string
+      x-k8s-cli:
+        setter:
+          name: app
+          value: nginx
+          setBy: me
+ `,
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  labels:
+    app: nginx # {"$openapi":"app"}
+ `,
+			expectedOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.app:
+      type: string
+      x-k8s-cli:
+        setter:
+          name: app
+          value: web
+          setBy: me
+ `,
+			expectedResources: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  labels:
+    app: 'web' # {"$openapi":"app"}
+ `,
+		},
+		{
+			name: "unknown style",
+			args: []string{"app", "web", "--style", "flow"},
+			inputOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.app:
+      type: string
+      x-k8s-cli:
+        setter:
+          name: app
+          value: nginx
+          setBy: me
+ `,
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  labels:
+    app: nginx # {"$openapi":"app"}
+ `,
+			expectedOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.app:
+      type: string
+      x-k8s-cli:
+        setter:
+          name: app
+          value: nginx
+          setBy: me
+ `,
+			expectedResources: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  labels:
+    app: nginx # {"$openapi":"app"}
+ `,
+			errMsg: "--style must be one of double, folded, literal, plain, single",
+		},
+		{
+			name: "validate openAPI enum",
+			args: []string{"env", "test"},
+			inputOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.env:
+      enum:
+      - dev
+      - staging
+      - prod
+      x-k8s-cli:
+        setter:
+          name: env
+          value: dev
+ `,
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  labels:
+    env: dev # {"$openapi":"env"}
+ `,
+			expectedOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.env:
+      enum:
+      - dev
+      - staging
+      - prod
+      x-k8s-cli:
+        setter:
+          name: env
+          value: dev
+ `,
+			expectedResources: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  labels:
+    env: dev # {"$openapi":"env"}
+ `,
+			errMsg: `value "test" of setter env isn't one of the allowed values: dev, staging, prod`,
+		},
+		{
+			name: "validate percentage maximum",
+			args: []string{"max-surge", "110%"},
+			inputOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.max-surge:
+      format: percentage
+      maximum: 100
+      type: string
+      x-k8s-cli:
+        setter:
+          name: max-surge
+          value: 25%
+ `,
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  strategy:
+    rollingUpdate:
+      maxSurge: 25% # {"$openapi":"max-surge"}
+ `,
+			expectedOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.max-surge:
+      format: percentage
+      maximum: 100
+      type: string
+      x-k8s-cli:
+        setter:
+          name: max-surge
+          value: 25%
+ `,
+			expectedResources: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  strategy:
+    rollingUpdate:
+      maxSurge: 25% # {"$openapi":"max-surge"}
+ `,
+			errMsg: `value "110%" of setter max-surge exceeds 100%`,
+		},
+
 		{
 			name: "validate substitution",
 			args: []string{"tag", "1.8.1"},
@@ -650,6 +898,122 @@ spec:
  `,
 		},
 
+		{
+			name: "openAPI list of objects",
+			args: []string{"env", `{"name":"FOO","value":"foo"}`, `{"name":"BAR","value":"bar"}`},
+			out:  "set 1 fields\n",
+			inputOpenAPI: `
+kind: Kptfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.env:
+      type: array
+      items:
+        type: object
+        required:
+        - name
+      x-k8s-cli:
+        setter:
+          name: env
+          listValues:
+          - name: FOO
+            value: old
+ `,
+			input: `
+apiVersion: example.com/v1beta1
+kind: Example
+spec:
+  env: # {"$ref":"#/definitions/io.k8s.cli.setters.env"}
+  - name: FOO
+    value: old
+ `,
+			expectedOpenAPI: `
+kind: Kptfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.env:
+      type: array
+      items:
+        type: object
+        required:
+        - name
+      x-k8s-cli:
+        setter:
+          name: env
+          listValues:
+          - name: FOO
+            value: foo
+          - name: BAR
+            value: bar
+ `,
+			expectedResources: `
+apiVersion: example.com/v1beta1
+kind: Example
+spec:
+  env: # {"$ref":"#/definitions/io.k8s.cli.setters.env"}
+  - name: FOO
+    value: foo
+  - name: BAR
+    value: bar
+ `,
+		},
+
+		{
+			name: "validate openAPI list of objects error",
+			args: []string{"env", `{"name":"FOO","value":"foo"}`, `{"value":"bar"}`},
+			inputOpenAPI: `
+kind: Kptfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.env:
+      type: array
+      items:
+        type: object
+        required:
+        - name
+      x-k8s-cli:
+        setter:
+          name: env
+          listValues:
+          - name: FOO
+            value: old
+ `,
+			input: `
+apiVersion: example.com/v1beta1
+kind: Example
+spec:
+  env: # {"$ref":"#/definitions/io.k8s.cli.setters.env"}
+  - name: FOO
+    value: old
+ `,
+			expectedOpenAPI: `
+kind: Kptfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.env:
+      type: array
+      items:
+        type: object
+        required:
+        - name
+      x-k8s-cli:
+        setter:
+          name: env
+          listValues:
+          - name: FOO
+            value: old
+ `,
+			expectedResources: `
+apiVersion: example.com/v1beta1
+kind: Example
+spec:
+  env: # {"$ref":"#/definitions/io.k8s.cli.setters.env"}
+  - name: FOO
+    value: old
+ `,
+			errMsg: `name in body is required`,
+		},
+
 		{
 			name: "validate openAPI list values set by flag error",
 			args: []string{"list", "--values", "10", "--values", "hi", "--values", "true"},
@@ -958,6 +1322,13 @@ spec:
 				return f.Name(), nil
 			}
 
+			// don't default setBy to the OS user
+			oldSetBy := ext.GetDefaultSetBy
+			defer func() { ext.GetDefaultSetBy = oldSetBy }()
+			ext.GetDefaultSetBy = func() (string, error) {
+				return "", nil
+			}
+
 			r, err := ioutil.TempFile("", "k8s-cli-*.yaml")
 			if !assert.NoError(t, err) {
 				t.FailNow()
@@ -1012,3 +1383,1350 @@ spec:
 		})
 	}
 }
+
+func TestSetCommand_noChange(t *testing.T) {
+	// reset the openAPI afterward
+	openapi.ResetOpenAPI()
+	defer openapi.ResetOpenAPI()
+
+	inputOpenAPI := `
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      description: hello world
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "4"
+          setBy: pw
+`
+	// the resource is intentionally not formatted so that any write would change it
+	input := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+    name: nginx-deployment
+spec:
+    replicas: 4 # {"$openapi":"replicas"}
+`
+
+	f, err := ioutil.TempFile("", "k8s-cli-")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.Remove(f.Name())
+	err = ioutil.WriteFile(f.Name(), []byte(inputOpenAPI), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	old := ext.GetOpenAPIFile
+	defer func() { ext.GetOpenAPIFile = old }()
+	ext.GetOpenAPIFile = func(args []string) (s string, err error) {
+		return f.Name(), nil
+	}
+
+	r, err := ioutil.TempFile("", "k8s-cli-*.yaml")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.Remove(r.Name())
+	err = ioutil.WriteFile(r.Name(), []byte(input), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	runner := commands.NewSetRunner("")
+	out := &bytes.Buffer{}
+	runner.Command.SetOut(out)
+	runner.Command.SetArgs([]string{r.Name(), "replicas", "4", "--set-by", "pw"})
+	if !assert.NoError(t, runner.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, "no change\n", out.String())
+
+	actualResources, err := ioutil.ReadFile(r.Name())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, input, string(actualResources))
+
+	actualOpenAPI, err := ioutil.ReadFile(f.Name())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, inputOpenAPI, string(actualOpenAPI))
+}
+
+func TestSetCommand_setBy(t *testing.T) {
+	var tests = []struct {
+		name            string
+		args            []string
+		lookupErr       error
+		expectedOpenAPI string
+	}{
+		{
+			name: "default to the OS user",
+			args: []string{"replicas", "4"},
+			expectedOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "4"
+          setBy: os-user
+`,
+		},
+		{
+			name: "explicit set-by",
+			args: []string{"replicas", "4", "--set-by", "ci-bot"},
+			expectedOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "4"
+          setBy: ci-bot
+`,
+		},
+		{
+			// e.g. a container user without a passwd entry
+			name:      "OS user lookup fails",
+			args:      []string{"replicas", "4"},
+			lookupErr: errors.New("user: unknown userid 1000"),
+			expectedOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "4"
+`,
+		},
+		{
+			name: "suppressed set-by",
+			args: []string{"replicas", "4", "--no-set-by"},
+			expectedOpenAPI: `
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "4"
+`,
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			// reset the openAPI afterward
+			openapi.ResetOpenAPI()
+			defer openapi.ResetOpenAPI()
+
+			f, err := ioutil.TempFile("", "k8s-cli-")
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			defer os.Remove(f.Name())
+			err = ioutil.WriteFile(f.Name(), []byte(`
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+          setBy: me
+`), 0600)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			old := ext.GetOpenAPIFile
+			defer func() { ext.GetOpenAPIFile = old }()
+			ext.GetOpenAPIFile = func(args []string) (s string, err error) {
+				return f.Name(), nil
+			}
+			oldSetBy := ext.GetDefaultSetBy
+			defer func() { ext.GetDefaultSetBy = oldSetBy }()
+			ext.GetDefaultSetBy = func() (string, error) {
+				if test.lookupErr != nil {
+					return "", test.lookupErr
+				}
+				return "os-user", nil
+			}
+
+			r, err := ioutil.TempFile("", "k8s-cli-*.yaml")
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			defer os.Remove(r.Name())
+			err = ioutil.WriteFile(r.Name(), []byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+`), 0600)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+
+			runner := commands.NewSetRunner("")
+			runner.Command.SetOut(&bytes.Buffer{})
+			runner.Command.SetArgs(append([]string{r.Name()}, test.args...))
+			if !assert.NoError(t, runner.Command.Execute()) {
+				t.FailNow()
+			}
+
+			actualOpenAPI, err := ioutil.ReadFile(f.Name())
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t,
+				strings.TrimSpace(test.expectedOpenAPI),
+				strings.TrimSpace(string(actualOpenAPI)))
+		})
+	}
+}
+
+func TestSetCommand_divergentValues(t *testing.T) {
+	// reset the openAPI afterward
+	openapi.ResetOpenAPI()
+	defer openapi.ResetOpenAPI()
+
+	f, err := ioutil.TempFile("", "k8s-cli-")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.Remove(f.Name())
+	err = ioutil.WriteFile(f.Name(), []byte(`
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	old := ext.GetOpenAPIFile
+	defer func() { ext.GetOpenAPIFile = old }()
+	ext.GetOpenAPIFile = func(args []string) (s string, err error) {
+		return f.Name(), nil
+	}
+
+	r, err := ioutil.TempFile("", "k8s-cli-*.yaml")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.Remove(r.Name())
+	err = ioutil.WriteFile(r.Name(), []byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: redis-deployment
+spec:
+  replicas: 5 # {"$openapi":"replicas"}
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	runner := commands.NewSetRunner("")
+	out := &bytes.Buffer{}
+	errOut := &bytes.Buffer{}
+	runner.Command.SetOut(out)
+	runner.Command.SetErr(errOut)
+	runner.Command.SetArgs([]string{r.Name(), "replicas", "4", "--no-set-by"})
+	if !assert.NoError(t, runner.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, "set 2 fields\n", out.String())
+	assert.Equal(t,
+		"warning: fields set by replicas had divergent values which were overwritten: 3, 5\n",
+		errOut.String())
+
+	actualResources, err := ioutil.ReadFile(r.Name())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 4 # {"$openapi":"replicas"}
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: redis-deployment
+spec:
+  replicas: 4 # {"$openapi":"replicas"}
+`, string(actualResources))
+}
+
+func TestSetCommand_interactive(t *testing.T) {
+	// reset the openAPI afterward
+	openapi.ResetOpenAPI()
+	defer openapi.ResetOpenAPI()
+
+	f, err := ioutil.TempFile("", "k8s-cli-")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.Remove(f.Name())
+	err = ioutil.WriteFile(f.Name(), []byte(`
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.name:
+      description: the application name
+      x-k8s-cli:
+        setter:
+          name: name
+          value: ""
+    io.k8s.cli.setters.replicas:
+      type: integer
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+          required: true
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	old := ext.GetOpenAPIFile
+	defer func() { ext.GetOpenAPIFile = old }()
+	ext.GetOpenAPIFile = func(args []string) (s string, err error) {
+		return f.Name(), nil
+	}
+
+	d, err := ioutil.TempDir("", "k8s-cli-")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(d)
+	err = ioutil.WriteFile(filepath.Join(d, "deployment.yaml"), []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app # {"$openapi":"name"}
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	runner := commands.NewSetRunner("")
+	out := &bytes.Buffer{}
+	runner.Command.SetOut(out)
+	// the first replicas value is rejected by the schema and prompted for again
+	runner.Command.SetIn(bytes.NewBufferString("my-app\nthree\n5\n"))
+	runner.Command.SetArgs([]string{d, "--interactive", "--no-set-by"})
+	if !assert.NoError(t, runner.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Contains(t, out.String(), "name (the application name): set 1 fields\n")
+	assert.Contains(t, out.String(), "replicas [3]: invalid value: ")
+	assert.True(t, strings.HasSuffix(out.String(), "replicas [3]: set 1 fields\n"), out.String())
+
+	actual, err := ioutil.ReadFile(filepath.Join(d, "deployment.yaml"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app # {"$openapi":"name"}
+spec:
+  replicas: 5 # {"$openapi":"replicas"}
+`, string(actual))
+}
+
+func TestSetCommand_interactiveNoInput(t *testing.T) {
+	// reset the openAPI afterward
+	openapi.ResetOpenAPI()
+	defer openapi.ResetOpenAPI()
+
+	f, err := ioutil.TempFile("", "k8s-cli-")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.Remove(f.Name())
+	err = ioutil.WriteFile(f.Name(), []byte(`
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.name:
+      x-k8s-cli:
+        setter:
+          name: name
+          value: ""
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	old := ext.GetOpenAPIFile
+	defer func() { ext.GetOpenAPIFile = old }()
+	ext.GetOpenAPIFile = func(args []string) (s string, err error) {
+		return f.Name(), nil
+	}
+
+	d, err := ioutil.TempDir("", "k8s-cli-")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(d)
+
+	runner := commands.NewSetRunner("")
+	runner.Command.SetOut(&bytes.Buffer{})
+	runner.Command.SetErr(&bytes.Buffer{})
+	runner.Command.SetIn(&bytes.Buffer{})
+	runner.Command.SetArgs([]string{d, "--interactive", "--no-set-by"})
+	err = runner.Command.Execute()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "no value provided for setter name")
+	}
+}
+
+func TestSetCommand_unset(t *testing.T) {
+	// reset the openAPI afterward
+	openapi.ResetOpenAPI()
+	defer openapi.ResetOpenAPI()
+
+	f, err := ioutil.TempFile("", "k8s-cli-")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.Remove(f.Name())
+	err = ioutil.WriteFile(f.Name(), []byte(`
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "5"
+          default: "1"
+          required: true
+          setBy: me
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	old := ext.GetOpenAPIFile
+	defer func() { ext.GetOpenAPIFile = old }()
+	ext.GetOpenAPIFile = func(args []string) (s string, err error) {
+		return f.Name(), nil
+	}
+
+	r, err := ioutil.TempFile("", "k8s-cli-*.yaml")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.Remove(r.Name())
+	err = ioutil.WriteFile(r.Name(), []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 5 # {"$openapi":"replicas"}
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	runner := commands.NewSetRunner("")
+	out := &bytes.Buffer{}
+	errOut := &bytes.Buffer{}
+	runner.Command.SetOut(out)
+	runner.Command.SetErr(errOut)
+	runner.Command.SetArgs([]string{r.Name(), "replicas", "--unset"})
+	if !assert.NoError(t, runner.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, "unset replicas, reverted 1 fields to \"1\"\n", out.String())
+	assert.Equal(t,
+		"warning: setter replicas is required and must be set before the package is used\n",
+		errOut.String())
+
+	actualResources, err := ioutil.ReadFile(r.Name())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 1 # {"$openapi":"replicas"}
+`, string(actualResources))
+
+	actualOpenAPI, err := ioutil.ReadFile(f.Name())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: ""
+          default: "1"
+          required: true
+`, "\n"+string(actualOpenAPI))
+}
+
+func TestSetCommand_record(t *testing.T) {
+	// reset the openAPI afterward
+	openapi.ResetOpenAPI()
+	defer openapi.ResetOpenAPI()
+
+	f, err := ioutil.TempFile("", "k8s-cli-")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.Remove(f.Name())
+	err = ioutil.WriteFile(f.Name(), []byte(`
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	old := ext.GetOpenAPIFile
+	defer func() { ext.GetOpenAPIFile = old }()
+	ext.GetOpenAPIFile = func(args []string) (s string, err error) {
+		return f.Name(), nil
+	}
+
+	r, err := ioutil.TempFile("", "k8s-cli-*.yaml")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.Remove(r.Name())
+	err = ioutil.WriteFile(r.Name(), []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	for _, value := range []string{"4", "5"} {
+		runner := commands.NewSetRunner("")
+		runner.Command.SetOut(&bytes.Buffer{})
+		runner.Command.SetArgs([]string{r.Name(), "replicas", value,
+			"--set-by", "me", "--record", "--max-history", "1"})
+		if !assert.NoError(t, runner.Command.Execute()) {
+			t.FailNow()
+		}
+	}
+
+	actualOpenAPI, err := ioutil.ReadFile(f.Name())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	// only the newest change is kept
+	assert.Equal(t, 1, strings.Count(string(actualOpenAPI), "timestamp:"))
+	assert.Contains(t, string(actualOpenAPI), `
+          history:
+          - timestamp: "`)
+	assert.Contains(t, string(actualOpenAPI), `
+            previousValue: "4"
+            value: "5"
+            setBy: me
+`)
+
+	// --max-history requires --record
+	runner := commands.NewSetRunner("")
+	runner.Command.SetOut(&bytes.Buffer{})
+	runner.Command.SetErr(&bytes.Buffer{})
+	runner.Command.SilenceUsage = true
+	runner.Command.SetArgs([]string{r.Name(), "replicas", "6", "--max-history", "1"})
+	err = runner.Command.Execute()
+	if assert.Error(t, err) {
+		assert.Equal(t, "--max-history may only be specified with --record", err.Error())
+	}
+}
+
+func TestSetCommand_fromFile(t *testing.T) {
+	// reset the openAPI afterward
+	openapi.ResetOpenAPI()
+	defer openapi.ResetOpenAPI()
+
+	f, err := ioutil.TempFile("", "k8s-cli-")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.Remove(f.Name())
+	err = ioutil.WriteFile(f.Name(), []byte(`
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.script:
+      x-k8s-cli:
+        setter:
+          name: script
+          value: "old"
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	old := ext.GetOpenAPIFile
+	defer func() { ext.GetOpenAPIFile = old }()
+	ext.GetOpenAPIFile = func(args []string) (s string, err error) {
+		return f.Name(), nil
+	}
+
+	r, err := ioutil.TempFile("", "k8s-cli-*.yaml")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.Remove(r.Name())
+	err = ioutil.WriteFile(r.Name(), []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: scripts
+data:
+  script: old # {"$openapi":"script"}
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	script, err := ioutil.TempFile("", "k8s-cli-*.sh")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.Remove(script.Name())
+	err = ioutil.WriteFile(script.Name(), []byte(`#!/bin/sh
+echo "hello"
+exit 0
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	runner := commands.NewSetRunner("")
+	out := &bytes.Buffer{}
+	runner.Command.SetOut(out)
+	runner.Command.SetArgs([]string{
+		r.Name(), "script", "--from-file", script.Name(), "--no-set-by"})
+	if !assert.NoError(t, runner.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, "set 1 fields\n", out.String())
+
+	actualResources, err := ioutil.ReadFile(r.Name())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: scripts
+data:
+  # {"$openapi":"script"}
+  script: |
+    #!/bin/sh
+    echo "hello"
+    exit 0
+`, string(actualResources))
+
+	// the setter reference is read back, so the field may be set again
+	err = ioutil.WriteFile(script.Name(), []byte(`#!/bin/sh
+exit 1
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	openapi.ResetOpenAPI()
+	runner = commands.NewSetRunner("")
+	out = &bytes.Buffer{}
+	runner.Command.SetOut(out)
+	runner.Command.SetArgs([]string{
+		r.Name(), "script", "--from-file", script.Name(), "--no-set-by"})
+	if !assert.NoError(t, runner.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, "set 1 fields\n", out.String())
+
+	actualResources, err = ioutil.ReadFile(r.Name())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: scripts
+data:
+  # {"$openapi":"script"}
+  script: |
+    #!/bin/sh
+    exit 1
+`, string(actualResources))
+
+	actualOpenAPI, err := ioutil.ReadFile(f.Name())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.script:
+      x-k8s-cli:
+        setter:
+          name: script
+          value: |
+            #!/bin/sh
+            exit 1
+`, "\n"+string(actualOpenAPI))
+}
+
+// fakeFetcher fetches values from a map, recording the references fetched.
+type fakeFetcher struct {
+	values  map[string]string
+	fetched []string
+}
+
+func (f *fakeFetcher) Fetch(ref string) (string, error) {
+	f.fetched = append(f.fetched, ref)
+	return f.values[ref], nil
+}
+
+func TestSetCommand_fromFetcher(t *testing.T) {
+	// reset the openAPI afterward
+	openapi.ResetOpenAPI()
+	defer openapi.ResetOpenAPI()
+
+	fetcher := &fakeFetcher{values: map[string]string{
+		"secret/data/db#password": "s3cr3t",
+	}}
+	ext.ValueFetchers["fake"] = fetcher
+	defer delete(ext.ValueFetchers, "fake")
+
+	f, err := ioutil.TempFile("", "k8s-cli-")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.Remove(f.Name())
+	err = ioutil.WriteFile(f.Name(), []byte(`
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.db-password:
+      x-k8s-cli:
+        setter:
+          name: db-password
+          value: "changeme"
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	old := ext.GetOpenAPIFile
+	defer func() { ext.GetOpenAPIFile = old }()
+	ext.GetOpenAPIFile = func(args []string) (s string, err error) {
+		return f.Name(), nil
+	}
+
+	r, err := ioutil.TempFile("", "k8s-cli-*.yaml")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.Remove(r.Name())
+	err = ioutil.WriteFile(r.Name(), []byte(`apiVersion: v1
+kind: Secret
+metadata:
+  name: db
+stringData:
+  password: changeme # {"$openapi":"db-password"}
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	runner := commands.NewSetRunner("")
+	out := &bytes.Buffer{}
+	errOut := &bytes.Buffer{}
+	runner.Command.SetOut(out)
+	runner.Command.SetErr(errOut)
+	runner.Command.SetArgs([]string{
+		r.Name(), "db-password", "--from-fake", "secret/data/db#password", "--no-set-by"})
+	if !assert.NoError(t, runner.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, []string{"secret/data/db#password"}, fetcher.fetched)
+
+	// the fetched value is never logged
+	assert.Equal(t, "set 1 fields\n", out.String())
+	assert.NotContains(t, errOut.String(), "s3cr3t")
+
+	actualResources, err := ioutil.ReadFile(r.Name())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `apiVersion: v1
+kind: Secret
+metadata:
+  name: db
+stringData:
+  password: s3cr3t # {"$openapi":"db-password"}
+`, string(actualResources))
+}
+
+func TestSetCommand_path(t *testing.T) {
+	var tests = []struct {
+		name        string
+		args        []string
+		input       string
+		expected    string
+		expectedOut string
+		err         string
+	}{
+		{
+			name: "existing path",
+			args: []string{"--path", "/spec/replicas", "5"},
+			input: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 3 # keep this comment
+`,
+			expected: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 5 # keep this comment
+`,
+			expectedOut: "set /spec/replicas on Deployment app\n",
+		},
+		{
+			name: "list index",
+			args: []string{"--path", "/spec/template/spec/containers/0/image", "--values", "nginx:1.8"},
+			input: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.7
+`,
+			expected: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.8
+`,
+			expectedOut: "set /spec/template/spec/containers/0/image on Deployment app\n",
+		},
+		{
+			name: "created path",
+			args: []string{"--path", "/spec/strategy/type", "Recreate", "--create"},
+			input: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 3
+`,
+			expected: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 3
+  strategy:
+    type: Recreate
+`,
+			expectedOut: "set /spec/strategy/type on Deployment app\n",
+		},
+		{
+			name: "missing path",
+			args: []string{"--path", "/spec/strategy/type", "Recreate"},
+			input: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 3
+`,
+			err: "no resources have field /spec/strategy/type; use --create to create it",
+		},
+		{
+			name: "ambiguous path",
+			args: []string{"--path", "/spec/replicas", "5"},
+			input: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 3
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+spec:
+  replicas: 1
+`,
+			err: "field /spec/replicas is ambiguous, it matches 2 resources " +
+				"(Deployment app, StatefulSet db); select one with --kind and --name",
+		},
+		{
+			name: "selected path",
+			args: []string{"--path", "/spec/replicas", "5", "--kind", "StatefulSet"},
+			input: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 3
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+spec:
+  replicas: 1
+`,
+			expected: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 3
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+spec:
+  replicas: 5
+`,
+			expectedOut: "set /spec/replicas on StatefulSet db\n",
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			r, err := ioutil.TempFile("", "k8s-cli-*.yaml")
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			defer os.Remove(r.Name())
+			err = ioutil.WriteFile(r.Name(), []byte(test.input), 0600)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+
+			runner := commands.NewSetRunner("")
+			out := &bytes.Buffer{}
+			runner.Command.SetOut(out)
+			runner.Command.SetArgs(append([]string{r.Name()}, test.args...))
+			err = runner.Command.Execute()
+			if test.err != "" {
+				if !assert.Error(t, err) {
+					t.FailNow()
+				}
+				assert.Equal(t, test.err, err.Error())
+				return
+			}
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, test.expectedOut, out.String())
+
+			actual, err := ioutil.ReadFile(r.Name())
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, test.expected, string(actual))
+		})
+	}
+}
+
+func TestSetCommand_glob(t *testing.T) {
+	// reset the openAPI afterward
+	openapi.ResetOpenAPI()
+	defer openapi.ResetOpenAPI()
+
+	d, err := ioutil.TempDir("", "kustomize-set-glob-test")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(d)
+
+	files := map[string]string{
+		"Krmfile": `apiVersion: v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+`,
+		filepath.Join("services", "a", "deployment.yaml"): `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: a
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+`,
+		filepath.Join("services", "b", "deployment.yaml"): `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: b
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+`,
+		filepath.Join("services", "b", "statefulset.yaml"): `apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: b
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+`,
+	}
+	for name, content := range files {
+		p := filepath.Join(d, name)
+		if !assert.NoError(t, os.MkdirAll(filepath.Dir(p), 0700)) {
+			t.FailNow()
+		}
+		if !assert.NoError(t, ioutil.WriteFile(p, []byte(content), 0600)) {
+			t.FailNow()
+		}
+	}
+
+	r := commands.NewSetRunner("")
+	out := &bytes.Buffer{}
+	r.Command.SetOut(out)
+	r.Command.SetArgs([]string{
+		filepath.Join(d, "services", "*", "deployment.yaml"), "replicas", "5", "--no-set-by"})
+	if !assert.NoError(t, r.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, "set 2 fields\n", out.String())
+
+	expected := map[string]string{
+		"Krmfile": `apiVersion: v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "5"
+`,
+		filepath.Join("services", "a", "deployment.yaml"): `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: a
+spec:
+  replicas: 5 # {"$openapi":"replicas"}
+`,
+		filepath.Join("services", "b", "deployment.yaml"): `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: b
+spec:
+  replicas: 5 # {"$openapi":"replicas"}
+`,
+		// not matched by the glob
+		filepath.Join("services", "b", "statefulset.yaml"): files[filepath.Join("services", "b", "statefulset.yaml")],
+	}
+	for name, content := range expected {
+		actual, err := ioutil.ReadFile(filepath.Join(d, name))
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.Equal(t, content, string(actual), name)
+	}
+
+	// globs must match a file
+	r = commands.NewSetRunner("")
+	r.Command.SetArgs([]string{
+		filepath.Join(d, "services", "*", "missing.yaml"), "replicas", "5", "--no-set-by"})
+	err = r.Command.Execute()
+	if !assert.Error(t, err) {
+		t.FailNow()
+	}
+	assert.Contains(t, err.Error(), "matches no files")
+}
+
+func TestSetCommand_fieldFilter(t *testing.T) {
+	// reset the openAPI afterward
+	openapi.ResetOpenAPI()
+	defer openapi.ResetOpenAPI()
+
+	f, err := ioutil.TempFile("", "k8s-cli-")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.Remove(f.Name())
+	err = ioutil.WriteFile(f.Name(), []byte(`
+apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.image:
+      x-k8s-cli:
+        setter:
+          name: image
+          value: nginx
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	old := ext.GetOpenAPIFile
+	defer func() { ext.GetOpenAPIFile = old }()
+	ext.GetOpenAPIFile = func(args []string) (s string, err error) {
+		return f.Name(), nil
+	}
+
+	r, err := ioutil.TempFile("", "k8s-cli-*.yaml")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.Remove(r.Name())
+	err = ioutil.WriteFile(r.Name(), []byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: nginx # {"$openapi":"image"}
+      containers:
+      - name: nginx
+        image: nginx # {"$openapi":"image"}
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	runner := commands.NewSetRunner("")
+	out := &bytes.Buffer{}
+	errOut := &bytes.Buffer{}
+	runner.Command.SetOut(out)
+	runner.Command.SetErr(errOut)
+	runner.Command.SetArgs([]string{r.Name(), "image", "nginx:1.19", "--no-set-by",
+		"--field-filter", "**.initContainers.image"})
+	if !assert.NoError(t, runner.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, "set 1 fields\n", out.String())
+	assert.Empty(t, errOut.String())
+
+	actualResources, err := ioutil.ReadFile(r.Name())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: nginx:1.19 # {"$openapi":"image"}
+      containers:
+      - name: nginx
+        image: nginx # {"$openapi":"image"}
+`, string(actualResources))
+
+	// the value of the setter is changed regardless
+	actualOpenAPI, err := ioutil.ReadFile(f.Name())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Contains(t, string(actualOpenAPI), "value: nginx:1.19")
+
+	// warn if no fields match
+	openapi.ResetOpenAPI()
+	runner = commands.NewSetRunner("")
+	out = &bytes.Buffer{}
+	errOut = &bytes.Buffer{}
+	runner.Command.SetOut(out)
+	runner.Command.SetErr(errOut)
+	runner.Command.SetArgs([]string{r.Name(), "image", "nginx:1.20", "--no-set-by",
+		"--field-filter", "spec.*.image"})
+	if !assert.NoError(t, runner.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, "set 0 fields\n", out.String())
+	assert.Equal(t, "warning: no fields of setter image match --field-filter spec.*.image\n",
+		errOut.String())
+}
+
+func TestSetCommand_outputDir(t *testing.T) {
+	// reset the openAPI afterward
+	openapi.ResetOpenAPI()
+	defer openapi.ResetOpenAPI()
+
+	d, err := ioutil.TempDir("", "kustomize-set-test")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(d)
+	openAPIFile := filepath.Join(d, "setters.yaml")
+	err = ioutil.WriteFile(openAPIFile, []byte(`apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	src := filepath.Join(d, "src")
+	deployment := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+`
+	service := `apiVersion: v1
+kind: Service
+metadata:
+  name: nginx
+`
+	files := map[string]string{
+		filepath.Join("app", "deployment.yaml"): deployment,
+		"service.yaml":                          service,
+	}
+	for name, data := range files {
+		if !assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(src, name)), 0700)) {
+			t.FailNow()
+		}
+		if !assert.NoError(t, ioutil.WriteFile(filepath.Join(src, name), []byte(data), 0600)) {
+			t.FailNow()
+		}
+	}
+
+	dst := filepath.Join(d, "dst")
+	runner := commands.NewSetRunner("")
+	out := &bytes.Buffer{}
+	runner.Command.SetOut(out)
+	runner.Command.SetErr(&bytes.Buffer{})
+	runner.Command.SetArgs([]string{src, "replicas", "4", "--no-set-by",
+		"--openapi-path", openAPIFile, "--output-dir", dst})
+	if !assert.NoError(t, runner.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, "set 1 fields\n", out.String())
+
+	// the resources are written to the output directory at the same paths
+	expected := map[string]string{
+		filepath.Join("app", "deployment.yaml"): strings.Replace(deployment, "3 #", "4 #", 1),
+		"service.yaml":                          service,
+	}
+	for name, data := range expected {
+		actual, err := ioutil.ReadFile(filepath.Join(dst, name))
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.Equal(t, data, string(actual))
+	}
+
+	// the original resources are unchanged
+	for name, data := range files {
+		actual, err := ioutil.ReadFile(filepath.Join(src, name))
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.Equal(t, data, string(actual))
+	}
+
+	// the setter definition is updated
+	actual, err := ioutil.ReadFile(openAPIFile)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Contains(t, string(actual), `value: "4"`)
+
+	// --output-dir doesn't change packages in place
+	runner = commands.NewSetRunner("")
+	runner.Command.SetOut(&bytes.Buffer{})
+	runner.Command.SetErr(&bytes.Buffer{})
+	runner.Command.SetArgs([]string{src, "replicas", "5", "--no-set-by",
+		"--output-dir", dst, "--dry-run"})
+	assert.Error(t, runner.Command.Execute())
+}
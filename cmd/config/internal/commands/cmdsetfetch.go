@@ -0,0 +1,55 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"sort"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/cmd/config/ext"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+)
+
+// addFetcherFlags adds a --from-<name> flag for each of the backends
+// registered in ext.ValueFetchers.
+func (r *SetRunner) addFetcherFlags(c *cobra.Command) {
+	var names []string
+	for name := range ext.ValueFetchers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	r.fetchRefs = map[string]*string{}
+	for _, name := range names {
+		r.fetchRefs[name] = c.Flags().String("from-"+name, "",
+			"set the value to the one fetched from the "+name+" backend, e.g. a secret.")
+	}
+}
+
+// fetchValue fetches the value referenced by the --from-<name> flag.  Returns
+// false if no such flag was specified.  The value is never logged.
+func (r *SetRunner) fetchValue(c *cobra.Command) (string, bool, error) {
+	var name string
+	for n := range r.fetchRefs {
+		if !c.Flag("from-" + n).Changed {
+			continue
+		}
+		if name != "" {
+			return "", false, errors.Errorf(
+				"--from-%s and --from-%s may not both be specified", name, n)
+		}
+		name = n
+	}
+	if name == "" {
+		return "", false, nil
+	}
+	fetcher, found := ext.ValueFetchers[name]
+	if !found {
+		return "", false, errors.Errorf("no %s backend is registered", name)
+	}
+	value, err := fetcher.Fetch(*r.fetchRefs[name])
+	if err != nil {
+		return "", false, errors.WrapPrefixf(err, "--from-%s", name)
+	}
+	return value, true, nil
+}
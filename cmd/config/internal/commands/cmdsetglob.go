@@ -0,0 +1,101 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kustomize/cmd/config/ext"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/sets"
+)
+
+// isGlob returns true if path is a glob pattern rather than a file or directory
+// -- e.g. services/*/deployment.yaml.
+func isGlob(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// preRunGlob expands the glob pattern args[0] to the files it matches, and finds
+// the package enclosing them whose OpenAPI file holds the setter definitions.
+func (r *SetRunner) preRunGlob(args []string) error {
+	if r.Interactive || r.Unset || r.InlineOpenAPI {
+		return errors.Errorf(
+			"a glob may not be specified with --interactive, --unset or --inline-openapi")
+	}
+	matches, err := filepath.Glob(args[0])
+	if err != nil {
+		return errors.WrapPrefixf(err, "invalid glob %s", args[0])
+	}
+	if len(matches) == 0 {
+		return errors.Errorf("glob %s matches no files", args[0])
+	}
+	sort.Strings(matches)
+	r.globMatches = matches
+	r.packageDir, err = enclosingPackage(args[0])
+	if err != nil {
+		return err
+	}
+	// globs are only supported by setters created with create-setter
+	setterVersion = "v2"
+	return nil
+}
+
+// enclosingPackage returns the nearest directory above the glob pattern which
+// contains an OpenAPI file.  Defaults to the directory containing the glob.
+func enclosingPackage(pattern string) (string, error) {
+	// the directory up to the first element containing a glob
+	dir := filepath.Dir(pattern)
+	for isGlob(dir) {
+		dir = filepath.Dir(dir)
+	}
+	for d := dir; ; d = filepath.Dir(d) {
+		openAPIFile, err := ext.GetOpenAPIFile([]string{d})
+		if err != nil {
+			return "", err
+		}
+		if _, err := os.Stat(openAPIFile); err == nil {
+			return d, nil
+		}
+		if parent := filepath.Dir(d); parent == d {
+			return dir, nil
+		}
+	}
+}
+
+// openAPIArgs returns the args used to find the OpenAPI file.  For globs, these
+// refer to the enclosing package rather than the glob.
+func (r *SetRunner) openAPIArgs(args []string) []string {
+	if r.packageDir == "" {
+		return args
+	}
+	return append([]string{r.packageDir}, args[1:]...)
+}
+
+// setAll sets the setter on the resources in args[0], or on each of the files
+// matching the glob, and returns the number of fields set.
+func (r *SetRunner) setAll(args []string) (int, error) {
+	if len(r.globMatches) == 0 {
+		return r.Set.Set(r.OpenAPIFile, args[0])
+	}
+	var count int
+	var changed bool
+	divergent := sets.String{}
+	for _, match := range r.globMatches {
+		n, err := r.Set.Set(r.OpenAPIFile, match)
+		if err != nil {
+			return count, err
+		}
+		count += n
+		changed = changed || r.Set.Changed
+		divergent.Insert(r.Set.DivergentValues...)
+	}
+	r.Set.Changed = changed
+	r.Set.DivergentValues = divergent.List()
+	sort.Strings(r.Set.DivergentValues)
+	return count, nil
+}
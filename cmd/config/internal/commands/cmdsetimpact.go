@@ -0,0 +1,154 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/cmd/config/ext"
+	"sigs.k8s.io/kustomize/cmd/config/internal/generateddocs/commands"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+	"sigs.k8s.io/kustomize/kyaml/setters2/settersutil"
+)
+
+// NewSetImpactRunner returns a command runner.
+func NewSetImpactRunner(parent string) *SetImpactRunner {
+	r := &SetImpactRunner{}
+	c := &cobra.Command{
+		Use:     "set-impact DIR NAME",
+		Args:    cobra.ExactArgs(2),
+		Short:   commands.SetImpactShort,
+		Long:    commands.SetImpactLong,
+		Example: commands.SetImpactExamples,
+		PreRunE: r.preRunE,
+		RunE:    r.runE,
+	}
+	fixDocs(parent, c)
+	c.Flags().BoolVar(&r.RecurseSubPackages, "recurse-subpackages", false,
+		"include the subpackages of DIR -- directories containing their own Krmfile.")
+	c.Flags().StringVar(&r.Output, "output", "table",
+		"output format -- one of table or json.")
+	r.Command = c
+	return r
+}
+
+func SetImpactCommand(parent string) *cobra.Command {
+	return NewSetImpactRunner(parent).Command
+}
+
+type SetImpactRunner struct {
+	Command *cobra.Command
+
+	// RecurseSubPackages includes the subpackages of the directory.
+	RecurseSubPackages bool
+
+	// Output is the output format -- table or json.
+	Output string
+
+	// Impact are the fields which would be changed by setting the setter.
+	Impact []setterImpact
+}
+
+// setterImpact is a field which would be changed by setting the setter, and
+// the package containing it.
+type setterImpact struct {
+	// Package is the path to the package, relative to DIR.
+	Package string `json:"package"`
+
+	settersutil.SetterReference
+}
+
+func (r *SetImpactRunner) preRunE(c *cobra.Command, args []string) error {
+	if r.Output != "table" && r.Output != "json" {
+		return errors.Errorf("--output must be one of table or json, was %s", r.Output)
+	}
+	return nil
+}
+
+func (r *SetImpactRunner) runE(c *cobra.Command, args []string) error {
+	packages, err := packageDirs(args[0], r.RecurseSubPackages)
+	if err != nil {
+		return handleError(c, err)
+	}
+	for _, pkg := range packages {
+		openAPIFile, err := ext.GetOpenAPIFile([]string{pkg})
+		if err != nil {
+			return handleError(c, err)
+		}
+		if _, err := os.Stat(openAPIFile); err != nil {
+			// not a package -- it defines no setters
+			continue
+		}
+		// each package has its own setter definitions
+		openapi.ResetOpenAPI()
+		refs, err := settersutil.SetterReferences(openAPIFile, pkg, args[1])
+		if err != nil {
+			return handleError(c, err)
+		}
+		rel, err := filepath.Rel(args[0], pkg)
+		if err != nil {
+			return handleError(c, errors.Wrap(err))
+		}
+		for i := range refs {
+			r.Impact = append(r.Impact, setterImpact{Package: rel, SetterReference: refs[i]})
+		}
+	}
+	return handleError(c, r.print(c, args[1]))
+}
+
+// packageDirs returns the directories which may be packages -- dir, and if
+// recurse is set, each of the directories below it.
+func packageDirs(dir string, recurse bool) ([]string, error) {
+	if !recurse {
+		return []string{dir}, nil
+	}
+	var packages []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != dir && info.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		packages = append(packages, path)
+		return nil
+	})
+	return packages, errors.Wrap(err)
+}
+
+func (r *SetImpactRunner) print(c *cobra.Command, name string) error {
+	if r.Output == "json" {
+		impact := r.Impact
+		if impact == nil {
+			impact = []setterImpact{}
+		}
+		b, err := json.MarshalIndent(impact, "", "  ")
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		fmt.Fprintf(c.OutOrStdout(), "%s\n", b)
+		return nil
+	}
+
+	if len(r.Impact) == 0 {
+		fmt.Fprintf(c.OutOrStdout(), "no fields reference setter %s\n", name)
+		return nil
+	}
+	table := newTable(c.OutOrStdout(), false)
+	table.SetHeader([]string{"PACKAGE", "FILE", "RESOURCE", "FIELD", "SUBSTITUTION"})
+	for _, i := range r.Impact {
+		table.Append([]string{
+			i.Package, i.File, i.Kind + "/" + i.Name, i.Field, i.Substitution})
+	}
+	table.Render()
+	return nil
+}
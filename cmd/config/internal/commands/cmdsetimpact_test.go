@@ -0,0 +1,141 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/cmd/config/internal/commands"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+)
+
+func TestSetImpactCommand(t *testing.T) {
+	d, err := ioutil.TempDir("", "kustomize-set-impact-test")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(d)
+	defer openapi.ResetOpenAPI()
+
+	files := map[string]string{
+		// references the setter directly
+		"a/Krmfile": `apiVersion: v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.tag:
+      x-k8s-cli:
+        setter:
+          name: tag
+          value: 1.7.9
+`,
+		"a/deploy.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: frontend
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.7.9 # {"$openapi":"tag"}
+      - name: sidecar
+        image: sidecar:1.7.9 # {"$openapi":"tag"}
+`,
+		// references the setter through a substitution
+		"b/Krmfile": `apiVersion: v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.tag:
+      x-k8s-cli:
+        setter:
+          name: tag
+          value: 1.7.9
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+    io.k8s.cli.substitutions.image:
+      x-k8s-cli:
+        substitution:
+          name: image
+          pattern: nginx:${tag}
+          values:
+          - marker: ${tag}
+            ref: '#/definitions/io.k8s.cli.setters.tag'
+`,
+		"b/deploy.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: backend
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.7.9 # {"$openapi":"image"}
+`,
+	}
+	for name, content := range files {
+		p := filepath.Join(d, name)
+		if !assert.NoError(t, os.MkdirAll(filepath.Dir(p), 0700)) {
+			t.FailNow()
+		}
+		if !assert.NoError(t, ioutil.WriteFile(p, []byte(content), 0600)) {
+			t.FailNow()
+		}
+	}
+
+	r := commands.NewSetImpactRunner("")
+	out := &bytes.Buffer{}
+	r.Command.SetOut(out)
+	r.Command.SetArgs([]string{d, "tag", "--recurse-subpackages", "--output", "json"})
+	if !assert.NoError(t, r.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, strings.TrimSpace(`
+[
+  {
+    "package": "a",
+    "file": "deploy.yaml",
+    "kind": "Deployment",
+    "name": "frontend",
+    "field": "spec.template.spec.containers.[name=nginx].image"
+  },
+  {
+    "package": "a",
+    "file": "deploy.yaml",
+    "kind": "Deployment",
+    "name": "frontend",
+    "field": "spec.template.spec.containers.[name=sidecar].image"
+  },
+  {
+    "package": "b",
+    "file": "deploy.yaml",
+    "kind": "Deployment",
+    "name": "backend",
+    "field": "spec.template.spec.containers.[name=nginx].image",
+    "substitution": "image"
+  }
+]`), strings.TrimSpace(out.String()))
+
+	// the files are unchanged
+	for name, content := range files {
+		actual, err := ioutil.ReadFile(filepath.Join(d, name))
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.Equal(t, content, string(actual))
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/setters2"
+)
+
+func (r *SetRunner) preRunInteractive(c *cobra.Command, args []string) error {
+	if c.Flag("values").Changed {
+		return errors.Errorf("--interactive and --values may not both be specified")
+	}
+	if c.Flag("set-by").Changed && r.NoSetBy {
+		return errors.Errorf("--set-by and --no-set-by may not both be specified")
+	}
+	if !c.Flag("set-by").Changed && !r.NoSetBy {
+		// record who set the value by default
+		r.Perform.SetBy = lookupSetBy()
+	}
+	r.Set.Description = r.Perform.Description
+	r.Set.SetBy = r.Perform.SetBy
+	var err error
+	r.OpenAPIFile, err = getOpenAPIFile(args, false, r.OpenAPIPath)
+	return err
+}
+
+// interactive prompts for the value of each setter which is required or has
+// no value, and sets it.  Values which are rejected, e.g. because they do not
+// match the setter schema, are prompted for again.
+func (r *SetRunner) interactive(c *cobra.Command, args []string) error {
+	in := c.InOrStdin()
+	if f, ok := in.(*os.File); ok && !isTerminal(f) {
+		// don't hang waiting for input which will never come
+		return errors.Errorf("--interactive requires a terminal on stdin")
+	}
+
+	l := setters2.List{}
+	if err := l.ListSetters(r.OpenAPIFile, args[0]); err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(in)
+	out := c.OutOrStdout()
+	for _, s := range l.Setters {
+		hasValue := s.Value != "" || len(s.ListValues) > 0
+		if hasValue && !s.Required {
+			continue
+		}
+		for {
+			fmt.Fprintf(out, "%s", s.Name)
+			if s.Description != "" {
+				fmt.Fprintf(out, " (%s)", s.Description)
+			}
+			if hasValue {
+				fmt.Fprintf(out, " [%s]", s.Value)
+			}
+			fmt.Fprintf(out, ": ")
+
+			line, readErr := reader.ReadString('\n')
+			if readErr != nil && readErr != io.EOF {
+				return readErr
+			}
+			if readErr == io.EOF && line == "" {
+				fmt.Fprintf(out, "\n")
+				return errors.Errorf("no value provided for setter %s", s.Name)
+			}
+			value := strings.TrimSpace(line)
+			if value == "" {
+				if hasValue {
+					// keep the current value
+					break
+				}
+				fmt.Fprintf(out, "a value is required\n")
+				continue
+			}
+
+			fs := r.Set
+			fs.Name = s.Name
+			fs.Value = value
+			count, err := fs.Set(r.OpenAPIFile, args[0])
+			if err != nil {
+				fmt.Fprintf(out, "invalid value: %v\n", err)
+				continue
+			}
+			fmt.Fprintf(out, "set %d fields\n", count)
+			break
+		}
+	}
+	return nil
+}
+
+// isTerminal returns true if f is a character device, e.g. a terminal.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
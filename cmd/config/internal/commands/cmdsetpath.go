@@ -0,0 +1,215 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+func (r *SetRunner) preRunPath(c *cobra.Command, args []string) error {
+	if r.Interactive || r.Unset || r.FromFile != "" {
+		return errors.Errorf(
+			"--path may not be specified with --interactive, --unset or --from-file")
+	}
+	valueFlagSet := c.Flag("values").Changed
+	switch {
+	case valueFlagSet && len(args) > 1:
+		return errors.Errorf("value should set either from flag or arg")
+	case valueFlagSet:
+		r.Perform.Value = r.Values[0]
+	case len(args) > 1:
+		r.Perform.Value = args[1]
+	default:
+		return errors.Errorf("--path requires a value")
+	}
+	var err error
+	r.pointer, err = parseJSONPointer(r.Path)
+	return err
+}
+
+// parseJSONPointer parses a JSON pointer -- e.g. /spec/template/spec/containers/0/image
+// -- into its unescaped path elements.
+func parseJSONPointer(pointer string) ([]string, error) {
+	if !strings.HasPrefix(pointer, "/") || len(pointer) == 1 {
+		return nil, errors.Errorf("--path %s must be a JSON pointer to a field -- e.g. /spec/replicas", pointer)
+	}
+	path := strings.Split(pointer[1:], "/")
+	for i := range path {
+		path[i] = strings.ReplaceAll(strings.ReplaceAll(path[i], "~1", "/"), "~0", "~")
+	}
+	return path, nil
+}
+
+// setPath sets the field at the JSON pointer to the value, regardless of any
+// setter references.  It fails if more than one Resource has the field.
+func (r *SetRunner) setPath(c *cobra.Command, args []string) error {
+	rw := &kio.LocalPackageReadWriter{PackagePath: args[0], NoDeleteFiles: true}
+	var modified string
+	err := kio.Pipeline{
+		Inputs: []kio.Reader{rw},
+		Filters: []kio.Filter{kio.FilterFunc(func(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
+			var err error
+			modified, err = r.setPathFilter(nodes)
+			return nodes, err
+		})},
+		Outputs: []kio.Writer{rw},
+	}.Execute()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.OutOrStdout(), "set %s on %s\n", r.Path, modified)
+	return nil
+}
+
+// setPathFilter sets the field at the JSON pointer in the single selected
+// Resource which has it, and returns the kind and name of that Resource.
+func (r *SetRunner) setPathFilter(nodes []*yaml.RNode) (string, error) {
+	selected := nodes
+	for _, f := range resourceSelectors(r.Kind, r.Name, "") {
+		var err error
+		if selected, err = f.Filter(selected); err != nil {
+			return "", err
+		}
+	}
+
+	// prefer the Resources which already have the field, only creating it
+	// if none do
+	matches := r.pointerMatches(selected, false)
+	if len(matches) == 0 && r.Create {
+		matches = r.pointerMatches(selected, true)
+	}
+	switch {
+	case len(matches) == 0 && r.Create:
+		return "", errors.Errorf("no resources selected to create %s in", r.Path)
+	case len(matches) == 0:
+		return "", errors.Errorf("no resources have field %s; use --create to create it", r.Path)
+	case len(matches) > 1:
+		var ids []string
+		for i := range matches {
+			ids = append(ids, resourceID(matches[i]))
+		}
+		return "", errors.Errorf(
+			"field %s is ambiguous, it matches %d resources (%s); select one with --kind and --name",
+			r.Path, len(matches), strings.Join(ids, ", "))
+	}
+	parent, err := lookupPointerParent(matches[0], r.pointer, r.Create)
+	if err != nil {
+		return "", err
+	}
+	return resourceID(matches[0]), setPointerChild(parent, r.pointer[len(r.pointer)-1], r.Perform.Value)
+}
+
+// pointerMatches returns the Resources which have the field at the JSON pointer.
+// If create is set, it returns the Resources in which the field could be created.
+func (r *SetRunner) pointerMatches(nodes []*yaml.RNode, create bool) []*yaml.RNode {
+	var matches []*yaml.RNode
+	for i := range nodes {
+		if create && canCreatePointer(nodes[i], r.pointer) ||
+			!create && pointerField(nodes[i], r.pointer) != nil {
+			matches = append(matches, nodes[i])
+		}
+	}
+	return matches
+}
+
+// resourceID returns the kind and name of the Resource.
+func resourceID(node *yaml.RNode) string {
+	meta, err := node.GetMeta()
+	if err != nil {
+		return ""
+	}
+	return meta.Kind + " " + meta.Name
+}
+
+// pointerField returns the field or list element at the pointer, or nil if it
+// doesn't exist.
+func pointerField(object *yaml.RNode, pointer []string) *yaml.RNode {
+	node := object
+	for _, p := range pointer {
+		if node = pointerChild(node, p); node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+// canCreatePointer returns true if the field at the pointer exists, or could
+// be created -- i.e. the first element which doesn't exist is a mapping field.
+func canCreatePointer(object *yaml.RNode, pointer []string) bool {
+	node := object
+	for _, p := range pointer {
+		child := pointerChild(node, p)
+		if child == nil {
+			return node.YNode().Kind == yaml.MappingNode
+		}
+		node = child
+	}
+	return true
+}
+
+// lookupPointerParent returns the node containing the field at the pointer, or
+// nil if it doesn't exist.  If create is set, missing fields are created.
+// Elements of lists are identified by index, and are never created.
+func lookupPointerParent(object *yaml.RNode, pointer []string, create bool) (*yaml.RNode, error) {
+	node := object
+	for _, p := range pointer[:len(pointer)-1] {
+		child := pointerChild(node, p)
+		if child == nil && create && node.YNode().Kind == yaml.MappingNode {
+			var err error
+			child, err = node.Pipe(yaml.LookupCreate(yaml.MappingNode, p))
+			if err != nil {
+				return nil, err
+			}
+		}
+		if child == nil {
+			return nil, nil
+		}
+		node = child
+	}
+	return node, nil
+}
+
+// pointerChild returns the field or list element of node at p, or nil.
+func pointerChild(node *yaml.RNode, p string) *yaml.RNode {
+	switch node.YNode().Kind {
+	case yaml.MappingNode:
+		if f := node.Field(p); f != nil {
+			return f.Value
+		}
+	case yaml.SequenceNode:
+		i, err := strconv.Atoi(p)
+		if err == nil && i >= 0 && i < len(node.YNode().Content) {
+			return yaml.NewRNode(node.YNode().Content[i])
+		}
+	}
+	return nil
+}
+
+// setPointerChild sets the field or list element of parent at p to value.
+// Existing scalars are updated in place so their comments are kept.
+func setPointerChild(parent *yaml.RNode, p, value string) error {
+	if child := pointerChild(parent, p); child != nil && child.YNode().Kind == yaml.ScalarNode {
+		child.YNode().Value = value
+		child.YNode().Tag = ""
+		child.YNode().Style = 0
+		return nil
+	}
+	switch parent.YNode().Kind {
+	case yaml.MappingNode:
+		return parent.PipeE(yaml.SetField(p, yaml.NewScalarRNode(value)))
+	case yaml.SequenceNode:
+		if i, err := strconv.Atoi(p); err == nil && i >= 0 && i < len(parent.YNode().Content) {
+			parent.YNode().Content[i] = yaml.NewScalarRNode(value).YNode()
+			return nil
+		}
+	}
+	return errors.Errorf("unable to set %s", p)
+}
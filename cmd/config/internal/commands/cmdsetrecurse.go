@@ -0,0 +1,97 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/cmd/config/ext"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/krmfile"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+)
+
+// packageSummary is the number of fields changed by setting the setter on a
+// package.
+type packageSummary struct {
+	// Package is the path to the package, relative to DIR.
+	Package string
+
+	// Changed is the number of fields whose value was, or would be, changed.
+	Changed int
+}
+
+// preRunPackages validates the flags for setting the setter on the subpackages
+// of DIR, or without writing.
+func (r *SetRunner) preRunPackages(args []string) error {
+	if r.Interactive || r.Unset || r.InlineOpenAPI || r.Path != "" || isGlob(args[0]) {
+		return errors.Errorf("--dry-run and --recurse-subpackages may not be specified " +
+			"with --interactive, --unset, --inline-openapi, --path or a glob")
+	}
+	if len(args) < 2 {
+		return errors.Errorf("--dry-run and --recurse-subpackages require a setter NAME")
+	}
+	// subpackages are only supported by setters created with create-setter
+	setterVersion = "v2"
+	return nil
+}
+
+// setPackages sets the setter on DIR, and on each of its subpackages if
+// RecurseSubPackages is set, each with its own OpenAPI file.  With DryRun
+// nothing is written.  Prints the number of fields changed in each package,
+// and the total.
+func (r *SetRunner) setPackages(c *cobra.Command, args []string) error {
+	dirs, err := packageDirs(args[0], r.RecurseSubPackages)
+	if err != nil {
+		return err
+	}
+	var summary []packageSummary
+	var total, matched int
+	for _, dir := range dirs {
+		openAPIFile, err := ext.GetOpenAPIFile(append([]string{dir}, args[1:]...))
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(openAPIFile); err != nil {
+			if dir == args[0] && !r.RecurseSubPackages {
+				return err
+			}
+			// not a package -- it defines no setters
+			continue
+		}
+		// each package has its own setter definitions
+		openapi.ResetOpenAPI()
+		s := r.Set
+		s.DryRun = r.DryRun
+		s.PackageFileName = krmfile.KrmfileName
+		count, err := s.Set(openAPIFile, dir)
+		if err != nil {
+			return errors.WrapPrefixf(err, dir)
+		}
+		matched += count
+		rel, err := filepath.Rel(args[0], dir)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		summary = append(summary, packageSummary{Package: rel, Changed: s.ChangedFields})
+		total += s.ChangedFields
+	}
+
+	verb := "changed"
+	if r.DryRun {
+		verb = "would change"
+	}
+	table := newTable(c.OutOrStdout(), false)
+	table.SetHeader([]string{"PACKAGE", "FIELDS"})
+	for _, s := range summary {
+		table.Append([]string{s.Package, fmt.Sprintf("%d", s.Changed)})
+	}
+	table.Render()
+	fmt.Fprintf(c.OutOrStdout(), "%s %d fields in %d packages\n", verb, total, len(summary))
+	r.warnNoFieldMatch(c, matched)
+	return nil
+}
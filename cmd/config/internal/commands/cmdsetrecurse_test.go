@@ -0,0 +1,212 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/cmd/config/internal/commands"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+)
+
+func TestSetCommand_dryRunRecurseSubPackages(t *testing.T) {
+	d, err := ioutil.TempDir("", "kustomize-set-recurse-test")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(d)
+	defer openapi.ResetOpenAPI()
+
+	krmfile := `apiVersion: v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+`
+	files := map[string]string{
+		"a/Krmfile": krmfile,
+		"a/deploy.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: frontend
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: backend
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+`,
+		"b/Krmfile": krmfile,
+		"b/deploy.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: worker
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+`,
+		// already has the value
+		"b/c/Krmfile": krmfile,
+		"b/c/deploy.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: cron
+spec:
+  replicas: 5 # {"$openapi":"replicas"}
+`,
+	}
+	for name, content := range files {
+		p := filepath.Join(d, name)
+		if !assert.NoError(t, os.MkdirAll(filepath.Dir(p), 0700)) {
+			t.FailNow()
+		}
+		if !assert.NoError(t, ioutil.WriteFile(p, []byte(content), 0600)) {
+			t.FailNow()
+		}
+	}
+
+	r := commands.NewSetRunner("")
+	out := &bytes.Buffer{}
+	r.Command.SetOut(out)
+	r.Command.SetArgs([]string{
+		d, "replicas", "5", "--dry-run", "--recurse-subpackages", "--no-set-by"})
+	if !assert.NoError(t, r.Command.Execute()) {
+		t.FailNow()
+	}
+	var rows [][]string
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		rows = append(rows, strings.Fields(line))
+	}
+	assert.Equal(t, [][]string{
+		{"PACKAGE", "FIELDS"},
+		{"a", "2"},
+		{"b", "1"},
+		{"b/c", "0"},
+		{"would", "change", "3", "fields", "in", "3", "packages"},
+	}, rows)
+
+	// the files are unchanged
+	for name, content := range files {
+		actual, err := ioutil.ReadFile(filepath.Join(d, name))
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.Equal(t, content, string(actual))
+	}
+}
+
+func TestSetCommand_recurseSubPackagesOpenAPIFileAnnotation(t *testing.T) {
+	d, err := ioutil.TempDir("", "kustomize-set-recurse-test")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(d)
+	defer openapi.ResetOpenAPI()
+
+	files := map[string]string{
+		"Krmfile": `apiVersion: v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+`,
+		"deploy.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: frontend
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+`,
+		// the subpackage keeps its definitions in another file
+		"sub/Krmfile": `apiVersion: v1alpha1
+kind: Krmfile
+metadata:
+  annotations:
+    config.kubernetes.io/openapi-file: definitions/setters.yaml
+`,
+		"sub/definitions/setters.yaml": `apiVersion: v1alpha1
+kind: OpenAPI
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "1"
+`,
+		"sub/deploy.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: backend
+spec:
+  replicas: 1 # {"$openapi":"replicas"}
+`,
+	}
+	for name, content := range files {
+		p := filepath.Join(d, name)
+		if !assert.NoError(t, os.MkdirAll(filepath.Dir(p), 0700)) {
+			t.FailNow()
+		}
+		if !assert.NoError(t, ioutil.WriteFile(p, []byte(content), 0600)) {
+			t.FailNow()
+		}
+	}
+
+	r := commands.NewSetRunner("")
+	out := &bytes.Buffer{}
+	r.Command.SetOut(out)
+	r.Command.SetArgs([]string{d, "replicas", "5", "--recurse-subpackages", "--no-set-by"})
+	if !assert.NoError(t, r.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Contains(t, out.String(), "changed 2 fields in 2 packages\n")
+
+	// the subpackage definitions are set in the annotated file
+	actual, err := ioutil.ReadFile(filepath.Join(d, "sub", "definitions", "setters.yaml"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `apiVersion: v1alpha1
+kind: OpenAPI
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "5"
+`, string(actual))
+	actual, err = ioutil.ReadFile(filepath.Join(d, "sub", "Krmfile"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, files["sub/Krmfile"], string(actual))
+	actual, err = ioutil.ReadFile(filepath.Join(d, "sub", "deploy.yaml"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: backend
+spec:
+  replicas: 5 # {"$openapi":"replicas"}
+`, string(actual))
+}
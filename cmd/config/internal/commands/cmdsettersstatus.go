@@ -0,0 +1,194 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/cmd/config/ext"
+	"sigs.k8s.io/kustomize/cmd/config/internal/generateddocs/commands"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/krmfile"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+	"sigs.k8s.io/kustomize/kyaml/setters2"
+	"sigs.k8s.io/kustomize/kyaml/setters2/settersutil"
+)
+
+// NewSettersStatusRunner returns a command runner.
+func NewSettersStatusRunner(parent string) *SettersStatusRunner {
+	r := &SettersStatusRunner{}
+	c := &cobra.Command{
+		Use:     "setters-status DIR",
+		Args:    cobra.ExactArgs(1),
+		Short:   commands.SettersStatusShort,
+		Long:    commands.SettersStatusLong,
+		Example: commands.SettersStatusExamples,
+		RunE:    r.runE,
+	}
+	fixDocs(parent, c)
+	c.Flags().BoolVar(&r.Fix, "fix", false,
+		"set the fields which are out of sync back to the values of their setters.")
+	c.Flags().BoolVar(&r.RecurseSubPackages, "recurse-subpackages", false,
+		"include the subpackages of DIR -- directories containing their own Krmfile.")
+	r.Command = c
+	return r
+}
+
+func SettersStatusCommand(parent string) *cobra.Command {
+	return NewSettersStatusRunner(parent).Command
+}
+
+type SettersStatusRunner struct {
+	Command *cobra.Command
+
+	// Fix sets the fields which are out of sync back to the values of their
+	// setters.
+	Fix bool
+
+	// RecurseSubPackages includes the subpackages of the directory.
+	RecurseSubPackages bool
+
+	// Drift are the fields which are out of sync with their setters.
+	Drift []setterDrift
+
+	// Fixed is the number of fields set back to the values of their setters.
+	Fixed int
+}
+
+// setterDrift is a field whose value differs from the value of the setter
+// it references.
+type setterDrift struct {
+	// Package is the path to the package, relative to DIR.
+	Package string
+
+	settersutil.SetterReference
+
+	// Setter is the setter referenced by the field.
+	Setter setters2.SetterDefinition
+}
+
+func (r *SettersStatusRunner) runE(c *cobra.Command, args []string) error {
+	packages, err := packageDirs(args[0], r.RecurseSubPackages)
+	if err != nil {
+		return handleError(c, err)
+	}
+	for _, pkg := range packages {
+		openAPIFile, err := ext.GetOpenAPIFile([]string{pkg})
+		if err != nil {
+			return handleError(c, err)
+		}
+		if _, err := os.Stat(openAPIFile); err != nil {
+			// not a package -- it defines no setters
+			continue
+		}
+		if err := r.status(args[0], pkg, openAPIFile); err != nil {
+			return handleError(c, err)
+		}
+	}
+	r.print(c)
+
+	if len(r.Drift) == 0 {
+		fmt.Fprintln(c.OutOrStdout(), "all fields are in sync with their setters")
+		return nil
+	}
+	if r.Fix {
+		fmt.Fprintf(c.OutOrStdout(), "fixed %d fields\n", r.Fixed)
+		return nil
+	}
+	// exit non-0 so that fields edited by hand are caught, e.g. by CI
+	return handleError(c, errors.Errorf(
+		"%d fields are out of sync with their setters, run with --fix to set them", len(r.Drift)))
+}
+
+// status records the fields of pkg which are out of sync with their setters,
+// setting them back to the values of the setters if r.Fix is set.
+func (r *SettersStatusRunner) status(dir, pkg, openAPIFile string) error {
+	// each package has its own setter definitions
+	openapi.ResetOpenAPI()
+	l := setters2.List{}
+	if err := l.ListSetters(openAPIFile, pkg); err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(dir, pkg)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	var names []string
+	var fixed int
+	for _, s := range l.Setters {
+		if s.Value == "" && len(s.ListValues) == 0 {
+			// never set -- the fields still have their placeholder values
+			continue
+		}
+		refs, err := settersutil.SetterReferences(openAPIFile, pkg, s.Name)
+		if err != nil {
+			return err
+		}
+		var drift bool
+		for i := range refs {
+			if drifted(refs[i], s) {
+				r.Drift = append(r.Drift,
+					setterDrift{Package: rel, SetterReference: refs[i], Setter: s})
+				drift = true
+				fixed++
+			}
+		}
+		if drift {
+			names = append(names, s.Name)
+		}
+	}
+	if !r.Fix {
+		return nil
+	}
+	for _, name := range names {
+		if err := r.fix(pkg, name); err != nil {
+			return err
+		}
+	}
+	// the fields in sync may be rewritten too, e.g. to quote list items,
+	// so only the drifted fields are counted
+	r.Fixed += fixed
+	return nil
+}
+
+// fix sets the fields of pkg referencing the setter name to its value.  The
+// setter definition is unchanged.
+func (r *SettersStatusRunner) fix(pkg, name string) error {
+	rw := &kio.LocalPackageReadWriter{
+		PackagePath:     pkg,
+		PackageFileName: krmfile.KrmfileName,
+		NoDeleteFiles:   true,
+	}
+	s := &setters2.Set{Name: name}
+	err := kio.Pipeline{
+		Inputs:  []kio.Reader{rw},
+		Filters: []kio.Filter{setters2.SetAll(s)},
+		Outputs: []kio.Writer{rw},
+	}.Execute()
+	return err
+}
+
+func (r *SettersStatusRunner) print(c *cobra.Command) {
+	if len(r.Drift) == 0 {
+		return
+	}
+	table := newTable(c.OutOrStdout(), false)
+	table.SetHeader([]string{"PACKAGE", "SETTER", "FILE", "RESOURCE", "FIELD", "VALUE", "SETTER VALUE"})
+	for _, d := range r.Drift {
+		value, setterValue := d.Value, d.Setter.Value
+		if d.ListValues != nil {
+			value = "[" + strings.Join(d.ListValues, ",") + "]"
+			setterValue = "[" + strings.Join(d.Setter.ListValues, ",") + "]"
+		}
+		table.Append([]string{
+			d.Package, d.Setter.Name, d.File, d.Kind + "/" + d.Name, d.Field, value, setterValue})
+	}
+	table.Render()
+}
@@ -0,0 +1,141 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/cmd/config/internal/commands"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+)
+
+func TestSettersStatusCommand(t *testing.T) {
+	d, err := ioutil.TempDir("", "kustomize-setters-status-test")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(d)
+	defer openapi.ResetOpenAPI()
+
+	files := map[string]string{
+		"Krmfile": `apiVersion: v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+    io.k8s.cli.setters.args:
+      type: array
+      x-k8s-cli:
+        setter:
+          name: args
+          listValues:
+          - a
+          - b
+    io.k8s.cli.setters.image:
+      x-k8s-cli:
+        setter:
+          name: image
+          value: ""
+`,
+		"deploy.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: frontend
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+  image: IMAGE # {"$openapi":"image"}
+  args: # {"$openapi":"args"}
+  - a
+  - b
+`,
+		// edited by hand
+		"worker.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: worker
+spec:
+  replicas: 5 # {"$openapi":"replicas"}
+  args: # {"$openapi":"args"}
+  - a
+`,
+	}
+	for name, content := range files {
+		if !assert.NoError(t, ioutil.WriteFile(filepath.Join(d, name), []byte(content), 0600)) {
+			t.FailNow()
+		}
+	}
+
+	// the fields edited by hand are reported, and the command fails
+	r := commands.NewSettersStatusRunner("")
+	out := &bytes.Buffer{}
+	r.Command.SetOut(out)
+	r.Command.SetArgs([]string{d})
+	err = r.Command.Execute()
+	if !assert.Error(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, "2 fields are out of sync with their setters, run with --fix to set them", err.Error())
+	if !assert.Len(t, r.Drift, 2) {
+		t.FailNow()
+	}
+	assert.Equal(t, "args", r.Drift[0].Setter.Name)
+	assert.Equal(t, []string{"a"}, r.Drift[0].ListValues)
+	assert.Equal(t, "replicas", r.Drift[1].Setter.Name)
+	assert.Equal(t, "5", r.Drift[1].Value)
+	assert.Contains(t, out.String(), "worker.yaml")
+	assert.NotContains(t, out.String(), "deploy.yaml")
+
+	// the fields are set back to the values of their setters
+	openapi.ResetOpenAPI()
+	r = commands.NewSettersStatusRunner("")
+	out = &bytes.Buffer{}
+	r.Command.SetOut(out)
+	r.Command.SetArgs([]string{d, "--fix"})
+	if !assert.NoError(t, r.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.True(t, strings.HasSuffix(out.String(), "fixed 2 fields\n"), out.String())
+
+	b, err := ioutil.ReadFile(filepath.Join(d, "worker.yaml"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: worker
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+  args: # {"$openapi":"args"}
+  - "a"
+  - "b"
+`, string(b))
+
+	// the setter definitions are unchanged
+	b, err = ioutil.ReadFile(filepath.Join(d, "Krmfile"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, files["Krmfile"], string(b))
+
+	openapi.ResetOpenAPI()
+	r = commands.NewSettersStatusRunner("")
+	out = &bytes.Buffer{}
+	r.Command.SetOut(out)
+	r.Command.SetArgs([]string{d})
+	if !assert.NoError(t, r.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, "all fields are in sync with their setters\n", out.String())
+}
@@ -0,0 +1,138 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/setters2"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+func (r *SetRunner) preRunValuesFile(c *cobra.Command, args []string) error {
+	if c.Flag("values").Changed || r.Interactive || r.Unset || r.FromFile != "" || r.Path != "" {
+		return errors.Errorf("--values-file may not be specified with " +
+			"--values, --interactive, --unset, --from-file or --path")
+	}
+	if r.InlineOpenAPI || r.DryRun || r.RecurseSubPackages {
+		return errors.Errorf("--values-file may not be specified with " +
+			"--inline-openapi, --dry-run or --recurse-subpackages")
+	}
+	if c.Flag("set-by").Changed && r.NoSetBy {
+		return errors.Errorf("--set-by and --no-set-by may not both be specified")
+	}
+	if !c.Flag("set-by").Changed && !r.NoSetBy {
+		// record who set the values by default
+		r.Perform.SetBy = lookupSetBy()
+	}
+	r.Set.Description = r.Perform.Description
+	r.Set.SetBy = r.Perform.SetBy
+
+	var err error
+	r.fileValues, err = readValuesFile(r.ValuesFile)
+	if err != nil {
+		return err
+	}
+	// values files are only supported by setters created with create-setter
+	setterVersion = "v2"
+	r.OpenAPIFile, err = getOpenAPIFile(args, false, r.OpenAPIPath)
+	return err
+}
+
+// readValuesFile reads the values of the setters from a YAML or JSON file
+// mapping setter names to values.  The values of list setters are lists.
+func readValuesFile(path string) (map[string][]string, error) {
+	object, err := yaml.ReadFile(path)
+	if err != nil {
+		return nil, errors.WrapPrefixf(err, "--values-file %s", path)
+	}
+	if object.YNode().Kind != yaml.MappingNode {
+		return nil, errors.Errorf("--values-file %s must map setter names to values", path)
+	}
+	values := map[string][]string{}
+	err = object.VisitFields(func(node *yaml.MapNode) error {
+		name := node.Key.YNode().Value
+		value := node.Value.YNode()
+		switch value.Kind {
+		case yaml.ScalarNode:
+			values[name] = []string{value.Value}
+		case yaml.SequenceNode:
+			if len(value.Content) == 0 {
+				return errors.Errorf("value of setter %s must not be an empty list", name)
+			}
+			for _, item := range value.Content {
+				if item.Kind != yaml.ScalarNode {
+					return errors.Errorf("values of list setter %s must be scalars", name)
+				}
+				values[name] = append(values[name], item.Value)
+			}
+		default:
+			return errors.Errorf("value of setter %s must be a scalar or a list", name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WrapPrefixf(err, "--values-file %s", path)
+	}
+	return values, nil
+}
+
+// setValuesFile sets each of the setters in the values file, and prints
+// whether each was set.  Values which are rejected, e.g. because they do not
+// match the setter schema, don't prevent the other setters from being set.
+func (r *SetRunner) setValuesFile(c *cobra.Command, args []string) error {
+	l := setters2.List{}
+	if err := l.ListSetters(r.OpenAPIFile, args[0]); err != nil {
+		return err
+	}
+	defined := map[string]bool{}
+	for _, s := range l.Setters {
+		defined[s.Name] = true
+	}
+
+	var names, unknown []string
+	for name := range r.fileValues {
+		names = append(names, name)
+		if !defined[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(names)
+	sort.Strings(unknown)
+	if len(unknown) > 0 && !r.IgnoreUnknown {
+		return errors.Errorf("%s sets unknown setters %s, "+
+			"use --ignore-unknown to skip them", r.ValuesFile, strings.Join(unknown, ", "))
+	}
+
+	out := c.OutOrStdout()
+	var failed int
+	for _, name := range names {
+		if !defined[name] {
+			fmt.Fprintf(out, "%s: skipped unknown setter\n", name)
+			continue
+		}
+		fs := r.Set
+		fs.Name = name
+		fs.Value = r.fileValues[name][0]
+		fs.ListValues = r.fileValues[name][1:]
+		count, err := fs.Set(r.OpenAPIFile, args[0])
+		switch {
+		case err != nil:
+			fmt.Fprintf(out, "%s: failed: %v\n", name, err)
+			failed++
+		case !fs.Changed:
+			fmt.Fprintf(out, "%s: no change\n", name)
+		default:
+			fmt.Fprintf(out, "%s: set %d fields\n", name, count)
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf("failed to set %d of the setters in %s", failed, r.ValuesFile)
+	}
+	return nil
+}
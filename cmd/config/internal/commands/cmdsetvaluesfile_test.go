@@ -0,0 +1,220 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/cmd/config/internal/commands"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+)
+
+func TestSetCommand_valuesFile(t *testing.T) {
+	krmfile := `apiVersion: v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      type: integer
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+    io.k8s.cli.setters.image:
+      x-k8s-cli:
+        setter:
+          name: image
+          value: nginx
+    io.k8s.cli.setters.args:
+      type: array
+      x-k8s-cli:
+        setter:
+          name: args
+          listValues:
+          - --verbose
+`
+	deployment := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx # {"$openapi":"image"}
+        args: # {"$openapi":"args"}
+        - --verbose
+`
+	var tests = []struct {
+		name     string
+		values   string
+		args     []string
+		out      string
+		err      string
+		expected string
+	}{
+		{
+			name: "set",
+			values: `replicas: 5
+image: nginx:1.8
+args: [--verbose, --port=8080]
+`,
+			out: `args: set 1 fields
+image: set 1 fields
+replicas: set 1 fields
+`,
+			expected: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 5 # {"$openapi":"replicas"}
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.8 # {"$openapi":"image"}
+        args: # {"$openapi":"args"}
+        - "--verbose"
+        - "--port=8080"
+`,
+		},
+		{
+			name:   "json",
+			values: `{"image": "nginx:1.8", "replicas": 3}`,
+			out: `image: set 1 fields
+replicas: no change
+`,
+			expected: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.8 # {"$openapi":"image"}
+        args: # {"$openapi":"args"}
+        - --verbose
+`,
+		},
+		{
+			name: "unknown setter",
+			values: `image: nginx:1.8
+tag: "1.8"
+`,
+			err:      "sets unknown setters tag, use --ignore-unknown to skip them",
+			expected: deployment,
+		},
+		{
+			name: "ignore unknown setter",
+			values: `image: nginx:1.8
+tag: "1.8"
+`,
+			args: []string{"--ignore-unknown"},
+			out: `image: set 1 fields
+tag: skipped unknown setter
+`,
+			expected: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.8 # {"$openapi":"image"}
+        args: # {"$openapi":"args"}
+        - --verbose
+`,
+		},
+		{
+			name: "invalid value",
+			values: `image: nginx:1.8
+replicas: many
+`,
+			err: "failed to set 1 of the setters",
+			out: "image: set 1 fields\nreplicas: failed: ",
+			expected: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.8 # {"$openapi":"image"}
+        args: # {"$openapi":"args"}
+        - --verbose
+`,
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			// reset the openAPI afterward
+			openapi.ResetOpenAPI()
+			defer openapi.ResetOpenAPI()
+
+			d, err := ioutil.TempDir("", "kustomize-set-values-file-test")
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			defer os.RemoveAll(d)
+			files := map[string]string{
+				"Krmfile":         krmfile,
+				"deployment.yaml": deployment,
+			}
+			for name, data := range files {
+				err := ioutil.WriteFile(filepath.Join(d, name), []byte(data), 0600)
+				if !assert.NoError(t, err) {
+					t.FailNow()
+				}
+			}
+			valuesFile := filepath.Join(d, "values")
+			if !assert.NoError(t, ioutil.WriteFile(valuesFile, []byte(test.values), 0600)) {
+				t.FailNow()
+			}
+
+			out := &bytes.Buffer{}
+			r := commands.NewSetRunner("")
+			r.Command.SetOut(out)
+			r.Command.SetErr(&bytes.Buffer{})
+			r.Command.SilenceUsage = true
+			r.Command.SetArgs(append([]string{d, "--values-file", valuesFile, "--no-set-by"},
+				test.args...))
+			err = r.Command.Execute()
+			if test.err != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), test.err)
+				}
+				assert.Contains(t, out.String(), test.out)
+			} else {
+				if !assert.NoError(t, err) {
+					t.FailNow()
+				}
+				assert.Equal(t, test.out, out.String())
+			}
+
+			actual, err := ioutil.ReadFile(filepath.Join(d, "deployment.yaml"))
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, test.expected, string(actual))
+		})
+	}
+}
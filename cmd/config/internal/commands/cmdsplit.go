@@ -0,0 +1,109 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/cmd/config/internal/generateddocs/commands"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/kio/filters"
+	"sigs.k8s.io/kustomize/kyaml/kio/kioutil"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// NewSplitRunner returns a command runner.
+func NewSplitRunner(parent string) *SplitRunner {
+	r := &SplitRunner{}
+	c := &cobra.Command{
+		Use:     "split FILE",
+		Args:    cobra.ExactArgs(1),
+		Short:   commands.SplitShort,
+		Long:    commands.SplitLong,
+		Example: commands.SplitExamples,
+		PreRunE: r.preRunE,
+		RunE:    r.runE,
+	}
+	fixDocs(parent, c)
+	c.Flags().StringVar(&r.By, "by", splitByKind,
+		"how to group the Resources into files.  may be 'kind'.")
+	c.Flags().StringVar(&r.Out, "out", "",
+		"directory to write the files to.  created if it doesn't exist.")
+	_ = c.MarkFlagRequired("out")
+	r.Command = c
+	return r
+}
+
+func SplitCommand(parent string) *cobra.Command {
+	return NewSplitRunner(parent).Command
+}
+
+const splitByKind = "kind"
+
+// splitPatterns are the file name patterns used for each --by value.
+var splitPatterns = map[string]string{
+	splitByKind: string(filters.KindFmt) + ".yaml",
+}
+
+type SplitRunner struct {
+	Command *cobra.Command
+
+	// By is how the Resources are grouped into files.
+	By string
+
+	// Out is the directory the files are written to.
+	Out string
+
+	// Resources is the number of Resources written.
+	Resources int
+
+	// Files is the number of files written.
+	Files int
+}
+
+func (r *SplitRunner) preRunE(c *cobra.Command, args []string) error {
+	if _, found := splitPatterns[r.By]; !found {
+		return errors.Errorf("unsupported --by %s; may be %s", r.By, splitByKind)
+	}
+	return nil
+}
+
+func (r *SplitRunner) runE(c *cobra.Command, args []string) error {
+	if err := os.MkdirAll(r.Out, 0700); err != nil {
+		return handleError(c, errors.Wrap(err))
+	}
+	err := kio.Pipeline{
+		Inputs: []kio.Reader{kio.LocalPackageReader{PackagePath: args[0]}},
+		Filters: []kio.Filter{
+			// keeps the order of the Resources within each file
+			&filters.FileSetter{FilenamePattern: splitPatterns[r.By], Override: true},
+			kio.FilterFunc(r.count),
+		},
+		Outputs: []kio.Writer{kio.LocalPackageWriter{PackagePath: r.Out}},
+	}.Execute()
+	if err != nil {
+		return handleError(c, err)
+	}
+	fmt.Fprintf(c.OutOrStdout(), "split %d resources into %d files\n",
+		r.Resources, r.Files)
+	return nil
+}
+
+// count records the number of Resources and files written.
+func (r *SplitRunner) count(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
+	files := map[string]bool{}
+	for i := range nodes {
+		path, _, err := kioutil.GetFileAnnotations(nodes[i])
+		if err != nil {
+			return nil, err
+		}
+		files[path] = true
+	}
+	r.Resources = len(nodes)
+	r.Files = len(files)
+	return nodes, nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/cmd/config/internal/commands"
+)
+
+func TestSplitCommand(t *testing.T) {
+	d, err := ioutil.TempDir("", "kustomize-split-test")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(d)
+
+	input := filepath.Join(d, "all.yaml")
+	err = ioutil.WriteFile(input, []byte(`# the frontend
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: frontend
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: frontend
+spec:
+  type: LoadBalancer # public
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+---
+# the backend
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: backend
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: backend
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	out := filepath.Join(d, "out")
+	r := commands.NewSplitRunner("")
+	b := &bytes.Buffer{}
+	r.Command.SetOut(b)
+	r.Command.SetArgs([]string{input, "--by", "kind", "--out", out})
+	if !assert.NoError(t, r.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Equal(t, "split 5 resources into 3 files\n", b.String())
+
+	expected := map[string]string{
+		"deployment.yaml": `# the frontend
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: frontend
+---
+# the backend
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: backend
+`,
+		"service.yaml": `apiVersion: v1
+kind: Service
+metadata:
+  name: frontend
+spec:
+  type: LoadBalancer # public
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: backend
+`,
+		"configmap.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+`,
+	}
+	files, err := ioutil.ReadDir(out)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Len(t, files, len(expected))
+	for name, content := range expected {
+		actual, err := ioutil.ReadFile(filepath.Join(out, name))
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.Equal(t, content, string(actual), name)
+	}
+}
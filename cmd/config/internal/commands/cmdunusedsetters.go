@@ -0,0 +1,143 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/cmd/config/ext"
+	"sigs.k8s.io/kustomize/cmd/config/internal/generateddocs/commands"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/fieldmeta"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+	"sigs.k8s.io/kustomize/kyaml/setters2"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// NewUnusedSettersRunner returns a command runner.
+func NewUnusedSettersRunner(parent string) *UnusedSettersRunner {
+	r := &UnusedSettersRunner{}
+	c := &cobra.Command{
+		Use:     "unused-setters DIR",
+		Args:    cobra.ExactArgs(1),
+		Short:   commands.UnusedSettersShort,
+		Long:    commands.UnusedSettersLong,
+		Example: commands.UnusedSettersExamples,
+		PreRunE: r.preRunE,
+		RunE:    r.runE,
+	}
+	fixDocs(parent, c)
+	c.Flags().StringVar(&r.Output, "output", "table",
+		"output format -- one of table or json.")
+	c.Flags().BoolVar(&r.Delete, "delete", false,
+		"delete the unused setter definitions from the Krmfile.")
+	r.Command = c
+	return r
+}
+
+func UnusedSettersCommand(parent string) *cobra.Command {
+	return NewUnusedSettersRunner(parent).Command
+}
+
+type UnusedSettersRunner struct {
+	Command *cobra.Command
+
+	// Output is the output format -- table or json.
+	Output string
+
+	// Delete deletes the unused setter definitions.
+	Delete bool
+
+	// Unused are the setters which aren't referenced by any field.
+	Unused []setters2.SetterDefinition
+}
+
+// unusedSetter is the json output for an unused setter.
+type unusedSetter struct {
+	Name        string `json:"name"`
+	Value       string `json:"value,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+func (r *UnusedSettersRunner) preRunE(c *cobra.Command, args []string) error {
+	if r.Output != "table" && r.Output != "json" {
+		return errors.Errorf("--output must be one of table or json, was %s", r.Output)
+	}
+	return nil
+}
+
+func (r *UnusedSettersRunner) runE(c *cobra.Command, args []string) error {
+	openAPIFile, err := ext.GetOpenAPIFile(args)
+	if err != nil {
+		return handleError(c, err)
+	}
+	l := setters2.List{}
+	if err := l.ListSetters(openAPIFile, args[0]); err != nil {
+		return handleError(c, err)
+	}
+	for i := range l.Setters {
+		// fields referencing a substitution count towards its setters
+		if l.Setters[i].Count == 0 {
+			r.Unused = append(r.Unused, l.Setters[i])
+		}
+	}
+	if r.Delete && len(r.Unused) > 0 {
+		if err := r.deleteUnused(openAPIFile); err != nil {
+			return handleError(c, err)
+		}
+	}
+	return handleError(c, r.print(c))
+}
+
+// deleteUnused deletes the definitions of the unused setters from the OpenAPI file.
+func (r *UnusedSettersRunner) deleteUnused(openAPIFile string) error {
+	return yaml.UpdateFile(yaml.FilterFunc(func(object *yaml.RNode) (*yaml.RNode, error) {
+		definitions, err := object.Pipe(yaml.Lookup(
+			openapi.SupplementaryOpenAPIFieldName, "definitions"))
+		if err != nil || definitions == nil {
+			return object, err
+		}
+		for i := range r.Unused {
+			_, err := definitions.Pipe(yaml.Clear(
+				fieldmeta.SetterDefinitionPrefix + r.Unused[i].Name))
+			if err != nil {
+				return nil, err
+			}
+		}
+		return object, nil
+	}), openAPIFile)
+}
+
+func (r *UnusedSettersRunner) print(c *cobra.Command) error {
+	if r.Output == "json" {
+		unused := []unusedSetter{}
+		for _, s := range r.Unused {
+			unused = append(unused, unusedSetter{
+				Name: s.Name, Value: s.Value, Description: s.Description})
+		}
+		b, err := json.MarshalIndent(unused, "", "  ")
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		fmt.Fprintf(c.OutOrStdout(), "%s\n", b)
+		return nil
+	}
+
+	if len(r.Unused) == 0 {
+		fmt.Fprintf(c.OutOrStdout(), "no unused setters\n")
+		return nil
+	}
+	table := newTable(c.OutOrStdout(), false)
+	table.SetHeader([]string{"NAME", "VALUE", "DESCRIPTION"})
+	for _, s := range r.Unused {
+		table.Append([]string{s.Name, s.Value, s.Description})
+	}
+	table.Render()
+	if r.Delete {
+		fmt.Fprintf(c.OutOrStdout(), "deleted %d unused setters\n", len(r.Unused))
+	}
+	return nil
+}
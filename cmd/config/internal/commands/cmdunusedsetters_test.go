@@ -0,0 +1,154 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/cmd/config/internal/commands"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+)
+
+const unusedSettersKrmfile = `apiVersion: v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.image:
+      x-k8s-cli:
+        setter:
+          name: image
+          value: nginx
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+    io.k8s.cli.setters.tag:
+      description: removed with the sidecar
+      x-k8s-cli:
+        setter:
+          name: tag
+          value: "1.7"
+    io.k8s.cli.substitutions.image-full:
+      x-k8s-cli:
+        substitution:
+          name: image-full
+          pattern: ${image}:1.7
+          values:
+          - marker: ${image}
+            ref: '#/definitions/io.k8s.cli.setters.image'
+`
+
+const unusedSettersResources = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.7 # {"$openapi":"image-full"}
+`
+
+func TestUnusedSettersCommand(t *testing.T) {
+	var tests = []struct {
+		name            string
+		args            []string
+		expectedOut     string
+		expectedKrmfile string
+	}{
+		{
+			name: "json",
+			args: []string{"--output", "json"},
+			expectedOut: `[
+  {
+    "name": "tag",
+    "value": "1.7",
+    "description": "removed with the sidecar"
+  }
+]
+`,
+			expectedKrmfile: unusedSettersKrmfile,
+		},
+		{
+			name: "delete",
+			args: []string{"--output", "json", "--delete"},
+			expectedOut: `[
+  {
+    "name": "tag",
+    "value": "1.7",
+    "description": "removed with the sidecar"
+  }
+]
+`,
+			expectedKrmfile: `apiVersion: v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.image:
+      x-k8s-cli:
+        setter:
+          name: image
+          value: nginx
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+    io.k8s.cli.substitutions.image-full:
+      x-k8s-cli:
+        substitution:
+          name: image-full
+          pattern: ${image}:1.7
+          values:
+          - marker: ${image}
+            ref: '#/definitions/io.k8s.cli.setters.image'
+`,
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			// reset the openAPI afterward
+			openapi.ResetOpenAPI()
+			defer openapi.ResetOpenAPI()
+
+			d, err := ioutil.TempDir("", "kustomize-unused-setters-test")
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			defer os.RemoveAll(d)
+			err = ioutil.WriteFile(filepath.Join(d, "Krmfile"), []byte(unusedSettersKrmfile), 0600)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			err = ioutil.WriteFile(filepath.Join(d, "deploy.yaml"), []byte(unusedSettersResources), 0600)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+
+			r := commands.NewUnusedSettersRunner("")
+			out := &bytes.Buffer{}
+			r.Command.SetOut(out)
+			r.Command.SetArgs(append([]string{d}, test.args...))
+			if !assert.NoError(t, r.Command.Execute()) {
+				t.FailNow()
+			}
+			assert.Equal(t, test.expectedOut, out.String())
+
+			actual, err := ioutil.ReadFile(filepath.Join(d, "Krmfile"))
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, test.expectedKrmfile, string(actual))
+		})
+	}
+}
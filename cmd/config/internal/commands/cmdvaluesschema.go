@@ -0,0 +1,56 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/cmd/config/ext"
+	"sigs.k8s.io/kustomize/cmd/config/internal/generateddocs/commands"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/setters2"
+)
+
+// NewValuesSchemaRunner returns a command runner.
+func NewValuesSchemaRunner(parent string) *ValuesSchemaRunner {
+	r := &ValuesSchemaRunner{}
+	c := &cobra.Command{
+		Use:     "values-schema DIR",
+		Args:    cobra.ExactArgs(1),
+		Short:   commands.ValuesSchemaShort,
+		Long:    commands.ValuesSchemaLong,
+		Example: commands.ValuesSchemaExamples,
+		RunE:    r.runE,
+	}
+	fixDocs(parent, c)
+	r.Command = c
+	return r
+}
+
+func ValuesSchemaCommand(parent string) *cobra.Command {
+	return NewValuesSchemaRunner(parent).Command
+}
+
+type ValuesSchemaRunner struct {
+	Command *cobra.Command
+}
+
+func (r *ValuesSchemaRunner) runE(c *cobra.Command, args []string) error {
+	openAPIFile, err := ext.GetOpenAPIFile(args)
+	if err != nil {
+		return handleError(c, err)
+	}
+	schema, err := setters2.ValuesSchema(openAPIFile)
+	if err != nil {
+		return handleError(c, err)
+	}
+	b, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return handleError(c, errors.Wrap(err))
+	}
+	fmt.Fprintf(c.OutOrStdout(), "%s\n", b)
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/cmd/config/internal/commands"
+)
+
+func TestValuesSchemaCommand(t *testing.T) {
+	d, err := ioutil.TempDir("", "kustomize-values-schema-test")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(d)
+	err = ioutil.WriteFile(filepath.Join(d, "Krmfile"), []byte(`apiVersion: v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      type: integer
+      maximum: 10
+      enum: [1, 3, 5]
+      description: number of replicas
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+    io.k8s.cli.setters.size:
+      x-k8s-cli:
+        setter:
+          name: size
+          value: small
+          required: true
+          enumValues:
+            small: "0.5"
+            large: "2"
+    io.k8s.cli.substitutions.image:
+      x-k8s-cli:
+        substitution:
+          name: image
+          pattern: nginx:${tag}
+          values:
+          - marker: ${tag}
+            ref: '#/definitions/io.k8s.cli.setters.tag'
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	r := commands.NewValuesSchemaRunner("")
+	out := &bytes.Buffer{}
+	r.Command.SetOut(out)
+	r.Command.SetArgs([]string{d})
+	if !assert.NoError(t, r.Command.Execute()) {
+		t.FailNow()
+	}
+
+	actual := map[string]interface{}{}
+	if !assert.NoError(t, json.Unmarshal(out.Bytes(), &actual)) {
+		t.FailNow()
+	}
+	assert.Equal(t, map[string]interface{}{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type":    "object",
+		"properties": map[string]interface{}{
+			"replicas": map[string]interface{}{
+				"type":        "integer",
+				"maximum":     float64(10),
+				"enum":        []interface{}{float64(1), float64(3), float64(5)},
+				"description": "number of replicas",
+				"default":     float64(3),
+			},
+			"size": map[string]interface{}{
+				"type":    "string",
+				"enum":    []interface{}{"large", "small"},
+				"default": "small",
+			},
+		},
+		"required": []interface{}{"size"},
+	}, actual)
+}
@@ -11,7 +11,7 @@ func TestCreateSetter(t *testing.T) {
 	tests := []test{
 		{
 			name: "create_setter",
-			args: []string{"cfg", "create-setter", ".", "replicas", "3"},
+			args: []string{"cfg", "create-setter", ".", "replicas", "3", "--no-set-by"},
 			files: map[string]string{
 				"deployment.yaml": `
 apiVersion: apps/v1
@@ -45,6 +45,7 @@ kind: Krmfile
 openAPI:
   definitions:
     io.k8s.cli.setters.replicas:
+      type: integer
       x-k8s-cli:
         setter:
           name: replicas
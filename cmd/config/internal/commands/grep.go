@@ -5,6 +5,7 @@ package commands
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -12,6 +13,8 @@ import (
 	"sigs.k8s.io/kustomize/cmd/config/internal/generateddocs/commands"
 	"sigs.k8s.io/kustomize/kyaml/kio"
 	"sigs.k8s.io/kustomize/kyaml/kio/filters"
+	"sigs.k8s.io/kustomize/kyaml/kio/kioutil"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
 // Cmd returns a command GrepRunner.
@@ -33,6 +36,10 @@ func GetGrepRunner(name string) *GrepRunner {
 		"annotate resources with their file origins.")
 	c.Flags().BoolVarP(&r.InvertMatch, "invert-match", "v", false,
 		" Selected Resources are those not matching any of the specified patterns..")
+	c.Flags().BoolVarP(&r.NameOnly, "name-only", "l", false,
+		"only print the paths of the files containing matching resources.")
+	c.Flags().BoolVar(&r.ResourceIDs, "resource-ids", false,
+		"with --name-only, also print the kind and name of each matching resource.")
 
 	r.Command = c
 	return r
@@ -48,7 +55,9 @@ type GrepRunner struct {
 	KeepAnnotations    bool
 	Command            *cobra.Command
 	filters.GrepFilter
-	Format bool
+	Format      bool
+	NameOnly    bool
+	ResourceIDs bool
 }
 
 func (r *GrepRunner) preRunE(c *cobra.Command, args []string) error {
@@ -103,6 +112,10 @@ func (r *GrepRunner) preRunE(c *cobra.Command, args []string) error {
 func (r *GrepRunner) runE(c *cobra.Command, args []string) error {
 	var filters = []kio.Filter{r.GrepFilter}
 
+	if r.NameOnly {
+		return handleError(c, r.printNames(c, args[1:], filters))
+	}
+
 	var inputs []kio.Reader
 	for _, a := range args[1:] {
 		inputs = append(inputs, kio.LocalPackageReader{
@@ -123,3 +136,71 @@ func (r *GrepRunner) runE(c *cobra.Command, args []string) error {
 		}},
 	}.Execute())
 }
+
+// printNames prints the paths of the files containing resources matched by filters,
+// rather than the resources themselves.
+func (r *GrepRunner) printNames(c *cobra.Command, dirs []string, filters []kio.Filter) error {
+	// read each directory separately so the file paths may be joined with it
+	var pipelines []kio.Pipeline
+	for i := range dirs {
+		pipelines = append(pipelines, kio.Pipeline{
+			Inputs: []kio.Reader{kio.LocalPackageReader{
+				PackagePath:        dirs[i],
+				IncludeSubpackages: r.IncludeSubpackages,
+			}},
+			Filters: filters,
+			Outputs: []kio.Writer{r.nameWriter(c, dirs[i])},
+		})
+	}
+	if len(dirs) == 0 {
+		pipelines = append(pipelines, kio.Pipeline{
+			Inputs:  []kio.Reader{&kio.ByteReader{Reader: c.InOrStdin()}},
+			Filters: filters,
+			Outputs: []kio.Writer{r.nameWriter(c, "")},
+		})
+	}
+	for i := range pipelines {
+		if err := pipelines[i].Execute(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nameWriter returns a Writer which prints the paths of the files containing
+// the resources, relative to dir.
+func (r *GrepRunner) nameWriter(c *cobra.Command, dir string) kio.Writer {
+	return kio.WriterFunc(func(nodes []*yaml.RNode) error {
+		printed := map[string]bool{}
+		for i := range nodes {
+			path, _, err := kioutil.GetFileAnnotations(nodes[i])
+			if err != nil {
+				return err
+			}
+			if path == "" {
+				// resources read from stdin may not have a path
+				path = "-"
+			} else if dir != "" {
+				path = filepath.Join(dir, path)
+			}
+
+			if !r.ResourceIDs {
+				if !printed[path] {
+					fmt.Fprintln(c.OutOrStdout(), path)
+					printed[path] = true
+				}
+				continue
+			}
+			meta, err := nodes[i].GetMeta()
+			if err != nil {
+				return err
+			}
+			name := meta.Name
+			if meta.Namespace != "" {
+				name = meta.Namespace + "/" + name
+			}
+			fmt.Fprintf(c.OutOrStdout(), "%s: %s %s\n", path, meta.Kind, name)
+		}
+		return nil
+	})
+}
@@ -268,3 +268,60 @@ spec:
 		return
 	}
 }
+
+// TestGrepCommand_nameOnly verifies grep only prints the paths of the files
+// containing matching resources
+func TestGrepCommand_nameOnly(t *testing.T) {
+	d, err := ioutil.TempDir("", "kustomize-kyaml-test")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(d)
+
+	err = ioutil.WriteFile(filepath.Join(d, "f1.yaml"), []byte(`
+kind: Deployment
+metadata:
+  name: foo
+spec:
+  replicas: 3
+---
+kind: Deployment
+metadata:
+  name: bar
+  namespace: ns
+spec:
+  replicas: 3
+`), 0600)
+	if !assert.NoError(t, err) {
+		return
+	}
+	err = ioutil.WriteFile(filepath.Join(d, "f2.yaml"), []byte(`
+kind: Deployment
+metadata:
+  name: baz
+spec:
+  replicas: 1
+`), 0600)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	b := &bytes.Buffer{}
+	r := commands.GetGrepRunner("")
+	r.Command.SetArgs([]string{"spec.replicas=3", d, "--name-only"})
+	r.Command.SetOut(b)
+	if !assert.NoError(t, r.Command.Execute()) {
+		return
+	}
+	assert.Equal(t, filepath.Join(d, "f1.yaml")+"\n", b.String())
+
+	b = &bytes.Buffer{}
+	r = commands.GetGrepRunner("")
+	r.Command.SetArgs([]string{"spec.replicas=3", d, "--name-only", "--resource-ids"})
+	r.Command.SetOut(b)
+	if !assert.NoError(t, r.Command.Execute()) {
+		return
+	}
+	assert.Equal(t, filepath.Join(d, "f1.yaml")+": Deployment foo\n"+
+		filepath.Join(d, "f1.yaml")+": Deployment ns/bar\n", b.String())
+}
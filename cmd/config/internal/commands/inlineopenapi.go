@@ -0,0 +1,122 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"sigs.k8s.io/kustomize/cmd/config/ext"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// inlineOpenAPIHeader starts the comment block at the top of a resource file
+// containing the OpenAPI definitions of its setters.  The lines of the block
+// are the commented out openAPI field of a Krmfile, indented under the header.
+const inlineOpenAPIHeader = "# openapi:"
+
+// getOpenAPIFile returns the path to the file containing the OpenAPI definitions.
+// If path is set, e.g. by --openapi-path, it overrides ext.GetOpenAPIFile.
+// If inline is set, the definitions inlined in the resource file args[0] are
+// copied to a temporary file, which must be written back with writeInlineOpenAPI.
+func getOpenAPIFile(args []string, inline bool, path string) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+	if !inline {
+		return ext.GetOpenAPIFile(args)
+	}
+	return readInlineOpenAPI(args[0])
+}
+
+// inlineOpenAPIBlock returns the range of lines containing the inline OpenAPI
+// comment block in the leading comments of the file, or -1, -1 if it has none.
+func inlineOpenAPIBlock(lines []string) (int, int) {
+	for i := range lines {
+		if !strings.HasPrefix(lines[i], "#") {
+			break
+		}
+		if strings.TrimSpace(lines[i]) != inlineOpenAPIHeader {
+			continue
+		}
+		end := i + 1
+		for end < len(lines) && strings.HasPrefix(lines[end], "#  ") {
+			end++
+		}
+		return i, end
+	}
+	return -1, -1
+}
+
+// readInlineOpenAPI copies the OpenAPI definitions inlined in the file at path
+// to a temporary file, so that they may be read and updated like a Krmfile.
+func readInlineOpenAPI(path string) (string, error) {
+	if fi, err := os.Stat(path); err != nil {
+		return "", err
+	} else if fi.IsDir() {
+		return "", errors.Errorf("--inline-openapi requires a file, %s is a directory", path)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Split(string(b), "\n")
+	content := "kind: Krmfile\n"
+	if start, end := inlineOpenAPIBlock(lines); start >= 0 && end > start+1 {
+		content += openapi.SupplementaryOpenAPIFieldName + ":\n"
+		for _, l := range lines[start+1 : end] {
+			content += strings.TrimPrefix(l, "# ") + "\n"
+		}
+	}
+
+	f, err := ioutil.TempFile("", "inline-openapi-*.yaml")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := f.WriteString(content); err != nil {
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// writeInlineOpenAPI replaces the inline OpenAPI comment block of the file at
+// path with the definitions in the file at openAPIPath.
+func writeInlineOpenAPI(path, openAPIPath string) error {
+	y, err := yaml.ReadFile(openAPIPath)
+	if err != nil {
+		return err
+	}
+	definitions, err := y.Pipe(yaml.Lookup(openapi.SupplementaryOpenAPIFieldName))
+	if err != nil {
+		return err
+	}
+	var block []string
+	if definitions != nil {
+		s, err := definitions.String()
+		if err != nil {
+			return err
+		}
+		block = append(block, inlineOpenAPIHeader)
+		for _, l := range strings.Split(strings.TrimSuffix(s, "\n"), "\n") {
+			block = append(block, "#   "+l)
+		}
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(b), "\n")
+	start, end := inlineOpenAPIBlock(lines)
+	if start < 0 {
+		// the block may have been dropped when the resources were written
+		start, end = 0, 0
+	}
+	lines = append(lines[:start], append(block, lines[end:]...)...)
+	return ioutil.WriteFile(path, []byte(strings.Join(lines, "\n")), 0600)
+}
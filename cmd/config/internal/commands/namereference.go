@@ -0,0 +1,150 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// nameReference describes a field which refers to another Resource by its name.
+type nameReference struct {
+	// Parent is the name of the field containing Field -- e.g. configMapRef.
+	// If empty, Field is matched regardless of its parent.
+	Parent string
+
+	// Field is the name of the field containing the name of the referenced Resource.
+	Field string
+
+	// Kind is the kind of the referenced Resource.  If empty, the kind is read from
+	// the kind field which is a sibling of Field -- e.g. roleRef.kind.
+	Kind string
+}
+
+// nameReferences are the well known fields which refer to other Resources by name.
+// This mirrors the subset of the kustomize nameReference configuration which may be
+// resolved within a local package.
+var nameReferences = []nameReference{
+	{Parent: "configMapRef", Field: "name", Kind: "ConfigMap"},
+	{Parent: "configMapKeyRef", Field: "name", Kind: "ConfigMap"},
+	{Parent: "configMap", Field: "name", Kind: "ConfigMap"},
+	{Parent: "secretRef", Field: "name", Kind: "Secret"},
+	{Parent: "secretKeyRef", Field: "name", Kind: "Secret"},
+	{Parent: "secret", Field: "secretName", Kind: "Secret"},
+	{Parent: "tls", Field: "secretName", Kind: "Secret"},
+	{Parent: "imagePullSecrets", Field: "name", Kind: "Secret"},
+	{Parent: "persistentVolumeClaim", Field: "claimName", Kind: "PersistentVolumeClaim"},
+	{Parent: "scaleTargetRef", Field: "name"},
+	{Parent: "roleRef", Field: "name"},
+	{Field: "serviceAccountName", Kind: "ServiceAccount"},
+	{Field: "serviceName", Kind: "Service"},
+}
+
+// nameReferenceFunc is called for each field referencing another Resource by name.
+// kind is the kind of the referenced Resource, field is the field containing the
+// referenced name and path is the path to field.
+type nameReferenceFunc func(kind string, field *yaml.RNode, path string) error
+
+// visitNameReferences invokes fn for each field of object referencing another
+// Resource by name.
+func visitNameReferences(object *yaml.RNode, fn nameReferenceFunc) error {
+	return walkNameReferences(object.YNode(), "", "", fn)
+}
+
+func walkNameReferences(node *yaml.Node, parent, path string, fn nameReferenceFunc) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for i := range node.Content {
+			if err := walkNameReferences(node.Content[i], parent, path, fn); err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i < len(node.Content)-1; i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			p := strings.TrimPrefix(path+"."+key.Value, ".")
+			if value.Kind != yaml.ScalarNode {
+				if err := walkNameReferences(value, key.Value, p, fn); err != nil {
+					return err
+				}
+				continue
+			}
+			for _, ref := range nameReferences {
+				if ref.Field != key.Value || (ref.Parent != "" && ref.Parent != parent) {
+					continue
+				}
+				kind := ref.Kind
+				if kind == "" {
+					kind = mappingValue(node, "kind")
+				}
+				if err := fn(kind, yaml.NewRNode(value), p); err != nil {
+					return err
+				}
+				break
+			}
+		}
+	case yaml.SequenceNode:
+		// list elements keep the parent of the list so that
+		// e.g. imagePullSecrets[].name is matched
+		for i := range node.Content {
+			if err := walkNameReferences(node.Content[i], parent, path, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// brokenReferences returns, for each of nodes, a description of each of its
+// references to another Resource which isn't one of nodes.  References from a
+// namespaced Resource are resolved within its namespace.
+func brokenReferences(nodes []*yaml.RNode) (map[*yaml.RNode][]string, error) {
+	metas := make([]yaml.ResourceMeta, len(nodes))
+	for i := range nodes {
+		meta, err := nodes[i].GetMeta()
+		if err != nil {
+			return nil, err
+		}
+		metas[i] = meta
+	}
+	resolves := func(kind, name, namespace string) bool {
+		for _, m := range metas {
+			if m.Kind == kind && m.Name == name &&
+				(m.Namespace == "" || namespace == "" || m.Namespace == namespace) {
+				return true
+			}
+		}
+		return false
+	}
+
+	broken := map[*yaml.RNode][]string{}
+	for i := range nodes {
+		err := visitNameReferences(nodes[i], func(kind string, field *yaml.RNode, p string) error {
+			name := field.YNode().Value
+			if kind == "" || name == "" || resolves(kind, name, metas[i].Namespace) {
+				return nil
+			}
+			broken[nodes[i]] = append(broken[nodes[i]], fmt.Sprintf(
+				"broken reference %s: %s %s not found", p, kind, name))
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return broken, nil
+}
+
+// mappingValue returns the value of the scalar field with name in node, or
+// the empty string if it is not present.
+func mappingValue(node *yaml.Node, name string) string {
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		if node.Content[i].Value == name && node.Content[i+1].Kind == yaml.ScalarNode {
+			return node.Content[i+1].Value
+		}
+	}
+	return ""
+}
@@ -45,6 +45,8 @@ func GetTreeRunner(name string) *TreeRunner {
 		"if true, include local-config in the output.")
 	c.Flags().BoolVar(&r.excludeNonLocal, "exclude-non-local", false,
 		"if true, exclude non-local-config in the output.")
+	c.Flags().BoolVar(&r.showBrokenRefs, "show-broken-refs", false,
+		"annotate Resources with references -- e.g. configMapRef -- to Resources missing from the tree.")
 	c.Flags().StringVar(&r.structure, "graph-structure", "",
 		"Graph structure to use for printing the tree.  may be any of: "+
 			strings.Join(kio.GraphStructures, ","))
@@ -74,6 +76,7 @@ type TreeRunner struct {
 	includeLocal       bool
 	excludeNonLocal    bool
 	structure          string
+	showBrokenRefs     bool
 }
 
 func (r *TreeRunner) runE(c *cobra.Command, args []string) error {
@@ -152,14 +155,27 @@ func (r *TreeRunner) runE(c *cobra.Command, args []string) error {
 		ExcludeNonLocalConfig: r.excludeNonLocal,
 	}}
 
+	tw := kio.TreeWriter{
+		Root:      root,
+		Writer:    c.OutOrStdout(),
+		Fields:    fields,
+		Structure: kio.TreeStructure(r.structure)}
+	if r.showBrokenRefs {
+		var brokenRefs map[*yaml.RNode][]string
+		fltrs = append(fltrs, kio.FilterFunc(func(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
+			var err error
+			brokenRefs, err = brokenReferences(nodes)
+			return nodes, err
+		}))
+		tw.Notes = func(node *yaml.RNode) []string {
+			return brokenRefs[node]
+		}
+	}
+
 	return handleError(c, kio.Pipeline{
 		Inputs:  []kio.Reader{input},
 		Filters: fltrs,
-		Outputs: []kio.Writer{kio.TreeWriter{
-			Root:      root,
-			Writer:    c.OutOrStdout(),
-			Fields:    fields,
-			Structure: kio.TreeStructure(r.structure)}},
+		Outputs: []kio.Writer{tw},
 	}.Execute())
 }
 
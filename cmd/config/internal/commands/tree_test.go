@@ -346,3 +346,51 @@ spec:
 		return
 	}
 }
+
+func TestTreeCommand_showBrokenRefs(t *testing.T) {
+	d, err := ioutil.TempDir("", "kustomize-tree-test")
+	defer os.RemoveAll(d)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	err = ioutil.WriteFile(filepath.Join(d, "f1.yaml"), []byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app
+        envFrom:
+        - configMapRef:
+            name: app-config
+        - configMapRef:
+            name: present
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: present
+`), 0600)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	b := &bytes.Buffer{}
+	r := commands.GetTreeRunner("")
+	r.Command.SetArgs([]string{d, "--show-broken-refs"})
+	r.Command.SetOut(b)
+	if !assert.NoError(t, r.Command.Execute()) {
+		return
+	}
+
+	assert.Equal(t, fmt.Sprintf(`%s
+├── [f1.yaml]  Deployment app
+│   └── broken reference spec.template.spec.containers.envFrom.configMapRef.name: ConfigMap app-config not found
+└── [f1.yaml]  ConfigMap present
+`, d), b.String())
+}
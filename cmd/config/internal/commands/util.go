@@ -6,10 +6,14 @@ package commands
 import (
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 
 	"github.com/go-errors/errors"
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/kio/filters"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
 // parseFieldPath parse a flag value into a field path
@@ -72,3 +76,27 @@ func fixDocs(new string, c *cobra.Command) {
 	c.Long = strings.ReplaceAll(c.Long, cmdName, new)
 	c.Example = strings.ReplaceAll(c.Example, cmdName, new)
 }
+
+// resourceSelectors returns the grep filters selecting the resources with the
+// given kind, name and namespace.  Empty values match any resource.
+func resourceSelectors(kind, name, namespace string) []kio.Filter {
+	var fltr []kio.Filter
+	for _, s := range []struct {
+		path  []string
+		value string
+	}{
+		{path: []string{yaml.KindField}, value: kind},
+		{path: []string{yaml.MetadataField, yaml.NameField}, value: name},
+		{path: []string{yaml.MetadataField, yaml.NamespaceField}, value: namespace},
+	} {
+		if s.value == "" {
+			continue
+		}
+		fltr = append(fltr, filters.GrepFilter{
+			Path:      s.path,
+			Value:     "^" + regexp.QuoteMeta(s.value) + "$",
+			MatchType: filters.Regexp,
+		})
+	}
+	return fltr
+}
@@ -4,6 +4,33 @@
 // Code generated by "mdtogo"; DO NOT EDIT.
 package commands
 
+var AddConstraintShort = `[Alpha] Add a constraint relating the values of setters.`
+var AddConstraintLong = `
+[Alpha] Add a constraint relating the values of setters.
+
+Adds a constraint to the OpenAPI definitions in the package Krmfile.  ` + "`" + `set` + "`" + `
+rejects values which would violate a constraint, and ` + "`" + `check-setters` + "`" + ` checks
+that the current setter values satisfy all of the constraints.
+
+  DIR:
+    Path to local directory.
+
+  EXPRESSION:
+    The constraint, of the form 'SETTER OPERATOR SETTER|NUMBER', where
+    OPERATOR is one of <, <=, >, >=, == or !=.  Setter values are compared
+    numerically, unless one of them isn't a number, in which case only ==
+    and != may be used.
+
+If the current setter values already violate the constraint, a warning is
+printed, but the constraint is still added.
+`
+var AddConstraintExamples = `
+    # require min-replicas to not exceed max-replicas
+    kustomize cfg add-constraint DIR/ 'min-replicas <= max-replicas'
+
+    # require at least 2 replicas
+    kustomize cfg add-constraint DIR/ 'min-replicas >= 2' --name ha`
+
 var AnnotateShort = `[Alpha] Set an annotation on Resources.`
 var AnnotateLong = `
 [Alpha]  Set an annotation on Resources.
@@ -24,17 +51,52 @@ var CatLong = `
 
   DIR:
     Path to local directory.
+
+` + "`" + `--kind` + "`" + `, ` + "`" + `--name` + "`" + ` and ` + "`" + `--namespace` + "`" + ` print only the Resources matching all of
+the specified values, using the same selection as ` + "`" + `grep` + "`" + `.  Only the matching
+documents of multi-document files are printed, and their comments are kept.
 `
 var CatExamples = `
     # print Resource config from a directory
     kustomize cfg cat my-dir/
 
+    # print only the Deployments from a directory
+    kustomize cfg cat my-dir/ --kind Deployment
+
+    # print the Resources named nginx in the default namespace
+    kustomize cfg cat my-dir/ --name nginx --namespace default
+
     # wrap Resource config from a directory in an ResourceList
     kustomize cfg cat my-dir/ --wrap-kind ResourceList --wrap-version config.kubernetes.io/v1alpha1 --function-config fn.yaml
 
     # unwrap Resource config from a directory in an ResourceList
     ... | kustomize cfg cat`
 
+var CheckSettersShort = `[Alpha] Check that the setter values of a package satisfy its constraints.`
+var CheckSettersLong = `
+[Alpha] Check that the setter values of a package satisfy its constraints.
+
+Evaluates each constraint added with ` + "`" + `add-constraint` + "`" + ` against the setter values
+in the package Krmfile, and fails listing the constraints which don't hold.
+
+Also fails listing each setter created with ` + "`" + `create-setter --required` + "`" + ` which
+hasn't been set -- whose value is empty, or is still the placeholder it was
+created with.  This may be used to gate CI on the users of a package having set
+its required setters.
+
+  DIR:
+    Path to local directory.
+`
+var CheckSettersExamples = `
+    # check the setter values of DIR/
+    kustomize cfg check-setters DIR/
+
+    # fails until the placeholder is replaced
+    $ kustomize cfg create-setter DIR/ namespace NAMESPACE --required
+    $ kustomize cfg check-setters DIR/
+    required setter namespace has its placeholder value "NAMESPACE"
+    Error: 1 required setters must be set`
+
 var CompletionShort = `Install shell completion.`
 var CompletionLong = `
 Install shell completion for kustomize commands and flags -- supports bash, fish and zsh.
@@ -52,6 +114,40 @@ To uninstall shell completion run:
 
     COMP_UNINSTALL=1 kustomize install-completion`
 
+var CopySetterShort = `[Alpha] Copy a setter definition to a new name.`
+var CopySetterLong = `
+[Alpha] Copy a setter definition to a new name.
+
+` + "`" + `copy-setter` + "`" + ` copies the definition of a setter in the Krmfile -- with its
+schema, description and value -- to a new setter, so that families of similar
+setters may be created from one, e.g. the replicas of each region.  The
+comments referencing the setter from the fields of the Resources are unchanged;
+use ` + "`" + `create-setter --force` + "`" + ` or edit them to reference the copy.
+
+  DIR:
+    Path to local directory.
+
+  NAME:
+    The name of the setter to copy.
+
+  NEW_NAME:
+    The name of the copy.  Must start with a letter and contain only letters,
+    digits, '-' and '_'.
+
+With ` + "`" + `--value` + "`" + `, the copy has that value rather than the value of the setter.
+The value is validated against the schema of the copy.  List setters may not
+be given a value.
+
+It is an error if the setter isn't defined, or if a setter or a substitution
+named NEW_NAME is already defined, in which case nothing is changed.
+`
+var CopySetterExamples = `
+    # copy the replicas setter to us-east-replicas
+    kustomize cfg copy-setter DIR/ replicas us-east-replicas
+
+    # copy the replicas setter to us-east-replicas, with the value 5
+    kustomize cfg copy-setter DIR/ replicas us-east-replicas --value 5`
+
 var CountShort = `[Alpha] Count Resources Config from a local directory.`
 var CountLong = `
 [Alpha] Count Resources Config from a local directory.
@@ -87,7 +183,108 @@ var CreateSetterExamples = `
     # create a setter for a substring of a field rather than the full field -- e.g. only the
     # image tag, not the full image
     kustomize cfg create-setter DIR/ image-tag v1.0.1 --type "string" \
-        --field image --description "current stable release"`
+        --field image --description "current stable release"
+
+    # create a setter for the image of only the nginx container
+    kustomize cfg create-setter DIR/ image nginx:1.7 \
+        --field 'spec.template.spec.containers[name=nginx].image'
+
+    # create a setter for only the tag of the nginx:1.7.9 image fields
+    kustomize cfg create-setter DIR/ tag 1.7.9 --field image --partial
+
+    # create a setter whose values must be versions -- e.g. v1.7
+    kustomize cfg create-setter DIR/ tag v1.7 --field version --pattern '^v[0-9]+\.[0-9]+$'
+
+    # create a setter which may only be dev, staging or prod
+    kustomize cfg create-setter DIR/ env dev --field metadata.labels.env --values dev,staging,prod
+
+    # create a setter whose value is read from the REPLICAS environment variable
+    kustomize cfg create-setter DIR/ replicas --value-from-env REPLICAS
+
+    # update the description of an existing setter
+    kustomize cfg create-setter DIR/ replicas 3 --description "number of replicas" --force
+
+    # create a setter in DIR and in each of its subpackages
+    kustomize cfg create-setter DIR/ replicas 3 --field replicas --recurse-subpackages
+
+    # preview the changes made by creating a setter
+    kustomize cfg create-setter DIR/ replicas 3 --dry-run
+
+    # create a setter in the resources read from stdin, writing them to stdout
+    cat deploy.yaml | kustomize cfg create-setter --stdin replicas 3 > out.yaml
+
+    # create a setter with its definition inline in the resource file
+    kustomize cfg create-setter resource.yaml replicas 3 --inline-openapi`
+
+var DeleteSetterShort = `[Alpha] Delete a setter and the references to it from Resource fields.`
+var DeleteSetterLong = `
+[Alpha] Delete a setter and the references to it from Resource fields.
+
+` + "`" + `delete-setter` + "`" + ` removes the definition of a setter from the Krmfile, and the
+comments referencing it from the fields of the Resources -- e.g.
+` + "`" + `# {"$openapi":"replicas"}` + "`" + `.  The field values are left as they are.
+
+  DIR:
+    Path to local directory.
+
+  NAME:
+    The name of the setter to delete.
+
+It is an error if the setter isn't defined, or if a substitution references it,
+in which case nothing is changed.  The substitution must first be changed to
+no longer reference the setter.
+
+With ` + "`" + `--recurse-subpackages` + "`" + ` (` + "`" + `-R` + "`" + `), the setter is also deleted from each
+subpackage of DIR -- a subdirectory containing its own Krmfile -- which defines
+it, along with the references to it from the fields of the subpackage.
+`
+var DeleteSetterExamples = `
+    # delete the replicas setter
+    kustomize cfg delete-setter DIR/ replicas
+
+    # delete the replicas setter from DIR and its subpackages
+    kustomize cfg delete-setter DIR/ replicas --recurse-subpackages`
+
+var ExportSettersShort = `[Alpha] Export the setters and substitutions of a package to a bundle file.`
+var ExportSettersLong = `
+[Alpha] Export the setters and substitutions of a package to a bundle file.
+
+Writes the setter and substitution definitions from the package Krmfile, and the
+path of each Resource field referencing them, to FILE.  The bundle may be applied
+to another package with ` + "`" + `import-setters` + "`" + `.
+
+  DIR:
+    Path to local directory.
+
+  FILE:
+    Path of the bundle file to write.
+`
+var ExportSettersExamples = `
+    # export the setters of my-dir/
+    kustomize cfg export-setters my-dir/ bundle.yaml`
+
+var FixSubstitutionsShort = `[Alpha] Find substitution references to missing setters.`
+var FixSubstitutionsLong = `
+[Alpha] Find substitution references to missing setters.
+
+Lists the values of the substitutions defined in the package Krmfile whose
+` + "`" + `ref` + "`" + ` refers to a setter or substitution which isn't defined -- e.g. because
+the setter was renamed after the substitution was created.  Exits non-zero
+if any are found.
+
+  DIR:
+    Path to local directory.
+
+With ` + "`" + `--interactive` + "`" + ` each dangling reference whose setter was obviously
+renamed -- it refers to a name close to that of a setter no substitution
+references -- is relinked to the renamed setter after a confirmation prompt.
+`
+var FixSubstitutionsExamples = `
+    # list the dangling substitution references in DIR/
+    kustomize cfg fix-substitutions DIR/
+
+    # relink the dangling substitution references to renamed setters
+    kustomize cfg fix-substitutions DIR/ --interactive`
 
 var FmtShort = `[Alpha] Format yaml configuration files.`
 var FmtLong = `
@@ -154,7 +351,36 @@ var GrepExamples = `
     kustomize cfg grep "metadata.name=nginx" my-dir/ | kustomize cfg tree
 
     # look for Resources matching a specific container image
-    kustomize cfg grep "spec.template.spec.containers[name=nginx].image=nginx:1\.7\.9" my-dir/ | kustomize cfg tree`
+    kustomize cfg grep "spec.template.spec.containers[name=nginx].image=nginx:1\.7\.9" my-dir/ | kustomize cfg tree
+
+    # print only the paths of the files containing Resources with 3 replicas
+    kustomize cfg grep --name-only "spec.replicas=3" my-dir/
+
+    # print the paths along with the kind and name of each matching Resource
+    kustomize cfg grep --name-only --resource-ids "spec.replicas=3" my-dir/`
+
+var ImportSettersShort = `[Alpha] Import the setters and substitutions from a bundle file into a package.`
+var ImportSettersLong = `
+[Alpha] Import the setters and substitutions from a bundle file into a package.
+
+Adds the setter and substitution definitions from FILE to the package Krmfile, and
+references them from the Resource fields recorded in FILE.  Fields are matched by
+Resource kind, name and namespace, and by path.
+
+Markers whose Resource or field doesn't exist in the package are not applied, and
+are reported as warnings.
+
+FILE is created by ` + "`" + `export-setters` + "`" + `.
+
+  DIR:
+    Path to local directory.  Must contain a Krmfile -- see ` + "`" + `init` + "`" + `.
+
+  FILE:
+    Path of the bundle file to read.
+`
+var ImportSettersExamples = `
+    # apply the setters exported from another package to my-dir/
+    kustomize cfg import-setters my-dir/ bundle.yaml`
 
 var InitShort = `[Alpha] Initialize a directory with a Krmfile.`
 var InitLong = `
@@ -170,6 +396,47 @@ var InitExamples = `
     # create a Krmfile in my-dir/
     kustomize cfg init my-dir/`
 
+var ListSetterRefsShort = `[Alpha] List the fields referencing a setter, and their current values.`
+var ListSetterRefsLong = `
+[Alpha] List the fields referencing a setter, and their current values.
+
+Lists each package, file and Resource field which references the setter NAME,
+either directly or through a substitution, with the current value of the field.
+It is the inverse of ` + "`" + `create-setter` + "`" + `, which adds the references.
+
+  DIR:
+    Path to local directory.
+
+  NAME:
+    The name of the setter.
+
+Fields which reference the setter directly, but whose value differs from the
+value of the setter -- e.g. because they were edited by hand -- are marked as
+drifted, and counted after the table.  Fields referencing the setter through a
+substitution are never marked.
+
+    $ kustomize cfg list-setter-refs DIR/ replicas
+      PACKAGE        FILE        RESOURCE            FIELD       VALUE   SUBSTITUTION   DRIFT
+      .         deploy.yaml   Deployment/nginx   spec.replicas   3
+      .         worker.yaml   Deployment/worker  spec.replicas   5                      yes
+    1 of 2 fields are out of sync with setter replicas
+
+With ` + "`" + `--recurse-subpackages` + "`" + ` each subpackage of DIR -- a directory containing
+its own Krmfile -- is read using its own setter definitions.
+
+With ` + "`" + `--output json` + "`" + ` the fields are printed as a json list.  The value of list
+fields is a list of the values of their items.
+`
+var ListSetterRefsExamples = `
+    # list the fields referencing the replicas setter in DIR/
+    kustomize cfg list-setter-refs DIR/ replicas
+
+    # list the fields referencing the replicas setter in all packages under DIR/
+    kustomize cfg list-setter-refs DIR/ replicas --recurse-subpackages
+
+    # list the fields as json
+    kustomize cfg list-setter-refs DIR/ replicas --output json`
+
 var ListSettersShort = `[Alpha] List setters for Resources.`
 var ListSettersLong = `
 List setters for Resources.
@@ -181,13 +448,37 @@ List setters for Resources.
   NAME
 
     Optional.  The name of the setter to display.
+
+If some setters may only have one of a list of values -- see ` + "`" + `--values` + "`" + ` of
+` + "`" + `kustomize help cfg create-setter` + "`" + ` -- an ALLOWED VALUES column lists them.
+
+With ` + "`" + `--openapi-path` + "`" + `, the setter definitions are read from the given file rather
+than from the Krmfile of DIR -- see ` + "`" + `kustomize help cfg create-setter` + "`" + `.
+
+The COUNT column is the number of fields referencing the setter, either
+directly or through a substitution.  Setters which no field references are
+flagged as unused -- with ` + "`" + `--unused` + "`" + ` only they are listed, e.g. to find
+definitions which may be deleted.
+
+With ` + "`" + `--output json` + "`" + ` or ` + "`" + `--output yaml` + "`" + ` the setters are printed as a list of
+objects with the fields name, value, listValues, setBy, description, type and
+count -- the number of fields referencing the setter -- for use by scripts.
+All of the fields are always present.  Substitutions aren't printed.
 `
 var ListSettersExamples = `
   Show setters:
 
     $ kustomize cfg list-setters DIR/
         NAME      DESCRIPTION   VALUE     TYPE     COUNT   SETBY  
-    name-prefix   ''            PREFIX    string   2`
+    name-prefix   ''            PREFIX    string   2
+
+  Show setters as json:
+
+    $ kustomize cfg list-setters DIR/ --output json
+
+  Show setters which no field references:
+
+    $ kustomize cfg list-setters DIR/ --unused`
 
 var MergeShort = `[Alpha] Merge Resource configuration files`
 var MergeLong = `
@@ -228,6 +519,151 @@ For information on merge rules, run:
 var Merge3Examples = `
     kustomize cfg merge3 --ancestor a/ --from b/ --to c/`
 
+var PromoteSetterShort = `[Alpha] Promote a marker of a substitution into a setter of its own.`
+var PromoteSetterLong = `
+[Alpha] Promote a marker of a substitution into a setter of its own.
+
+Creates a setter for the MARKER of the SUBSTITUTION defined in the package
+Krmfile, and references it from the substitution value with the marker -- so
+that part of the substituted fields may be set, described and constrained on
+its own.  If the setter already exists it is only referenced.
+
+  DIR:
+    Path to local directory.
+
+  SUBSTITUTION:
+    Name of the substitution -- e.g. image.
+
+  MARKER:
+    Marker of the substitution value to promote -- e.g. '${tag}'.
+
+The setter is named after the marker unless ` + "`" + `--name` + "`" + ` is specified, and its
+value defaults to the value of the setter the marker referenced, so the
+substituted fields don't change.  The resources are left as they are -- run
+` + "`" + `set` + "`" + ` to apply a different ` + "`" + `--value` + "`" + ` to them.
+`
+var PromoteSetterExamples = `
+    # promote the tag part of the image substitution into a setter
+    kustomize cfg promote-setter DIR/ image '${tag}' --description "release tag"
+
+    # promote into a setter with another name and value
+    kustomize cfg promote-setter DIR/ image '${tag}' --name image-tag --value 1.8.0`
+
+var PruneShort = `[Alpha] Print the Resources of a package without those excluded by setters.`
+var PruneLong = `
+[Alpha] Print the Resources of a package without those excluded by setters.
+
+Resources, fields and list elements may be included only if a boolean setter
+is true, with an include directive comment naming the setter:
+
+    # {"$openapi-include":"tls_enabled"}
+    apiVersion: v1
+    kind: Secret
+    ...
+    spec:
+      tls: # {"$openapi-include":"tls_enabled"}
+      - secretName: tls
+
+The directive of a Resource is a comment at its top.  The directive of a field,
+or of a list element, is a comment on the line above it, or on the same line as
+its key.
+
+` + "`" + `prune` + "`" + ` prints the Resources of the package, removing those, and the fields
+and list elements, whose setter is false or has no value.  The package is left
+unchanged, so that the setter may be toggled with ` + "`" + `set` + "`" + ` later on.  It is an
+error for a directive to name an undefined setter, or a setter whose value
+isn't a boolean.
+
+  DIR:
+    Path to local directory.
+
+With ` + "`" + `--dest` + "`" + `, the Resources are written to a file rather than stdout.
+`
+var PruneExamples = `
+    # print the Resources included by the setter values
+    kustomize cfg prune DIR/
+
+    # apply them
+    kustomize cfg prune DIR/ | kubectl apply -f -`
+
+var ReleaseCheckShort = `[Alpha] Check that all setters are set before releasing a package.`
+var ReleaseCheckLong = `
+[Alpha] Check that all setters are set before releasing a package.
+
+Checks the setters of the package and of each of its subpackages, and
+prints a report of the setters which must be set before the package is
+released.  A setter must be set if it is required and has no value, or if
+it still holds its default placeholder value.
+
+Exits non-zero if any setter must be set.
+
+  DIR:
+    Path to local directory.
+
+With ` + "`" + `--output json` + "`" + ` the setters which must be set are printed as a json list.
+`
+var ReleaseCheckExamples = `
+    # check the setters of DIR/ and its subpackages
+    kustomize cfg release-check DIR/
+
+    # print the setters which must be set as json
+    kustomize cfg release-check DIR/ --output json`
+
+var RenameResourcesShort = `[Alpha] Rename Resources matching a regular expression.`
+var RenameResourcesLong = `
+[Alpha] Rename Resources matching a regular expression.
+
+Renames the metadata.name of each Resource matching ` + "`" + `--match` + "`" + ` to ` + "`" + `--replace` + "`" + `, and
+updates the well known name references (e.g. configMapRef, secretKeyRef, volumes,
+serviceAccountName) to the renamed Resources within the package.  References are
+resolved within the namespace of the referring Resource.
+
+References which match ` + "`" + `--match` + "`" + ` but can't be resolved to a Resource in the package
+are left unchanged and reported as warnings.
+
+  DIR:
+    Path to local directory.
+`
+var RenameResourcesExamples = `
+    # rename all Resources with the 'old-' prefix to use the 'new-' prefix
+    kustomize cfg rename-resources my-dir/ --match '^old-(.*)$' --replace 'new-$1'`
+
+var RenameSetterShort = `[Alpha] Rename a setter and the references to it.`
+var RenameSetterLong = `
+[Alpha] Rename a setter and the references to it.
+
+` + "`" + `rename-setter` + "`" + ` renames a setter in one pass, changing:
+
+- the key and the name of its definition in the Krmfile
+- the ` + "`" + `ref` + "`" + ` of each substitution value referencing it, and the constraints
+  relating it to other setters
+- the comments referencing it from the fields of the Resources -- e.g.
+  ` + "`" + `# {"$openapi":"replicas"}` + "`" + ` -- and the include directives referencing it
+
+  DIR:
+    Path to local directory.
+
+  NAME:
+    The name of the setter to rename.
+
+  NEW_NAME:
+    The new name of the setter.  Must start with a letter and contain only
+    letters, digits, '-' and '_'.
+
+It is an error if the setter isn't defined, or if a setter or a substitution
+named NEW_NAME is already defined, in which case nothing is changed.
+
+With ` + "`" + `--recurse-subpackages` + "`" + ` (` + "`" + `-R` + "`" + `), the setter is also renamed in each
+subpackage of DIR -- a subdirectory containing its own Krmfile -- which defines
+it.  The definitions of all of the packages are checked before any are changed.
+`
+var RenameSetterExamples = `
+    # rename the replicas setter to replica-count
+    kustomize cfg rename-setter DIR/ replicas replica-count
+
+    # rename the setter in DIR and its subpackages
+    kustomize cfg rename-setter DIR/ replicas replica-count --recurse-subpackages`
+
 var RunFnsShort = `[Alpha] Reoncile config functions to Resources.`
 var RunFnsLong = `
 [Alpha] Reconcile config functions to Resources.
@@ -277,6 +713,36 @@ order they appear in the file).
 var RunFnsExamples = `
 kustomize fn run example/`
 
+var SetImpactShort = `[Alpha] Preview the fields which setting a setter would change.`
+var SetImpactLong = `
+[Alpha] Preview the fields which setting a setter would change.
+
+Lists each package, file and Resource field which references the setter NAME,
+either directly or through a substitution, without changing anything.  Use it
+before ` + "`" + `set` + "`" + ` to review the impact of a change -- e.g. across a monorepo of
+packages sharing setter names.
+
+  DIR:
+    Path to local directory.
+
+  NAME:
+    The name of the setter.
+
+With ` + "`" + `--recurse-subpackages` + "`" + ` each subpackage of DIR -- a directory containing
+its own Krmfile -- is read using its own setter definitions.
+
+With ` + "`" + `--output json` + "`" + ` the fields are printed as a json list.
+`
+var SetImpactExamples = `
+    # list the fields referencing the replicas setter in DIR/
+    kustomize cfg set-impact DIR/ replicas
+
+    # list the fields referencing the replicas setter in all packages under DIR/
+    kustomize cfg set-impact DIR/ replicas --recurse-subpackages
+
+    # list the fields as json
+    kustomize cfg set-impact DIR/ replicas --recurse-subpackages --output json`
+
 var SetShort = `[Alpha] Set values on Resources fields values.`
 var SetLong = `
 Set values on Resources fields.  May set either the complete or partial field value.
@@ -309,10 +775,130 @@ To print the possible setters for the Resources in a directory, run
 #### Tips
 
 - A description of the value may be specified with ` + "`" + `--description` + "`" + `.
-- The last setter for the field's value may be defined with ` + "`" + `--set-by` + "`" + `.
+- The last setter for the field's value may be defined with ` + "`" + `--set-by` + "`" + `.  It defaults
+  to the git ` + "`" + `user.email` + "`" + `, if configured, or else the current OS user, unless
+  ` + "`" + `--no-set-by` + "`" + ` is specified.
 - Create custom setters on Resources, Kustomization.yaml's, patches, etc
 
-The description and setBy fields are left unmodified unless specified with flags.
+The description field is left unmodified unless specified with flags.
+
+Files are only written if the new value differs from the current value.  If
+nothing differs, ` + "`" + `set` + "`" + ` prints ` + "`" + `no change` + "`" + ` and leaves all files untouched.
+
+If the fields referencing a setter held different values before being set -- e.g.
+because one of them was edited by hand -- ` + "`" + `set` + "`" + ` prints a warning listing the
+divergent values which were overwritten.
+
+With ` + "`" + `--interactive` + "`" + `, ` + "`" + `set` + "`" + ` prompts on stdin for the value of each setter which has
+no value, or which is marked ` + "`" + `required: true` + "`" + ` in its ` + "`" + `x-k8s-cli.setter` + "`" + ` definition.
+Values which don't match the setter schema are rejected and prompted for again.
+Pressing enter keeps the current value, if there is one.  ` + "`" + `--interactive` + "`" + ` fails
+rather than waiting if stdin is not a terminal.
+
+With ` + "`" + `--values-file` + "`" + `, ` + "`" + `set` + "`" + ` sets each of the setters named in a YAML or JSON file
+mapping setter names to values -- a list for list setters.  Each value is
+validated against the schema of its setter, and ` + "`" + `set` + "`" + ` prints for each setter
+whether it was set.  A setter whose value is rejected doesn't prevent the others
+from being set, but makes ` + "`" + `set` + "`" + ` fail once all have been tried.  It is an error
+if the file names a setter which isn't defined, unless ` + "`" + `--ignore-unknown` + "`" + ` is
+specified, in which case it is skipped.  ` + "`" + `--values-file` + "`" + ` is only supported by
+setters created with ` + "`" + `create-setter` + "`" + `.
+
+With ` + "`" + `--unset` + "`" + `, ` + "`" + `set` + "`" + ` clears the value of the setter.  If the setter has a
+` + "`" + `default` + "`" + ` in its ` + "`" + `x-k8s-cli.setter` + "`" + ` definition, the fields referencing it are
+reverted to the default, otherwise they are left as they are.  Unsetting a
+setter marked ` + "`" + `required: true` + "`" + ` prints a warning, since it must be set again
+before the package is used.
+
+With ` + "`" + `--from-file` + "`" + `, ` + "`" + `set` + "`" + ` uses the contents of a file as the value -- e.g. a
+script or a certificate.  Multi-line values are written as literal block scalars
+(` + "`" + `|` + "`" + `) so they remain readable, with the setter reference on the line above the
+field, and are rejected for fields which don't accept a string.  ` + "`" + `--from-file` + "`" + `
+is only supported by setters created with ` + "`" + `create-setter` + "`" + `.
+
+With ` + "`" + `--from-<backend>` + "`" + `, ` + "`" + `set` + "`" + ` fetches the value from an external store -- e.g.
+` + "`" + `--from-vault secret/data/db#password` + "`" + ` -- rather than taking it on the command
+line.  ` + "`" + `--from-env NAME` + "`" + ` fetches the value of an environment variable.  Other
+backends, such as vault, must be registered by the binary embedding the command
+through ` + "`" + `ext.ValueFetchers` + "`" + `.  The fetched value is never printed.
+
+With ` + "`" + `--style` + "`" + `, the fields are written in the given scalar style -- one of
+` + "`" + `plain` + "`" + `, ` + "`" + `single` + "`" + `, ` + "`" + `double` + "`" + `, ` + "`" + `literal` + "`" + ` or ` + "`" + `folded` + "`" + ` -- e.g. to match the quoting
+of the surrounding YAML.  Fields written as ` + "`" + `literal` + "`" + ` or ` + "`" + `folded` + "`" + ` block scalars
+have their setter reference on the line above them.  By default each field
+keeps its style.  Only string
+fields may be written in a style other than ` + "`" + `plain` + "`" + ` -- quoting an integer or
+boolean field would make it a string, so it is an error.  ` + "`" + `--style` + "`" + ` is only
+supported by setters created with ` + "`" + `create-setter` + "`" + `, and may not be combined with
+` + "`" + `--path` + "`" + `.
+
+Values which would violate a constraint between setters, added with
+` + "`" + `add-constraint` + "`" + `, are rejected and nothing is written.
+
+DIR may be a glob matching resource files -- e.g. ` + "`" + `'services/*/deployment.yaml'` + "`" + `
+-- in which case the setter is set on each of the matching files, in sorted order.
+The setter definitions are read from the package enclosing the files: the nearest
+directory above the glob containing a Krmfile.  It is an error if the glob matches
+no files.
+
+With ` + "`" + `--field-filter` + "`" + `, only the fields whose path matches the given glob are set
+-- e.g. to roll a new value out to part of a large package in stages.  The path
+of a field is its field names separated by ` + "`" + `.` + "`" + `, e.g. ` + "`" + `spec.template.spec.replicas` + "`" + `;
+each element of the glob matches one element of the path, and ` + "`" + `**` + "`" + ` matches any
+number of them, e.g. ` + "`" + `'**.containers.image'` + "`" + `.  Combine it with a glob for DIR to
+scope the set to files.  The value of the setter is still changed in its
+definition.  A warning is printed if no fields match.  ` + "`" + `--field-filter` + "`" + ` is only
+supported by setters created with ` + "`" + `create-setter` + "`" + `, and may not be combined with
+` + "`" + `--path` + "`" + `, ` + "`" + `--interactive` + "`" + `, ` + "`" + `--values-file` + "`" + ` or ` + "`" + `--unset` + "`" + `.
+
+With ` + "`" + `--inline-openapi` + "`" + `, ` + "`" + `set` + "`" + ` reads and writes the setter definitions in the
+` + "`" + `# openapi:` + "`" + ` comment block at the top of the resource file, rather than in the
+Krmfile -- see ` + "`" + `kustomize help cfg create-setter` + "`" + `.  DIR must be a file.
+
+The values of list setters are given as the remaining arguments, or by repeating
+` + "`" + `--values` + "`" + `.  Items of lists of objects are given as JSON objects -- e.g.
+` + "`" + `'{"name":"FOO","value":"foo"}'` + "`" + ` -- and written to the fields as mappings.
+
+With ` + "`" + `--path` + "`" + `, ` + "`" + `set` + "`" + ` sets the field at a JSON pointer -- e.g. ` + "`" + `/spec/replicas` + "`" + ` --
+rather than the fields of a setter, regardless of any setter references.  The
+value is given as the second argument or with ` + "`" + `--values` + "`" + `.  List elements are
+identified by their index, and ` + "`" + `~1` + "`" + ` and ` + "`" + `~0` + "`" + ` escape ` + "`" + `/` + "`" + ` and ` + "`" + `~` + "`" + `.  The field must
+exist unless ` + "`" + `--create` + "`" + ` is specified, in which case missing fields are created.
+If more than one Resource has the field, select one with ` + "`" + `--kind` + "`" + ` and ` + "`" + `--name` + "`" + `.
+
+With ` + "`" + `--recurse-subpackages` + "`" + `, ` + "`" + `set` + "`" + ` also sets the setter on each subpackage of
+DIR -- a directory containing its own Krmfile -- using the definitions of that
+subpackage, and prints the number of fields changed in each package and in total.
+A package may keep its definitions in another file by naming it, relative to the
+package, with a ` + "`" + `config.kubernetes.io/openapi-file` + "`" + ` annotation in its Krmfile.
+With ` + "`" + `--dry-run` + "`" + `, nothing is written, and the printed counts are of the fields
+which would change.  Neither may be combined with ` + "`" + `--interactive` + "`" + `, ` + "`" + `--unset` + "`" + `,
+` + "`" + `--inline-openapi` + "`" + `, ` + "`" + `--path` + "`" + ` or a glob.
+
+With ` + "`" + `--openapi-path` + "`" + `, ` + "`" + `set` + "`" + ` reads and writes the setter definitions in the given
+file rather than in the Krmfile of DIR -- e.g. a file shared by the packages of a
+monorepo.  It may not be combined with ` + "`" + `--inline-openapi` + "`" + `, ` + "`" + `--recurse-subpackages` + "`" + `,
+` + "`" + `--dry-run` + "`" + ` or ` + "`" + `--path` + "`" + `.
+
+With ` + "`" + `--output-dir` + "`" + `, ` + "`" + `set` + "`" + ` writes the resources of DIR to another directory, at
+the same paths relative to DIR, rather than modifying them in place -- e.g. when
+DIR is a read-only vendored package and the configuration is generated from it.
+All of the resources are written, whether or not their fields were set, and DIR
+is left untouched.  The setter definition is still updated, so combine it with
+` + "`" + `--openapi-path` + "`" + ` to keep the definitions outside a read-only DIR.
+` + "`" + `--output-dir` + "`" + ` is only supported by setters created with ` + "`" + `create-setter` + "`" + `, and
+may not be combined with a glob, ` + "`" + `--path` + "`" + `, ` + "`" + `--interactive` + "`" + `, ` + "`" + `--values-file` + "`" + `,
+` + "`" + `--unset` + "`" + `, ` + "`" + `--dry-run` + "`" + `, ` + "`" + `--recurse-subpackages` + "`" + ` or ` + "`" + `--inline-openapi` + "`" + `.
+
+    $ kustomize cfg set vendor/nginx/ replicas 5 \
+        --openapi-path setters.yaml --output-dir generated/nginx/
+
+With ` + "`" + `--record` + "`" + `, each change of value is appended to a ` + "`" + `history` + "`" + ` list in the
+setter definition, under ` + "`" + `x-k8s-cli.setter` + "`" + `, as an entry holding the time, the
+previous and new values, and who set it -- an audit trail kept with the package.
+Setting a setter to the value it already has records nothing.  ` + "`" + `--max-history N` + "`" + `
+keeps only the newest N entries.  ` + "`" + `--record` + "`" + ` is only supported by setters created
+with ` + "`" + `create-setter` + "`" + `, and may not be combined with ` + "`" + `--path` + "`" + ` or ` + "`" + `--unset` + "`" + `.
 
 To create a custom setter for a field see: ` + "`" + `kustomize help cfg create-setter` + "`" + `
 `
@@ -341,6 +927,68 @@ var SetExamples = `
     $ kustomize cfg set DIR/ name-prefix "test" --description "test environment" --set-by "dev"
     set 2 values
 
+  Interactive set: prompt for the values of required and unset setters
+  (the name-prefix setter is marked required)
+
+    $ kustomize cfg set DIR/ --interactive
+    name-prefix (test environment) [PREFIX]: test
+    set 2 fields
+
+  Set from a values file: set several setters at once
+
+    # values.yaml
+    replicas: 3
+    image: nginx
+    args: [--verbose, --port=8080]
+
+    $ kustomize cfg set DIR/ --values-file values.yaml
+    args: set 1 fields
+    image: set 2 fields
+    replicas: no change
+
+  Record: keep a history of the values of the setter
+
+    $ kustomize cfg set DIR/ replicas 5 --record --max-history 10
+    set 1 fields
+
+  Unset: clear the value of a setter
+
+    $ kustomize cfg set DIR/ replicas --unset
+    unset replicas, reverted 1 fields to "1"
+
+  Set from file: use the contents of a file as the value
+
+    $ kustomize cfg set DIR/ script --from-file run.sh
+    set 1 fields
+
+  Set from a backend: fetch the value from an environment variable
+
+    $ kustomize cfg set DIR/ db-password --from-env DB_PASSWORD
+    set 1 fields
+
+  Set by glob: set the setter on the matching files only
+
+    $ kustomize cfg set 'DIR/services/*/deployment.yaml' replicas 5
+    set 2 fields
+
+  Set by field path: set the setter on the matching fields only
+
+    $ kustomize cfg set DIR/ image nginx:1.19 --field-filter 'spec.template.spec.initContainers.image'
+    set 1 fields
+
+  Set by path: set a field which has no setter
+
+    $ kustomize cfg set DIR/ --path /spec/replicas 5 --kind Deployment --name app
+    set /spec/replicas on Deployment app
+
+  Dry run across subpackages: count the fields which would change
+
+    $ kustomize cfg set DIR/ replicas 5 --dry-run --recurse-subpackages
+      PACKAGE   FIELDS
+      api       2
+      worker    1
+    would change 3 fields in 2 packages
+
   List setters: Show the new values
 
     $ config list-setters DIR/
@@ -360,6 +1008,43 @@ var SetExamples = `
         name: test-app2 # {"description":"test environment","type":"string","x-kustomize":{"setBy":"dev","setter":[{"name":"name-prefix","value":"test"}]}}
     ...`
 
+var SettersStatusShort = `[Alpha] Report the fields which are out of sync with their setters.`
+var SettersStatusLong = `
+[Alpha] Report the fields which are out of sync with their setters.
+
+Compares the value of each setter with each field referencing it directly, and
+lists the fields whose value differs -- e.g. because they were edited by hand
+rather than with ` + "`" + `set` + "`" + `.  Fields referencing a setter through a substitution,
+and setters which have never been set, aren't compared.
+
+  DIR:
+    Path to local directory.
+
+The command exits non-0 if any field is out of sync, so that it may be used to
+catch fields edited by hand in CI.
+
+    $ kustomize cfg setters-status DIR/
+      PACKAGE   SETTER      FILE          RESOURCE          FIELD       VALUE   SETTER VALUE
+      .        replicas  worker.yaml  Deployment/worker  spec.replicas   5       3
+    Error: 1 fields are out of sync with their setters, run with --fix to set them
+
+With ` + "`" + `--fix` + "`" + ` the fields are set back to the values of their setters, as if
+each setter had been set again to its current value, and the command exits 0.
+The setter definitions are unchanged.
+
+With ` + "`" + `--recurse-subpackages` + "`" + ` each subpackage of DIR -- a directory containing
+its own Krmfile -- is checked using its own setter definitions.
+`
+var SettersStatusExamples = `
+    # report the fields which are out of sync with their setters
+    kustomize cfg setters-status DIR/
+
+    # set the fields which are out of sync back to the values of their setters
+    kustomize cfg setters-status DIR/ --fix
+
+    # check all packages under DIR/
+    kustomize cfg setters-status DIR/ --recurse-subpackages`
+
 var SinkShort = `[Alpha] Implement a Sink by writing input to a local directory.`
 var SinkLong = `
 [Alpha] Implement a Sink by writing input to a local directory.
@@ -392,6 +1077,22 @@ var SourceExamples = `
 
     kustomize fn source DIR/ | your-function | kustomize fn sink DIR/`
 
+var SplitShort = `[Alpha] Split a file of Resources into one file per kind.`
+var SplitLong = `
+[Alpha] Split a file of Resources into one file per kind.
+
+Writes the Resources in FILE to a file per kind in the ` + "`" + `--out` + "`" + ` directory -- e.g.
+the Deployments to deployment.yaml and the Services to service.yaml.  Comments
+are kept, as is the order of the Resources of each kind.  Existing files with
+the same names are overwritten.
+
+  FILE:
+    Path to a file, or directory, containing Resources.
+`
+var SplitExamples = `
+    # split all.yaml into a file per kind in my-dir/
+    kustomize cfg split all.yaml --by kind --out my-dir/`
+
 var TreeShort = `[Alpha] Display Resource structure from a directory or stdin.`
 var TreeLong = `
 [Alpha] Display Resource structure from a directory or stdin.
@@ -413,6 +1114,10 @@ kustomize cfg tree supports printing arbitrary fields using the '--field' flag.
 By default, kustomize cfg tree uses Resource graph structure if any relationships between resources (ownerReferences)
 are detected, as is typically the case when printing from a cluster. Otherwise, directory graph structure is used. The
 graph structure can also be selected explicitly using the '--graph-structure' flag.
+
+With '--show-broken-refs', Resources referencing other Resources by name -- e.g. with a
+configMapRef or secretKeyRef -- which are missing from the tree are annotated with each
+broken reference.
 `
 var TreeExamples = `
     # print Resources using directory structure
@@ -427,6 +1132,9 @@ var TreeExamples = `
     # print the "foo"" annotation
     kustomize cfg tree my-dir/ --field "metadata.annotations.foo"
 
+    # print the references to Resources missing from my-dir/
+    kustomize cfg tree my-dir/ --show-broken-refs
+
     # print the "foo"" annotation
     kubectl get all -o yaml | kustomize cfg tree \
       --field="status.conditions[type=Completed].status"
@@ -441,3 +1149,47 @@ var TreeExamples = `
       --field="status.conditions[type=Complete].status" \
       --field="status.conditions[type=Ready].status" \
       --field="status.conditions[type=ContainersReady].status"`
+
+var UnusedSettersShort = `[Alpha] List the setters which aren't referenced by any Resource field.`
+var UnusedSettersLong = `
+[Alpha] List the setters which aren't referenced by any Resource field.
+
+Lists the setters defined in the package Krmfile which no Resource field
+references, either directly or through a substitution -- e.g. because the
+fields were removed by a refactor.
+
+  DIR:
+    Path to local directory.
+
+With ` + "`" + `--output json` + "`" + ` the unused setters are printed as a json list.
+
+With ` + "`" + `--delete` + "`" + ` the unused setter definitions are deleted from the Krmfile.
+`
+var UnusedSettersExamples = `
+    # list the unused setters of DIR/
+    kustomize cfg unused-setters DIR/
+
+    # list the unused setters as json
+    kustomize cfg unused-setters DIR/ --output json
+
+    # delete the unused setters
+    kustomize cfg unused-setters DIR/ --delete`
+
+var ValuesSchemaShort = `[Alpha] Print a JSON Schema describing the values of the setters of a package.`
+var ValuesSchemaLong = `
+[Alpha] Print a JSON Schema describing the values of the setters of a package.
+
+Prints a JSON Schema with a property for each setter defined in the package
+Krmfile, e.g. so that a form may be rendered to collect the values to ` + "`" + `set` + "`" + `.
+
+Each property has the type and constraints of the setter schema, the setter
+description, and its enum values.  Setters without a type are strings.  The
+default of a property is the setter default, or its current value if it has
+none.  Setters marked ` + "`" + `required: true` + "`" + ` are listed as required properties.
+
+  DIR:
+    Path to local directory.
+`
+var ValuesSchemaExamples = `
+    # print the values schema of DIR/
+    kustomize cfg values-schema DIR/`
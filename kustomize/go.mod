@@ -24,4 +24,7 @@ exclude (
 	sigs.k8s.io/kustomize/api v0.2.0
 )
 
-replace sigs.k8s.io/kustomize/api => ../api
+replace (
+	sigs.k8s.io/kustomize/api => ../api
+	sigs.k8s.io/kustomize/kyaml => ../kyaml
+)
@@ -4,6 +4,7 @@
 package build
 
 import (
+	"fmt"
 	"io"
 	"log"
 	"path/filepath"
@@ -25,6 +26,7 @@ type Options struct {
 	kustomizationPath string
 	outputPath        string
 	outOrder          reorderOutput
+	warnOut           io.Writer
 }
 
 // NewOptions creates a Options object
@@ -66,6 +68,7 @@ func NewCmdBuild(out io.Writer) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			o.warnOut = cmd.ErrOrStderr()
 			return o.RunBuild(out)
 		},
 	}
@@ -76,8 +79,26 @@ func NewCmdBuild(out io.Writer) *cobra.Command {
 		"If specified, write the build output to this path.")
 	addFlagLoadRestrictor(cmd.Flags())
 	addFlagEnablePlugins(cmd.Flags())
+	addFlagPluginLimits(cmd.Flags())
 	addFlagReorderOutput(cmd.Flags())
 	addFlagEnableManagedbyLabel(cmd.Flags())
+	addFlagMaxResources(cmd.Flags())
+	addFlagMaxDepth(cmd.Flags())
+	addFlagCanonical(cmd.Flags())
+	addFlagCheckReferences(cmd.Flags())
+	addFlagSet(cmd.Flags())
+	addFlagSafeLabels(cmd.Flags())
+	addFlagPreserveFileGroups(cmd.Flags())
+	addFlagGraph(cmd.Flags())
+	addFlagOnly(cmd.Flags())
+	addFlagDocSeparator(cmd.Flags())
+	addFlagFinalNewline(cmd.Flags())
+	addFlagWarnMissingLabels(cmd.Flags())
+	addFlagStrict(cmd.Flags())
+	addFlagInjectBuildEnv(cmd.Flags())
+	addFlagHelmify(cmd.Flags())
+	addFlagChangelog(cmd.Flags())
+	addFlagProfile(cmd.Flags())
 	return cmd
 }
 
@@ -93,24 +114,101 @@ func (o *Options) Validate(args []string) (err error) {
 	} else {
 		o.kustomizationPath = args[0]
 	}
+	err = validateExclusiveOutputFlags()
+	if err != nil {
+		return err
+	}
 	err = validateFlagLoadRestrictor()
 	if err != nil {
 		return err
 	}
+	err = validateFlagPluginLimits()
+	if err != nil {
+		return err
+	}
+	err = validateFlagMaxResources()
+	if err != nil {
+		return err
+	}
+	err = validateFlagMaxDepth()
+	if err != nil {
+		return err
+	}
+	err = validateFlagSet()
+	if err != nil {
+		return err
+	}
+	err = validateFlagGraph()
+	if err != nil {
+		return err
+	}
+	err = validateFlagOnly()
+	if err != nil {
+		return err
+	}
+	err = validateFlagDocSeparator()
+	if err != nil {
+		return err
+	}
+	err = validateFlagInjectBuildEnv()
+	if err != nil {
+		return err
+	}
+	err = validateFlagChangelog()
+	if err != nil {
+		return err
+	}
+	err = validateFlagProfile()
+	if err != nil {
+		return err
+	}
 	o.outOrder, err = validateFlagReorderOutput()
 	return
 }
 
+// validateExclusiveOutputFlags returns an error if more than one
+// of the flags replacing the emitted resources is set, as only
+// one of them can be emitted.
+func validateExclusiveOutputFlags() error {
+	var set []string
+	for _, f := range []struct{ name, value string }{
+		{flagHelmifyName, flagHelmifyValue},
+		{flagGraphName, flagGraphValue},
+		{flagChangelogFromName, flagChangelogFromValue},
+	} {
+		if f.value != "" {
+			set = append(set, "--"+f.name)
+		}
+	}
+	if len(set) > 1 {
+		return fmt.Errorf(
+			"%s cannot be combined", strings.Join(set, " and "))
+	}
+	return nil
+}
+
 func (o *Options) makeOptions() *krusty.Options {
 	opts := &krusty.Options{
 		DoLegacyResourceSort: o.outOrder == legacy,
 		LoadRestrictions:     getFlagLoadRestrictorValue(),
+		MaxResources:         getFlagMaxResourcesValue(),
+		MaxDepth:             getFlagMaxDepthValue(),
+		CheckReferences:      isFlagCheckReferencesSet(),
+		Overrides:            getFlagSetValue(),
+		SafeLabels:           isFlagSafeLabelsSet(),
+		PreserveFileGroups:   isFlagPreserveFileGroupsSet(),
+		Only:                 getFlagOnlyValue(),
+		WarnMissingLabels:    getFlagWarnMissingLabelsValue(),
+		Strict:               isFlagStrictSet(),
+		InjectBuildEnv:       getFlagInjectBuildEnvValue(),
+		Profile:              isFlagProfileSet(),
 	}
 	if isFlagEnablePluginsSet() {
 		c, err := konfig.EnabledPluginConfig(types.BploUseStaticallyLinked)
 		if err != nil {
 			log.Fatal(err)
 		}
+		c.ResourceLimits = getFlagPluginLimitsValue()
 		opts.PluginConfig = c
 	} else {
 		opts.PluginConfig = konfig.DisabledPluginConfig()
@@ -128,9 +226,77 @@ func (o *Options) RunBuild(out io.Writer) error {
 	if err != nil {
 		return err
 	}
+	if o.warnOut != nil {
+		for _, w := range k.Warnings() {
+			fmt.Fprintf(o.warnOut, "Warning: %s\n", w)
+		}
+	}
+	if p := k.Profile(); p != nil && o.warnOut != nil {
+		err = emitProfile(o.warnOut, p, getFlagProfileFormatValue())
+		if err != nil {
+			return err
+		}
+	}
+	if getFlagHelmifyValue() != "" {
+		return helmify(fSys, getFlagHelmifyValue(), o.kustomizationPath, m)
+	}
+	if getFlagGraphValue() != "" {
+		return o.emitGraph(out, fSys, m)
+	}
+	if getFlagChangelogFromValue() != "" {
+		return o.emitChangelog(out, fSys, m)
+	}
 	return o.emitResources(out, fSys, m)
 }
 
+func (o *Options) emitGraph(
+	out io.Writer, fSys filesys.FileSystem, m resmap.ResMap) error {
+	g, err := krusty.MakeResourceGraph(m)
+	if err != nil {
+		return err
+	}
+	var res []byte
+	if getFlagGraphValue() == graphJSON {
+		res, err = g.JSON()
+		if err != nil {
+			return err
+		}
+	} else {
+		res = []byte(g.Dot())
+	}
+	if o.outputPath != "" {
+		return fSys.WriteFile(o.outputPath, res)
+	}
+	_, err = out.Write(res)
+	return err
+}
+
+// emitChangelog builds the kustomization given to
+// --changelog-from with the same options, and emits the
+// changes from its resources to those of m.
+func (o *Options) emitChangelog(
+	out io.Writer, fSys filesys.FileSystem, m resmap.ResMap) error {
+	k := krusty.MakeKustomizer(fSys, o.makeOptions())
+	from, err := k.Run(getFlagChangelogFromValue())
+	if err != nil {
+		return errors.Wrapf(err, "building --%s %s",
+			flagChangelogFromName, getFlagChangelogFromValue())
+	}
+	cl, err := makeChangeLog(from, m)
+	if err != nil {
+		return err
+	}
+	res := []byte(cl.Text())
+	if getFlagChangelogFormatValue() == changelogMarkdown {
+		res = []byte(cl.Markdown())
+	}
+	if o.outputPath != "" {
+		return fSys.WriteFile(o.outputPath, res)
+	}
+	_, err = out.Write(res)
+	return err
+}
+
 func (o *Options) emitResources(
 	out io.Writer, fSys filesys.FileSystem, m resmap.ResMap) error {
 	if o.outputPath != "" && fSys.IsDir(o.outputPath) {
@@ -140,6 +306,13 @@ func (o *Options) emitResources(
 	if err != nil {
 		return err
 	}
+	if isFlagCanonicalSet() {
+		res, err = canonicalize(res)
+		if err != nil {
+			return err
+		}
+	}
+	res = frame(res, getFlagDocSeparatorValue(), isFlagFinalNewlineSet())
 	if o.outputPath != "" {
 		return fSys.WriteFile(o.outputPath, res)
 	}
@@ -182,5 +355,12 @@ func writeFile(
 	if err != nil {
 		return err
 	}
+	if isFlagCanonicalSet() {
+		out, err = canonicalize(out)
+		if err != nil {
+			return err
+		}
+	}
+	out = frame(out, getFlagDocSeparatorValue(), isFlagFinalNewlineSet())
 	return fSys.WriteFile(filepath.Join(path, fName), out)
 }
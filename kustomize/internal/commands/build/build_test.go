@@ -52,3 +52,48 @@ func TestBuildValidate(t *testing.T) {
 		}
 	}
 }
+
+func TestBuildValidateExclusiveOutputFlags(t *testing.T) {
+	defer func() {
+		flagHelmifyValue = ""
+		flagGraphValue = ""
+		flagChangelogFromValue = ""
+	}()
+	var cases = []struct {
+		name      string
+		helmify   string
+		graph     string
+		changelog string
+		erMsg     string
+	}{
+		{"helmify", "chart", "", "", ""},
+		{"graph", "", graphDot, "", ""},
+		{"changelog", "", "", "v1", ""},
+		{"helmify and graph", "chart", graphDot, "",
+			"--helmify and --graph cannot be combined"},
+		{"graph and changelog", "", graphJSON, "v1",
+			"--graph and --changelog-from cannot be combined"},
+		{"all", "chart", graphDot, "v1",
+			"--helmify and --graph and --changelog-from cannot be combined"},
+	}
+	for _, mycase := range cases {
+		flagHelmifyValue = mycase.helmify
+		flagGraphValue = mycase.graph
+		flagChangelogFromValue = mycase.changelog
+		opts := Options{}
+		e := opts.Validate([]string{})
+		if len(mycase.erMsg) > 0 {
+			if e == nil {
+				t.Errorf("%s: Expected an error %v", mycase.name, mycase.erMsg)
+				continue
+			}
+			if e.Error() != mycase.erMsg {
+				t.Errorf("%s: Expected error %s, but got %v", mycase.name, mycase.erMsg, e)
+			}
+			continue
+		}
+		if e != nil {
+			t.Errorf("%s: unknown error: %v", mycase.name, e)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"bytes"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/kio/filters"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// canonicalize normalizes the yaml Resources in the input so that
+// Resources which are equal are emitted identically, regardless of
+// how they were written in their sources.  It normalizes:
+//
+//   - field order: fields are ordered using the same precedence as
+//     'kustomize cfg fmt' (apiVersion, kind, metadata, spec, ...),
+//     falling back on lexicographical order
+//   - collection style: maps and lists always use block style,
+//     never flow style (e.g. {a: b} or [a, b])
+//   - scalar style: multi-line strings use literal style (|),
+//     strings which would otherwise be parsed as another type
+//     (e.g. "true" or "1") are double quoted, and all other
+//     scalars are unquoted
+func canonicalize(in []byte) ([]byte, error) {
+	var out bytes.Buffer
+	err := kio.Pipeline{
+		Inputs: []kio.Reader{&kio.ByteReader{
+			Reader: bytes.NewReader(in), OmitReaderAnnotations: true}},
+		Filters: []kio.Filter{
+			kio.FilterAll(yaml.FilterFunc(canonicalStyle)),
+			filters.FormatFilter{},
+		},
+		Outputs: []kio.Writer{kio.ByteWriter{Writer: &out}},
+	}.Execute()
+	if err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// canonicalStyle sets the canonical style on each node of the Resource.
+func canonicalStyle(rn *yaml.RNode) (*yaml.RNode, error) {
+	setCanonicalStyle(rn.YNode())
+	return rn, nil
+}
+
+func setCanonicalStyle(n *yaml.Node) {
+	switch n.Kind {
+	case yaml.MappingNode, yaml.SequenceNode:
+		n.Style = 0
+	case yaml.ScalarNode:
+		n.Style = 0
+		if n.Tag != yaml.StringTag {
+			break
+		}
+		if strings.Contains(n.Value, "\n") {
+			n.Style = yaml.LiteralStyle
+		} else if yaml.IsYaml1_1NonString(n) {
+			// must quote values so they are parsed as strings
+			n.Style = yaml.DoubleQuotedStyle
+		}
+	}
+	for i := range n.Content {
+		setCanonicalStyle(n.Content[i])
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"testing"
+)
+
+func TestCanonicalize(t *testing.T) {
+	// the same Resources, using flow style, unusual quoting and
+	// field order
+	input1 := `
+kind: Deployment
+apiVersion: apps/v1
+metadata: {name: app, labels: {version: '1', app: web}}
+spec:
+  template:
+    spec:
+      containers:
+      - {image: nginx, name: web, args: [--port, '8080']}
+---
+kind: ConfigMap
+apiVersion: v1
+metadata:
+  name: config
+data: {script: "echo a\necho b\n", mode: fast, enabled: 'true'}
+`
+	// the same Resources, using block style and quoting everything
+	input2 := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    app: "web"
+    version: "1"
+  name: "app"
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        args:
+        - "--port"
+        - "8080"
+        image: 'nginx'
+---
+apiVersion: v1
+data:
+  mode: "fast"
+  enabled: "true"
+  script: |
+    echo a
+    echo b
+kind: ConfigMap
+metadata:
+  name: config
+`
+	expected := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  labels:
+    app: web
+    version: "1"
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx
+        args:
+        - --port
+        - "8080"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  enabled: "true"
+  mode: fast
+  script: |
+    echo a
+    echo b
+`
+	for _, input := range []string{input1, input2} {
+		actual, err := canonicalize([]byte(input))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(actual) != expected {
+			t.Fatalf("expected:\n%s\nbut got:\n%s", expected, actual)
+		}
+	}
+}
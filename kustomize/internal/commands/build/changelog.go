@@ -0,0 +1,245 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/resid"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+)
+
+// changeLog holds the changes of the resources between two
+// builds, grouped by type of change.
+type changeLog struct {
+	added    []resid.ResId
+	removed  []resid.ResId
+	modified []resourceChange
+}
+
+// resourceChange holds the fields of a resource which were
+// changed between two builds.
+type resourceChange struct {
+	id     resid.ResId
+	fields []fieldChange
+}
+
+// fieldChange is a field added, removed or changed between
+// two builds.  Values are JSON encoded, and empty if the field
+// is absent.
+type fieldChange struct {
+	path string
+	from string
+	to   string
+}
+
+// makeChangeLog returns the changes from the resources of
+// from to those of to.  Resources are matched by their
+// current id, or failing that by their original id, so that
+// e.g. a ConfigMap whose name hash changed is modified rather
+// than removed and added.
+func makeChangeLog(from, to resmap.ResMap) (*changeLog, error) {
+	matches := map[*resource.Resource]*resource.Resource{}
+	matched := map[*resource.Resource]bool{}
+	for _, r := range to.Resources() {
+		if old, err := from.GetByCurrentId(r.CurId()); err == nil {
+			matches[r] = old
+			matched[old] = true
+		}
+	}
+	for _, r := range to.Resources() {
+		if matches[r] != nil {
+			continue
+		}
+		candidates := from.GetMatchingResourcesByOriginalId(r.OrgId().Equals)
+		if len(candidates) == 1 && !matched[candidates[0]] &&
+			len(to.GetMatchingResourcesByOriginalId(r.OrgId().Equals)) == 1 {
+			matches[r] = candidates[0]
+			matched[candidates[0]] = true
+		}
+	}
+
+	cl := &changeLog{}
+	for _, r := range to.Resources() {
+		old := matches[r]
+		if old == nil {
+			cl.added = append(cl.added, r.CurId())
+			continue
+		}
+		fields, err := fieldChanges(old, r)
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) > 0 {
+			cl.modified = append(cl.modified, resourceChange{id: r.CurId(), fields: fields})
+		}
+	}
+	for _, r := range from.Resources() {
+		if !matched[r] {
+			cl.removed = append(cl.removed, r.CurId())
+		}
+	}
+	return cl, nil
+}
+
+// fieldChanges returns the fields which differ between the
+// two resources, sorted by path.
+func fieldChanges(from, to *resource.Resource) ([]fieldChange, error) {
+	before := map[string]string{}
+	if err := flatten(from.Map(), "", before); err != nil {
+		return nil, err
+	}
+	after := map[string]string{}
+	if err := flatten(to.Map(), "", after); err != nil {
+		return nil, err
+	}
+	var changes []fieldChange
+	for path, value := range after {
+		if before[path] != value {
+			changes = append(changes, fieldChange{path: path, from: before[path], to: value})
+		}
+	}
+	for path, value := range before {
+		if _, found := after[path]; !found {
+			changes = append(changes, fieldChange{path: path, from: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].path < changes[j].path
+	})
+	return changes, nil
+}
+
+// flatten records the JSON encoded value of each scalar field
+// of obj, and of each empty map or list, keyed by its path --
+// e.g. spec.template.spec.containers[0].image.
+func flatten(obj interface{}, path string, fields map[string]string) error {
+	switch typedObj := obj.(type) {
+	case map[string]interface{}:
+		if len(typedObj) > 0 {
+			for k, v := range typedObj {
+				p := k
+				if path != "" {
+					p = path + "." + k
+				}
+				if err := flatten(v, p, fields); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case []interface{}:
+		if len(typedObj) > 0 {
+			for i, v := range typedObj {
+				if err := flatten(v, fmt.Sprintf("%s[%d]", path, i), fields); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	fields[path] = string(b)
+	return nil
+}
+
+// describe returns the kind, namespace and name of the
+// resource -- e.g. Deployment prod/web.
+func describe(id resid.ResId) string {
+	if id.Namespace == "" {
+		return id.Kind + " " + id.Name
+	}
+	return id.Kind + " " + id.Namespace + "/" + id.Name
+}
+
+// describeChange returns the type of the field change, and
+// its values.
+func describeChange(f fieldChange, quote func(string) string) string {
+	switch {
+	case f.from == "":
+		return fmt.Sprintf("added %s: %s", quote(f.path), quote(f.to))
+	case f.to == "":
+		return fmt.Sprintf("removed %s: %s", quote(f.path), quote(f.from))
+	default:
+		return fmt.Sprintf("changed %s: %s -> %s",
+			quote(f.path), quote(f.from), quote(f.to))
+	}
+}
+
+// Text returns the changelog as plain text.
+func (cl *changeLog) Text() string {
+	if cl.empty() {
+		return "No changes.\n"
+	}
+	plain := func(s string) string { return s }
+	var b strings.Builder
+	if len(cl.added) > 0 {
+		b.WriteString("Added:\n")
+		for _, id := range cl.added {
+			fmt.Fprintf(&b, "  %s\n", describe(id))
+		}
+	}
+	if len(cl.removed) > 0 {
+		b.WriteString("Removed:\n")
+		for _, id := range cl.removed {
+			fmt.Fprintf(&b, "  %s\n", describe(id))
+		}
+	}
+	if len(cl.modified) > 0 {
+		b.WriteString("Modified:\n")
+		for _, c := range cl.modified {
+			fmt.Fprintf(&b, "  %s\n", describe(c.id))
+			for _, f := range c.fields {
+				fmt.Fprintf(&b, "    %s\n", describeChange(f, plain))
+			}
+		}
+	}
+	return b.String()
+}
+
+// Markdown returns the changelog as markdown, with a section
+// for each type of change.
+func (cl *changeLog) Markdown() string {
+	if cl.empty() {
+		return "No changes.\n"
+	}
+	code := func(s string) string { return "`" + s + "`" }
+	var sections []string
+	if len(cl.added) > 0 {
+		s := "## Added\n\n"
+		for _, id := range cl.added {
+			s += "- " + code(describe(id)) + "\n"
+		}
+		sections = append(sections, s)
+	}
+	if len(cl.removed) > 0 {
+		s := "## Removed\n\n"
+		for _, id := range cl.removed {
+			s += "- " + code(describe(id)) + "\n"
+		}
+		sections = append(sections, s)
+	}
+	if len(cl.modified) > 0 {
+		s := "## Modified\n"
+		for _, c := range cl.modified {
+			s += "\n### " + code(describe(c.id)) + "\n\n"
+			for _, f := range c.fields {
+				s += "- " + describeChange(f, code) + "\n"
+			}
+		}
+		sections = append(sections, s)
+	}
+	return strings.Join(sections, "\n")
+}
+
+func (cl *changeLog) empty() bool {
+	return len(cl.added) == 0 && len(cl.removed) == 0 && len(cl.modified) == 0
+}
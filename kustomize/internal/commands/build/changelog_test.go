@@ -0,0 +1,102 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/krusty"
+)
+
+func TestChangelog(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	files := map[string]string{
+		"/old/kustomization.yaml": `
+resources:
+- deployment.yaml
+`,
+		"/old/deployment.yaml": `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+  paused: true
+`,
+		"/new/kustomization.yaml": `
+resources:
+- deployment.yaml
+- service.yaml
+`,
+		"/new/deployment.yaml": `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    tier: frontend
+spec:
+  replicas: 3
+`,
+		"/new/service.yaml": `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: prod
+`,
+	}
+	for name, content := range files {
+		if err := fSys.WriteFile(name, []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	k := krusty.MakeKustomizer(fSys, krusty.MakeDefaultOptions())
+	from, err := k.Run("/old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	to, err := k.Run("/new")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cl, err := makeChangeLog(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `Added:
+  Service prod/web
+Modified:
+  Deployment web
+    added metadata.labels.tier: "frontend"
+    removed spec.paused: true
+    changed spec.replicas: 1 -> 3
+`
+	if actual := cl.Text(); actual != expected {
+		t.Errorf("expected:\n%s\nbut got:\n%s", expected, actual)
+	}
+
+	expected = "## Added\n\n" +
+		"- `Service prod/web`\n" +
+		"\n" +
+		"## Modified\n\n" +
+		"### `Deployment web`\n\n" +
+		"- added `metadata.labels.tier`: `\"frontend\"`\n" +
+		"- removed `spec.paused`: `true`\n" +
+		"- changed `spec.replicas`: `1` -> `3`\n"
+	if actual := cl.Markdown(); actual != expected {
+		t.Errorf("expected:\n%s\nbut got:\n%s", expected, actual)
+	}
+
+	cl, err = makeChangeLog(to, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := cl.Text(); actual != "No changes.\n" {
+		t.Errorf("expected no changes, but got:\n%s", actual)
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagCanonicalName = "canonical"
+	flagCanonicalHelp = `normalize the output for stable diffs.  Fields are
+ordered per Kubernetes conventions, collections use block style, and
+scalars are only quoted where required.
+`
+)
+
+var (
+	flagCanonicalValue = false
+)
+
+func addFlagCanonical(set *pflag.FlagSet) {
+	set.BoolVar(
+		&flagCanonicalValue, flagCanonicalName,
+		false, flagCanonicalHelp)
+}
+
+func isFlagCanonicalSet() bool {
+	return flagCanonicalValue
+}
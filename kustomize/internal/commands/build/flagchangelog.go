@@ -0,0 +1,59 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagChangelogFromName = "changelog-from"
+	flagChangelogFromHelp = `if set, also build the kustomization at this path or
+URL, e.g. one pinned with ?ref=v1.0.0, and emit a changelog
+of the resources added, removed and modified since then,
+instead of the resources.
+`
+	flagChangelogFormatName = "changelog-format"
+	flagChangelogFormatHelp = `the format of the changelog emitted with
+--changelog-from, either 'text' or 'markdown'.
+`
+	changelogText     = "text"
+	changelogMarkdown = "markdown"
+)
+
+var (
+	flagChangelogFromValue   = ""
+	flagChangelogFormatValue = changelogText
+)
+
+func addFlagChangelog(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagChangelogFromValue, flagChangelogFromName,
+		"", flagChangelogFromHelp)
+	set.StringVar(
+		&flagChangelogFormatValue, flagChangelogFormatName,
+		changelogText, flagChangelogFormatHelp)
+}
+
+func validateFlagChangelog() error {
+	switch flagChangelogFormatValue {
+	case changelogText, changelogMarkdown:
+	default:
+		return fmt.Errorf(
+			"illegal flag value --%s %s; legal values: %v",
+			flagChangelogFormatName, flagChangelogFormatValue,
+			[]string{changelogText, changelogMarkdown})
+	}
+	return nil
+}
+
+func getFlagChangelogFromValue() string {
+	return flagChangelogFromValue
+}
+
+func getFlagChangelogFormatValue() string {
+	return flagChangelogFormatValue
+}
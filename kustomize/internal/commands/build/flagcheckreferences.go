@@ -0,0 +1,30 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagCheckReferencesName = "check-references"
+	flagCheckReferencesHelp = `fail the build if a name reference, e.g. a
+Deployment's configMapRef, refers to a resource that
+isn't present in the build output.
+`
+)
+
+var (
+	flagCheckReferencesValue = false
+)
+
+func addFlagCheckReferences(set *pflag.FlagSet) {
+	set.BoolVar(
+		&flagCheckReferencesValue, flagCheckReferencesName,
+		false, flagCheckReferencesHelp)
+}
+
+func isFlagCheckReferencesSet() bool {
+	return flagCheckReferencesValue
+}
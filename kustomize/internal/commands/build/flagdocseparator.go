@@ -0,0 +1,46 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagDocSeparatorName = "doc-separator"
+	flagDocSeparatorHelp = `when to emit the '---' document separator.  If 'needed',
+separators are only emitted between resources.  If 'always',
+a separator also precedes the first resource.
+`
+	docSeparatorNeeded = "needed"
+	docSeparatorAlways = "always"
+)
+
+var (
+	flagDocSeparatorValue = docSeparatorNeeded
+)
+
+func addFlagDocSeparator(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagDocSeparatorValue, flagDocSeparatorName,
+		docSeparatorNeeded, flagDocSeparatorHelp)
+}
+
+func validateFlagDocSeparator() error {
+	switch flagDocSeparatorValue {
+	case docSeparatorNeeded, docSeparatorAlways:
+		return nil
+	default:
+		return fmt.Errorf(
+			"illegal flag value --%s %s; legal values: %v",
+			flagDocSeparatorName, flagDocSeparatorValue,
+			[]string{docSeparatorNeeded, docSeparatorAlways})
+	}
+}
+
+func getFlagDocSeparatorValue() string {
+	return flagDocSeparatorValue
+}
@@ -0,0 +1,29 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagFinalNewlineName = "final-newline"
+	flagFinalNewlineHelp = `end the output with a newline.  If false, trailing
+newlines are removed from the output.
+`
+)
+
+var (
+	flagFinalNewlineValue = true
+)
+
+func addFlagFinalNewline(set *pflag.FlagSet) {
+	set.BoolVar(
+		&flagFinalNewlineValue, flagFinalNewlineName,
+		true, flagFinalNewlineHelp)
+}
+
+func isFlagFinalNewlineSet() bool {
+	return flagFinalNewlineValue
+}
@@ -0,0 +1,46 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagGraphName = "graph"
+	flagGraphHelp = `if set to 'dot' or 'json', emit a graph of the
+resources and the references between them, in the Graphviz
+DOT language or as JSON, instead of the resources.
+`
+	graphDot  = "dot"
+	graphJSON = "json"
+)
+
+var (
+	flagGraphValue = ""
+)
+
+func addFlagGraph(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagGraphValue, flagGraphName,
+		"", flagGraphHelp)
+}
+
+func validateFlagGraph() error {
+	switch flagGraphValue {
+	case "", graphDot, graphJSON:
+		return nil
+	default:
+		return fmt.Errorf(
+			"illegal flag value --%s %s; legal values: %v",
+			flagGraphName, flagGraphValue,
+			[]string{graphDot, graphJSON})
+	}
+}
+
+func getFlagGraphValue() string {
+	return flagGraphValue
+}
@@ -0,0 +1,31 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagHelmifyName = "helmify"
+	flagHelmifyHelp = `if set, write the resources to the given directory as a
+minimal Helm chart, instead of emitting them.  The fields
+referencing a setter are templated, and values.yaml holds
+the values of the setters.
+`
+)
+
+var (
+	flagHelmifyValue = ""
+)
+
+func addFlagHelmify(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagHelmifyValue, flagHelmifyName,
+		"", flagHelmifyHelp)
+}
+
+func getFlagHelmifyValue() string {
+	return flagHelmifyValue
+}
@@ -0,0 +1,45 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagInjectBuildEnvName = "inject-build-env"
+	flagInjectBuildEnvHelp = `add an env var carrying build metadata to every
+container, e.g. --inject-build-env BUILD_SHA=$GIT_SHA.
+Containers already holding the var are left untouched.
+May be repeated.
+`
+)
+
+var (
+	flagInjectBuildEnvValue []string
+)
+
+func addFlagInjectBuildEnv(set *pflag.FlagSet) {
+	set.StringArrayVar(
+		&flagInjectBuildEnvValue, flagInjectBuildEnvName,
+		nil, flagInjectBuildEnvHelp)
+}
+
+func validateFlagInjectBuildEnv() error {
+	for _, v := range flagInjectBuildEnvValue {
+		if strings.Index(v, "=") < 1 {
+			return fmt.Errorf(
+				"illegal flag value --%s %s; expected NAME=VALUE",
+				flagInjectBuildEnvName, v)
+		}
+	}
+	return nil
+}
+
+func getFlagInjectBuildEnvValue() []string {
+	return flagInjectBuildEnvValue
+}
@@ -0,0 +1,40 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagMaxDepthName = "max-depth"
+	flagMaxDepthHelp = `if greater than zero, fail the build if bases or
+components are nested more than this many levels deep.
+`
+)
+
+var (
+	flagMaxDepthValue = 0
+)
+
+func addFlagMaxDepth(set *pflag.FlagSet) {
+	set.IntVar(
+		&flagMaxDepthValue, flagMaxDepthName,
+		0, flagMaxDepthHelp)
+}
+
+func validateFlagMaxDepth() error {
+	if flagMaxDepthValue < 0 {
+		return fmt.Errorf(
+			"illegal flag value --%s %d; must not be negative",
+			flagMaxDepthName, flagMaxDepthValue)
+	}
+	return nil
+}
+
+func getFlagMaxDepthValue() int {
+	return flagMaxDepthValue
+}
@@ -0,0 +1,40 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagMaxResourcesName = "max-resources"
+	flagMaxResourcesHelp = `if greater than zero, fail the build if it
+would emit more than this many resources.
+`
+)
+
+var (
+	flagMaxResourcesValue = 0
+)
+
+func addFlagMaxResources(set *pflag.FlagSet) {
+	set.IntVar(
+		&flagMaxResourcesValue, flagMaxResourcesName,
+		0, flagMaxResourcesHelp)
+}
+
+func validateFlagMaxResources() error {
+	if flagMaxResourcesValue < 0 {
+		return fmt.Errorf(
+			"illegal flag value --%s %d; must not be negative",
+			flagMaxResourcesName, flagMaxResourcesValue)
+	}
+	return nil
+}
+
+func getFlagMaxResourcesValue() int {
+	return flagMaxResourcesValue
+}
@@ -0,0 +1,48 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagOnlyName = "only"
+	flagOnlyHelp = `if set, build only the contribution of the resources or
+components at this path, relative to the kustomization
+directory, e.g. components/web.  The transformers of the
+kustomizations containing it are still applied.
+`
+)
+
+var (
+	flagOnlyValue = ""
+)
+
+func addFlagOnly(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagOnlyValue, flagOnlyName,
+		"", flagOnlyHelp)
+}
+
+func validateFlagOnly() error {
+	if flagOnlyValue == "" {
+		return nil
+	}
+	if path.IsAbs(flagOnlyValue) ||
+		strings.HasPrefix(path.Clean(flagOnlyValue), "..") {
+		return fmt.Errorf(
+			"illegal flag value --%s %s; must be a path within the kustomization directory",
+			flagOnlyName, flagOnlyValue)
+	}
+	return nil
+}
+
+func getFlagOnlyValue() string {
+	return flagOnlyValue
+}
@@ -0,0 +1,59 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+const (
+	flagPluginMemoryLimitName = "plugin-memory-limit"
+	flagPluginMemoryLimitHelp = `with plugins enabled, the maximum memory of each
+run of an exec plugin -- e.g. 512m.  The plugin is killed if
+it exceeds it.  Enforced with cgroups, where available.
+`
+	flagPluginCPULimitName = "plugin-cpu-limit"
+	flagPluginCPULimitHelp = `with plugins enabled, the number of CPUs each run
+of an exec plugin may use -- e.g. 0.5.  Enforced with cgroups,
+where available.
+`
+)
+
+var (
+	flagPluginMemoryLimitValue = ""
+	flagPluginCPULimitValue    = ""
+)
+
+func addFlagPluginLimits(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagPluginMemoryLimitValue, flagPluginMemoryLimitName,
+		"", flagPluginMemoryLimitHelp)
+	set.StringVar(
+		&flagPluginCPULimitValue, flagPluginCPULimitName,
+		"", flagPluginCPULimitHelp)
+}
+
+func validateFlagPluginLimits() error {
+	limits := getFlagPluginLimitsValue()
+	if !limits.IsSet() {
+		return nil
+	}
+	if !isFlagEnablePluginsSet() {
+		return fmt.Errorf(
+			"--%s and --%s require --%s",
+			flagPluginMemoryLimitName, flagPluginCPULimitName,
+			flagEnablePluginsName)
+	}
+	return limits.Validate()
+}
+
+func getFlagPluginLimitsValue() types.PluginResourceLimits {
+	return types.PluginResourceLimits{
+		Memory: flagPluginMemoryLimitValue,
+		CPU:    flagPluginCPULimitValue,
+	}
+}
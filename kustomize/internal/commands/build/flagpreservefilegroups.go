@@ -0,0 +1,30 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagPreserveFileGroupsName = "preserve-file-groups"
+	flagPreserveFileGroupsHelp = `emit the resources read from the same file
+contiguously, in the position of the first of them, rather
+than splitting them across the output, e.g. by kind.
+`
+)
+
+var (
+	flagPreserveFileGroupsValue = false
+)
+
+func addFlagPreserveFileGroups(set *pflag.FlagSet) {
+	set.BoolVar(
+		&flagPreserveFileGroupsValue, flagPreserveFileGroupsName,
+		false, flagPreserveFileGroupsHelp)
+}
+
+func isFlagPreserveFileGroupsSet() bool {
+	return flagPreserveFileGroupsValue
+}
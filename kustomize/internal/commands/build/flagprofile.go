@@ -0,0 +1,57 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagProfileName = "profile"
+	flagProfileHelp = `emit, to stderr, the wall-clock time each generator
+and transformer took, and the number of resources emitted.
+`
+	flagProfileFormatName = "profile-format"
+	flagProfileFormatHelp = `the format of the timings emitted with --profile,
+either 'table' or 'json'.
+`
+	profileTable = "table"
+	profileJSON  = "json"
+)
+
+var (
+	flagProfileValue       = false
+	flagProfileFormatValue = profileTable
+)
+
+func addFlagProfile(set *pflag.FlagSet) {
+	set.BoolVar(
+		&flagProfileValue, flagProfileName,
+		false, flagProfileHelp)
+	set.StringVar(
+		&flagProfileFormatValue, flagProfileFormatName,
+		profileTable, flagProfileFormatHelp)
+}
+
+func validateFlagProfile() error {
+	switch flagProfileFormatValue {
+	case profileTable, profileJSON:
+		return nil
+	default:
+		return fmt.Errorf(
+			"illegal flag value --%s %s; legal values: %v",
+			flagProfileFormatName, flagProfileFormatValue,
+			[]string{profileTable, profileJSON})
+	}
+}
+
+func isFlagProfileSet() bool {
+	return flagProfileValue
+}
+
+func getFlagProfileFormatValue() string {
+	return flagProfileFormatValue
+}
@@ -0,0 +1,31 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagSafeLabelsName = "safe-labels"
+	flagSafeLabelsHelp = `fail the build if commonLabels would modify the
+selector of a Deployment, StatefulSet or DaemonSet.  Such
+selectors are immutable, so the output would fail to apply
+to existing workloads.
+`
+)
+
+var (
+	flagSafeLabelsValue = false
+)
+
+func addFlagSafeLabels(set *pflag.FlagSet) {
+	set.BoolVar(
+		&flagSafeLabelsValue, flagSafeLabelsName,
+		false, flagSafeLabelsHelp)
+}
+
+func isFlagSafeLabelsSet() bool {
+	return flagSafeLabelsValue
+}
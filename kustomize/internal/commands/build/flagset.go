@@ -0,0 +1,45 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagSetName = "set"
+	flagSetHelp = `override a field of the kustomization for this build
+only, e.g. --set namespace=test --set images.nginx=nginx:2.
+Supported keys are namespace, namePrefix, images.<name>
+and replicas.<name>.  May be repeated.
+`
+)
+
+var (
+	flagSetValue []string
+)
+
+func addFlagSet(set *pflag.FlagSet) {
+	set.StringArrayVar(
+		&flagSetValue, flagSetName,
+		nil, flagSetHelp)
+}
+
+func validateFlagSet() error {
+	for _, v := range flagSetValue {
+		if !strings.Contains(v, "=") {
+			return fmt.Errorf(
+				"illegal flag value --%s %s; expected key=value",
+				flagSetName, v)
+		}
+	}
+	return nil
+}
+
+func getFlagSetValue() []string {
+	return flagSetValue
+}
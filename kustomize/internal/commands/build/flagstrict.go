@@ -0,0 +1,29 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagStrictName = "strict"
+	flagStrictHelp = `fail the build if there are any warnings, e.g. from
+--warn-missing-labels.
+`
+)
+
+var (
+	flagStrictValue = false
+)
+
+func addFlagStrict(set *pflag.FlagSet) {
+	set.BoolVar(
+		&flagStrictValue, flagStrictName,
+		false, flagStrictHelp)
+}
+
+func isFlagStrictSet() bool {
+	return flagStrictValue
+}
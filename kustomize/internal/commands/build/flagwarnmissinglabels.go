@@ -0,0 +1,31 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagWarnMissingLabelsName = "warn-missing-labels"
+	flagWarnMissingLabelsHelp = `comma separated labels, e.g.
+app.kubernetes.io/name,app.kubernetes.io/version, which
+every resource is recommended to have.  A warning is
+printed for each resource lacking any of them.
+`
+)
+
+var (
+	flagWarnMissingLabelsValue []string
+)
+
+func addFlagWarnMissingLabels(set *pflag.FlagSet) {
+	set.StringSliceVar(
+		&flagWarnMissingLabelsValue, flagWarnMissingLabelsName,
+		nil, flagWarnMissingLabelsHelp)
+}
+
+func getFlagWarnMissingLabelsValue() []string {
+	return flagWarnMissingLabelsValue
+}
@@ -0,0 +1,32 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"bytes"
+)
+
+const docSeparator = "---\n"
+
+// frame applies the document separator and final newline
+// settings to the yaml output.  The input has separators
+// only between documents, and ends with a newline.
+func frame(in []byte, separator string, finalNewline bool) []byte {
+	if len(in) == 0 {
+		return in
+	}
+	out := in
+	if separator == docSeparatorAlways &&
+		!bytes.HasPrefix(out, []byte(docSeparator)) {
+		out = append([]byte(docSeparator), out...)
+	}
+	if finalNewline {
+		if !bytes.HasSuffix(out, []byte("\n")) {
+			out = append(out, '\n')
+		}
+	} else {
+		out = bytes.TrimRight(out, "\n")
+	}
+	return out
+}
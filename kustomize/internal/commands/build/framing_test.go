@@ -0,0 +1,41 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"testing"
+)
+
+func TestFrame(t *testing.T) {
+	const twoDocs = `kind: ConfigMap
+---
+kind: Secret
+`
+	var cases = []struct {
+		name         string
+		input        string
+		separator    string
+		finalNewline bool
+		expected     string
+	}{
+		{"default", twoDocs, docSeparatorNeeded, true, twoDocs},
+		{"always", twoDocs, docSeparatorAlways, true,
+			"---\nkind: ConfigMap\n---\nkind: Secret\n"},
+		{"noFinalNewline", twoDocs, docSeparatorNeeded, false,
+			"kind: ConfigMap\n---\nkind: Secret"},
+		{"alwaysNoFinalNewline", twoDocs, docSeparatorAlways, false,
+			"---\nkind: ConfigMap\n---\nkind: Secret"},
+		{"alreadySeparated", "---\nkind: ConfigMap\n", docSeparatorAlways, true,
+			"---\nkind: ConfigMap\n"},
+		{"addFinalNewline", "kind: ConfigMap", docSeparatorNeeded, true,
+			"kind: ConfigMap\n"},
+		{"empty", "", docSeparatorAlways, true, ""},
+	}
+	for _, c := range cases {
+		actual := string(frame([]byte(c.input), c.separator, c.finalNewline))
+		if actual != c.expected {
+			t.Errorf("%s: expected %q, got %q", c.name, c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/kyaml/fieldmeta"
+	"sigs.k8s.io/kustomize/kyaml/krmfile"
+	"sigs.k8s.io/kustomize/kyaml/setters2"
+	"sigs.k8s.io/kustomize/kyaml/setters2/settersutil"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// helmify writes a minimal Helm chart to chartDir, with a
+// template for each resource in m, and a values.yaml holding
+// the values of the setters defined in the Krmfile of the
+// kustomization at kustomizationPath.
+//
+// The fields referencing a setter, in the resources read from
+// the kustomization directory, are templated with the value
+// keyed by the setter name, e.g. {{ .Values.replicas }}.
+// This is a one-way export to ease migrating to Helm.
+func helmify(
+	fSys filesys.FileSystem, chartDir, kustomizationPath string,
+	m resmap.ResMap) error {
+	values := map[string]interface{}{}
+	var markers []settersutil.SetterMarker
+	openAPIFile, err := krmfile.OpenAPIFile(kustomizationPath)
+	if err != nil {
+		return err
+	}
+	if fSys.Exists(openAPIFile) {
+		values, err = setterValues(openAPIFile, kustomizationPath)
+		if err != nil {
+			return err
+		}
+		b, err := settersutil.ExportSetters(openAPIFile, kustomizationPath)
+		if err != nil {
+			return err
+		}
+		prefix := fieldmeta.DefinitionsPrefix + fieldmeta.SetterDefinitionPrefix
+		for _, mk := range b.Markers {
+			// substitutions aren't templated
+			if strings.HasPrefix(mk.Ref, prefix) {
+				mk.Ref = strings.TrimPrefix(mk.Ref, prefix)
+				markers = append(markers, mk)
+			}
+		}
+	}
+
+	templates := filepath.Join(chartDir, "templates")
+	if err := fSys.MkdirAll(templates); err != nil {
+		return err
+	}
+	for _, res := range m.Resources() {
+		out, err := helmTemplate(res, markers)
+		if err != nil {
+			return err
+		}
+		err = fSys.WriteFile(filepath.Join(templates, fileName(res)), out)
+		if err != nil {
+			return err
+		}
+	}
+	out, err := yaml.Marshal(values)
+	if err != nil {
+		return err
+	}
+	err = fSys.WriteFile(filepath.Join(chartDir, "values.yaml"), out)
+	if err != nil {
+		return err
+	}
+	out, err = yaml.Marshal(map[string]string{
+		"apiVersion":  "v2",
+		"name":        filepath.Base(chartDir),
+		"description": "Exported from " + kustomizationPath + " by kustomize build --helmify",
+		"version":     "0.1.0",
+	})
+	if err != nil {
+		return err
+	}
+	return fSys.WriteFile(filepath.Join(chartDir, "Chart.yaml"), out)
+}
+
+// setterValues returns the values of the setters defined in
+// the OpenAPI file, keyed by setter name.  Values of setters
+// whose schema has an integer, number or boolean type are
+// emitted as such.
+func setterValues(
+	openAPIFile, resourcesPath string) (map[string]interface{}, error) {
+	l := setters2.List{}
+	if err := l.ListSetters(openAPIFile, resourcesPath); err != nil {
+		return nil, err
+	}
+	schema, err := setters2.ValuesSchema(openAPIFile)
+	if err != nil {
+		return nil, err
+	}
+	properties, _ := schema["properties"].(map[string]interface{})
+	values := map[string]interface{}{}
+	for _, s := range l.Setters {
+		if len(s.ListValues) > 0 {
+			values[s.Name] = s.ListValues
+			continue
+		}
+		values[s.Name] = s.Value
+		property, _ := properties[s.Name].(map[string]interface{})
+		var v interface{}
+		switch property["type"] {
+		case "integer":
+			v, err = strconv.Atoi(s.Value)
+		case "number":
+			v, err = strconv.ParseFloat(s.Value, 64)
+		case "boolean":
+			v, err = strconv.ParseBool(s.Value)
+		default:
+			continue
+		}
+		if err == nil {
+			values[s.Name] = v
+		}
+	}
+	return values, nil
+}
+
+// helmTemplate returns the resource as a Helm template, in
+// which the fields identified by the markers are templated
+// with the value of their setter.
+func helmTemplate(
+	res *resource.Resource, markers []settersutil.SetterMarker) ([]byte, error) {
+	out, err := res.AsYAML()
+	if err != nil {
+		return nil, err
+	}
+	node, err := kyaml.Parse(string(out))
+	if err != nil {
+		return nil, err
+	}
+	id := res.OrgId()
+	replacements := map[string]string{}
+	for _, mk := range markers {
+		if mk.Kind != id.Kind || mk.Name != id.Name || mk.Namespace != id.Namespace {
+			continue
+		}
+		field, err := node.Pipe(kyaml.Lookup(mk.Path...))
+		if err != nil {
+			return nil, err
+		}
+		if field == nil || field.YNode().Kind != kyaml.ScalarNode {
+			continue
+		}
+		// the template isn't valid yaml, so it replaces a
+		// placeholder once the resource is serialized
+		placeholder := fmt.Sprintf("kustomize-helmify-%d-", len(replacements))
+		replacements[placeholder] = helmValue(mk.Ref, field.YNode().Tag == kyaml.StringTag)
+		field.YNode().Value = placeholder
+		field.YNode().Tag = kyaml.StringTag
+		field.YNode().Style = 0
+	}
+	s, err := node.String()
+	if err != nil {
+		return nil, err
+	}
+	for placeholder, value := range replacements {
+		s = strings.Replace(s, placeholder, value, 1)
+	}
+	return []byte(s), nil
+}
+
+var helmIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// helmValue returns the Helm template action emitting the
+// value keyed by name, quoted if the field is a string.
+func helmValue(name string, quote bool) string {
+	ref := ".Values." + name
+	if !helmIdentifier.MatchString(name) {
+		ref = fmt.Sprintf("index .Values %q", name)
+	}
+	if quote {
+		return "{{ " + ref + " | quote }}"
+	}
+	return "{{ " + ref + " }}"
+}
@@ -0,0 +1,86 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+)
+
+func TestHelmify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer openapi.ResetOpenAPI()
+	files := map[string]string{
+		"kustomization.yaml": `
+resources:
+- deployment.yaml
+`,
+		"deployment.yaml": `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+`,
+		"Krmfile": `
+apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      type: integer
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+`,
+	}
+	for name, content := range files {
+		err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fSys := filesys.MakeFsOnDisk()
+	m, err := krusty.MakeKustomizer(fSys, krusty.MakeDefaultOptions()).Run(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chartDir := filepath.Join(dir, "chart")
+	if err := helmify(fSys, chartDir, dir, m); err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := fSys.ReadFile(filepath.Join(chartDir, "values.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(values) != "replicas: 3\n" {
+		t.Errorf("unexpected values.yaml:\n%s", values)
+	}
+	template, err := fSys.ReadFile(
+		filepath.Join(chartDir, "templates", fileName(m.Resources()[0])))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(template), "replicas: {{ .Values.replicas }}") {
+		t.Errorf("unexpected template:\n%s", template)
+	}
+	if !fSys.Exists(filepath.Join(chartDir, "Chart.yaml")) {
+		t.Errorf("expected Chart.yaml")
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"sigs.k8s.io/kustomize/api/krusty"
+)
+
+type stageJSON struct {
+	Kind         string  `json:"kind"`
+	Name         string  `json:"name"`
+	Root         string  `json:"root"`
+	Milliseconds float64 `json:"milliseconds"`
+	Resources    int     `json:"resources"`
+}
+
+type buildProfileJSON struct {
+	Stages       []stageJSON `json:"stages"`
+	Milliseconds float64     `json:"milliseconds"`
+	Resources    int         `json:"resources"`
+}
+
+// emitProfile writes the timing of each generator and
+// transformer of the build, slowest first, followed by the
+// total time and number of resources.
+func emitProfile(w io.Writer, p *krusty.Profile, format string) error {
+	stages := append([]krusty.StageTiming{}, p.Stages...)
+	sort.SliceStable(stages, func(i, j int) bool {
+		return stages[i].Duration > stages[j].Duration
+	})
+	if format == profileJSON {
+		out := buildProfileJSON{
+			Stages:       []stageJSON{},
+			Milliseconds: milliseconds(p.Duration),
+			Resources:    p.Resources,
+		}
+		for _, s := range stages {
+			out.Stages = append(out.Stages, stageJSON{
+				Kind:         s.Kind,
+				Name:         s.Name,
+				Root:         s.Root,
+				Milliseconds: milliseconds(s.Duration),
+				Resources:    s.Resources,
+			})
+		}
+		b, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", b)
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KIND\tNAME\tROOT\tTIME\tRESOURCES")
+	for _, s := range stages {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%v\t%d\n",
+			s.Kind, s.Name, s.Root, s.Duration.Round(time.Microsecond), s.Resources)
+	}
+	fmt.Fprintf(tw, "total\t\t\t%v\t%d\n",
+		p.Duration.Round(time.Microsecond), p.Resources)
+	return tw.Flush()
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,81 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kustomize/api/krusty"
+)
+
+func makeTestProfile() *krusty.Profile {
+	return &krusty.Profile{
+		Stages: []krusty.StageTiming{
+			{
+				Kind:      "generator",
+				Name:      "ConfigMapGenerator",
+				Root:      "/app",
+				Duration:  time.Millisecond,
+				Resources: 1,
+			},
+			{
+				Kind:      "transformer",
+				Name:      "HashTransformer",
+				Root:      "/app",
+				Duration:  3 * time.Millisecond,
+				Resources: 2,
+			},
+		},
+		Duration:  4 * time.Millisecond,
+		Resources: 2,
+	}
+}
+
+func TestEmitProfileTable(t *testing.T) {
+	var out bytes.Buffer
+	if err := emitProfile(&out, makeTestProfile(), profileTable); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `KIND         NAME                ROOT  TIME  RESOURCES
+transformer  HashTransformer     /app  3ms   2
+generator    ConfigMapGenerator  /app  1ms   1
+total                                  4ms   2
+`
+	if out.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestEmitProfileJSON(t *testing.T) {
+	var out bytes.Buffer
+	if err := emitProfile(&out, makeTestProfile(), profileJSON); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `{
+  "stages": [
+    {
+      "kind": "transformer",
+      "name": "HashTransformer",
+      "root": "/app",
+      "milliseconds": 3,
+      "resources": 2
+    },
+    {
+      "kind": "generator",
+      "name": "ConfigMapGenerator",
+      "root": "/app",
+      "milliseconds": 1,
+      "resources": 1
+    }
+  ],
+  "milliseconds": 4,
+  "resources": 2
+}
+`
+	if out.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
@@ -138,6 +138,100 @@ func PrettyFileDiff(s1, s2 string) string {
 	return dmp.DiffPrettyText(diffs)
 }
 
+// diffContext is the number of unchanged lines around the changes in the
+// hunks of a unified diff.
+const diffContext = 3
+
+// diffLine is a line of a unified diff -- op is one of ' ', '-' or '+'.
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// UnifiedDiff returns the unified diff between the old content s1 and the new
+// content s2 of the file name, or the empty string if they are identical.
+func UnifiedDiff(name, s1, s2 string) string {
+	if s1 == s2 {
+		return ""
+	}
+	dmp := diffmatchpatch.New()
+	wSrc, wDst, warray := dmp.DiffLinesToRunes(s1, s2)
+	diffs := dmp.DiffMainRunes(wSrc, wDst, false)
+	diffs = dmp.DiffCharsToLines(diffs, warray)
+
+	var lines []diffLine
+	for _, d := range diffs {
+		op := byte(' ')
+		switch d.Type {
+		case diffmatchpatch.DiffDelete:
+			op = '-'
+		case diffmatchpatch.DiffInsert:
+			op = '+'
+		}
+		for _, l := range strings.SplitAfter(d.Text, "\n") {
+			if l != "" {
+				lines = append(lines, diffLine{op: op, text: strings.TrimSuffix(l, "\n")})
+			}
+		}
+	}
+
+	// oldLine[i] and newLine[i] are the number of lines of the old and new
+	// content preceding lines[i]
+	oldLine := make([]int, len(lines)+1)
+	newLine := make([]int, len(lines)+1)
+	for i, l := range lines {
+		oldLine[i+1], newLine[i+1] = oldLine[i], newLine[i]
+		if l.op != '+' {
+			oldLine[i+1]++
+		}
+		if l.op != '-' {
+			newLine[i+1]++
+		}
+	}
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "--- a/%s\n+++ b/%s\n", name, name)
+	for i := 0; i < len(lines); {
+		if lines[i].op == ' ' {
+			i++
+			continue
+		}
+		// extend the hunk until the changes are further apart than twice
+		// the context
+		start, end := i-diffContext, i
+		if start < 0 {
+			start = 0
+		}
+		for j := i; j < len(lines) && j-end <= 2*diffContext; j++ {
+			if lines[j].op != ' ' {
+				end = j
+			}
+		}
+		stop := end + diffContext + 1
+		if stop > len(lines) {
+			stop = len(lines)
+		}
+		fmt.Fprintf(b, "@@ -%s +%s @@\n",
+			hunkRange(oldLine[start], oldLine[stop]-oldLine[start]),
+			hunkRange(newLine[start], newLine[stop]-newLine[start]))
+		for _, l := range lines[start:stop] {
+			fmt.Fprintf(b, "%c%s\n", l.op, l.text)
+		}
+		i = stop
+	}
+	return b.String()
+}
+
+// hunkRange returns the range of lines of a hunk, given the number of lines
+// preceding it and the number of lines in it.
+func hunkRange(preceding, count int) string {
+	if count == 0 {
+		// an empty range is given by the line preceding it
+		return fmt.Sprintf("%d,0", preceding)
+	}
+	return fmt.Sprintf("%d,%d", preceding+1, count)
+}
+
 // SyncFile copies file from src file path to a dst file path by replacement
 // deletes dst file if src file doesn't exist
 func SyncFile(src, dst string) error {
@@ -283,3 +283,22 @@ metadata:
 	assert.Contains(t, PrettyFileDiff(s1, s2), expectedLine1)
 	assert.Contains(t, PrettyFileDiff(s1, s2), expectedLine2)
 }
+
+func TestUnifiedDiff(t *testing.T) {
+	s1 := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	s2 := "0\n1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n"
+	assert.Equal(t, `--- a/f.yaml
++++ b/f.yaml
+@@ -1,3 +1,4 @@
++0
+ 1
+ 2
+ 3
+@@ -9,4 +10,3 @@
+ 9
+ 10
+ 11
+-12
+`, UnifiedDiff("f.yaml", s1, s2))
+	assert.Equal(t, "", UnifiedDiff("f.yaml", s1, s1))
+}
@@ -231,6 +231,9 @@ const (
 	// SubstitutionDefinitionPrefix is the prefix for substitution definition keys.
 	SubstitutionDefinitionPrefix = CLIDefinitionsPrefix + "substitutions."
 
+	// ConstraintDefinitionPrefix is the prefix for constraint definition keys.
+	ConstraintDefinitionPrefix = CLIDefinitionsPrefix + "constraints."
+
 	// DefinitionsPrefix is the prefix used to reference definitions in the OpenAPI
 	DefinitionsPrefix = "#/definitions/"
 )
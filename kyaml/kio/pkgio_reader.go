@@ -76,6 +76,7 @@ type LocalPackageReadWriter struct {
 func (r *LocalPackageReadWriter) Read() ([]*yaml.RNode, error) {
 	nodes, err := LocalPackageReader{
 		PackagePath:         r.PackagePath,
+		PackageFileName:     r.PackageFileName,
 		MatchFilesGlob:      r.MatchFilesGlob,
 		IncludeSubpackages:  r.IncludeSubpackages,
 		ErrorIfNonResources: r.ErrorIfNonResources,
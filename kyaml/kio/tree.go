@@ -37,6 +37,10 @@ type TreeWriter struct {
 	Root      string
 	Fields    []TreeWriterField
 	Structure TreeStructure
+
+	// Notes, if set, returns notes to print under a Resource after its fields
+	// -- e.g. its broken references.
+	Notes func(*yaml.RNode) []string
 }
 
 // TreeWriterField configures a Resource field to be included in the tree
@@ -355,6 +359,12 @@ func (p TreeWriter) doResource(leaf *yaml.RNode, metaString string, branch treep
 		}
 	}
 
+	if p.Notes != nil {
+		for _, note := range p.Notes(leaf) {
+			n.AddNode(note)
+		}
+	}
+
 	return n, nil
 }
 
@@ -3,8 +3,49 @@
 
 package krmfile
 
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
 // KRMFileName is the file where Krm metadata is stored
 const (
 	// KrmfileName is the name of the file that KRM metadata is written to
 	KrmfileName = "Krmfile"
+
+	// OpenAPIFileAnnotation on a Krmfile is the path, relative to the package,
+	// of the file containing the OpenAPI definitions of the package -- when
+	// they aren't in the Krmfile itself.
+	OpenAPIFileAnnotation = "config.kubernetes.io/openapi-file"
 )
+
+// OpenAPIFile returns the path to the file containing the OpenAPI definitions
+// of the package in dir: the file named by the OpenAPIFileAnnotation of its
+// Krmfile if it has one, otherwise the Krmfile.
+func OpenAPIFile(dir string) (string, error) {
+	path := filepath.Join(dir, KrmfileName)
+	if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() {
+		// not a package, e.g. dir is a file -- leave it to the caller
+		return path, nil
+	}
+	object, err := yaml.ReadFile(path)
+	if err != nil {
+		return "", errors.WrapPrefixf(err, path)
+	}
+	a, err := object.Pipe(yaml.GetAnnotation(OpenAPIFileAnnotation))
+	if err != nil || a == nil {
+		return path, err
+	}
+	file := filepath.Clean(a.YNode().Value)
+	if filepath.IsAbs(file) || file == ".." ||
+		strings.HasPrefix(file, ".."+string(filepath.Separator)) {
+		return "", errors.Errorf(
+			"%s annotation of %s must be a path within the package, was %s",
+			OpenAPIFileAnnotation, path, a.YNode().Value)
+	}
+	return filepath.Join(dir, file), nil
+}
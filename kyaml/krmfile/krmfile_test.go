@@ -0,0 +1,97 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krmfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIFile(t *testing.T) {
+	var tests = []struct {
+		name     string
+		krmfile  string
+		arg      string
+		expected string
+		err      string
+	}{
+		{
+			name: "no Krmfile",
+			// the Krmfile is created by the caller
+			expected: KrmfileName,
+		},
+		{
+			name: "Krmfile",
+			krmfile: `apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+`,
+			expected: KrmfileName,
+		},
+		{
+			name: "openapi-file annotation",
+			krmfile: `apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+metadata:
+  annotations:
+    config.kubernetes.io/openapi-file: setters/openapi.yaml
+`,
+			expected: filepath.Join("setters", "openapi.yaml"),
+		},
+		{
+			name: "openapi-file annotation outside the package",
+			krmfile: `apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+metadata:
+  annotations:
+    config.kubernetes.io/openapi-file: ../openapi.yaml
+`,
+			err: "must be a path within the package",
+		},
+		{
+			name: "file",
+			// a file isn't a package, and has no Krmfile
+			arg:      "deployment.yaml",
+			expected: filepath.Join("deployment.yaml", KrmfileName),
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			d, err := ioutil.TempDir("", "krmfile-test")
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			defer os.RemoveAll(d)
+			if test.krmfile != "" {
+				err := ioutil.WriteFile(filepath.Join(d, KrmfileName), []byte(test.krmfile), 0600)
+				if !assert.NoError(t, err) {
+					t.FailNow()
+				}
+			}
+			dir := d
+			if test.arg != "" {
+				dir = filepath.Join(d, test.arg)
+				if !assert.NoError(t, ioutil.WriteFile(dir, []byte("kind: Deployment\n"), 0600)) {
+					t.FailNow()
+				}
+			}
+
+			actual, err := OpenAPIFile(dir)
+			if test.err != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), test.err)
+				}
+				return
+			}
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, filepath.Join(d, test.expected), actual)
+		})
+	}
+}
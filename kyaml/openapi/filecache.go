@@ -0,0 +1,72 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package openapi
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/go-openapi/spec"
+)
+
+// schemaFiles contains the schemas parsed from OpenAPI files by
+// AddSchemaFromFile, if caching is enabled.  It isn't reset by ResetOpenAPI,
+// so that programs which reset the OpenAPI before each operation on a package,
+// e.g. setting many setters one after another, don't parse the same file again.
+var schemaFiles schemaFileCache
+
+// CacheSchemaFiles enables or disables caching the schemas parsed from OpenAPI
+// files by AddSchemaFromFile.  A cached schema is reused while the contents of
+// the file are unchanged -- files are still read, so that changes made to a
+// file between calls are never missed, even when they don't change its size
+// or modification time.  Disabling the cache empties it.
+func CacheSchemaFiles(enabled bool) {
+	schemaFiles.Lock()
+	defer schemaFiles.Unlock()
+	schemaFiles.enabled = enabled
+	schemaFiles.entries = nil
+}
+
+type schemaFileCache struct {
+	sync.Mutex
+	enabled bool
+	entries map[schemaFileKey]schemaFileEntry
+}
+
+type schemaFileKey struct {
+	path  string
+	field string
+}
+
+type schemaFileEntry struct {
+	// contents of the file the schema was parsed from
+	contents []byte
+
+	// schema parsed from the file, nil if the file contains no OpenAPI
+	schema *spec.Schema
+}
+
+// get returns the schema parsed from the field of the file at path with
+// contents b, parsing it only if it isn't cached.
+func (c *schemaFileCache) get(path, field string, b []byte) (*spec.Schema, error) {
+	c.Lock()
+	defer c.Unlock()
+	if !c.enabled {
+		return parseSchemaFile(b, field)
+	}
+
+	key := schemaFileKey{path: path, field: field}
+	if e, found := c.entries[key]; found && bytes.Equal(e.contents, b) {
+		return e.schema, nil
+	}
+	sc, err := parseSchemaFile(b, field)
+	if err != nil {
+		return nil, err
+	}
+	if c.entries == nil {
+		c.entries = map[schemaFileKey]schemaFileEntry{}
+	}
+	c.entries[key] = schemaFileEntry{contents: b, schema: sc}
+	return sc, nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package openapi
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheSchemaFiles(t *testing.T) {
+	CacheSchemaFiles(true)
+	defer CacheSchemaFiles(false)
+	ResetOpenAPI()
+	defer ResetOpenAPI()
+
+	f, err := ioutil.TempFile("", "openapi-")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.Remove(f.Name())
+	if !assert.NoError(t, ioutil.WriteFile(f.Name(), []byte(setterSchema("image", "nginx")), 0600)) {
+		t.FailNow()
+	}
+
+	if !assert.NoError(t, AddSchemaFromFile(f.Name())) {
+		t.FailNow()
+	}
+	key := schemaFileKey{path: f.Name(), field: SupplementaryOpenAPIFieldName}
+	cached := schemaFiles.entries[key].schema
+	if !assert.NotNil(t, cached) {
+		t.FailNow()
+	}
+
+	// the unchanged file is not parsed again
+	ResetOpenAPI()
+	if !assert.NoError(t, AddSchemaFromFile(f.Name())) {
+		t.FailNow()
+	}
+	assert.True(t, cached == schemaFiles.entries[key].schema)
+	s, err := GetSchema(`{"$ref": "#/definitions/io.k8s.cli.setters.image"}`)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `map[x-k8s-cli:map[setter:map[name:image value:nginx]]]`,
+		fmt.Sprintf("%v", s.Schema.Extensions))
+
+	// a change of the same size is not missed
+	if !assert.NoError(t, ioutil.WriteFile(f.Name(), []byte(setterSchema("image", "apach")), 0600)) {
+		t.FailNow()
+	}
+	ResetOpenAPI()
+	if !assert.NoError(t, AddSchemaFromFile(f.Name())) {
+		t.FailNow()
+	}
+	assert.False(t, cached == schemaFiles.entries[key].schema)
+	s, err = GetSchema(`{"$ref": "#/definitions/io.k8s.cli.setters.image"}`)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `map[x-k8s-cli:map[setter:map[name:image value:apach]]]`,
+		fmt.Sprintf("%v", s.Schema.Extensions))
+
+	// disabling the cache empties it
+	CacheSchemaFiles(false)
+	assert.Empty(t, schemaFiles.entries)
+}
+
+func setterSchema(name, value string) string {
+	return fmt.Sprintf(`
+openAPI:
+  definitions:
+    io.k8s.cli.setters.%s:
+      x-k8s-cli:
+        setter:
+          name: %s
+          value: "%s"
+`, name, name, value)
+}
+
+func BenchmarkAddSchemaFromFile(b *testing.B) {
+	var setters []string
+	for i := 0; i < 500; i++ {
+		setters = append(setters, strings.TrimPrefix(
+			setterSchema(fmt.Sprintf("setter-%d", i), "value"), "\nopenAPI:\n  definitions:\n"))
+	}
+	f, err := ioutil.TempFile("", "openapi-")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	contents := "openAPI:\n  definitions:\n" + strings.Join(setters, "")
+	if err := ioutil.WriteFile(f.Name(), []byte(contents), 0600); err != nil {
+		b.Fatal(err)
+	}
+
+	for _, cache := range []bool{false, true} {
+		b.Run(fmt.Sprintf("cache=%t", cache), func(b *testing.B) {
+			CacheSchemaFiles(cache)
+			defer CacheSchemaFiles(false)
+			for i := 0; i < b.N; i++ {
+				ResetOpenAPI()
+				SuppressBuiltInSchemaUse()
+				if err := AddSchemaFromFile(f.Name()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+	ResetOpenAPI()
+}
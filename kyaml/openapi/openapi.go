@@ -75,10 +75,27 @@ func AddSchemaFromFileUsingField(path, field string) error {
 		return err
 	}
 
+	sc, err := schemaFiles.get(path, field, b)
+	if err != nil {
+		return err
+	}
+	if sc == nil {
+		// doesn't contain openAPI definitions
+		return nil
+	}
+
+	// add the definitions to the global schema
+	AddDefinitions(sc.Definitions)
+	return nil
+}
+
+// parseSchemaFile parses the OpenAPI in the field of the file contents b.  It
+// returns nil if the field is empty.
+func parseSchemaFile(b []byte, field string) (*spec.Schema, error) {
 	// parse the yaml file (json is a subset of yaml, so will also parse)
 	y, err := yaml.Parse(string(b))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if field != "" {
@@ -86,14 +103,14 @@ func AddSchemaFromFileUsingField(path, field string) error {
 		m := y.Field(field)
 		if yaml.IsFieldEmpty(m) {
 			// doesn't contain openAPI definitions
-			return nil
+			return nil, nil
 		}
 		y = m.Value
 	}
 
 	oAPI, err := y.String()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// convert the yaml openAPI to a JSON string by unmarshalling it to an
@@ -101,19 +118,18 @@ func AddSchemaFromFileUsingField(path, field string) error {
 	var o interface{}
 	err = yaml.Unmarshal([]byte(oAPI), &o)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	j, err := json.Marshal(o)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// add the json schema to the global schema
-	_, err = AddSchema(j)
-	if err != nil {
-		return err
+	var sc spec.Schema
+	if err := sc.UnmarshalJSON(j); err != nil {
+		return nil, errors.Wrap(err)
 	}
-	return nil
+	return &sc, nil
 }
 
 // AddSchema parses s, and adds definitions from s to the global schema.
@@ -21,12 +21,15 @@ type Add struct {
 	// Optional.  If unspecified match all field values.
 	FieldValue string
 
-	// FieldName if set will add the OpenAPI reference to fields with this name or path
-	// FieldName may be the full name of the field, full path to the field, or the path suffix.
-	// e.g. all of the following would match spec.template.spec.containers.image --
-	// [image, containers.image, spec.containers.image, template.spec.containers.image,
-	//  spec.template.spec.containers.image]
+	// FieldName if set will add the OpenAPI reference to fields with this name or path.
+	// FieldName may be the name of the field -- e.g. image -- or the full path
+	// to the field from the root of the resource -- e.g.
+	// spec.template.spec.containers.image.
 	// Optional.  If unspecified match all field names.
+	//
+	// If FieldName is a path only the fields at exactly that path are matched,
+	// and FieldValue is ignored.  The path may have list element selectors --
+	// e.g. spec.template.spec.containers[name=nginx].image.
 	FieldName string
 
 	// Ref is the OpenAPI reference to set on the matching fields as a comment.
@@ -37,6 +40,61 @@ type Add struct {
 
 	// Type is the type of the setter value
 	Type string
+
+	// Fields are the paths to the fields which the reference was added to by
+	// calling Filter -- e.g. spec.replicas.
+	Fields []string
+
+	// Tags are the YAML tags of the scalar fields which the reference was
+	// added to by calling Filter -- e.g. !!int.
+	Tags []string
+
+	// Values are the values of the scalar fields which the reference was
+	// added to by calling Filter.
+	Values []string
+
+	// Kinds, if set, are the kinds of the resources whose fields may be added
+	// the reference -- e.g. Deployment.
+	Kinds []string
+
+	// APIVersion, if set, is the apiVersion of the resources whose fields may
+	// be added the reference -- e.g. apps/v1.
+	APIVersion string
+
+	// anchors are the anchored nodes which the reference was added to, which
+	// are visited again through their aliases.
+	anchors map[*yaml.Node]bool
+}
+
+// InferType returns the OpenAPI type of the values of scalar fields with the
+// YAML tags -- integer, number, boolean or string -- or "" if there are no
+// tags, or their values don't share a type.  Integers and floats are numbers.
+func InferType(tags []string) string {
+	var t string
+	for _, tag := range tags {
+		var tagType string
+		switch tag {
+		case yaml.IntTag:
+			tagType = "integer"
+		case "!!float":
+			tagType = "number"
+		case yaml.BoolTag:
+			tagType = "boolean"
+		case yaml.StringTag:
+			tagType = "string"
+		default:
+			return ""
+		}
+		switch {
+		case t == "" || t == tagType:
+			t = tagType
+		case t == "integer" && tagType == "number", t == "number" && tagType == "integer":
+			t = "number"
+		default:
+			return ""
+		}
+	}
+	return t
 }
 
 // Filter implements yaml.Filter
@@ -47,9 +105,106 @@ func (a *Add) Filter(object *yaml.RNode) (*yaml.RNode, error) {
 	if a.Ref == "" {
 		return nil, errors.Errorf("must specify ref")
 	}
+	if len(a.Kinds) > 0 || a.APIVersion != "" {
+		meta, err := object.GetMeta()
+		if err != nil {
+			return nil, err
+		}
+		if !matchesScope(a.Kinds, a.APIVersion, meta) {
+			return object, nil
+		}
+	}
+	if path := fieldPath(a.FieldName); path != nil {
+		return object, a.addRefAtPath(object, path)
+	}
 	return object, accept(a, object)
 }
 
+// fieldPath splits name into the elements of a path for yaml.Lookup if it
+// contains list element selectors -- e.g. splits containers[name=nginx].image
+// into [containers, [name=nginx], image].  Returns nil if name isn't such a
+// path.
+func fieldPath(name string) []string {
+	if !strings.Contains(name, "[") {
+		return nil
+	}
+	var path []string
+	var elem strings.Builder
+	inSelector := false
+	for _, c := range name {
+		switch {
+		case c == '[' && !inSelector:
+			if elem.Len() > 0 {
+				path = append(path, elem.String())
+				elem.Reset()
+			}
+			inSelector = true
+			elem.WriteRune(c)
+		case c == ']' && inSelector:
+			inSelector = false
+			elem.WriteRune(c)
+			path = append(path, elem.String())
+			elem.Reset()
+		case c == '.' && !inSelector:
+			if elem.Len() > 0 {
+				path = append(path, elem.String())
+				elem.Reset()
+			}
+		default:
+			elem.WriteRune(c)
+		}
+	}
+	if elem.Len() > 0 {
+		path = append(path, elem.String())
+	}
+	return path
+}
+
+// addRefAtPath adds the ref to the field at path, if object has it.
+// Scalar fields are referenced by their value, and array fields by their key.
+func (a *Add) addRefAtPath(object *yaml.RNode, path []string) error {
+	name := path[len(path)-1]
+	if yaml.IsListIndex(name) {
+		return errors.Errorf("field path %s must end with a field name", a.FieldName)
+	}
+	for _, p := range path {
+		if yaml.IsListIndex(p) {
+			if _, _, err := yaml.SplitIndexNameValue(p); err != nil {
+				return errors.WrapPrefixf(err, "invalid field path %s", a.FieldName)
+			}
+		}
+	}
+	parent, err := object.Pipe(yaml.Lookup(path[:len(path)-1]...))
+	if err != nil || parent == nil || parent.YNode().Kind != yaml.MappingNode {
+		return err
+	}
+	field := parent.Field(name)
+	if field == nil {
+		return nil
+	}
+	value := resolveAlias(field.Value)
+
+	if a.Type != "array" {
+		if value.YNode().Kind != yaml.ScalarNode {
+			return errors.Errorf("field %s isn't a scalar", a.FieldName)
+		}
+		return a.addRef(value, a.FieldName)
+	}
+	if value.YNode().Kind != yaml.SequenceNode {
+		return errors.Errorf("field %s isn't an array", a.FieldName)
+	}
+	values, err := listItemValues(value.YNode(), a.FieldName)
+	if err != nil {
+		return err
+	}
+	if len(a.ListValues) > 0 && !reflect.DeepEqual(values, a.ListValues) {
+		return errors.Errorf("setters can only be created for fields with same values, "+
+			"encountered different array values for specified field path: %s, %s", values, a.ListValues)
+	}
+	a.ListValues = values
+	return a.addRef(field.Key, a.FieldName)
+}
+
 func (a *Add) visitSequence(_ *yaml.RNode, _ string, _ *openapi.ResourceSchema) error {
 	// no-op
 	return nil
@@ -60,7 +215,8 @@ func (a *Add) visitSequence(_ *yaml.RNode, _ string, _ *openapi.ResourceSchema)
 // if the path path spec matches with input FiledName
 func (a *Add) visitMapping(object *yaml.RNode, p string, _ *openapi.ResourceSchema) error {
 	return object.VisitFields(func(node *yaml.MapNode) error {
-		if node.Value.YNode().Kind != yaml.SequenceNode {
+		value := resolveAlias(node.Value)
+		if value.YNode().Kind != yaml.SequenceNode {
 			return nil
 		}
 
@@ -69,28 +225,42 @@ func (a *Add) visitMapping(object *yaml.RNode, p string, _ *openapi.ResourceSche
 			return err
 		}
 
-		// derive the list values for the sequence node to write it to openAPI definitions
-		var values []string
-		for _, sc := range node.Value.Content() {
-			values = append(values, sc.Value)
-		}
-
 		// pathToKey refers to the path address of the key node ex: metadata.annotations
 		// p is the path till parent node, pathToKey is obtained by appending child key
 		pathToKey := p + "." + strings.Trim(key, "\n")
-		if a.FieldName != "" && strings.HasSuffix(pathToKey, a.FieldName) {
+		if a.FieldName != "" && a.matchesField(pathToKey) {
+			// derive the list values for the sequence node to write it to openAPI definitions
+			values, err := listItemValues(value.YNode(), pathToKey)
+			if err != nil {
+				return err
+			}
+
 			// check if there are different values for field path before adding ref to the field
 			if len(a.ListValues) > 0 && !reflect.DeepEqual(values, a.ListValues) {
 				return errors.Errorf("setters can only be created for fields with same values, "+
 					"encountered different array values for specified field path: %s, %s", values, a.ListValues)
 			}
 			a.ListValues = values
-			return a.addRef(node.Key)
+			return a.addRef(node.Key, pathToKey)
 		}
 		return nil
 	})
 }
 
+// listItemValues returns the list setter values of the items of the sequence
+// field at path p.
+func listItemValues(field *yaml.Node, p string) ([]string, error) {
+	var values []string
+	for _, item := range field.Content {
+		v, err := listItemValue(item)
+		if err != nil {
+			return nil, errors.WrapPrefixf(err, "field %s", strings.TrimPrefix(p, "."))
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
 // visitScalar implements visitor
 // visitScalar will set the field metadata on each scalar field whose name + value match
 func (a *Add) visitScalar(object *yaml.RNode, p string, _ *openapi.ResourceSchema) error {
@@ -98,17 +268,49 @@ func (a *Add) visitScalar(object *yaml.RNode, p string, _ *openapi.ResourceSchem
 	if a.Type == "array" {
 		return nil
 	}
-	if a.FieldName != "" && !strings.HasSuffix(p, a.FieldName) {
+	if !a.matchesField(p) {
 		return nil
 	}
-	if a.FieldValue != "" && a.FieldValue != object.YNode().Value {
+	// the fields at a path are matched regardless of their value
+	if a.FieldValue != "" && !IsFieldPath(a.FieldName) && a.FieldValue != object.YNode().Value {
 		return nil
 	}
-	return a.addRef(object)
+	return a.addRef(object, p)
 }
 
-// addRef adds the setter/subst ref to the object node as a line comment
-func (a *Add) addRef(object *yaml.RNode) error {
+// IsFieldPath returns true if name is the path to a field from the root of a
+// resource -- e.g. spec.replicas or spec.containers[name=nginx].image --
+// rather than the name of a field.
+func IsFieldPath(name string) bool {
+	return strings.ContainsAny(name, ".[")
+}
+
+// matchesField returns true if the field at path p matches FieldName -- its
+// path is FieldName if FieldName is a path, or else ends with it.
+func (a *Add) matchesField(p string) bool {
+	if a.FieldName == "" {
+		return true
+	}
+	if IsFieldPath(a.FieldName) {
+		return p == "."+strings.TrimPrefix(a.FieldName, ".")
+	}
+	return strings.HasSuffix(p, a.FieldName)
+}
+
+// addRef adds the setter/subst ref to the object node as a line comment,
+// and records the path p to it
+func (a *Add) addRef(object *yaml.RNode, p string) error {
+	if anchor := object.YNode(); anchor.Anchor != "" {
+		// the anchor is referenced once, rather than again for each alias
+		if a.anchors[anchor] {
+			return nil
+		}
+		if a.anchors == nil {
+			a.anchors = map[*yaml.Node]bool{}
+		}
+		a.anchors[anchor] = true
+	}
+
 	// read the field metadata
 	fm := fieldmeta.FieldMeta{}
 	if err := fm.Read(object); err != nil {
@@ -126,6 +328,11 @@ func (a *Add) addRef(object *yaml.RNode) error {
 	if err := fm.Write(object); err != nil {
 		return err
 	}
+	a.Fields = append(a.Fields, strings.TrimPrefix(p, "."))
+	if object.YNode().Kind == yaml.ScalarNode {
+		a.Tags = append(a.Tags, object.YNode().ShortTag())
+		a.Values = append(a.Values, object.YNode().Value)
+	}
 	return nil
 }
 
@@ -138,7 +345,7 @@ type SetterDefinition struct {
 	Value string `yaml:"value"`
 
 	// ListValues are the value of a list setter.
-	ListValues []string `yaml:"listValues,omitempty"`
+	ListValues ListValues `yaml:"listValues,omitempty"`
 
 	// SetBy is the person or role that last set the value.
 	SetBy string `yaml:"setBy,omitempty"`
@@ -149,12 +356,27 @@ type SetterDefinition struct {
 	// Count is the number of fields set by this setter.
 	Count int `yaml:"count,omitempty"`
 
-	// Type is the type of the setter value.
+	// Type is the type of the setter value.  Values of percentage setters are
+	// strings -- e.g. 25% -- of at most 100% unless Unbounded is set.
 	Type string `yaml:"type,omitempty"`
 
+	// Unbounded allows the values of a percentage setter to exceed 100%.
+	Unbounded bool `yaml:"-"`
+
 	// Schema is the openAPI schema for setter constraints.
 	Schema string `yaml:"schema,omitempty"`
 
+	// Pattern is a regular expression which the setter value must match.
+	Pattern string `yaml:"pattern,omitempty"`
+
+	// UniqueItems requires the values of a list setter to be unique.  It is
+	// written to the uniqueItems of the definition.
+	UniqueItems bool `yaml:"-"`
+
+	// Enum are the values which the setter may have -- e.g. dev, staging and
+	// prod.  They are written to the enum of the definition.
+	Enum []string `yaml:"-"`
+
 	// EnumValues is a map of possible setter values to actual field values.
 	// If EnumValues is specified, then the value set the by user 1) MUST
 	// be present in the enumValues map as a key, and 2) the map entry value
@@ -162,12 +384,61 @@ type SetterDefinition struct {
 	// Example -- may be used for t-shirt sizing values by allowing cpu to be
 	// set to small, medium or large, and then mapping these values to cpu values -- 0.5, 2, 8
 	EnumValues map[string]string `yaml:"enumValues,omitempty"`
+
+	// Required indicates the value must be provided by the user of the package,
+	// e.g. when prompted by set --interactive, even if it has a default value.
+	Required bool `yaml:"required,omitempty"`
+
+	// Default is the value the fields referencing the setter are reverted to
+	// when the setter is unset.
+	Default string `yaml:"default,omitempty"`
+
+	// Kinds, if set, are the kinds of the resources whose fields the setter
+	// may reference and set -- e.g. Deployment.
+	Kinds []string `yaml:"kinds,omitempty"`
+
+	// APIVersion, if set, is the apiVersion of the resources whose fields the
+	// setter may reference and set -- e.g. apps/v1.
+	APIVersion string `yaml:"apiVersion,omitempty"`
+
+	// Field, if set, is the name or path of the fields which CreateSetter adds
+	// a reference to the setter to -- see Add.FieldName.
+	Field string `yaml:"-"`
+
+	// Force makes CreateSetter update the definition of the setter if it
+	// already exists, rather than returning an error.
+	Force bool `yaml:"-"`
+
+	// Partial makes CreateSetter create a setter for only the part of the
+	// values of the Field fields which is Value, and a substitution of it
+	// into the rest of their values.
+	Partial bool `yaml:"-"`
 }
 
 func (sd SetterDefinition) AddToFile(path string) error {
 	return yaml.UpdateFile(sd, path)
 }
 
+// Validate returns an error if the value of the setter doesn't validate
+// against the definition which AddToFile would write -- e.g. if it exceeds the
+// maximum of its schema.  The items of list setters are validated against the
+// items schema, once their values are known.
+func (sd SetterDefinition) Validate() error {
+	if sd.Type == "array" && len(sd.ListValues) == 0 {
+		return nil
+	}
+	object, err := sd.Filter(yaml.NewRNode(&yaml.Node{Kind: yaml.MappingNode}))
+	if err != nil {
+		return err
+	}
+	def, err := object.Pipe(yaml.Lookup(openapi.SupplementaryOpenAPIFieldName,
+		"definitions", fieldmeta.SetterDefinitionPrefix+sd.Name))
+	if err != nil {
+		return err
+	}
+	return validateDefinition(def)
+}
+
 func (sd SetterDefinition) Filter(object *yaml.RNode) (*yaml.RNode, error) {
 	key := fieldmeta.SetterDefinitionPrefix + sd.Name
 
@@ -205,6 +476,38 @@ func (sd SetterDefinition) Filter(object *yaml.RNode) (*yaml.RNode, error) {
 		sd.Description = ""
 	}
 
+	if sd.Type == PercentageType {
+		err = setterDef.PipeE(yaml.FieldSetter{Name: "format", StringValue: PercentageType})
+		if err != nil {
+			return nil, err
+		}
+		if sd.Unbounded {
+			err = setterDef.PipeE(yaml.Clear("maximum"))
+		} else {
+			err = setterDef.PipeE(yaml.FieldSetter{Name: "maximum", StringValue: "100"})
+		}
+		if err != nil {
+			return nil, err
+		}
+		// percentages are strings with a percentage format
+		sd.Type = "string"
+	}
+
+	if len(sd.Enum) > 0 {
+		enum := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, v := range sd.Enum {
+			value := &yaml.Node{Kind: yaml.ScalarNode, Value: v}
+			if sd.Type == "string" {
+				// quote values which would otherwise be read as numbers or booleans
+				value.Tag = yaml.StringTag
+			}
+			enum.Content = append(enum.Content, value)
+		}
+		if err := setterDef.PipeE(yaml.SetField("enum", yaml.NewRNode(enum))); err != nil {
+			return nil, err
+		}
+	}
+
 	if sd.Type != "" {
 		err = setterDef.PipeE(yaml.FieldSetter{Name: "type", StringValue: sd.Type})
 		if err != nil {
@@ -214,6 +517,22 @@ func (sd SetterDefinition) Filter(object *yaml.RNode) (*yaml.RNode, error) {
 		sd.Type = ""
 	}
 
+	if sd.Pattern != "" {
+		err = setterDef.PipeE(yaml.FieldSetter{Name: "pattern", StringValue: sd.Pattern})
+		if err != nil {
+			return nil, err
+		}
+		// don't write the pattern to the extension
+		sd.Pattern = ""
+	}
+
+	if sd.UniqueItems {
+		err = setterDef.PipeE(yaml.SetField("uniqueItems", yaml.NewScalarRNode("true")))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	ext, err := setterDef.Pipe(yaml.LookupCreate(yaml.MappingNode, K8sCliExtensionKey))
 	if err != nil {
 		return nil, err
@@ -20,6 +20,7 @@ func TestAdd_Filter(t *testing.T) {
 		add      Add
 		input    string
 		expected string
+		fields   []string
 		err      string
 	}{
 		{
@@ -154,6 +155,192 @@ metadata:
     replicas: 3
 spec:
   replicas: 3 # {"$openapi":"replicas"}
+ `,
+		},
+		{
+			name: "add-image-field-path",
+			add: Add{
+				FieldName:  "spec.template.spec.containers[name=nginx].image",
+				FieldValue: "nginx:1.8",
+				Ref:        "#/definitions/io.k8s.cli.setters.image",
+			},
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.7
+      - name: sidecar
+        image: nginx:1.7
+ `,
+			expected: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.7 # {"$openapi":"image"}
+      - name: sidecar
+        image: nginx:1.7
+ `,
+		},
+		{
+			name: "add-alias",
+			add: Add{
+				FieldName: "spec.sidecar.image",
+				Ref:       "#/definitions/io.k8s.cli.setters.image",
+			},
+			input: `
+apiVersion: example.com/v1
+kind: Example
+spec:
+  main:
+    image: &image nginx:1.7
+  sidecar:
+    image: *image
+ `,
+			expected: `
+apiVersion: example.com/v1
+kind: Example
+spec:
+  main:
+    image: &image nginx:1.7 # {"$openapi":"image"}
+  sidecar:
+    image: *image
+ `,
+			fields: []string{"spec.sidecar.image"},
+		},
+		{
+			name: "add-alias-value",
+			add: Add{
+				FieldValue: "nginx:1.7",
+				Ref:        "#/definitions/io.k8s.cli.setters.image",
+			},
+			input: `
+apiVersion: example.com/v1
+kind: Example
+spec:
+  main:
+    image: &image nginx:1.7
+  sidecar:
+    image: *image
+ `,
+			expected: `
+apiVersion: example.com/v1
+kind: Example
+spec:
+  main:
+    image: &image nginx:1.7 # {"$openapi":"image"}
+  sidecar:
+    image: *image
+ `,
+			fields: []string{"spec.main.image"},
+		},
+		{
+			name: "add-merge-key",
+			add: Add{
+				FieldValue: "3",
+				Ref:        "#/definitions/io.k8s.cli.setters.replicas",
+			},
+			input: `
+apiVersion: example.com/v1
+kind: Example
+spec:
+  defaults: &defaults
+    replicas: 3
+  web:
+    <<: *defaults
+    name: web
+ `,
+			// the merge key is tagged when it is written
+			expected: `
+apiVersion: example.com/v1
+kind: Example
+spec:
+  defaults: &defaults
+    replicas: 3 # {"$openapi":"replicas"}
+  web:
+    !!merge <<: *defaults
+    name: web
+ `,
+			fields: []string{"spec.defaults.replicas"},
+		},
+		{
+			name: "add-field-path-value",
+			add: Add{
+				FieldValue: "5",
+				FieldName:  "spec.replicas",
+				Ref:        "#/definitions/io.k8s.cli.setters.replicas",
+			},
+			input: `
+apiVersion: example.com/v1
+kind: Example
+spec:
+  replicas: 3
+  worker:
+    replicas: 3
+ `,
+			expected: `
+apiVersion: example.com/v1
+kind: Example
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+  worker:
+    replicas: 3
+ `,
+			fields: []string{"spec.replicas"},
+		},
+		{
+			name: "add-field-path-alias",
+			add: Add{
+				FieldName: "spec.containers[name=sidecar].image",
+				Ref:       "#/definitions/io.k8s.cli.setters.image",
+			},
+			input: `
+apiVersion: example.com/v1
+kind: Example
+spec:
+  containers:
+  - name: nginx
+    image: &image nginx:1.7
+  - name: sidecar
+    image: *image
+ `,
+			expected: `
+apiVersion: example.com/v1
+kind: Example
+spec:
+  containers:
+  - name: nginx
+    image: &image nginx:1.7 # {"$openapi":"image"}
+  - name: sidecar
+    image: *image
+ `,
+			fields: []string{"spec.containers[name=sidecar].image"},
+		},
+		{
+			name: "add-field-path-error",
+			add: Add{
+				FieldName: "spec.template.spec.containers[name=nginx]",
+				Ref:       "#/definitions/io.k8s.cli.setters.image",
+			},
+			err: "field path spec.template.spec.containers[name=nginx] must end with a field name",
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 3
  `,
 		},
 		{
@@ -204,6 +391,9 @@ spec:
 			if !assert.Equal(t, expected, actual) {
 				t.FailNow()
 			}
+			if test.fields != nil {
+				assert.Equal(t, test.fields, test.add.Fields)
+			}
 		})
 	}
 }
@@ -356,3 +546,42 @@ openAPI:
 `
 	assert.Equal(t, expected, string(b))
 }
+
+func TestSetterDefinition_Validate(t *testing.T) {
+	sd := SetterDefinition{
+		Name:   "replicas",
+		Value:  "20",
+		Type:   "integer",
+		Schema: `{"maximum": 10}`,
+	}
+	err := sd.Validate()
+	if assert.Error(t, err) {
+		assert.Equal(t, "value 20 exceeds maximum 10 for setter replicas", err.Error())
+	}
+
+	sd.Value = "5"
+	assert.NoError(t, sd.Validate())
+
+	// the value must match the type
+	sd.Value = "five"
+	assert.Error(t, sd.Validate())
+}
+
+func TestInferType(t *testing.T) {
+	var tests = []struct {
+		tags     []string
+		expected string
+	}{
+		{tags: nil, expected: ""},
+		{tags: []string{"!!int", "!!int"}, expected: "integer"},
+		{tags: []string{"!!int", "!!float"}, expected: "number"},
+		{tags: []string{"!!float", "!!int"}, expected: "number"},
+		{tags: []string{"!!bool"}, expected: "boolean"},
+		{tags: []string{"!!str"}, expected: "string"},
+		{tags: []string{"!!int", "!!str"}, expected: ""},
+		{tags: []string{"!!null"}, expected: ""},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, InferType(test.tags), "%v", test.tags)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package setters2
+
+import (
+	"encoding/json"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/fieldmeta"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// MoveBlockScalarRefs moves the setter references of the block scalars in
+// node from their line comments to head comments -- those of their keys, or
+// their own for list elements.  The line comment of a block scalar is written
+// after its value, and isn't read back, whereas the head comments are kept
+// and read as the reference of the field.  Block scalars which already have
+// another head comment are double quoted instead.
+func MoveBlockScalarRefs(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for i := range node.Content {
+			MoveBlockScalarRefs(node.Content[i])
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if IsBlockScalar(value) {
+				moveRef(key, key, value)
+			}
+			MoveBlockScalarRefs(value)
+		}
+	case yaml.SequenceNode:
+		for i := range node.Content {
+			if IsBlockScalar(node.Content[i]) {
+				moveRef(node.Content[i], node.Content[i], node.Content[i])
+			}
+			MoveBlockScalarRefs(node.Content[i])
+		}
+	}
+}
+
+// IsBlockScalar returns true if node is a literal or folded scalar, whose
+// setter reference is the head comment of its field -- see
+// MoveBlockScalarRefs.
+func IsBlockScalar(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode &&
+		node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0
+}
+
+// moveRef moves the setter reference in the line comment of key or value to
+// the head comment of head.
+func moveRef(head, key, value *yaml.Node) {
+	comment := &value.LineComment
+	if !isRef(*comment) {
+		comment = &key.LineComment
+	}
+	if !isRef(*comment) {
+		return
+	}
+	if head.HeadComment != "" && !isRef(head.HeadComment) {
+		value.Style = yaml.DoubleQuotedStyle
+		return
+	}
+	head.HeadComment = *comment
+	*comment = ""
+}
+
+// isRef returns true if the comment is a setter or substitution reference,
+// in either the short hand or the $ref format.
+func isRef(comment string) bool {
+	comment = strings.TrimSpace(strings.TrimLeft(comment, "#"))
+	if !strings.HasPrefix(comment, "{") {
+		return false
+	}
+	input := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(comment), &input); err != nil {
+		return false
+	}
+	return input[fieldmeta.ShortHandRef()] != nil || input["$ref"] != nil
+}
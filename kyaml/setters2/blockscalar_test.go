@@ -0,0 +1,75 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package setters2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+func TestMoveBlockScalarRefs(t *testing.T) {
+	object, err := yaml.Parse(`data:
+  script: a # {"$openapi":"script"}
+  other: b
+  key: c # {"$openapi":"key"}
+noted:
+  # a note
+  key: d # {"$openapi":"noted"}
+list:
+- e # {"$openapi":"list"}
+`)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	for _, path := range [][]string{{"data", "script"}, {"data", "other"}, {"noted", "key"}} {
+		field, err := object.Pipe(yaml.Lookup(path...))
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		field.YNode().Style = yaml.LiteralStyle
+	}
+	// the reference of list fields is on their key
+	key := object.Field("data").Key
+	key.YNode().LineComment = `# {"$openapi":"data"}`
+	object.Field("data").Value.Field("key").Value.YNode().Style = yaml.FoldedStyle
+	object.Field("list").Value.YNode().Content[0].Style = yaml.LiteralStyle
+
+	MoveBlockScalarRefs(object.YNode())
+	actual, err := object.String()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	expected := `data: # {"$openapi":"data"}
+  # {"$openapi":"script"}
+  script: |-
+    a
+  other: |-
+    b
+  # {"$openapi":"key"}
+  key: >-
+    c
+noted:
+  # a note
+  key: "d" # {"$openapi":"noted"}
+list:
+# {"$openapi":"list"}
+- |-
+  e
+`
+	if !assert.Equal(t, expected, actual) {
+		t.FailNow()
+	}
+
+	// the references are read back from the head comments
+	object, err = yaml.Parse(actual)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `# {"$openapi":"script"}`,
+		object.Field("data").Value.Field("script").Key.YNode().HeadComment)
+	assert.Equal(t, `# {"$openapi":"list"}`,
+		object.Field("list").Value.YNode().Content[0].HeadComment)
+}
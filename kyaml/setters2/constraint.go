@@ -0,0 +1,207 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package setters2
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/fieldmeta"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// ConstraintDefinition may be used to update a files OpenAPI definitions with a
+// constraint relating the values of setters -- e.g. min-replicas <= max-replicas.
+type ConstraintDefinition struct {
+	// Name is the name of the constraint to create or update
+	Name string `yaml:"name"`
+
+	// Expression is the relation which must hold between the setter values
+	Expression string `yaml:"expression"`
+}
+
+func (cd ConstraintDefinition) AddToFile(path string) error {
+	return yaml.UpdateFile(cd, path)
+}
+
+func (cd ConstraintDefinition) Filter(object *yaml.RNode) (*yaml.RNode, error) {
+	if _, err := ParseConstraint(cd.Expression); err != nil {
+		return nil, err
+	}
+	key := fieldmeta.ConstraintDefinitionPrefix + cd.Name
+
+	definitions, err := object.Pipe(yaml.LookupCreate(
+		yaml.MappingNode, openapi.SupplementaryOpenAPIFieldName, "definitions"))
+	if err != nil {
+		return nil, err
+	}
+	ext, err := definitions.Pipe(yaml.LookupCreate(yaml.MappingNode, key, K8sCliExtensionKey))
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := yaml.Marshal(cd)
+	if err != nil {
+		return nil, err
+	}
+	y, err := yaml.Parse(string(b))
+	if err != nil {
+		return nil, err
+	}
+	if err := ext.PipeE(yaml.SetField("constraint", y)); err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// Constraint is a parsed constraint expression of the form
+// 'LEFT OPERATOR RIGHT'.  The operands are setter names or numbers.
+type Constraint struct {
+	Left     string
+	Operator string
+	Right    string
+}
+
+// constraintPattern matches constraint expressions.  The longer operators
+// are listed first so that e.g. '<=' isn't matched as '<'.
+var constraintPattern = regexp.MustCompile(`^\s*(\S+?)\s*(<=|>=|==|!=|<|>)\s*(\S+)\s*$`)
+
+// ParseConstraint parses a constraint expression -- e.g. 'min-replicas <= max-replicas'.
+func ParseConstraint(expression string) (Constraint, error) {
+	m := constraintPattern.FindStringSubmatch(expression)
+	if m == nil {
+		return Constraint{}, errors.Errorf(
+			"invalid constraint %q: must be of the form 'SETTER OPERATOR SETTER|NUMBER' "+
+				"where OPERATOR is one of <, <=, >, >=, ==, !=", expression)
+	}
+	return Constraint{Left: m[1], Operator: m[2], Right: m[3]}, nil
+}
+
+func (c Constraint) String() string {
+	return c.Left + " " + c.Operator + " " + c.Right
+}
+
+// Holds returns true if the constraint holds for the setter values.
+func (c Constraint) Holds(values map[string]string) (bool, error) {
+	left, err := operand(c.Left, values)
+	if err != nil {
+		return false, err
+	}
+	right, err := operand(c.Right, values)
+	if err != nil {
+		return false, err
+	}
+
+	// compare numerically if possible, otherwise only equality may be checked
+	l, errL := strconv.ParseFloat(left, 64)
+	r, errR := strconv.ParseFloat(right, 64)
+	if errL != nil || errR != nil {
+		switch c.Operator {
+		case "==":
+			return left == right, nil
+		case "!=":
+			return left != right, nil
+		}
+		return false, errors.Errorf(
+			"constraint %s compares non-numeric values %q and %q", c, left, right)
+	}
+	switch c.Operator {
+	case "<":
+		return l < r, nil
+	case "<=":
+		return l <= r, nil
+	case ">":
+		return l > r, nil
+	case ">=":
+		return l >= r, nil
+	case "==":
+		return l == r, nil
+	default:
+		return l != r, nil
+	}
+}
+
+// operand returns the value of a constraint operand, which is either the name
+// of a setter or a number.
+func operand(s string, values map[string]string) (string, error) {
+	if v, found := values[s]; found {
+		return v, nil
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return s, nil
+	}
+	return "", errors.Errorf("constraint refers to unknown setter %s", s)
+}
+
+// CheckConstraints checks the constraints defined in the OpenAPI file against
+// the setter values, with the values in overrides taking precedence over the
+// values in the file -- e.g. to check a value before it is set.
+// Returns the number of constraints checked, and an error listing each
+// constraint which doesn't hold.
+func CheckConstraints(openAPIPath string, overrides map[string]string) (int, error) {
+	object, err := yaml.ReadFile(openAPIPath)
+	if err != nil {
+		return 0, err
+	}
+	definitions, err := object.Pipe(yaml.Lookup(openapi.SupplementaryOpenAPIFieldName, "definitions"))
+	if err != nil || definitions == nil {
+		return 0, err
+	}
+
+	values := map[string]string{}
+	var constraints []string
+	err = definitions.VisitFields(func(node *yaml.MapNode) error {
+		key := node.Key.YNode().Value
+		switch {
+		case strings.HasPrefix(key, fieldmeta.SetterDefinitionPrefix):
+			value, err := node.Value.Pipe(yaml.Lookup(K8sCliExtensionKey, "setter", "value"))
+			if err != nil {
+				return err
+			}
+			if value != nil {
+				values[strings.TrimPrefix(key, fieldmeta.SetterDefinitionPrefix)] = value.YNode().Value
+			}
+		case strings.HasPrefix(key, fieldmeta.ConstraintDefinitionPrefix):
+			expr, err := node.Value.Pipe(yaml.Lookup(K8sCliExtensionKey, "constraint", "expression"))
+			if err != nil {
+				return err
+			}
+			if expr != nil {
+				constraints = append(constraints, expr.YNode().Value)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	for k, v := range overrides {
+		values[k] = v
+	}
+
+	var violated []string
+	for _, expr := range constraints {
+		c, err := ParseConstraint(expr)
+		if err != nil {
+			return 0, err
+		}
+		ok, err := c.Holds(values)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			violated = append(violated, c.String())
+		}
+	}
+	if len(violated) > 0 {
+		sort.Strings(violated)
+		return len(constraints), errors.Errorf(
+			"setter values violate constraints: %s", strings.Join(violated, ", "))
+	}
+	return len(constraints), nil
+}
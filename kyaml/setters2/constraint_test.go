@@ -0,0 +1,62 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package setters2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstraintHolds(t *testing.T) {
+	values := map[string]string{
+		"min-replicas": "2",
+		"max-replicas": "10",
+		"env":          "prod",
+	}
+	var tests = []struct {
+		name        string
+		expression  string
+		expected    bool
+		expectedErr string
+	}{
+		{name: "satisfied", expression: "min-replicas <= max-replicas", expected: true},
+		{name: "violated", expression: "min-replicas > max-replicas", expected: false},
+		{name: "numeric not lexical", expression: "max-replicas > min-replicas", expected: true},
+		{name: "number literal", expression: "min-replicas>=3", expected: false},
+		{name: "string equality", expression: "env == prod", expectedErr: "unknown setter prod"},
+		{name: "string setters", expression: "env != min-replicas", expected: true},
+		{name: "string ordering", expression: "env < max-replicas",
+			expectedErr: `constraint env < max-replicas compares non-numeric values "prod" and "10"`},
+		{name: "unknown setter", expression: "replicas < max-replicas",
+			expectedErr: "constraint refers to unknown setter replicas"},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			c, err := ParseConstraint(test.expression)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			holds, err := c.Holds(values)
+			if test.expectedErr != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), test.expectedErr)
+				}
+				return
+			}
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, test.expected, holds)
+		})
+	}
+}
+
+func TestParseConstraint_invalid(t *testing.T) {
+	_, err := ParseConstraint("min-replicas max-replicas")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `invalid constraint "min-replicas max-replicas"`)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package setters2
+
+import (
+	"github.com/go-openapi/spec"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/fieldmeta"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// CopyDefinition may be used to copy a setter in a files OpenAPI definitions
+// to a new name -- with its schema, description and value.  The fields
+// referencing the setter are unchanged.  It is an error if the setter isn't
+// defined, or if a setter or substitution with the new name is already defined.
+type CopyDefinition struct {
+	// Name is the name of the setter to copy.
+	Name string
+
+	// NewName is the name of the copy.
+	NewName string
+
+	// Value, if set, is the value of the copy rather than that of the setter.
+	// It is validated against the schema of the copy.
+	Value string
+}
+
+func (cd CopyDefinition) CopyInFile(path string) error {
+	return yaml.UpdateFile(cd, path)
+}
+
+func (cd CopyDefinition) Filter(object *yaml.RNode) (*yaml.RNode, error) {
+	if err := ValidateName(cd.NewName); err != nil {
+		return nil, err
+	}
+	key := fieldmeta.SetterDefinitionPrefix + cd.Name
+	newKey := fieldmeta.SetterDefinitionPrefix + cd.NewName
+	definitions, err := object.Pipe(yaml.Lookup(openapi.SupplementaryOpenAPIFieldName, "definitions"))
+	if err != nil {
+		return nil, err
+	}
+	if definitions == nil || definitions.Field(key) == nil {
+		return nil, errors.Errorf("setter %s is not defined", cd.Name)
+	}
+	if definitions.Field(newKey) != nil {
+		return nil, errors.Errorf("setter %s already exists", cd.NewName)
+	}
+	if definitions.Field(fieldmeta.SubstitutionDefinitionPrefix+cd.NewName) != nil {
+		return nil, errors.Errorf("substitution %s already exists", cd.NewName)
+	}
+
+	def := copyResource(definitions.Field(key).Value)
+	setter, err := def.Pipe(yaml.Lookup(K8sCliExtensionKey, "setter"))
+	if err != nil {
+		return nil, err
+	}
+	if setter == nil {
+		return nil, errors.Errorf("setter %s has no %s extension", cd.Name, K8sCliExtensionKey)
+	}
+	if err := setter.PipeE(yaml.FieldSetter{Name: "name", StringValue: cd.NewName}); err != nil {
+		return nil, err
+	}
+
+	if cd.Value != "" {
+		if setter.Field("listValues") != nil {
+			return nil, errors.Errorf("setter %s is a list setter, its value may not be set", cd.Name)
+		}
+		value := yaml.NewScalarRNode(cd.Value)
+		// the value of a setter is always a string
+		value.YNode().Tag = yaml.StringTag
+		if err := setter.PipeE(yaml.SetField("value", value)); err != nil {
+			return nil, err
+		}
+		if err := validateDefinition(def); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := definitions.PipeE(yaml.SetField(newKey, def)); err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// validateDefinition returns an error if the value of the setter with the
+// definition def doesn't validate against its schema.
+func validateDefinition(def *yaml.RNode) error {
+	b, err := def.MarshalJSON()
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	sch := &spec.Schema{}
+	if err := sch.UnmarshalJSON(b); err != nil {
+		return errors.Wrap(err)
+	}
+	ext, err := GetExtFromSchema(sch)
+	if err != nil {
+		return err
+	}
+	return validateAgainstSchema(ext, sch)
+}
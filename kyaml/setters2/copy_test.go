@@ -0,0 +1,142 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package setters2
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+func TestCopyDefinition_Filter(t *testing.T) {
+	replicas := `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      description: number of replicas
+      maximum: 10
+      type: integer
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+          setBy: me
+`
+	var tests = []struct {
+		name     string
+		setter   string
+		newName  string
+		value    string
+		input    string
+		expected string
+		err      string
+	}{
+		{
+			name:    "copy",
+			setter:  "replicas",
+			newName: "us-east-replicas",
+			input:   replicas,
+			expected: replicas + `    io.k8s.cli.setters.us-east-replicas:
+      description: number of replicas
+      maximum: 10
+      type: integer
+      x-k8s-cli:
+        setter:
+          name: us-east-replicas
+          value: "3"
+          setBy: me
+`,
+		},
+		{
+			name:    "copy with value",
+			setter:  "replicas",
+			newName: "us-east-replicas",
+			value:   "5",
+			input:   replicas,
+			expected: replicas + `    io.k8s.cli.setters.us-east-replicas:
+      description: number of replicas
+      maximum: 10
+      type: integer
+      x-k8s-cli:
+        setter:
+          name: us-east-replicas
+          value: "5"
+          setBy: me
+`,
+		},
+		{
+			name:    "value exceeding the schema",
+			setter:  "replicas",
+			newName: "us-east-replicas",
+			value:   "11",
+			input:   replicas,
+			err:     "value 11 exceeds maximum 10 for setter us-east-replicas",
+		},
+		{
+			name:    "list setter value",
+			setter:  "args",
+			newName: "worker-args",
+			value:   "a",
+			input: `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.args:
+      type: array
+      x-k8s-cli:
+        setter:
+          name: args
+          listValues:
+          - a
+`,
+			err: "setter args is a list setter, its value may not be set",
+		},
+		{
+			name:    "not defined",
+			setter:  "image",
+			newName: "sidecar-image",
+			input:   replicas,
+			err:     "setter image is not defined",
+		},
+		{
+			name:    "setter exists",
+			setter:  "replicas",
+			newName: "replicas",
+			input:   replicas,
+			err:     "setter replicas already exists",
+		},
+		{
+			name:    "invalid name",
+			setter:  "replicas",
+			newName: "1replicas",
+			input:   replicas,
+			err:     `invalid setter name "1replicas": must start with a letter and contain only letters, digits, '-' and '_'`,
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			r, err := yaml.Parse(test.input)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			result, err := CopyDefinition{Name: test.setter, NewName: test.newName, Value: test.value}.Filter(r)
+			if test.err != "" {
+				if assert.Error(t, err) {
+					assert.Equal(t, test.err, err.Error())
+				}
+				return
+			}
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			actual, err := result.String()
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, strings.TrimSpace(test.expected), strings.TrimSpace(actual))
+		})
+	}
+}
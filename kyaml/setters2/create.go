@@ -0,0 +1,315 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package setters2
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/fieldmeta"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// ExistsError is returned by CreateSetter if the name of the setter is
+// already defined -- by a setter, unless it is forced, or by a substitution.
+type ExistsError struct {
+	// Name is the name of the setter.
+	Name string
+
+	// Substitution is true if the name is defined by a substitution.
+	Substitution bool
+}
+
+func (e *ExistsError) Error() string {
+	if e.Substitution {
+		return fmt.Sprintf("substitution with name %s already exists, "+
+			"substitution and setter can't have same name", e.Name)
+	}
+	return fmt.Sprintf("setter with name %s already exists, use --force to update it", e.Name)
+}
+
+// ValidationError is returned by CreateSetter if the value of the setter
+// doesn't validate against its definition.
+type ValidationError struct {
+	// Name is the name of the setter.
+	Name string
+
+	// Err is the reason the value isn't valid.
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// FieldRef is a field which CreateSetter added a reference to the setter to.
+type FieldRef struct {
+	// Resource is the resource containing the field.
+	Resource *yaml.RNode
+
+	// Field is the path to the field -- e.g. spec.replicas.
+	Field string
+}
+
+// CreateSetter adds the setter def to the OpenAPI definitions in the file at
+// openAPIPath, and a reference to it to the matching fields of resources --
+// those with the value of the setter, and the name or path def.Field if set.
+// It is the work of the create-setter command, for callers which read and
+// write the resources themselves.
+//
+// Unless def.Type or def.Schema are set, the type of the setter is inferred
+// from the values of the fields.  The values of list setters are those of the
+// matching field.  Returns an *ExistsError if the name is already defined,
+// or a *ValidationError if the value of the setter isn't valid, in which case
+// the resources are unchanged.
+func CreateSetter(openAPIPath string, resources []*yaml.RNode, def SetterDefinition) ([]FieldRef, error) {
+	if err := existingSetter(&def, openAPIPath); err != nil {
+		return nil, err
+	}
+	if def.Partial {
+		return createPartialSetter(openAPIPath, resources, def)
+	}
+	ref := fieldmeta.DefinitionsPrefix + fieldmeta.SetterDefinitionPrefix + def.Name
+
+	// match the fields of copies of the resources first, to infer the type of
+	// the setter and validate its value before the resources are changed
+	match := &Add{FieldName: def.Field, FieldValue: def.Value, Ref: ref, Type: def.Type,
+		Kinds: def.Kinds, APIVersion: def.APIVersion}
+	for i := range resources {
+		if _, err := match.Filter(copyResource(resources[i])); err != nil {
+			return nil, errors.Wrap(err)
+		}
+	}
+	if def.Type == "" && def.Schema == "" && !def.Required && len(match.ListValues) == 0 {
+		// the placeholder values of required setters may not have their type
+		def.Type = InferType(match.Tags)
+	}
+	if def.Required {
+		// the value is a placeholder for the users of the package to replace
+		def.Default = def.Value
+	} else {
+		if err := def.Validate(); err != nil {
+			return nil, &ValidationError{Name: def.Name, Err: err}
+		}
+		if len(match.ListValues) > 0 {
+			list := def
+			list.ListValues, list.Value = match.ListValues, ""
+			if err := list.Validate(); err != nil {
+				return nil, &ValidationError{Name: def.Name, Err: err}
+			}
+		}
+	}
+
+	if err := def.AddToFile(openAPIPath); err != nil {
+		return nil, err
+	}
+	// Load the updated definitions
+	if err := openapi.AddSchemaFromFile(openAPIPath); err != nil {
+		return nil, err
+	}
+
+	a := &Add{FieldName: def.Field, FieldValue: def.Value, Ref: ref, Type: def.Type,
+		Kinds: def.Kinds, APIVersion: def.APIVersion}
+	var refs []FieldRef
+	for i := range resources {
+		n := len(a.Fields)
+		if _, err := a.Filter(resources[i]); err != nil {
+			return nil, errors.Wrap(err)
+		}
+		for _, f := range a.Fields[n:] {
+			refs = append(refs, FieldRef{Resource: resources[i], Field: f})
+		}
+	}
+
+	// write the list values derived from the fields back to the definition
+	if len(a.ListValues) > 0 {
+		def.ListValues = a.ListValues
+		def.Value = ""
+		if err := def.AddToFile(openAPIPath); err != nil {
+			return nil, err
+		}
+	}
+	return refs, nil
+}
+
+// createPartialSetter creates the setter def for part of the values of the
+// def.Field fields, and a substitution of it into the rest of their values,
+// which is referenced by the fields -- e.g. a setter tag with value 1.7.9, and
+// a substitution image-tag with pattern nginx:${tag} referenced by the image
+// fields with value nginx:1.7.9.
+func createPartialSetter(openAPIPath string, resources []*yaml.RNode, def SetterDefinition) ([]FieldRef, error) {
+	if def.Field == "" || def.Value == "" {
+		return nil, errors.Errorf("partial setter %s requires a field and a value", def.Name)
+	}
+	name := PartialSubstitutionName(def.Name, def.Field)
+	definitions, err := openAPIDefinitions(openAPIPath)
+	if err != nil {
+		return nil, err
+	}
+	if definitions != nil && !def.Force &&
+		definitions.Field(fieldmeta.SubstitutionDefinitionPrefix+name) != nil {
+		return nil, errors.Errorf(
+			"substitution with name %s already exists, use --force to update it", name)
+	}
+	ref := fieldmeta.DefinitionsPrefix + fieldmeta.SubstitutionDefinitionPrefix + name
+
+	// the fields must all have the same value, so that the pattern of the
+	// substitution -- the value around that of the setter -- is the same
+	match := &Add{FieldName: def.Field, Ref: ref, Kinds: def.Kinds, APIVersion: def.APIVersion}
+	for i := range resources {
+		if _, err := match.Filter(copyResource(resources[i])); err != nil {
+			return nil, errors.Wrap(err)
+		}
+	}
+	var value string
+	for _, v := range match.Values {
+		if !strings.Contains(v, def.Value) {
+			if IsFieldPath(def.Field) {
+				// the fields at a path are all referenced, whatever their value
+				return nil, errors.Errorf("field %s has value %s, which doesn't contain %s",
+					def.Field, v, def.Value)
+			}
+			continue
+		}
+		if value != "" && v != value {
+			return nil, errors.Errorf("partial setters can only be created for fields with the same value, "+
+				"encountered different values of fields %s containing %s: %s, %s", def.Field, def.Value, value, v)
+		}
+		value = v
+	}
+	if value == "" {
+		return nil, errors.Errorf("no fields %s contain value %s", def.Field, def.Value)
+	}
+
+	if def.Required {
+		def.Default = def.Value
+	} else if err := def.Validate(); err != nil {
+		return nil, &ValidationError{Name: def.Name, Err: err}
+	}
+	marker := "${" + def.Name + "}"
+	subst := SubstitutionDefinition{
+		Name:    name,
+		Pattern: strings.ReplaceAll(value, def.Value, marker),
+		Values: []Value{{
+			Marker: marker,
+			Ref:    fieldmeta.DefinitionsPrefix + fieldmeta.SetterDefinitionPrefix + def.Name,
+		}},
+	}
+	if err := def.AddToFile(openAPIPath); err != nil {
+		return nil, err
+	}
+	if err := subst.AddToFile(openAPIPath); err != nil {
+		return nil, err
+	}
+	// Load the updated definitions
+	if err := openapi.AddSchemaFromFile(openAPIPath); err != nil {
+		return nil, err
+	}
+
+	a := &Add{FieldName: def.Field, FieldValue: value, Ref: ref,
+		Kinds: def.Kinds, APIVersion: def.APIVersion}
+	var refs []FieldRef
+	for i := range resources {
+		n := len(a.Fields)
+		if _, err := a.Filter(resources[i]); err != nil {
+			return nil, errors.Wrap(err)
+		}
+		for _, f := range a.Fields[n:] {
+			refs = append(refs, FieldRef{Resource: resources[i], Field: f})
+		}
+	}
+	return refs, nil
+}
+
+// PartialSubstitutionName returns the name of the substitution created for
+// the partial setter name of the fields field -- the name of the fields and
+// of the setter, e.g. image-tag for setter tag of field
+// spec.containers[name=nginx].image.
+func PartialSubstitutionName(name, field string) string {
+	if path := fieldPath(field); path != nil {
+		field = path[len(path)-1]
+	}
+	field = field[strings.LastIndex(field, ".")+1:]
+	return field + "-" + name
+}
+
+// openAPIDefinitions returns the OpenAPI definitions of the file at
+// openAPIPath, or nil if it has none.
+func openAPIDefinitions(openAPIPath string) (*yaml.RNode, error) {
+	object, err := yaml.ReadFile(openAPIPath)
+	if err != nil {
+		return nil, err
+	}
+	return object.Pipe(yaml.Lookup(openapi.SupplementaryOpenAPIFieldName, "definitions"))
+}
+
+// existingSetter returns an error if the name of the setter def is already
+// defined in the OpenAPI file, unless by a setter and def.Force is set, in
+// which case the description, type and setBy of the existing setter are
+// copied to def if not set.
+func existingSetter(def *SetterDefinition, openAPIPath string) error {
+	definitions, err := openAPIDefinitions(openAPIPath)
+	if err != nil || definitions == nil {
+		return err
+	}
+	if definitions.Field(fieldmeta.SubstitutionDefinitionPrefix+def.Name) != nil {
+		return &ExistsError{Name: def.Name, Substitution: true}
+	}
+	existing := definitions.Field(fieldmeta.SetterDefinitionPrefix + def.Name)
+	if existing == nil {
+		return nil
+	}
+	if !def.Force {
+		return &ExistsError{Name: def.Name}
+	}
+
+	if def.Description == "" {
+		if description := existing.Value.Field("description"); description != nil {
+			def.Description = description.Value.YNode().Value
+		}
+	}
+	if def.Type == "" {
+		// keep the type rather than inferring it again
+		if t := existing.Value.Field("type"); t != nil {
+			def.Type = t.Value.YNode().Value
+		}
+	}
+	if def.SetBy == "" {
+		setBy, err := existing.Value.Pipe(yaml.Lookup(K8sCliExtensionKey, "setter", "setBy"))
+		if err != nil {
+			return err
+		}
+		if setBy != nil {
+			def.SetBy = setBy.YNode().Value
+		}
+	}
+	return nil
+}
+
+// copyResource returns a copy of the resource, whose fields may be changed
+// without changing those of the resource.
+func copyResource(r *yaml.RNode) *yaml.RNode {
+	return yaml.NewRNode(copyNode(r.YNode(), map[*yaml.Node]*yaml.Node{}))
+}
+
+// copyNode copies n and its content.  Aliases are pointed to the copies of
+// their anchors, which precede them and are recorded in copies.
+func copyNode(n *yaml.Node, copies map[*yaml.Node]*yaml.Node) *yaml.Node {
+	c := *n
+	copies[n] = &c
+	c.Content = nil
+	for i := range n.Content {
+		c.Content = append(c.Content, copyNode(n.Content[i], copies))
+	}
+	if a, found := copies[n.Alias]; found {
+		c.Alias = a
+	}
+	return &c
+}
@@ -0,0 +1,201 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package setters2
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const createResource = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx
+`
+
+func TestCreateSetter(t *testing.T) {
+	var tests = []struct {
+		name         string
+		openAPI      string
+		def          SetterDefinition
+		expectedRefs []string
+		expected     string
+		openAPIOut   string
+		err          string
+	}{
+		{
+			name: "create",
+			openAPI: `apiVersion: v1alpha1
+kind: Krmfile
+`,
+			def:          SetterDefinition{Name: "replicas", Value: "3"},
+			expectedRefs: []string{"spec.replicas"},
+			expected: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx
+`,
+			openAPIOut: `apiVersion: v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      type: integer
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+`,
+		},
+		{
+			name: "setter-exists",
+			openAPI: `apiVersion: v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "4"
+`,
+			def: SetterDefinition{Name: "replicas", Value: "3"},
+			err: "setter with name replicas already exists, use --force to update it",
+		},
+		{
+			name: "substitution-exists",
+			openAPI: `apiVersion: v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.substitutions.replicas:
+      x-k8s-cli:
+        substitution:
+          name: replicas
+          pattern: ${replicas}
+`,
+			def: SetterDefinition{Name: "replicas", Value: "3"},
+			err: "substitution with name replicas already exists, " +
+				"substitution and setter can't have same name",
+		},
+		{
+			name: "invalid-value",
+			openAPI: `apiVersion: v1alpha1
+kind: Krmfile
+`,
+			def: SetterDefinition{Name: "image", Value: "nginx", Field: "image", Type: "integer"},
+			err: "image in body must be of type integer",
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			d, err := ioutil.TempDir("", "")
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			defer os.RemoveAll(d)
+			defer openapi.ResetOpenAPI()
+
+			path := filepath.Join(d, "Krmfile")
+			if !assert.NoError(t, ioutil.WriteFile(path, []byte(test.openAPI), 0600)) {
+				t.FailNow()
+			}
+			r := yaml.MustParse(createResource)
+
+			refs, err := CreateSetter(path, []*yaml.RNode{r}, test.def)
+			if test.err != "" {
+				if !assert.Error(t, err) {
+					t.FailNow()
+				}
+				assert.Contains(t, err.Error(), test.err)
+
+				// neither the resources nor the definitions are changed
+				assert.Equal(t, createResource, r.MustString())
+				b, err := ioutil.ReadFile(path)
+				if !assert.NoError(t, err) {
+					t.FailNow()
+				}
+				assert.Equal(t, test.openAPI, string(b))
+				return
+			}
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+
+			var fields []string
+			for _, ref := range refs {
+				assert.Equal(t, r, ref.Resource)
+				fields = append(fields, ref.Field)
+			}
+			assert.Equal(t, test.expectedRefs, fields)
+			assert.Equal(t, test.expected, r.MustString())
+
+			b, err := ioutil.ReadFile(path)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, test.openAPIOut, string(b))
+		})
+	}
+}
+
+func TestCreateSetter_errorTypes(t *testing.T) {
+	d, err := ioutil.TempDir("", "")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(d)
+	defer openapi.ResetOpenAPI()
+
+	path := filepath.Join(d, "Krmfile")
+	err = ioutil.WriteFile(path, []byte(`apiVersion: v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.substitutions.image:
+      x-k8s-cli:
+        substitution:
+          name: image
+          pattern: ${image}
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	r := yaml.MustParse(createResource)
+
+	_, err = CreateSetter(path, []*yaml.RNode{r}, SetterDefinition{Name: "image", Value: "nginx"})
+	exists, ok := err.(*ExistsError)
+	if assert.True(t, ok, err) {
+		assert.Equal(t, "image", exists.Name)
+		assert.True(t, exists.Substitution)
+	}
+
+	_, err = CreateSetter(path, []*yaml.RNode{r},
+		SetterDefinition{Name: "replicas", Value: "3", Pattern: "^[0-2]$"})
+	invalid, ok := err.(*ValidationError)
+	if assert.True(t, ok, err) {
+		assert.Equal(t, "replicas", invalid.Name)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package setters2
+
+import (
+	"encoding/json"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/fieldmeta"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// Delete removes the references to a setter from the fields of Resources,
+// leaving the field values as they are.
+type Delete struct {
+	// Name is the name of the setter whose references are removed.
+	Name string
+
+	// Count is the number of fields whose reference was removed by Filter.
+	Count int
+}
+
+// Filter implements Delete as a yaml.Filter
+func (d *Delete) Filter(object *yaml.RNode) (*yaml.RNode, error) {
+	d.removeRefs(object.YNode())
+	return object, nil
+}
+
+// removeRefs removes the comments referencing the setter from node, and from
+// the nodes it contains.  List setters are referenced by the key of the field.
+func (d *Delete) removeRefs(node *yaml.Node) {
+	if isSetterRef(node.LineComment, d.Name) {
+		node.LineComment = ""
+		d.Count++
+	}
+	if isSetterRef(node.HeadComment, d.Name) {
+		node.HeadComment = ""
+		d.Count++
+	}
+	for i := range node.Content {
+		d.removeRefs(node.Content[i])
+	}
+}
+
+// isSetterRef returns true if the comment references the setter with name,
+// in either the short hand or the $ref format.
+func isSetterRef(comment, name string) bool {
+	comment = strings.TrimSpace(strings.TrimLeft(comment, "#"))
+	if !strings.HasPrefix(comment, "{") {
+		return false
+	}
+	input := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(comment), &input); err != nil {
+		return false
+	}
+	return input[fieldmeta.ShortHandRef()] == name ||
+		input["$ref"] == fieldmeta.DefinitionsPrefix+fieldmeta.SetterDefinitionPrefix+name
+}
+
+// DeleteDefinition may be used to remove a setter from a files OpenAPI
+// definitions.  It is an error if the setter isn't defined, or if a
+// substitution references it.
+type DeleteDefinition struct {
+	// Name is the name of the setter to remove.
+	Name string
+}
+
+func (dd DeleteDefinition) DeleteFromFile(path string) error {
+	return yaml.UpdateFile(dd, path)
+}
+
+func (dd DeleteDefinition) Filter(object *yaml.RNode) (*yaml.RNode, error) {
+	key := fieldmeta.SetterDefinitionPrefix + dd.Name
+	definitions, err := object.Pipe(yaml.Lookup(openapi.SupplementaryOpenAPIFieldName, "definitions"))
+	if err != nil {
+		return nil, err
+	}
+	if definitions == nil || definitions.Field(key) == nil {
+		return nil, errors.Errorf("setter %s is not defined", dd.Name)
+	}
+
+	// substitutions referencing the setter would no longer resolve
+	ref := fieldmeta.DefinitionsPrefix + key
+	err = definitions.VisitFields(func(node *yaml.MapNode) error {
+		name := node.Key.YNode().Value
+		if !strings.HasPrefix(name, fieldmeta.SubstitutionDefinitionPrefix) {
+			return nil
+		}
+		values, err := node.Value.Pipe(yaml.Lookup(K8sCliExtensionKey, "substitution", "values"))
+		if err != nil || values == nil {
+			return err
+		}
+		for _, v := range values.Content() {
+			r := yaml.NewRNode(v).Field("ref")
+			if r != nil && r.Value.YNode().Value == ref {
+				return errors.Errorf("setter %s is referenced by substitution %s",
+					dd.Name, strings.TrimPrefix(name, fieldmeta.SubstitutionDefinitionPrefix))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := definitions.PipeE(yaml.Clear(key)); err != nil {
+		return nil, err
+	}
+	return object, nil
+}
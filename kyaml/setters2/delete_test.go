@@ -0,0 +1,163 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package setters2
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+func TestDelete_Filter(t *testing.T) {
+	r, err := yaml.Parse(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  labels:
+    app: nginx # {"$openapi":"app"}
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+  template:
+    spec:
+      containers:
+      - name: nginx
+        args: # {"$openapi":"replicas"}
+        - "3"
+      - name: sidecar
+        replicas: 3 # {"$ref":"#/definitions/io.k8s.cli.setters.replicas"}
+`)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	d := &Delete{Name: "replicas"}
+	result, err := d.Filter(r)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	actual, err := result.String()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, strings.TrimSpace(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  labels:
+    app: nginx # {"$openapi":"app"}
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: nginx
+        args:
+        - "3"
+      - name: sidecar
+        replicas: 3
+`), strings.TrimSpace(actual))
+	assert.Equal(t, 3, d.Count)
+}
+
+func TestDeleteDefinition_Filter(t *testing.T) {
+	var tests = []struct {
+		name     string
+		setter   string
+		input    string
+		expected string
+		err      string
+	}{
+		{
+			name:   "delete",
+			setter: "replicas",
+			input: `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.image:
+      x-k8s-cli:
+        setter:
+          name: image
+          value: nginx
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+`,
+			expected: `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.image:
+      x-k8s-cli:
+        setter:
+          name: image
+          value: nginx
+`,
+		},
+		{
+			name:   "not defined",
+			setter: "replicas",
+			input: `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.image:
+      x-k8s-cli:
+        setter:
+          name: image
+          value: nginx
+`,
+			err: "setter replicas is not defined",
+		},
+		{
+			name:   "referenced by a substitution",
+			setter: "tag",
+			input: `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.tag:
+      x-k8s-cli:
+        setter:
+          name: tag
+          value: "1.7"
+    io.k8s.cli.substitutions.image:
+      x-k8s-cli:
+        substitution:
+          name: image
+          pattern: nginx:TAG
+          values:
+          - marker: TAG
+            ref: '#/definitions/io.k8s.cli.setters.tag'
+`,
+			err: "setter tag is referenced by substitution image",
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			r, err := yaml.Parse(test.input)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			result, err := DeleteDefinition{Name: test.setter}.Filter(r)
+			if test.err != "" {
+				if assert.Error(t, err) {
+					assert.Equal(t, test.err, err.Error())
+				}
+				return
+			}
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			actual, err := result.String()
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, strings.TrimSpace(test.expected), strings.TrimSpace(actual))
+		})
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package setters2
+
+import (
+	"path"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/errors"
+)
+
+// MatchFieldPath returns true if the path of a field -- its elements separated
+// by '.', e.g. spec.template.spec.containers.image -- matches the glob pattern.
+// Each element of the pattern is matched against one element of the path as
+// by path.Match, e.g. spec.*.replicas, and the element ** matches any number
+// of elements, e.g. **.image.  The elements of lists aren't part of the path.
+func MatchFieldPath(pattern, fieldPath string) (bool, error) {
+	elements := strings.Split(strings.TrimPrefix(pattern, "."), ".")
+	for _, e := range elements {
+		if _, err := path.Match(e, ""); err != nil {
+			return false, errors.WrapPrefixf(err, "invalid field filter %s", pattern)
+		}
+	}
+	return matchElements(elements, strings.Split(strings.TrimPrefix(fieldPath, "."), ".")), nil
+}
+
+// matchElements returns true if the elements of a field path match those of
+// a well formed pattern.
+func matchElements(pattern, elements []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// try matching the rest of the pattern from each of the elements
+			for i := len(elements); i >= 0; i-- {
+				if matchElements(pattern[1:], elements[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(elements) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], elements[0]); !ok {
+			return false
+		}
+		pattern, elements = pattern[1:], elements[1:]
+	}
+	return len(elements) == 0
+}
@@ -0,0 +1,40 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package setters2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchFieldPath(t *testing.T) {
+	var tests = []struct {
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{pattern: "spec.replicas", path: ".spec.replicas", expected: true},
+		{pattern: ".spec.replicas", path: ".spec.replicas", expected: true},
+		{pattern: "spec", path: ".spec.replicas", expected: false},
+		{pattern: "spec.*", path: ".spec.replicas", expected: true},
+		{pattern: "spec.*", path: ".spec.template.replicas", expected: false},
+		{pattern: "spec.rep*", path: ".spec.replicas", expected: true},
+		{pattern: "**.image", path: ".spec.template.spec.containers.image", expected: true},
+		{pattern: "**.image", path: ".image", expected: true},
+		{pattern: "spec.**.image", path: ".spec.image", expected: true},
+		{pattern: "spec.**.image", path: ".metadata.image", expected: false},
+		{pattern: "**", path: ".spec.replicas", expected: true},
+	}
+	for _, test := range tests {
+		actual, err := MatchFieldPath(test.pattern, test.path)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.Equal(t, test.expected, actual, "%s %s", test.pattern, test.path)
+	}
+
+	_, err := MatchFieldPath("spec.[", ".spec.replicas")
+	assert.Error(t, err)
+}
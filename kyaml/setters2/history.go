@@ -0,0 +1,85 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package setters2
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// HistoryField is the field of the setter extension recording the changes to
+// the value of the setter -- when, from and to which value, and by whom.
+const HistoryField = "history"
+
+// now returns the time recorded in the history, replaced by tests.
+var now = time.Now
+
+// setterValue returns the value of the setter extension def, with list values
+// formatted as [a,b].
+func setterValue(def *yaml.RNode, t string) string {
+	if t != "array" {
+		if f := def.Field("value"); f != nil {
+			return f.Value.YNode().Value
+		}
+		return ""
+	}
+	f := def.Field("listValues")
+	if f == nil {
+		return ""
+	}
+	var values []string
+	for _, n := range f.Value.YNode().Content {
+		v, err := listItemValue(n)
+		if err != nil {
+			v = n.Value
+		}
+		values = append(values, v)
+	}
+	return fmt.Sprintf("[%s]", strings.Join(values, ","))
+}
+
+// recordHistory appends an entry to the history of the setter extension def if
+// its value changed from previous, dropping the oldest entries beyond max if
+// max is positive.
+func recordHistory(def *yaml.RNode, previous, value, setBy string, max int) error {
+	if previous == value {
+		return nil
+	}
+	history, err := def.Pipe(yaml.LookupCreate(yaml.SequenceNode, HistoryField))
+	if err != nil {
+		return err
+	}
+	entry := yaml.NewRNode(&yaml.Node{Kind: yaml.MappingNode})
+	for _, f := range []struct {
+		name, value string
+		// quote values which may otherwise be parsed as another type
+		quote bool
+	}{
+		{"timestamp", now().UTC().Format(time.RFC3339), true},
+		{"previousValue", previous, true},
+		{"value", value, true},
+		{"setBy", setBy, false},
+	} {
+		if f.name == "setBy" && f.value == "" {
+			continue
+		}
+		v := yaml.NewScalarRNode(f.value)
+		if f.quote {
+			v.YNode().Tag = yaml.StringTag
+			v.YNode().Style = yaml.DoubleQuotedStyle
+		}
+		if err := entry.PipeE(&yaml.FieldSetter{Name: f.name, Value: v}); err != nil {
+			return err
+		}
+	}
+	entries := append(history.YNode().Content, entry.YNode())
+	if max > 0 && len(entries) > max {
+		entries = entries[len(entries)-max:]
+	}
+	history.YNode().Content = entries
+	return nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package setters2
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+func TestSetOpenAPI_Filter_record(t *testing.T) {
+	var tests = []struct {
+		name       string
+		value      string
+		maxHistory int
+		input      string
+		expected   string
+	}{
+		{
+			name:  "record",
+			value: "2",
+			input: `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "1"
+ `,
+			expected: `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "2"
+          setBy: me
+          history:
+          - timestamp: "2020-01-02T03:04:05Z"
+            previousValue: "1"
+            value: "2"
+            setBy: me
+`,
+		},
+		{
+			name:  "unchanged",
+			value: "1",
+			input: `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "1"
+          setBy: me
+ `,
+			expected: `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "1"
+          setBy: me
+`,
+		},
+		{
+			name:       "max-history",
+			value:      "3",
+			maxHistory: 2,
+			input: `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "2"
+          setBy: me
+          history:
+          - timestamp: "2020-01-01T00:00:00Z"
+            previousValue: ""
+            value: "1"
+          - timestamp: "2020-01-01T12:00:00Z"
+            previousValue: "1"
+            value: "2"
+            setBy: me
+ `,
+			expected: `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+          setBy: me
+          history:
+          - timestamp: "2020-01-01T12:00:00Z"
+            previousValue: "1"
+            value: "2"
+            setBy: me
+          - timestamp: "2020-01-02T03:04:05Z"
+            previousValue: "2"
+            value: "3"
+            setBy: me
+`,
+		},
+	}
+	defer func() { now = time.Now }()
+	now = func() time.Time {
+		return time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			in, err := yaml.Parse(test.input)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			instance := &SetOpenAPI{
+				Name: "replicas", Value: test.value, SetBy: "me",
+				Record: true, MaxHistory: test.maxHistory}
+			result, err := instance.Filter(in)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			actual, err := result.String()
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, strings.TrimSpace(test.expected), strings.TrimSpace(actual))
+		})
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package setters2
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/fieldmeta"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// IncludeDirective returns the key of the comment directive which includes a
+// Resource, or a field, only if the value of a boolean setter is true -- e.g.
+// # {"$openapi-include":"tls_enabled"}
+func IncludeDirective() string {
+	return fieldmeta.ShortHandRef() + "-include"
+}
+
+// Prune removes the Resources, the fields and the sequence elements whose
+// include directive references a setter with a false value.  Setters without
+// a value are false.
+//
+// The directive of a Resource is a comment at the top of the Resource.  The
+// directive of a field, or of a sequence element, is a comment on the line
+// above it, or on the same line as its key.
+type Prune struct {
+	// Resources is the number of Resources removed by Filter.
+	Resources int
+
+	// Fields is the number of fields and sequence elements removed by Filter.
+	Fields int
+}
+
+// Filter implements Prune as a kio.Filter
+func (p *Prune) Filter(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
+	var included []*yaml.RNode
+	for i := range nodes {
+		ok, err := isIncluded(resourceComments(nodes[i])...)
+		if err != nil {
+			return nil, errors.WrapPrefixf(err, "resource %d", i)
+		}
+		if !ok {
+			p.Resources++
+			continue
+		}
+		if err := p.pruneFields(nodes[i].YNode(), true); err != nil {
+			return nil, err
+		}
+		included = append(included, nodes[i])
+	}
+	return included, nil
+}
+
+// resourceComments returns the comments at the top of the Resource.
+func resourceComments(node *yaml.RNode) []string {
+	comments := []string{node.YNode().HeadComment}
+	if doc := node.Document(); doc != nil && doc != node.YNode() {
+		comments = append(comments, doc.HeadComment)
+	}
+	if content := node.YNode().Content; node.YNode().Kind == yaml.MappingNode && len(content) > 0 {
+		comments = append(comments, content[0].HeadComment)
+	}
+	return comments
+}
+
+// pruneFields removes the fields and the sequence elements of node, and of
+// the nodes it contains, whose include directive references a false setter.
+// If node is a Resource or a sequence element, the head comment of its first
+// field is its own directive rather than the field's.
+func (p *Prune) pruneFields(node *yaml.Node, element bool) error {
+	switch node.Kind {
+	case yaml.MappingNode:
+		var content []*yaml.Node
+		for i := 0; i < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			comments := []string{key.LineComment, value.LineComment}
+			if i > 0 || !element {
+				comments = append(comments, key.HeadComment)
+			}
+			ok, err := isIncluded(comments...)
+			if err != nil {
+				return errors.WrapPrefixf(err, "field %s", key.Value)
+			}
+			if !ok {
+				p.Fields++
+				continue
+			}
+			if err := p.pruneFields(value, false); err != nil {
+				return err
+			}
+			content = append(content, key, value)
+		}
+		node.Content = content
+	case yaml.SequenceNode:
+		var content []*yaml.Node
+		for i, item := range node.Content {
+			comments := []string{item.HeadComment}
+			if item.Kind == yaml.MappingNode && len(item.Content) > 0 {
+				comments = append(comments, item.Content[0].HeadComment)
+			}
+			ok, err := isIncluded(comments...)
+			if err != nil {
+				return errors.WrapPrefixf(err, "element %d", i)
+			}
+			if !ok {
+				p.Fields++
+				continue
+			}
+			if err := p.pruneFields(item, true); err != nil {
+				return err
+			}
+			content = append(content, item)
+		}
+		node.Content = content
+	}
+	return nil
+}
+
+// isIncluded returns false if one of the comments is an include directive
+// referencing a setter whose value is false.
+func isIncluded(comments ...string) (bool, error) {
+	for _, comment := range comments {
+		for _, line := range strings.Split(comment, "\n") {
+			name := includeSetter(line)
+			if name == "" {
+				continue
+			}
+			ok, err := setterBool(name)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+	}
+	return true, nil
+}
+
+// includeSetter returns the name of the setter referenced by the include
+// directive in the comment line, or "" if it isn't a directive.
+func includeSetter(line string) string {
+	line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "#"))
+	if !strings.HasPrefix(line, "{") {
+		return ""
+	}
+	input := map[string]string{}
+	if err := json.Unmarshal([]byte(line), &input); err != nil {
+		return ""
+	}
+	return input[IncludeDirective()]
+}
+
+// setterBool returns the value of the setter as a boolean.
+func setterBool(name string) (bool, error) {
+	ref, err := spec.NewRef(fieldmeta.DefinitionsPrefix + fieldmeta.SetterDefinitionPrefix + name)
+	if err != nil {
+		return false, errors.Wrap(err)
+	}
+	def, err := openapi.Resolve(&ref)
+	if err != nil || def == nil {
+		return false, errors.Errorf("include directive references undefined setter %s", name)
+	}
+	ext, err := GetExtFromSchema(def)
+	if err != nil {
+		return false, errors.Wrap(err)
+	}
+	if ext == nil || ext.Setter == nil {
+		return false, errors.Errorf("include directive references undefined setter %s", name)
+	}
+	if ext.Setter.Value == "" {
+		return false, nil
+	}
+	ok, err := strconv.ParseBool(ext.Setter.Value)
+	if err != nil {
+		return false, errors.Errorf(
+			"value %q of setter %s isn't a boolean, as required by include directives",
+			ext.Setter.Value, name)
+	}
+	return ok, nil
+}
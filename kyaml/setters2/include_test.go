@@ -0,0 +1,203 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package setters2
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+)
+
+func TestPrune_Filter(t *testing.T) {
+	var tests = []struct {
+		name      string
+		openapi   string
+		input     string
+		expected  string
+		resources int
+		fields    int
+		err       string
+	}{
+		{
+			name: "exclude",
+			openapi: `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.tls_enabled:
+      x-k8s-cli:
+        setter:
+          name: tls_enabled
+          value: "false"
+ `,
+			input: `
+# {"$openapi-include":"tls_enabled"}
+apiVersion: v1
+kind: Secret
+metadata:
+  name: tls
+---
+apiVersion: networking.k8s.io/v1beta1
+kind: Ingress
+metadata:
+  name: web
+spec:
+  tls: # {"$openapi-include":"tls_enabled"}
+  - secretName: tls
+  rules:
+  - host: example.com
+ `,
+			expected: `
+apiVersion: networking.k8s.io/v1beta1
+kind: Ingress
+metadata:
+  name: web
+spec:
+  rules:
+  - host: example.com
+ `,
+			resources: 1,
+			fields:    1,
+		},
+		{
+			name: "include",
+			openapi: `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.tls_enabled:
+      x-k8s-cli:
+        setter:
+          name: tls_enabled
+          value: "true"
+ `,
+			input: `
+apiVersion: networking.k8s.io/v1beta1
+kind: Ingress
+metadata:
+  name: web
+spec:
+  tls: # {"$openapi-include":"tls_enabled"}
+  - secretName: tls
+ `,
+			expected: `
+apiVersion: networking.k8s.io/v1beta1
+kind: Ingress
+metadata:
+  name: web
+spec:
+  tls: # {"$openapi-include":"tls_enabled"}
+  - secretName: tls
+ `,
+		},
+		{
+			name: "exclude sequence element",
+			openapi: `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.sidecar_enabled:
+      x-k8s-cli:
+        setter:
+          name: sidecar_enabled
+ `,
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: web
+      # {"$openapi-include":"sidecar_enabled"}
+      - name: proxy
+        image: proxy
+ `,
+			expected: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: web
+ `,
+			fields: 1,
+		},
+		{
+			name: "undefined setter",
+			openapi: `
+openAPI:
+  definitions: {}
+ `,
+			input: `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: tls
+  # {"$openapi-include":"tls_enabled"}
+  namespace: default
+ `,
+			err: "field namespace: include directive references undefined setter tls_enabled",
+		},
+		{
+			name: "not a boolean",
+			openapi: `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.tls_enabled:
+      x-k8s-cli:
+        setter:
+          name: tls_enabled
+          value: "maybe"
+ `,
+			input: `
+# {"$openapi-include":"tls_enabled"}
+apiVersion: v1
+kind: Secret
+metadata:
+  name: tls
+ `,
+			err: `value "maybe" of setter tls_enabled isn't a boolean, as required by include directives`,
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			// reset the openAPI afterward
+			defer openapi.ResetOpenAPI()
+			initSchema(t, test.openapi)
+
+			out := &bytes.Buffer{}
+			p := &Prune{}
+			err := kio.Pipeline{
+				Inputs: []kio.Reader{&kio.ByteReader{
+					Reader:                bytes.NewBufferString(test.input),
+					OmitReaderAnnotations: true,
+				}},
+				Filters: []kio.Filter{p},
+				Outputs: []kio.Writer{kio.ByteWriter{Writer: out}},
+			}.Execute()
+			if test.err != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), test.err)
+				}
+				return
+			}
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, strings.TrimSpace(test.expected), strings.TrimSpace(out.String()))
+			assert.Equal(t, test.resources, p.Resources)
+			assert.Equal(t, test.fields, p.Fields)
+		})
+	}
+}
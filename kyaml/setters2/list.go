@@ -99,6 +99,21 @@ func (l *List) listSetters(object *yaml.RNode, resourcePath string) error {
 			setter.Description = description.Value.YNode().Value
 		}
 
+		// the enum is part of the schema rather than the extension
+		if enum := node.Value.Field("enum"); enum != nil {
+			for _, v := range enum.Value.YNode().Content {
+				setter.Enum = append(setter.Enum, v.Value)
+			}
+		}
+
+		// the type is part of the schema rather than the extension
+		if t := node.Value.Field("type"); t != nil {
+			setter.Type = t.Value.YNode().Value
+		}
+		if f := node.Value.Field("format"); f != nil && f.Value.YNode().Value == PercentageType {
+			setter.Type = PercentageType
+		}
+
 		// count the number of fields set by this setter
 		setter.Count, err = l.count(resourcePath, setter.Name)
 		if err != nil {
@@ -0,0 +1,183 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package setters2
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// ListValues are the values of a list setter.  The items of lists of objects
+// -- e.g. of env vars -- are JSON objects, e.g. {"name":"FOO","value":"bar"},
+// which are written to the setter definition, and to the fields, as mappings.
+type ListValues []string
+
+// MarshalYAML writes the items which are JSON objects as mappings, and the
+// others as strings.
+func (l ListValues) MarshalYAML() (interface{}, error) {
+	n := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, v := range l {
+		item := objectItem(v)
+		if item == nil {
+			item = &yaml.Node{Kind: yaml.ScalarNode, Tag: yaml.StringTag, Value: v}
+		}
+		n.Content = append(n.Content, item)
+	}
+	return n, nil
+}
+
+// UnmarshalYAML reads mapping items as JSON objects.
+func (l *ListValues) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind != yaml.SequenceNode {
+		return errors.Errorf("listValues must be a list")
+	}
+	*l = nil
+	for _, item := range n.Content {
+		v, err := listItemValue(item)
+		if err != nil {
+			return err
+		}
+		*l = append(*l, v)
+	}
+	return nil
+}
+
+// UnmarshalJSON reads items which aren't strings as JSON.
+func (l *ListValues) UnmarshalJSON(b []byte) error {
+	var items []json.RawMessage
+	if err := json.Unmarshal(b, &items); err != nil {
+		return err
+	}
+	*l = nil
+	for _, item := range items {
+		var s string
+		if err := json.Unmarshal(item, &s); err == nil {
+			*l = append(*l, s)
+			continue
+		}
+		var c bytes.Buffer
+		if err := json.Compact(&c, item); err != nil {
+			return err
+		}
+		*l = append(*l, c.String())
+	}
+	return nil
+}
+
+// listItemValue returns the list setter value of the sequence item n -- its
+// value if it is a scalar, or its JSON encoding if it is an object.
+func listItemValue(n *yaml.Node) (string, error) {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		return n.Value, nil
+	case yaml.MappingNode:
+		var b strings.Builder
+		writeJSON(&b, n)
+		return b.String(), nil
+	}
+	return "", errors.Errorf("list items must be scalars or objects")
+}
+
+// objectItem returns the item of a list of objects for the value v, or nil if
+// v isn't a JSON object.  The item is written in block style.
+func objectItem(v string) *yaml.Node {
+	if !strings.HasPrefix(v, "{") || !json.Valid([]byte(v)) {
+		return nil
+	}
+	item, err := yaml.Parse(v)
+	if err != nil {
+		return nil
+	}
+	clearStyle(item.YNode())
+	return item.YNode()
+}
+
+// sameItem returns true if the list items a and b have the same value,
+// ignoring the order of the fields of objects, and their style.
+func sameItem(a, b *yaml.Node) bool {
+	if a.Kind != b.Kind {
+		return false
+	}
+	if a.Kind != yaml.MappingNode {
+		return a.Value == b.Value
+	}
+	return canonicalJSON(a) == canonicalJSON(b)
+}
+
+// canonicalJSON returns the JSON encoding of n with the fields of objects
+// sorted.
+func canonicalJSON(n *yaml.Node) string {
+	var b strings.Builder
+	writeJSON(&b, n)
+	var v interface{}
+	if err := json.Unmarshal([]byte(b.String()), &v); err != nil {
+		return b.String()
+	}
+	c, err := json.Marshal(v)
+	if err != nil {
+		return b.String()
+	}
+	return string(c)
+}
+
+// writeJSON writes n as JSON, keeping the order of the fields of objects.
+func writeJSON(b *strings.Builder, n *yaml.Node) {
+	switch n.Kind {
+	case yaml.MappingNode:
+		b.WriteString("{")
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			writeJSONString(b, n.Content[i].Value)
+			b.WriteString(":")
+			writeJSON(b, n.Content[i+1])
+		}
+		b.WriteString("}")
+	case yaml.SequenceNode:
+		b.WriteString("[")
+		for i := range n.Content {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			writeJSON(b, n.Content[i])
+		}
+		b.WriteString("]")
+	case yaml.AliasNode:
+		writeJSON(b, n.Alias)
+	default:
+		switch n.ShortTag() {
+		case "!!null":
+			b.WriteString("null")
+		case yaml.BoolTag:
+			b.WriteString(strings.ToLower(n.Value))
+		case yaml.IntTag, "!!float":
+			if json.Valid([]byte(n.Value)) {
+				b.WriteString(n.Value)
+			} else {
+				writeJSONString(b, n.Value)
+			}
+		default:
+			writeJSONString(b, n.Value)
+		}
+	}
+}
+
+func writeJSONString(b *strings.Builder, s string) {
+	j, _ := json.Marshal(s)
+	b.Write(j)
+}
+
+// clearStyle clears the style of n and its descendants, so that they are
+// written in block style, and scalars are only quoted if they need to be.
+func clearStyle(n *yaml.Node) {
+	n.Style = 0
+	for i := range n.Content {
+		clearStyle(n.Content[i])
+	}
+}
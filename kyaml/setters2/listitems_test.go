@@ -0,0 +1,85 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package setters2
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+func TestListValues(t *testing.T) {
+	var tests = []struct {
+		name     string
+		values   ListValues
+		expected string
+	}{
+		{
+			name:   "scalars",
+			values: ListValues{"a", "1", "true"},
+			expected: `
+- a
+- "1"
+- "true"
+`,
+		},
+		{
+			name:   "objects",
+			values: ListValues{`{"name":"FOO","value":"1"}`, `{"name":"BAR","port":8080,"debug":true}`},
+			expected: `
+- name: FOO
+  value: "1"
+- name: BAR
+  port: 8080
+  debug: true
+`,
+		},
+		{
+			name:   "strings which aren't JSON objects",
+			values: ListValues{"{{ .Values.name }}"},
+			expected: `
+- '{{ .Values.name }}'
+`,
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			n, err := test.values.MarshalYAML()
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			actual, err := yaml.NewRNode(n.(*yaml.Node)).String()
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			if !assert.Equal(t, strings.TrimSpace(test.expected), strings.TrimSpace(actual)) {
+				t.FailNow()
+			}
+
+			// the values are read back unchanged, keeping the order of fields
+			object, err := yaml.Parse(actual)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			var values ListValues
+			if !assert.NoError(t, values.UnmarshalYAML(object.YNode())) {
+				t.FailNow()
+			}
+			assert.Equal(t, test.values, values)
+		})
+	}
+}
+
+func TestListValues_UnmarshalJSON(t *testing.T) {
+	var values ListValues
+	err := json.Unmarshal([]byte(`["a", {"name": "FOO", "value": "1"}]`), &values)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, ListValues{"a", `{"name":"FOO","value":"1"}`}, values)
+}
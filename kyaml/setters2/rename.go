@@ -0,0 +1,195 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package setters2
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/fieldmeta"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// namePattern matches legal setter names.  Names must start with a letter so
+// that they aren't mistaken for numbers in constraint expressions.
+var namePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]*$`)
+
+// ValidateName returns an error if name isn't a legal setter name.
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return errors.Errorf("invalid setter name %q: must start with a letter and "+
+			"contain only letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+// Rename changes the references to a setter from the fields of Resources,
+// and the include directives referencing it, to reference the setter by its
+// new name.
+type Rename struct {
+	// Name is the current name of the setter.
+	Name string
+
+	// NewName is the name the references are changed to.
+	NewName string
+
+	// Count is the number of comments changed by Filter.
+	Count int
+}
+
+// Filter implements Rename as a yaml.Filter
+func (r *Rename) Filter(object *yaml.RNode) (*yaml.RNode, error) {
+	if doc := object.Document(); doc != nil && doc != object.YNode() {
+		doc.HeadComment = r.renameComment(doc.HeadComment)
+	}
+	r.renameRefs(object.YNode())
+	return object, nil
+}
+
+// renameRefs renames the setter in the comments of node, and of the nodes it
+// contains.
+func (r *Rename) renameRefs(node *yaml.Node) {
+	node.LineComment = r.renameComment(node.LineComment)
+	node.HeadComment = r.renameComment(node.HeadComment)
+	for i := range node.Content {
+		r.renameRefs(node.Content[i])
+	}
+}
+
+// renameComment returns the comment with each line referencing the setter --
+// in the short hand, $ref or include directive format -- changed to reference
+// the new name.
+func (r *Rename) renameComment(comment string) string {
+	if comment == "" {
+		return comment
+	}
+	lines := strings.Split(comment, "\n")
+	for i := range lines {
+		index := strings.Index(lines[i], "{")
+		if index < 0 || strings.TrimSpace(strings.TrimLeft(lines[i][:index], "#")) != "" {
+			continue
+		}
+		input := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(lines[i][index:]), &input); err != nil {
+			continue
+		}
+		ref := fieldmeta.DefinitionsPrefix + fieldmeta.SetterDefinitionPrefix
+		changed := false
+		for _, key := range []string{fieldmeta.ShortHandRef(), IncludeDirective(), "$ref"} {
+			value, newValue := r.Name, r.NewName
+			if key == "$ref" {
+				value, newValue = ref+r.Name, ref+r.NewName
+			}
+			if input[key] == value {
+				input[key] = newValue
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		b, err := json.Marshal(input)
+		if err != nil {
+			continue
+		}
+		lines[i] = lines[i][:index] + string(b)
+		r.Count++
+	}
+	return strings.Join(lines, "\n")
+}
+
+// RenameDefinition may be used to rename a setter in a files OpenAPI
+// definitions, along with the substitutions and constraints referencing it.
+// It is an error if the setter isn't defined, or if a setter or substitution
+// with the new name is already defined.
+type RenameDefinition struct {
+	// Name is the current name of the setter.
+	Name string
+
+	// NewName is the name to rename the setter to.
+	NewName string
+}
+
+func (rd RenameDefinition) RenameInFile(path string) error {
+	return yaml.UpdateFile(rd, path)
+}
+
+func (rd RenameDefinition) Filter(object *yaml.RNode) (*yaml.RNode, error) {
+	if err := ValidateName(rd.NewName); err != nil {
+		return nil, err
+	}
+	key := fieldmeta.SetterDefinitionPrefix + rd.Name
+	newKey := fieldmeta.SetterDefinitionPrefix + rd.NewName
+	definitions, err := object.Pipe(yaml.Lookup(openapi.SupplementaryOpenAPIFieldName, "definitions"))
+	if err != nil {
+		return nil, err
+	}
+	if definitions == nil || definitions.Field(key) == nil {
+		return nil, errors.Errorf("setter %s is not defined", rd.Name)
+	}
+	if definitions.Field(newKey) != nil {
+		return nil, errors.Errorf("setter %s already exists", rd.NewName)
+	}
+	if definitions.Field(fieldmeta.SubstitutionDefinitionPrefix+rd.NewName) != nil {
+		return nil, errors.Errorf("substitution %s already exists", rd.NewName)
+	}
+
+	// rename the definition, keeping its position
+	field := definitions.Field(key)
+	field.Key.YNode().Value = newKey
+	name, err := field.Value.Pipe(yaml.Lookup(K8sCliExtensionKey, "setter", "name"))
+	if err != nil {
+		return nil, err
+	}
+	if name != nil {
+		name.YNode().Value = rd.NewName
+	}
+
+	// update the substitutions and constraints referencing the setter
+	ref := fieldmeta.DefinitionsPrefix + key
+	err = definitions.VisitFields(func(node *yaml.MapNode) error {
+		switch k := node.Key.YNode().Value; {
+		case strings.HasPrefix(k, fieldmeta.SubstitutionDefinitionPrefix):
+			values, err := node.Value.Pipe(yaml.Lookup(K8sCliExtensionKey, "substitution", "values"))
+			if err != nil || values == nil {
+				return err
+			}
+			for _, v := range values.Content() {
+				r := yaml.NewRNode(v).Field("ref")
+				if r != nil && r.Value.YNode().Value == ref {
+					r.Value.YNode().Value = fieldmeta.DefinitionsPrefix + newKey
+				}
+			}
+		case strings.HasPrefix(k, fieldmeta.ConstraintDefinitionPrefix):
+			expression, err := node.Value.Pipe(
+				yaml.Lookup(K8sCliExtensionKey, "constraint", "expression"))
+			if err != nil || expression == nil {
+				return err
+			}
+			c, err := ParseConstraint(expression.YNode().Value)
+			if err != nil {
+				return errors.WrapPrefixf(err, "constraint %s",
+					strings.TrimPrefix(k, fieldmeta.ConstraintDefinitionPrefix))
+			}
+			if c.Left != rd.Name && c.Right != rd.Name {
+				return nil
+			}
+			if c.Left == rd.Name {
+				c.Left = rd.NewName
+			}
+			if c.Right == rd.Name {
+				c.Right = rd.NewName
+			}
+			expression.YNode().Value = c.String()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return object, nil
+}
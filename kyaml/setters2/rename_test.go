@@ -0,0 +1,231 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package setters2
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+func TestRename_Filter(t *testing.T) {
+	r, err := yaml.Parse(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  labels:
+    app: nginx # {"$openapi":"app"}
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+  template:
+    spec:
+      # {"$openapi-include":"replicas"}
+      hostNetwork: true
+      containers:
+      - name: sidecar
+        replicas: 3 # {"$ref":"#/definitions/io.k8s.cli.setters.replicas"}
+`)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	rn := &Rename{Name: "replicas", NewName: "replica-count"}
+	result, err := rn.Filter(r)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	actual, err := result.String()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, strings.TrimSpace(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  labels:
+    app: nginx # {"$openapi":"app"}
+spec:
+  replicas: 3 # {"$openapi":"replica-count"}
+  template:
+    spec:
+      # {"$openapi-include":"replica-count"}
+      hostNetwork: true
+      containers:
+      - name: sidecar
+        replicas: 3 # {"$ref":"#/definitions/io.k8s.cli.setters.replica-count"}
+`), strings.TrimSpace(actual))
+	assert.Equal(t, 3, rn.Count)
+}
+
+func TestRenameDefinition_Filter(t *testing.T) {
+	var tests = []struct {
+		name     string
+		setter   string
+		newName  string
+		input    string
+		expected string
+		err      string
+	}{
+		{
+			name:    "rename",
+			setter:  "tag",
+			newName: "image-tag",
+			input: `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.tag:
+      x-k8s-cli:
+        setter:
+          name: tag
+          value: "1.7"
+    io.k8s.cli.setters.min-tag:
+      x-k8s-cli:
+        setter:
+          name: min-tag
+          value: "1.5"
+    io.k8s.cli.substitutions.image:
+      x-k8s-cli:
+        substitution:
+          name: image
+          pattern: nginx:TAG
+          values:
+          - marker: TAG
+            ref: '#/definitions/io.k8s.cli.setters.tag'
+    io.k8s.cli.constraints.tag:
+      x-k8s-cli:
+        constraint:
+          name: tag
+          expression: min-tag <= tag
+`,
+			expected: `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.image-tag:
+      x-k8s-cli:
+        setter:
+          name: image-tag
+          value: "1.7"
+    io.k8s.cli.setters.min-tag:
+      x-k8s-cli:
+        setter:
+          name: min-tag
+          value: "1.5"
+    io.k8s.cli.substitutions.image:
+      x-k8s-cli:
+        substitution:
+          name: image
+          pattern: nginx:TAG
+          values:
+          - marker: TAG
+            ref: '#/definitions/io.k8s.cli.setters.image-tag'
+    io.k8s.cli.constraints.tag:
+      x-k8s-cli:
+        constraint:
+          name: tag
+          expression: min-tag <= image-tag
+`,
+		},
+		{
+			name:    "not defined",
+			setter:  "replicas",
+			newName: "replica-count",
+			input: `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.image:
+      x-k8s-cli:
+        setter:
+          name: image
+          value: nginx
+`,
+			err: "setter replicas is not defined",
+		},
+		{
+			name:    "setter exists",
+			setter:  "tag",
+			newName: "image",
+			input: `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.tag:
+      x-k8s-cli:
+        setter:
+          name: tag
+          value: "1.7"
+    io.k8s.cli.setters.image:
+      x-k8s-cli:
+        setter:
+          name: image
+          value: nginx
+`,
+			err: "setter image already exists",
+		},
+		{
+			name:    "substitution exists",
+			setter:  "tag",
+			newName: "image",
+			input: `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.tag:
+      x-k8s-cli:
+        setter:
+          name: tag
+          value: "1.7"
+    io.k8s.cli.substitutions.image:
+      x-k8s-cli:
+        substitution:
+          name: image
+          pattern: nginx:TAG
+          values:
+          - marker: TAG
+            ref: '#/definitions/io.k8s.cli.setters.tag'
+`,
+			err: "substitution image already exists",
+		},
+		{
+			name:    "invalid name",
+			setter:  "tag",
+			newName: "1tag",
+			input: `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.tag:
+      x-k8s-cli:
+        setter:
+          name: tag
+          value: "1.7"
+`,
+			err: `invalid setter name "1tag": must start with a letter and contain only letters, digits, '-' and '_'`,
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			r, err := yaml.Parse(test.input)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			result, err := RenameDefinition{Name: test.setter, NewName: test.newName}.Filter(r)
+			if test.err != "" {
+				if assert.Error(t, err) {
+					assert.Equal(t, test.err, err.Error())
+				}
+				return
+			}
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			actual, err := result.String()
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, strings.TrimSpace(test.expected), strings.TrimSpace(actual))
+		})
+	}
+}
@@ -5,6 +5,9 @@ package setters2
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -30,13 +33,56 @@ type Set struct {
 	// Count is the number of fields that were updated by calling Filter
 	Count int
 
+	// Changed is the number of fields whose value was modified by calling Filter.
+	// Fields which already had the setter value are counted by Count, but not Changed.
+	Changed int
+
+	// PreviousValues records, for each setter, the distinct values held by the
+	// fields it set directly before they were set.  More than one value for a
+	// setter indicates the fields had diverged.
+	PreviousValues map[string][]string
+
 	// SetAll if set to true will set all setters regardless of name
 	SetAll bool
+
+	// Style, if set, is the name of the style the scalar fields are written
+	// in when set -- one of the keys of ScalarStyles.  If unset, the style of
+	// the fields is kept.
+	Style string
+
+	// FieldFilter, if set, is a glob which the path of a field must match for
+	// it to be set -- see MatchFieldPath.
+	FieldFilter string
+
+	// meta is the metadata of the resource being filtered, which setters
+	// scoped to kinds or an apiVersion must match.
+	meta yaml.ResourceMeta
+
+	// anchors are the anchored nodes which have been set, which are visited
+	// again through their aliases.
+	anchors map[*yaml.Node]bool
+}
+
+// ScalarStyles are the styles which Set may write scalar fields in, keyed by
+// their name.  Only string fields may be written in styles other than plain.
+// The setter reference of a field written as a block scalar is moved to the
+// line above it -- see MoveBlockScalarRefs.
+var ScalarStyles = map[string]yaml.Style{
+	"plain":   0,
+	"single":  yaml.SingleQuotedStyle,
+	"double":  yaml.DoubleQuotedStyle,
+	"literal": yaml.LiteralStyle,
+	"folded":  yaml.FoldedStyle,
 }
 
 // Filter implements Set as a yaml.Filter
 func (s *Set) Filter(object *yaml.RNode) (*yaml.RNode, error) {
-	return object, accept(s, object)
+	// objects which aren't resources match only setters which aren't scoped
+	s.meta, _ = object.GetMeta()
+	err := accept(s, object)
+	// keep the references of fields written as block scalars
+	MoveBlockScalarRefs(object.YNode())
+	return object, err
 }
 
 // isMatch returns true if the setter with name should have the field
@@ -45,6 +91,31 @@ func (s *Set) isMatch(name string) bool {
 	return s.SetAll || s.Name == name
 }
 
+// isFieldMatch returns true if the field at path p matches s.FieldFilter.
+func (s *Set) isFieldMatch(p string) (bool, error) {
+	if s.FieldFilter == "" {
+		return true, nil
+	}
+	return MatchFieldPath(s.FieldFilter, p)
+}
+
+// isSetAnchor returns true if node is anchored and has already been set --
+// fields set through an alias are only counted once.  Otherwise it records
+// node as set if it is anchored.
+func (s *Set) isSetAnchor(node *yaml.Node) bool {
+	if node.Anchor == "" {
+		return false
+	}
+	if s.anchors[node] {
+		return true
+	}
+	if s.anchors == nil {
+		s.anchors = map[*yaml.Node]bool{}
+	}
+	s.anchors[node] = true
+	return false
+}
+
 func (s *Set) visitMapping(object *yaml.RNode, p string, _ *openapi.ResourceSchema) error {
 	return nil
 }
@@ -56,10 +127,16 @@ func (s *Set) visitSequence(object *yaml.RNode, p string, schema *openapi.Resour
 		return err
 	}
 	if ext == nil || ext.Setter == nil || !s.isMatch(ext.Setter.Name) ||
-		len(ext.Setter.ListValues) == 0 {
+		len(ext.Setter.ListValues) == 0 || !ext.Setter.inScope(s.meta) {
 		// setter was not invoked for this sequence
 		return nil
 	}
+	if ok, err := s.isFieldMatch(p); !ok || err != nil {
+		return err
+	}
+	if s.isSetAnchor(object.YNode()) {
+		return nil
+	}
 	s.Count++
 
 	// set the values on the sequences
@@ -71,15 +148,56 @@ func (s *Set) visitSequence(object *yaml.RNode, p string, schema *openapi.Resour
 	}
 	for i := range ext.Setter.ListValues {
 		v := ext.Setter.ListValues[i]
+		if n := objectItem(v); n != nil {
+			if i < len(object.YNode().Content) && sameItem(object.YNode().Content[i], n) {
+				// keep the order of the fields of unchanged objects
+				n = object.YNode().Content[i]
+			}
+			elements = append(elements, n)
+			continue
+		}
 		n := yaml.NewScalarRNode(v).YNode()
 		n.Style = yaml.DoubleQuotedStyle
 		elements = append(elements, n)
 	}
+	if !sequenceEqual(object.YNode(), elements, yaml.FoldedStyle) {
+		s.Changed++
+	}
 	object.YNode().Content = elements
 	object.YNode().Style = yaml.FoldedStyle
 	return nil
 }
 
+// sequenceEqual returns true if node already contains the elements with the
+// given style.
+func sequenceEqual(node *yaml.Node, elements []*yaml.Node, style yaml.Style) bool {
+	if node.Style != style || len(node.Content) != len(elements) {
+		return false
+	}
+	for i := range elements {
+		if elements[i].Kind == yaml.MappingNode {
+			if !sameItem(node.Content[i], elements[i]) {
+				return false
+			}
+			continue
+		}
+		// the new elements are untagged, so only compare the values and styles
+		if node.Content[i].Value != elements[i].Value ||
+			node.Content[i].Style != elements[i].Style {
+			return false
+		}
+	}
+	return true
+}
+
+// scalarEqual returns true if the value, tag and style of the scalar
+// node after match before.
+func scalarEqual(before yaml.Node, after *yaml.Node) bool {
+	return before.Value == after.Value &&
+		before.Tag == after.Tag &&
+		before.Style == after.Style
+}
+
 // visitScalar
 func (s *Set) visitScalar(object *yaml.RNode, p string, schema *openapi.ResourceSchema) error {
 	// get the openAPI for this field describing how to apply the setter
@@ -90,6 +208,15 @@ func (s *Set) visitScalar(object *yaml.RNode, p string, schema *openapi.Resource
 	if ext == nil {
 		return nil
 	}
+	if ok, err := s.isFieldMatch(p); !ok || err != nil {
+		return err
+	}
+	if s.isSetAnchor(object.YNode()) {
+		return nil
+	}
+
+	// record the field before it is set so no-op sets may be detected
+	before := *object.YNode()
 
 	// perform a direct set of the field if it matches
 	ok, err := s.set(object, ext, schema.Schema)
@@ -97,7 +224,14 @@ func (s *Set) visitScalar(object *yaml.RNode, p string, schema *openapi.Resource
 		return err
 	}
 	if ok {
+		if err := s.applyStyle(object, p); err != nil {
+			return err
+		}
 		s.Count++
+		s.recordPrevious(ext.Setter.Name, before.Value)
+		if !scalarEqual(before, object.YNode()) {
+			s.Changed++
+		}
 		return nil
 	}
 
@@ -107,11 +241,72 @@ func (s *Set) visitScalar(object *yaml.RNode, p string, schema *openapi.Resource
 		return err
 	}
 	if sub {
+		if err := s.applyStyle(object, p); err != nil {
+			return err
+		}
 		s.Count++
+		if !scalarEqual(before, object.YNode()) {
+			s.Changed++
+		}
 	}
 	return nil
 }
 
+// applyStyle writes the field at path p in the style named by s.Style, if set.
+// Only string fields may be quoted or written as block scalars -- which would
+// otherwise make integers and booleans strings.  Filter moves the setter
+// reference of block scalars to the line above the field.
+func (s *Set) applyStyle(field *yaml.RNode, p string) error {
+	if s.Style == "" {
+		return nil
+	}
+	style, found := ScalarStyles[s.Style]
+	if !found {
+		return errors.Errorf("unknown style %s, must be one of %s",
+			s.Style, strings.Join(StyleNames(), ", "))
+	}
+	if tag := field.YNode().ShortTag(); style != 0 && tag != yaml.StringTag {
+		return errors.Errorf("style %s may only be used for string fields, field %s is %s",
+			s.Style, strings.TrimPrefix(p, "."), tag)
+	}
+	field.YNode().Style = style
+	return nil
+}
+
+// StyleNames returns the sorted names of the ScalarStyles.
+func StyleNames() []string {
+	var names []string
+	for name := range ScalarStyles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// recordPrevious records value as a previous value of a field set by the setter
+// with name, if it has not already been recorded.
+func (s *Set) recordPrevious(name, value string) {
+	if s.PreviousValues == nil {
+		s.PreviousValues = map[string][]string{}
+	}
+	for _, v := range s.PreviousValues[name] {
+		if v == value {
+			return
+		}
+	}
+	s.PreviousValues[name] = append(s.PreviousValues[name], value)
+}
+
+// DivergentValues returns the distinct previous values of the fields set by
+// the setter with name if they differed from one another, or nil if the fields
+// all held the same value.
+func (s *Set) DivergentValues(name string) []string {
+	if len(s.PreviousValues[name]) < 2 {
+		return nil
+	}
+	return s.PreviousValues[name]
+}
+
 // substitute updates the value of field from ext if ext contains a substitution that
 // depends on a setter whose name matches s.Name.
 func (s *Set) substitute(field *yaml.RNode, ext *CliExtension) (bool, error) {
@@ -216,10 +411,17 @@ func (s *Set) substituteUtil(ext *CliExtension, visited sets.String, nameMatch *
 // set applies the value from ext to field if its name matches s.Name
 func (s *Set) set(field *yaml.RNode, ext *CliExtension, sch *spec.Schema) (bool, error) {
 	// check full setter
-	if ext.Setter == nil || !s.isMatch(ext.Setter.Name) {
+	if ext.Setter == nil || !s.isMatch(ext.Setter.Name) || !ext.Setter.inScope(s.meta) {
 		return false, nil
 	}
 
+	if strings.Contains(ext.Setter.Value, "\n") &&
+		len(sch.Type) > 0 && !sch.Type.Contains("string") {
+		return false, errors.Errorf(
+			"setter %s has a multi-line value, but the field is of type %s",
+			ext.Setter.Name, strings.Join(sch.Type, ","))
+	}
+
 	if err := validateAgainstSchema(ext, sch); err != nil {
 		return false, err
 	}
@@ -234,6 +436,14 @@ func (s *Set) set(field *yaml.RNode, ext *CliExtension, sch *spec.Schema) (bool,
 	// this has a full setter, set its value
 	field.YNode().Value = ext.Setter.Value
 
+	if strings.Contains(ext.Setter.Value, "\n") {
+		// multi-line values, e.g. file contents, are written as literal
+		// block scalars so their newlines are kept
+		field.YNode().Tag = yaml.StringTag
+		field.YNode().Style = yaml.LiteralStyle
+		return true, nil
+	}
+
 	// format the node so it is quoted if it is a string
 	yaml.FormatNonStringStyle(field.YNode(), *sch)
 	return true, nil
@@ -242,6 +452,32 @@ func (s *Set) set(field *yaml.RNode, ext *CliExtension, sch *spec.Schema) (bool,
 // validateAgainstSchema validates the input setter value against user provided
 // openAI schema
 func validateAgainstSchema(ext *CliExtension, sch *spec.Schema) error {
+	if sch.Pattern != "" && len(ext.Setter.ListValues) == 0 {
+		// name the setter and the pattern, which the schema validation doesn't
+		if err := validatePattern(ext.Setter.Name, ext.Setter.Value, sch.Pattern); err != nil {
+			return err
+		}
+	}
+	if sch.Format == PercentageType && len(ext.Setter.ListValues) == 0 {
+		if err := ValidatePercentage(ext.Setter.Name, ext.Setter.Value, sch.Maximum); err != nil {
+			return err
+		}
+	}
+
+	if sch.Format != PercentageType && len(ext.Setter.ListValues) == 0 {
+		// name the setter and the bound, which the schema validation doesn't
+		if err := validateBounds(ext.Setter.Name, ext.Setter.Value, sch); err != nil {
+			return err
+		}
+	}
+
+	if sch.UniqueItems && len(ext.Setter.ListValues) > 0 {
+		// list the duplicated values, which the schema validation doesn't
+		if err := validateUniqueItems(ext.Setter.Name, ext.Setter.ListValues); err != nil {
+			return err
+		}
+	}
+
 	sc := spec.Schema{}
 	sc.Properties = map[string]spec.Schema{}
 	sc.Properties[ext.Setter.Name] = *sch
@@ -290,17 +526,127 @@ func validateAgainstSchema(ext *CliExtension, sch *spec.Schema) error {
 	}
 
 	input := map[string]interface{}{}
-	err := goyaml.Unmarshal([]byte(inputYAML), &input)
-	if err != nil {
+	if len(ext.Setter.ListValues) == 0 && strings.Contains(ext.Setter.Value, "\n") {
+		// multi-line values may not be embedded in a yaml document, and are
+		// always strings
+		input[ext.Setter.Name] = ext.Setter.Value
+	} else if err := goyaml.Unmarshal([]byte(inputYAML), &input); err != nil {
 		return err
 	}
-	err = validate.AgainstSchema(&sc, input, strfmt.Default)
+	err := validate.AgainstSchema(&sc, input, strfmt.Default)
+	if err != nil && len(sch.Enum) > 0 && len(ext.Setter.ListValues) == 0 {
+		// list the allowed values, which the schema validation doesn't
+		if allowed := enumValues(sch.Enum); !allowed.Has(ext.Setter.Value) {
+			return errors.Errorf("value %q of setter %s isn't one of the allowed values: %s",
+				ext.Setter.Value, ext.Setter.Name, strings.Join(enumStrings(sch.Enum), ", "))
+		}
+	}
 	if err != nil {
 		return errors.Errorf("The input value doesn't validate against provided OpenAPI schema: %v\n", err.Error())
 	}
 	return nil
 }
 
+// enumStrings returns the values of the enum as strings.
+func enumStrings(enum []interface{}) []string {
+	var values []string
+	for _, v := range enum {
+		if f, ok := v.(float64); ok {
+			// don't format large numbers with an exponent
+			values = append(values, strconv.FormatFloat(f, 'f', -1, 64))
+			continue
+		}
+		values = append(values, fmt.Sprint(v))
+	}
+	return values
+}
+
+// enumValues returns the set of the values of the enum as strings.
+func enumValues(enum []interface{}) sets.String {
+	values := sets.String{}
+	values.Insert(enumStrings(enum)...)
+	return values
+}
+
+// validatePattern returns an error if value doesn't match the pattern of the
+// setter with name.
+func validatePattern(name, value, pattern string) error {
+	match, err := regexp.MatchString(pattern, value)
+	if err != nil {
+		return errors.WrapPrefixf(err, "setter %s has an invalid pattern %s", name, pattern)
+	}
+	if !match {
+		return errors.Errorf("value %q of setter %s doesn't match pattern %s", value, name, pattern)
+	}
+	return nil
+}
+
+// validateUniqueItems returns an error listing the values of the list setter
+// with name which are duplicated, wherever they are in the list.  Items which
+// are objects are the same if their fields are, in any order.
+func validateUniqueItems(name string, values []string) error {
+	counts := map[string]int{}
+	var duplicates []string
+	for _, v := range values {
+		key := v
+		if item := objectItem(v); item != nil {
+			key = canonicalJSON(item)
+		}
+		counts[key]++
+		if counts[key] == 2 {
+			duplicates = append(duplicates, v)
+		}
+	}
+	if len(duplicates) > 0 {
+		return errors.Errorf("values of setter %s must be unique, found duplicates: %s",
+			name, strings.Join(duplicates, ", "))
+	}
+	return nil
+}
+
+// validateBounds returns an error if value is a number outside of the maximum
+// or minimum of the schema of the setter with name.  Values which aren't
+// numbers are left to the schema validation.
+func validateBounds(name, value string, sch *spec.Schema) error {
+	if sch.Maximum == nil && sch.Minimum == nil {
+		return nil
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil
+	}
+	if max := sch.Maximum; max != nil && (v > *max || sch.ExclusiveMaximum && v == *max) {
+		return errors.Errorf("value %s exceeds maximum %v for setter %s", value, *max, name)
+	}
+	if min := sch.Minimum; min != nil && (v < *min || sch.ExclusiveMinimum && v == *min) {
+		return errors.Errorf("value %s is below minimum %v for setter %s", value, *min, name)
+	}
+	return nil
+}
+
+// PercentageType is the type of setters whose values are percentages -- e.g.
+// 25%.  Their definitions have a string type with a percentage format.
+const PercentageType = "percentage"
+
+var percentage = regexp.MustCompile(`^([0-9]+)%$`)
+
+// ValidatePercentage returns an error if value of the setter with name isn't
+// a percentage, or if max is set and the percentage exceeds it.
+func ValidatePercentage(name, value string, max *float64) error {
+	m := percentage.FindStringSubmatch(value)
+	if m == nil {
+		return errors.Errorf("value %q of setter %s isn't a percentage -- e.g. 25%%", value, name)
+	}
+	n, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if max != nil && n > *max {
+		return errors.Errorf("value %q of setter %s exceeds %v%%", value, name, *max)
+	}
+	return nil
+}
+
 // SetOpenAPI updates a setter value
 type SetOpenAPI struct {
 	// Name is the name of the setter to add
@@ -314,6 +660,13 @@ type SetOpenAPI struct {
 	Description string `yaml:"description"`
 
 	SetBy string `yaml:"setBy"`
+
+	// Record appends the change of value to the history of the setter.
+	Record bool `yaml:"-"`
+
+	// MaxHistory, if positive, caps the number of entries in the history of
+	// the setter -- the oldest are dropped.
+	MaxHistory int `yaml:"-"`
 }
 
 // UpdateFile updates the OpenAPI definitions in a file with the given setter value.
@@ -343,6 +696,7 @@ func (s SetOpenAPI) Filter(object *yaml.RNode) (*yaml.RNode, error) {
 	if n := oa.Field("type"); n != nil {
 		t = n.Value.YNode().Value
 	}
+	previous := setterValue(def, t)
 
 	// if the setter contains an enumValues map, then ensure the set value appears
 	// as a key in the map
@@ -381,10 +735,17 @@ func (s SetOpenAPI) Filter(object *yaml.RNode) (*yaml.RNode, error) {
 	// ensure this consistently.
 	v.YNode().Tag = yaml.StringTag
 	v.YNode().Style = yaml.DoubleQuotedStyle
+	multiLine := strings.Contains(s.Value, "\n")
+	if multiLine {
+		// keep multi-line values readable
+		v.YNode().Style = yaml.LiteralStyle
+	}
 
 	if t != "array" {
-		// set a scalar value
-		if err := def.PipeE(&yaml.FieldSetter{Name: "value", Value: v}); err != nil {
+		// set a scalar value, overriding the style of the current value
+		// if it is multi-line
+		setter := &yaml.FieldSetter{Name: "value", Value: v, OverrideStyle: multiLine}
+		if err := def.PipeE(setter); err != nil {
 			return nil, err
 		}
 	} else {
@@ -394,12 +755,20 @@ func (s SetOpenAPI) Filter(object *yaml.RNode) (*yaml.RNode, error) {
 		}
 		// create the list values
 		var elements []*yaml.Node
-		n := yaml.NewScalarRNode(s.Value).YNode()
-		n.Tag = yaml.StringTag
-		n.Style = yaml.DoubleQuotedStyle
+		n := objectItem(s.Value)
+		if n == nil {
+			n = yaml.NewScalarRNode(s.Value).YNode()
+			n.Tag = yaml.StringTag
+			n.Style = yaml.DoubleQuotedStyle
+		}
 		elements = append(elements, n)
 		for i := range s.ListValues {
 			v := s.ListValues[i]
+			if n := objectItem(v); n != nil {
+				// items of lists of objects are written as mappings
+				elements = append(elements, n)
+				continue
+			}
 			n := yaml.NewScalarRNode(v).YNode()
 			n.Style = yaml.DoubleQuotedStyle
 			elements = append(elements, n)
@@ -419,6 +788,13 @@ func (s SetOpenAPI) Filter(object *yaml.RNode) (*yaml.RNode, error) {
 		return nil, err
 	}
 
+	if s.Record {
+		err := recordHistory(def, previous, setterValue(def, t), s.SetBy, s.MaxHistory)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if s.Description != "" {
 		d, err := object.Pipe(yaml.LookupCreate(
 			yaml.MappingNode, "openAPI", "definitions", key))
@@ -434,19 +810,20 @@ func (s SetOpenAPI) Filter(object *yaml.RNode) (*yaml.RNode, error) {
 }
 
 // SetAll applies the set filter for all yaml nodes and only returns the nodes whose
-// corresponding file has at least one node with input setter
+// corresponding file has at least one field modified by the input setter.  Files
+// whose fields already have the setter values are left untouched.
 func SetAll(s *Set) kio.Filter {
 	return kio.FilterFunc(func(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
 		filesToUpdate := sets.String{}
-		// for each node record the set fields count before and after filter is applied and
-		// store the corresponding file paths if there is an increment in setters count
+		// for each node record the changed fields count before and after filter is applied and
+		// store the corresponding file paths if there is an increment in changed count
 		for i := range nodes {
-			preCount := s.Count
+			preChanged := s.Changed
 			_, err := s.Filter(nodes[i])
 			if err != nil {
 				return nil, errors.Wrap(err)
 			}
-			if s.Count > preCount {
+			if s.Changed > preChanged {
 				path, _, err := kioutil.GetFileAnnotations(nodes[i])
 				if err != nil {
 					return nil, errors.Wrap(err)
@@ -455,7 +832,7 @@ func SetAll(s *Set) kio.Filter {
 			}
 		}
 		var nodesInUpdatedFiles []*yaml.RNode
-		// return only the nodes whose corresponding file has at least one node with input setter
+		// return only the nodes whose corresponding file has at least one modified field
 		for i := range nodes {
 			path, _, err := kioutil.GetFileAnnotations(nodes[i])
 			if err != nil {
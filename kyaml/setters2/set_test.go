@@ -56,6 +56,71 @@ spec:
 			expected: `
 apiVersion: apps/v1
 kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 4 # {"$ref": "#/definitions/io.k8s.cli.setters.replicas"}
+ `,
+		},
+		{
+			name:        "set-replicas-scoped-kind",
+			description: "setters scoped to kinds only set the fields of resources of the kinds",
+			setter:      "replicas",
+			openapi: `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "4"
+          kinds:
+          - Deployment
+ `,
+			input: `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: nginx-statefulset
+spec:
+  replicas: 3 # {"$ref": "#/definitions/io.k8s.cli.setters.replicas"}
+ `,
+			expected: `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: nginx-statefulset
+spec:
+  replicas: 3 # {"$ref": "#/definitions/io.k8s.cli.setters.replicas"}
+ `,
+		},
+		{
+			name:        "set-replicas-scoped-api-version",
+			description: "setters scoped to an apiVersion only set the fields of resources with it",
+			setter:      "replicas",
+			openapi: `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "4"
+          kinds:
+          - Deployment
+          apiVersion: apps/v1
+ `,
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 3 # {"$ref": "#/definitions/io.k8s.cli.setters.replicas"}
+ `,
+			expected: `
+apiVersion: apps/v1
+kind: Deployment
 metadata:
   name: nginx-deployment
 spec:
@@ -703,6 +768,367 @@ spec:
 	}
 }
 
+func TestSet_Anchors(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected string
+		count    int
+	}{
+		{
+			name: "alias",
+			input: `
+apiVersion: example.com/v1
+kind: Example
+spec:
+  main:
+    replicas: &replicas 3 # {"$openapi":"replicas"}
+  sidecar:
+    replicas: *replicas
+`,
+			expected: `
+apiVersion: example.com/v1
+kind: Example
+spec:
+  main:
+    replicas: &replicas 4 # {"$openapi":"replicas"}
+  sidecar:
+    replicas: *replicas
+`,
+			count: 1,
+		},
+		{
+			name: "merge key",
+			input: `
+apiVersion: example.com/v1
+kind: Example
+spec:
+  defaults: &defaults
+    replicas: 3 # {"$openapi":"replicas"}
+  web:
+    <<: *defaults
+    name: web
+  worker:
+    <<: *defaults
+    replicas: 2 # {"$openapi":"replicas"}
+`,
+			// the merge key is tagged when it is written
+			expected: `
+apiVersion: example.com/v1
+kind: Example
+spec:
+  defaults: &defaults
+    replicas: 4 # {"$openapi":"replicas"}
+  web:
+    !!merge <<: *defaults
+    name: web
+  worker:
+    !!merge <<: *defaults
+    replicas: 4 # {"$openapi":"replicas"}
+`,
+			count: 2,
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			defer openapi.ResetOpenAPI()
+			initSchema(t, `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "4"
+`)
+			r, err := yaml.Parse(test.input)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			instance := &Set{Name: "replicas"}
+			result, err := instance.Filter(r)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			actual, err := result.String()
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+
+			// the fields are set once, at their anchor
+			assert.Equal(t, strings.TrimSpace(test.expected), strings.TrimSpace(actual))
+			assert.Equal(t, test.count, instance.Count)
+			assert.Equal(t, test.count, instance.Changed)
+		})
+	}
+}
+
+func TestSet_Style(t *testing.T) {
+	var tests = []struct {
+		name     string
+		style    string
+		setter   string
+		input    string
+		expected string
+		err      string
+	}{
+		{
+			name: "default keeps the style",
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  image: 'nginx:1.7' # {"$openapi":"image"}
+`,
+			expected: `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  image: 'nginx:1.8' # {"$openapi":"image"}
+`,
+		},
+		{
+			name:  "plain",
+			style: "plain",
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  image: "nginx:1.7" # {"$openapi":"image"}
+`,
+			expected: `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  image: nginx:1.8 # {"$openapi":"image"}
+`,
+		},
+		{
+			name:  "single",
+			style: "single",
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  image: nginx:1.7 # {"$openapi":"image"}
+`,
+			expected: `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  image: 'nginx:1.8' # {"$openapi":"image"}
+`,
+		},
+		{
+			name:  "double",
+			style: "double",
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  image: nginx:1.7 # {"$openapi":"image"}
+`,
+			expected: `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  image: "nginx:1.8" # {"$openapi":"image"}
+`,
+		},
+		{
+			name:   "plain int",
+			style:  "plain",
+			setter: "replicas",
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+`,
+			expected: `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  replicas: 4 # {"$openapi":"replicas"}
+`,
+		},
+		{
+			name:   "double int",
+			style:  "double",
+			setter: "replicas",
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+`,
+			err: "style double may only be used for string fields, field spec.replicas is !!int",
+		},
+		{
+			name:  "literal",
+			style: "literal",
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  image: nginx:1.7 # {"$openapi":"image"}
+`,
+			expected: `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  # {"$openapi":"image"}
+  image: |-
+    nginx:1.8
+`,
+		},
+		{
+			name:  "folded",
+			style: "folded",
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  image: nginx:1.7 # {"$openapi":"image"}
+`,
+			expected: `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  # {"$openapi":"image"}
+  image: >-
+    nginx:1.8
+`,
+		},
+		{
+			name:   "literal int",
+			style:  "literal",
+			setter: "replicas",
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+`,
+			err: "style literal may only be used for string fields, field spec.replicas is !!int",
+		},
+		{
+			name:   "single bool",
+			style:  "single",
+			setter: "enabled",
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  enabled: true # {"$openapi":"enabled"}
+`,
+			err: "style single may only be used for string fields, field spec.enabled is !!bool",
+		},
+		{
+			name:   "plain bool",
+			style:  "plain",
+			setter: "enabled",
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  enabled: true # {"$openapi":"enabled"}
+`,
+			expected: `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  enabled: false # {"$openapi":"enabled"}
+`,
+		},
+		{
+			name:  "unknown style",
+			style: "flow",
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  image: nginx:1.7 # {"$openapi":"image"}
+`,
+			err: "unknown style flow, must be one of double, folded, literal, plain, single",
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			// reset the openAPI afterward
+			defer openapi.ResetOpenAPI()
+			initSchema(t, `
+openAPI:
+  definitions:
+    io.k8s.cli.setters.image:
+      type: string
+      x-k8s-cli:
+        setter:
+          name: image
+          value: "nginx:1.8"
+    io.k8s.cli.setters.replicas:
+      type: integer
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "4"
+    io.k8s.cli.setters.enabled:
+      type: boolean
+      x-k8s-cli:
+        setter:
+          name: enabled
+          value: "false"
+`)
+
+			r, err := yaml.Parse(test.input)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			setter := test.setter
+			if setter == "" {
+				setter = "image"
+			}
+			instance := &Set{Name: setter, Style: test.style}
+			result, err := instance.Filter(r)
+			if test.err != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), test.err)
+				}
+				return
+			}
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			actual, err := result.String()
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			if !assert.Equal(t, strings.TrimSpace(test.expected), strings.TrimSpace(actual)) {
+				t.FailNow()
+			}
+
+			// the setter reference is read back, so the field is set again
+			r, err = yaml.Parse(actual)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			instance = &Set{Name: setter, Style: test.style}
+			result, err = instance.Filter(r)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, 1, instance.Count)
+			actual, err = result.String()
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, strings.TrimSpace(test.expected), strings.TrimSpace(actual))
+		})
+	}
+}
+
 func TestSet_SetAll(t *testing.T) {
 	var tests = []struct {
 		name        string
@@ -1297,6 +1723,9 @@ openAPI:
 
 func TestValidateAgainstSchema(t *testing.T) {
 	maxLength := int64(3)
+	maxPercentage := float64(100)
+	maxReplicas := float64(10)
+	minReplicas := float64(1)
 
 	testCases := []struct {
 		name             string
@@ -1448,6 +1877,97 @@ func TestValidateAgainstSchema(t *testing.T) {
 			},
 			shouldValidate: true,
 		},
+		{
+			name: "integer value within bounds",
+			setter: &setter{
+				Name:  "foo",
+				Value: "10",
+			},
+			schema: spec.SchemaProps{
+				Type:    []string{"integer"},
+				Maximum: &maxReplicas,
+				Minimum: &minReplicas,
+			},
+			shouldValidate: true,
+		},
+		{
+			name: "integer value exceeding the maximum",
+			setter: &setter{
+				Name:  "foo",
+				Value: "20",
+			},
+			schema: spec.SchemaProps{
+				Type:    []string{"integer"},
+				Maximum: &maxReplicas,
+			},
+			shouldValidate:   false,
+			expectedErrorMsg: "value 20 exceeds maximum 10 for setter foo",
+		},
+		{
+			name: "integer value below the minimum",
+			setter: &setter{
+				Name:  "foo",
+				Value: "0",
+			},
+			schema: spec.SchemaProps{
+				Type:    []string{"integer"},
+				Minimum: &minReplicas,
+			},
+			shouldValidate:   false,
+			expectedErrorMsg: "value 0 is below minimum 1 for setter foo",
+		},
+		{
+			name: "percentage value",
+			setter: &setter{
+				Name:  "foo",
+				Value: "25%",
+			},
+			schema: spec.SchemaProps{
+				Type:    []string{"string"},
+				Format:  PercentageType,
+				Maximum: &maxPercentage,
+			},
+			shouldValidate: true,
+		},
+		{
+			name: "percentage value exceeding the maximum",
+			setter: &setter{
+				Name:  "foo",
+				Value: "110%",
+			},
+			schema: spec.SchemaProps{
+				Type:    []string{"string"},
+				Format:  PercentageType,
+				Maximum: &maxPercentage,
+			},
+			shouldValidate:   false,
+			expectedErrorMsg: `value "110%" of setter foo exceeds 100%`,
+		},
+		{
+			name: "unbounded percentage value",
+			setter: &setter{
+				Name:  "foo",
+				Value: "110%",
+			},
+			schema: spec.SchemaProps{
+				Type:   []string{"string"},
+				Format: PercentageType,
+			},
+			shouldValidate: true,
+		},
+		{
+			name: "non-numeric percentage value",
+			setter: &setter{
+				Name:  "foo",
+				Value: "half%",
+			},
+			schema: spec.SchemaProps{
+				Type:   []string{"string"},
+				Format: PercentageType,
+			},
+			shouldValidate:   false,
+			expectedErrorMsg: `value "half%" of setter foo isn't a percentage -- e.g. 25%`,
+		},
 		{
 			name: "List values without any schema",
 			setter: &setter{
@@ -1457,6 +1977,55 @@ func TestValidateAgainstSchema(t *testing.T) {
 			schema:         spec.SchemaProps{},
 			shouldValidate: true,
 		},
+		{
+			name: "unique list values",
+			setter: &setter{
+				Name:       "foo",
+				ListValues: []string{"a", "b", "c"},
+			},
+			schema: spec.SchemaProps{
+				Type:        []string{"array"},
+				UniqueItems: true,
+			},
+			shouldValidate: true,
+		},
+		{
+			name: "duplicated list values",
+			setter: &setter{
+				Name:       "foo",
+				ListValues: []string{"a", "b", "c", "b", "a", "b"},
+			},
+			schema: spec.SchemaProps{
+				Type:        []string{"array"},
+				UniqueItems: true,
+			},
+			shouldValidate:   false,
+			expectedErrorMsg: "values of setter foo must be unique, found duplicates: b, a",
+		},
+		{
+			name: "duplicated list values of objects",
+			setter: &setter{
+				Name:       "foo",
+				ListValues: []string{`{"name":"FOO","value":"1"}`, `{"value":"1","name":"FOO"}`},
+			},
+			schema: spec.SchemaProps{
+				Type:        []string{"array"},
+				UniqueItems: true,
+			},
+			shouldValidate:   false,
+			expectedErrorMsg: `values of setter foo must be unique, found duplicates: {"value":"1","name":"FOO"}`,
+		},
+		{
+			name: "duplicated list values without uniqueItems",
+			setter: &setter{
+				Name:       "foo",
+				ListValues: []string{"a", "a"},
+			},
+			schema: spec.SchemaProps{
+				Type: []string{"array"},
+			},
+			shouldValidate: true,
+		},
 	}
 
 	for i := range testCases {
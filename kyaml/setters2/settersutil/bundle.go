@@ -0,0 +1,337 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package settersutil
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/fieldmeta"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+	"sigs.k8s.io/kustomize/kyaml/setters2"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// SetterBundleKind is the kind of a SetterBundle file.
+const SetterBundleKind = "SetterBundle"
+
+// SetterBundle is a portable representation of the setters and substitutions
+// of a package, and of the fields which reference them.
+type SetterBundle struct {
+	Kind string `yaml:"kind"`
+
+	// Definitions are the setter and substitution OpenAPI definitions keyed
+	// by their definition name -- e.g. io.k8s.cli.setters.replicas.
+	Definitions *yaml.Node `yaml:"definitions,omitempty"`
+
+	// Markers are the fields which reference the definitions.
+	Markers []SetterMarker `yaml:"markers,omitempty"`
+}
+
+// SetterMarker identifies a field which references a setter or substitution.
+type SetterMarker struct {
+	// Kind is the kind of the Resource containing the field.
+	Kind string `yaml:"kind"`
+
+	// Name is the name of the Resource containing the field.
+	Name string `yaml:"name"`
+
+	// Namespace is the namespace of the Resource containing the field.
+	Namespace string `yaml:"namespace,omitempty"`
+
+	// Path is the path to the field.  List elements are identified by
+	// their name -- e.g. [spec, containers, "[name=nginx]", image].
+	Path []string `yaml:"path,flow"`
+
+	// Ref is the OpenAPI reference to the setter or substitution
+	// -- e.g. #/definitions/io.k8s.cli.setters.replicas.
+	Ref string `yaml:"ref"`
+}
+
+// ExportSetters returns a SetterBundle containing the setter and substitution
+// definitions in the OpenAPI file, and the resource fields referencing them.
+func ExportSetters(openAPIPath, resourcesPath string) (*SetterBundle, error) {
+	if err := openapi.AddSchemaFromFile(openAPIPath); err != nil {
+		return nil, err
+	}
+	b := &SetterBundle{Kind: SetterBundleKind}
+
+	// copy the setter and substitution definitions
+	y, err := yaml.ReadFile(openAPIPath)
+	if err != nil {
+		return nil, err
+	}
+	definitions, err := y.Pipe(yaml.Lookup(openapi.SupplementaryOpenAPIFieldName, "definitions"))
+	if err != nil {
+		return nil, err
+	}
+	bundled := yaml.NewRNode(&yaml.Node{Kind: yaml.MappingNode})
+	if definitions != nil {
+		err = definitions.VisitFields(func(node *yaml.MapNode) error {
+			key := node.Key.YNode().Value
+			if !strings.HasPrefix(key, fieldmeta.SetterDefinitionPrefix) &&
+				!strings.HasPrefix(key, fieldmeta.SubstitutionDefinitionPrefix) {
+				return nil
+			}
+			return bundled.PipeE(yaml.SetField(key, node.Value))
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	b.Definitions = bundled.YNode()
+
+	// record the fields referencing the definitions
+	nodes, err := kio.LocalPackageReader{PackagePath: resourcesPath}.Read()
+	if err != nil {
+		return nil, err
+	}
+	for i := range nodes {
+		meta, err := nodes[i].GetMeta()
+		if err != nil {
+			return nil, err
+		}
+		err = walkMarkers(nodes[i], nil, func(path []string, field, _ *yaml.RNode) error {
+			fm := fieldmeta.FieldMeta{}
+			if err := fm.Read(field); err != nil {
+				return err
+			}
+			ref := fm.Schema.Ref.String()
+			if ref == "" {
+				return nil
+			}
+			b.Markers = append(b.Markers, SetterMarker{
+				Kind:      meta.Kind,
+				Name:      meta.Name,
+				Namespace: meta.Namespace,
+				Path:      path,
+				Ref:       ref,
+			})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// walkMarkers invokes fn for each node of object which may hold a setter
+// reference.  These are scalar field values, scalar list elements, and the keys
+// of list fields and of block scalar fields.  fn is also passed the value of
+// the field -- e.g. the list, for the keys of list fields.
+func walkMarkers(object *yaml.RNode, path []string, fn func([]string, *yaml.RNode, *yaml.RNode) error) error {
+	switch object.YNode().Kind {
+	case yaml.MappingNode:
+		return object.VisitFields(func(node *yaml.MapNode) error {
+			p := append(append([]string{}, path...), node.Key.YNode().Value)
+			if setters2.IsBlockScalar(node.Value.YNode()) {
+				// block scalar setter references are on the field key
+				return fn(p, node.Key, node.Value)
+			}
+			if node.Value.YNode().Kind == yaml.SequenceNode {
+				// list setter references are on the field key
+				if err := fn(p, node.Key, node.Value); err != nil {
+					return err
+				}
+			}
+			return walkMarkers(node.Value, p, fn)
+		})
+	case yaml.SequenceNode:
+		return object.VisitElements(func(node *yaml.RNode) error {
+			// elements must be addressable by name or value to be portable
+			var index string
+			switch node.YNode().Kind {
+			case yaml.MappingNode:
+				name := node.Field("name")
+				if name == nil || name.Value.YNode().Kind != yaml.ScalarNode {
+					return nil
+				}
+				index = "[name=" + name.Value.YNode().Value + "]"
+			case yaml.ScalarNode:
+				index = "[=" + node.YNode().Value + "]"
+			default:
+				return nil
+			}
+			return walkMarkers(node, append(append([]string{}, path...), index), fn)
+		})
+	case yaml.ScalarNode:
+		return fn(path, object, object)
+	}
+	return nil
+}
+
+// DefinitionCount returns the number of definitions in the SetterBundle.
+func (b SetterBundle) DefinitionCount() int {
+	if b.Definitions == nil {
+		return 0
+	}
+	return len(b.Definitions.Content) / 2
+}
+
+// ReadSetterBundle reads a SetterBundle from the file at path.
+func ReadSetterBundle(path string) (*SetterBundle, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	b := &SetterBundle{}
+	if err := yaml.Unmarshal(data, b); err != nil {
+		return nil, errors.WrapPrefixf(err, "unable to parse setter bundle %s", path)
+	}
+	if b.Kind != SetterBundleKind {
+		return nil, errors.Errorf("%s is not a %s", path, SetterBundleKind)
+	}
+
+	// yaml.Unmarshal leaves *yaml.Node fields empty, so the definitions are
+	// read from the parsed file
+	object, err := yaml.Parse(string(data))
+	if err != nil {
+		return nil, errors.WrapPrefixf(err, "unable to parse setter bundle %s", path)
+	}
+	b.Definitions = nil
+	if definitions := object.Field("definitions"); definitions != nil {
+		b.Definitions = definitions.Value.YNode()
+	}
+	return b, nil
+}
+
+// WriteFile writes the SetterBundle to the file at path.
+func (b SetterBundle) WriteFile(path string) error {
+	var out bytes.Buffer
+	e := yaml.NewEncoder(&out)
+	if err := e.Encode(b); err != nil {
+		return errors.Wrap(err)
+	}
+	if err := e.Close(); err != nil {
+		return errors.Wrap(err)
+	}
+	return ioutil.WriteFile(path, out.Bytes(), 0600)
+}
+
+// Import adds the definitions from the SetterBundle to the OpenAPI file and
+// references them from the resource fields identified by the markers.
+// Returns the number of markers applied, and the markers which could not be
+// applied because their resource or field does not exist.
+func (b SetterBundle) Import(openAPIPath, resourcesPath string) (int, []SetterMarker, error) {
+	// add the definitions to the OpenAPI file
+	if b.Definitions != nil {
+		err := yaml.UpdateFile(yaml.FilterFunc(func(object *yaml.RNode) (*yaml.RNode, error) {
+			definitions, err := object.Pipe(yaml.LookupCreate(
+				yaml.MappingNode, openapi.SupplementaryOpenAPIFieldName, "definitions"))
+			if err != nil {
+				return nil, err
+			}
+			err = yaml.NewRNode(b.Definitions).VisitFields(func(node *yaml.MapNode) error {
+				return definitions.PipeE(yaml.SetField(node.Key.YNode().Value, node.Value))
+			})
+			return object, err
+		}), openAPIPath)
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+
+	// Load the updated definitions
+	if err := openapi.AddSchemaFromFile(openAPIPath); err != nil {
+		return 0, nil, err
+	}
+
+	// add the references to the resource fields
+	applied := map[int]bool{}
+	inout := &kio.LocalPackageReadWriter{PackagePath: resourcesPath, NoDeleteFiles: true}
+	err := kio.Pipeline{
+		Inputs: []kio.Reader{inout},
+		Filters: []kio.Filter{kio.FilterFunc(func(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
+			for i := range nodes {
+				meta, err := nodes[i].GetMeta()
+				if err != nil {
+					return nil, err
+				}
+				for j, m := range b.Markers {
+					if m.Kind != meta.Kind || m.Name != meta.Name || m.Namespace != meta.Namespace {
+						continue
+					}
+					ok, err := m.apply(nodes[i])
+					if err != nil {
+						return nil, err
+					}
+					if ok {
+						applied[j] = true
+					}
+				}
+			}
+			return nodes, nil
+		})},
+		Outputs: []kio.Writer{inout},
+	}.Execute()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var unapplied []SetterMarker
+	for j := range b.Markers {
+		if !applied[j] {
+			unapplied = append(unapplied, b.Markers[j])
+		}
+	}
+	return len(applied), unapplied, nil
+}
+
+// apply adds the marker reference to the field in object.  Returns false if
+// the field does not exist.
+func (m SetterMarker) apply(object *yaml.RNode) (bool, error) {
+	if len(m.Path) == 0 {
+		return false, nil
+	}
+	last := m.Path[len(m.Path)-1]
+
+	var field *yaml.RNode
+	if yaml.IsListIndex(last) {
+		elem, err := object.Pipe(yaml.Lookup(m.Path...))
+		if err != nil {
+			return false, err
+		}
+		field = elem
+	} else {
+		parent, err := object.Pipe(yaml.Lookup(m.Path[:len(m.Path)-1]...))
+		if err != nil {
+			return false, err
+		}
+		if parent == nil || parent.YNode().Kind != yaml.MappingNode {
+			return false, nil
+		}
+		node := parent.Field(last)
+		if node != nil {
+			// list and block scalar setter references are on the field key
+			field = node.Value
+			if node.Value.YNode().Kind == yaml.SequenceNode ||
+				setters2.IsBlockScalar(node.Value.YNode()) {
+				field = node.Key
+			}
+		}
+	}
+	if field == nil {
+		return false, nil
+	}
+
+	fm := fieldmeta.FieldMeta{}
+	if err := fm.Read(field); err != nil {
+		return false, err
+	}
+	ref, err := spec.NewRef(m.Ref)
+	if err != nil {
+		return false, err
+	}
+	fm.Schema.Ref = ref
+	if err := fm.Write(field); err != nil {
+		return false, err
+	}
+	setters2.MoveBlockScalarRefs(object.YNode())
+	return true, nil
+}
@@ -7,6 +7,8 @@ import (
 	"io/ioutil"
 	"os"
 
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/fieldmeta"
 	"sigs.k8s.io/kustomize/kyaml/kio"
 	"sigs.k8s.io/kustomize/kyaml/openapi"
 	"sigs.k8s.io/kustomize/kyaml/setters2"
@@ -30,6 +32,49 @@ type FieldSetter struct {
 
 	Count int
 
+	// Changed is set to true by Set if the OpenAPI definitions or any of the
+	// resources were modified.  It is false if the setter already had Value.
+	Changed bool
+
+	// DivergentValues is set by Set to the distinct values held by the fields
+	// referencing the setter if they differed from one another before being set.
+	DivergentValues []string
+
+	// ChangedFields is set by Set to the number of fields whose value was
+	// modified.  Fields which already had Value are counted by Count only.
+	ChangedFields int
+
+	// DryRun computes the fields which would be set without writing the
+	// OpenAPI definitions or the resources.
+	DryRun bool
+
+	// Style, if set, is the name of the style the fields are written in --
+	// see setters2.ScalarStyles.  If unset, the style of the fields is kept.
+	Style string
+
+	// FieldFilter, if set, is a glob which the path of a field must match for
+	// it to be set -- see setters2.MatchFieldPath.  The setter definition is
+	// updated regardless.
+	FieldFilter string
+
+	// Record appends the change of value, and who made it, to the history of
+	// the setter in the OpenAPI definitions.
+	Record bool
+
+	// MaxHistory, if positive, caps the number of entries in the history of
+	// the setter.
+	MaxHistory int
+
+	// PackageFileName, if set, identifies subpackages by the presence of this
+	// file.  Resources in subpackages of the resources path are not set.
+	PackageFileName string
+
+	// OutputDir, if set, is the directory the resources are written to, at
+	// their paths relative to the resources path, which is left unchanged.
+	// All of the resources are written, not only those whose fields were set.
+	// The OpenAPI definitions are updated in place.
+	OutputDir string
+
 	OpenAPIPath string
 
 	ResourcesPath string
@@ -40,8 +85,17 @@ func (fs *FieldSetter) Filter(input []*yaml.RNode) ([]*yaml.RNode, error) {
 	return nil, nil
 }
 
-// Set updates the OpenAPI definitions and resources with the new setter value
-func (fs FieldSetter) Set(openAPIPath, resourcesPath string) (int, error) {
+// Set updates the OpenAPI definitions and resources with the new setter value.
+// Files which already contain the new value are not written.
+func (fs *FieldSetter) Set(openAPIPath, resourcesPath string) (int, error) {
+	// reject values which would violate the constraints between setters
+	if len(fs.ListValues) == 0 {
+		_, err := setters2.CheckConstraints(openAPIPath, map[string]string{fs.Name: fs.Value})
+		if err != nil {
+			return 0, err
+		}
+	}
+
 	// Update the OpenAPI definitions
 	soa := setters2.SetOpenAPI{
 		Name:        fs.Name,
@@ -49,6 +103,8 @@ func (fs FieldSetter) Set(openAPIPath, resourcesPath string) (int, error) {
 		ListValues:  fs.ListValues,
 		Description: fs.Description,
 		SetBy:       fs.SetBy,
+		Record:      fs.Record,
+		MaxHistory:  fs.MaxHistory,
 	}
 
 	// the input field value is updated in the openAPI file and then parsed
@@ -65,8 +121,26 @@ func (fs FieldSetter) Set(openAPIPath, resourcesPath string) (int, error) {
 		return 0, err
 	}
 
+	if fs.DryRun {
+		// update a copy of the definitions, leaving the OpenAPI file as is
+		f, err := ioutil.TempFile("", "openapi")
+		if err != nil {
+			return 0, err
+		}
+		defer os.Remove(f.Name())
+		_, err = f.Write(curOpenAPI)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return 0, err
+		}
+		openAPIPath = f.Name()
+	}
+
 	// write the new input value to openAPI file
-	if err := soa.UpdateFile(openAPIPath); err != nil {
+	openAPIChanged, err := updateFileIfChanged(soa, openAPIPath)
+	if err != nil {
 		return 0, err
 	}
 
@@ -78,23 +152,121 @@ func (fs FieldSetter) Set(openAPIPath, resourcesPath string) (int, error) {
 	// Update the resources with the new value
 	// Set NoDeleteFiles to true as SetAll will return only the nodes of files which should be updated and
 	// hence, rest of the files should not be deleted
-	inout := &kio.LocalPackageReadWriter{PackagePath: resourcesPath, NoDeleteFiles: true}
-	s := &setters2.Set{Name: fs.Name}
-	err = kio.Pipeline{
+	inout := &kio.LocalPackageReadWriter{
+		PackagePath:     resourcesPath,
+		PackageFileName: fs.PackageFileName,
+		NoDeleteFiles:   true,
+	}
+	s := &setters2.Set{Name: fs.Name, Style: fs.Style, FieldFilter: fs.FieldFilter}
+	p := kio.Pipeline{
 		Inputs:  []kio.Reader{inout},
 		Filters: []kio.Filter{setters2.SetAll(s)},
-		Outputs: []kio.Writer{inout},
-	}.Execute()
+	}
+	switch {
+	case fs.DryRun:
+		// the resources aren't written
+	case fs.OutputDir != "":
+		// mirror all of the resources, rather than only those of the files
+		// which were changed
+		p.Filters = []kio.Filter{kio.FilterFunc(func(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
+			_, err := setters2.SetAll(s).Filter(nodes)
+			return nodes, err
+		})}
+		p.Outputs = []kio.Writer{kio.LocalPackageWriter{PackagePath: fs.OutputDir}}
+		err = os.MkdirAll(fs.OutputDir, 0700)
+	default:
+		p.Outputs = []kio.Writer{inout}
+	}
+	if err == nil {
+		err = p.Execute()
+	}
 
 	// revert openAPI file if set operation fails
-	if err != nil {
+	if err != nil && openAPIChanged {
 		if writeErr := ioutil.WriteFile(openAPIPath, curOpenAPI, stat.Mode().Perm()); writeErr != nil {
 			return 0, writeErr
 		}
 	}
+	fs.Changed = openAPIChanged || s.Changed > 0
+	fs.ChangedFields = s.Changed
+	fs.DivergentValues = s.DivergentValues(fs.Name)
 	return s.Count, err
 }
 
+// Unset clears the value of the setter in the OpenAPI definitions.  If the
+// setter has a default, the fields referencing it are reverted to the default.
+// Returns the setter definition prior to being unset, and the number of fields
+// reverted.
+func (fs *FieldSetter) Unset(openAPIPath, resourcesPath string) (setters2.SetterDefinition, int, error) {
+	l := setters2.List{Name: fs.Name}
+	if err := l.ListSetters(openAPIPath, resourcesPath); err != nil {
+		return setters2.SetterDefinition{}, 0, err
+	}
+	if len(l.Setters) == 0 {
+		return setters2.SetterDefinition{}, 0, errors.Errorf("no setter %s found", fs.Name)
+	}
+	def := l.Setters[0]
+
+	// revert the fields to the default
+	var count int
+	if def.Default != "" {
+		revert := FieldSetter{Name: fs.Name, Value: def.Default}
+		var err error
+		if count, err = revert.Set(openAPIPath, resourcesPath); err != nil {
+			return def, 0, err
+		}
+	}
+
+	// clear the value, and who set it
+	err := yaml.UpdateFile(yaml.FilterFunc(func(object *yaml.RNode) (*yaml.RNode, error) {
+		setter, err := object.Pipe(yaml.Lookup(openapi.SupplementaryOpenAPIFieldName,
+			"definitions", fieldmeta.SetterDefinitionPrefix+fs.Name,
+			setters2.K8sCliExtensionKey, "setter"))
+		if err != nil || setter == nil {
+			return object, err
+		}
+		v := yaml.NewScalarRNode("")
+		v.YNode().Tag = yaml.StringTag
+		v.YNode().Style = yaml.DoubleQuotedStyle
+		if err := setter.PipeE(&yaml.FieldSetter{Name: "value", Value: v}); err != nil {
+			return nil, err
+		}
+		if err := setter.PipeE(yaml.Clear("listValues")); err != nil {
+			return nil, err
+		}
+		return object, setter.PipeE(yaml.Clear("setBy"))
+	}), openAPIPath)
+	if err != nil {
+		return def, 0, err
+	}
+	fs.Changed = true
+	return def, count, nil
+}
+
+// updateFileIfChanged applies filter to the yaml file at path and writes the
+// result back only if the filter modified it.  Returns true if the file was written.
+func updateFileIfChanged(filter yaml.Filter, path string) (bool, error) {
+	y, err := yaml.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	before, err := y.String()
+	if err != nil {
+		return false, err
+	}
+	if err := y.PipeE(filter); err != nil {
+		return false, err
+	}
+	after, err := y.String()
+	if err != nil {
+		return false, err
+	}
+	if before == after {
+		return false, nil
+	}
+	return true, yaml.WriteFile(y, path)
+}
+
 // SetAllSetterDefinitions reads all the Setter Definitions from the OpenAPI
 // file and sets all values in the provided directories.
 func SetAllSetterDefinitions(openAPIPath string, dirs ...string) error {
@@ -0,0 +1,150 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package settersutil
+
+import (
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/fieldmeta"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/kio/kioutil"
+	"sigs.k8s.io/kustomize/kyaml/krmfile"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+	"sigs.k8s.io/kustomize/kyaml/setters2"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// SetterReference identifies a field which would be changed by setting a setter.
+type SetterReference struct {
+	// File is the path to the file containing the Resource, relative to the package.
+	File string `json:"file" yaml:"file"`
+
+	// Kind is the kind of the Resource containing the field.
+	Kind string `json:"kind" yaml:"kind"`
+
+	// Name is the name of the Resource containing the field.
+	Name string `json:"name" yaml:"name"`
+
+	// Namespace is the namespace of the Resource containing the field.
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+
+	// Field is the path to the field -- e.g. spec.template.spec.containers.[name=nginx].image
+	Field string `json:"field" yaml:"field"`
+
+	// Substitution is the name of the substitution through which the field
+	// references the setter, if it doesn't reference the setter directly.
+	Substitution string `json:"substitution,omitempty" yaml:"substitution,omitempty"`
+
+	// Value is the current value of the field, if it isn't a list.
+	Value string `json:"-" yaml:"-"`
+
+	// ListValues are the current values of the items of the field, if it is
+	// a list.
+	ListValues []string `json:"-" yaml:"-"`
+}
+
+// SetterReferences returns the fields of the Resources in resourcesPath which
+// reference the setter name, either directly or through a substitution.
+// Resources in subpackages of resourcesPath are not read.
+func SetterReferences(openAPIPath, resourcesPath, name string) ([]SetterReference, error) {
+	if err := openapi.AddSchemaFromFile(openAPIPath); err != nil {
+		return nil, err
+	}
+	nodes, err := kio.LocalPackageReader{
+		PackagePath:     resourcesPath,
+		PackageFileName: krmfile.KrmfileName,
+	}.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	setterRef := fieldmeta.DefinitionsPrefix + fieldmeta.SetterDefinitionPrefix + name
+	var refs []SetterReference
+	for i := range nodes {
+		meta, err := nodes[i].GetMeta()
+		if err != nil {
+			return nil, err
+		}
+		err = walkMarkers(nodes[i], nil, func(path []string, field, value *yaml.RNode) error {
+			fm := fieldmeta.FieldMeta{}
+			if err := fm.Read(field); err != nil {
+				return err
+			}
+			ref := fm.Schema.Ref.String()
+			if ref == "" {
+				return nil
+			}
+			r := SetterReference{
+				File:      meta.Annotations[kioutil.PathAnnotation],
+				Kind:      meta.Kind,
+				Name:      meta.Name,
+				Namespace: meta.Namespace,
+				Field:     strings.Join(path, "."),
+			}
+			if value.YNode().Kind == yaml.SequenceNode {
+				var values setters2.ListValues
+				if err := values.UnmarshalYAML(value.YNode()); err != nil {
+					return errors.WrapPrefixf(err, "%s %s", r.File, r.Field)
+				}
+				// non-nil, even for empty lists
+				r.ListValues = append([]string{}, values...)
+			} else {
+				r.Value = value.YNode().Value
+			}
+			if ref != setterRef {
+				found, err := substitutionReferences(fm.Schema.Ref, setterRef, map[string]bool{})
+				if err != nil || !found {
+					return err
+				}
+				r.Substitution = strings.TrimPrefix(ref,
+					fieldmeta.DefinitionsPrefix+fieldmeta.SubstitutionDefinitionPrefix)
+			}
+			refs = append(refs, r)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return refs, nil
+}
+
+// substitutionReferences returns true if ref is a substitution whose values
+// reference setterRef, directly or through nested substitutions.
+func substitutionReferences(ref spec.Ref, setterRef string, visited map[string]bool) (bool, error) {
+	if visited[ref.String()] {
+		return false, errors.Errorf("cyclic substitution detected with ref %s", ref.String())
+	}
+	visited[ref.String()] = true
+	defer delete(visited, ref.String())
+
+	def, err := openapi.Resolve(&ref)
+	if err != nil {
+		// not a definition known to the package -- e.g. a Resource schema
+		return false, nil
+	}
+	ext, err := setters2.GetExtFromSchema(def)
+	if err != nil {
+		return false, err
+	}
+	if ext == nil || ext.Substitution == nil {
+		return false, nil
+	}
+	for _, v := range ext.Substitution.Values {
+		if v.Ref == setterRef {
+			return true, nil
+		}
+		valueRef, err := spec.NewRef(v.Ref)
+		if err != nil {
+			return false, errors.Wrap(err)
+		}
+		found, err := substitutionReferences(valueRef, setterRef, visited)
+		if err != nil || found {
+			return found, err
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,127 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package settersutil
+
+import (
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/fieldmeta"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+	"sigs.k8s.io/kustomize/kyaml/setters2"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// SetterPromoter promotes a marker of a substitution into a setter of its own:
+// it creates the setter if it doesn't exist, and references it from the
+// substitution value with the marker.  The resources are left as they are.
+type SetterPromoter struct {
+	// Substitution is the name of the substitution containing the marker.
+	Substitution string
+
+	// Marker is the marker to promote -- e.g. ${tag}.
+	Marker string
+
+	// Name is the name of the setter.  Defaults to the marker without its
+	// ${ and } delimiters -- e.g. tag for ${tag}.
+	Name string
+
+	// Value is the value of the setter if it is created.  Defaults to the
+	// value of the setter the marker currently references.
+	Value string
+
+	// Description is the description of the setter if it is created.
+	Description string
+
+	// Type is the OpenAPI type of the setter if it is created.
+	Type string
+
+	// Created is set by Promote to true if the setter was created, and false
+	// if the marker was referenced to an existing setter.
+	Created bool
+}
+
+// Promote updates the OpenAPI definitions in openAPIPath.
+func (p *SetterPromoter) Promote(openAPIPath string) error {
+	object, err := yaml.ReadFile(openAPIPath)
+	if err != nil {
+		return err
+	}
+	definitions, err := object.Pipe(yaml.Lookup(
+		openapi.SupplementaryOpenAPIFieldName, "definitions"))
+	if err != nil {
+		return err
+	}
+	var values *yaml.RNode
+	if definitions != nil {
+		values, err = definitions.Pipe(yaml.Lookup(
+			fieldmeta.SubstitutionDefinitionPrefix+p.Substitution,
+			setters2.K8sCliExtensionKey, "substitution", "values"))
+		if err != nil {
+			return err
+		}
+	}
+	if values == nil {
+		return errors.Errorf("no substitution %s found", p.Substitution)
+	}
+	value, err := values.Pipe(yaml.MatchElement("marker", p.Marker))
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		return errors.Errorf("substitution %s has no marker %s", p.Substitution, p.Marker)
+	}
+
+	if p.Name == "" {
+		p.Name = strings.TrimSuffix(strings.TrimPrefix(p.Marker, "${"), "}")
+	}
+	if definitions.Field(fieldmeta.SubstitutionDefinitionPrefix+p.Name) != nil {
+		return errors.Errorf("substitution with name %s already exists, "+
+			"substitution and setter can't have same name", p.Name)
+	}
+	p.Created = definitions.Field(fieldmeta.SetterDefinitionPrefix+p.Name) == nil
+	if p.Created {
+		if p.Value == "" {
+			p.Value, err = referencedValue(definitions, value)
+			if err != nil {
+				return err
+			}
+		}
+		if p.Value == "" {
+			return errors.Errorf("marker %s doesn't reference a setter with a value, "+
+				"the value of setter %s must be specified", p.Marker, p.Name)
+		}
+		sd := setters2.SetterDefinition{
+			Name: p.Name, Value: p.Value, Description: p.Description, Type: p.Type,
+		}
+		if err := object.PipeE(sd); err != nil {
+			return err
+		}
+	}
+
+	ref := yaml.NewScalarRNode(fieldmeta.DefinitionsPrefix + fieldmeta.SetterDefinitionPrefix + p.Name)
+	ref.YNode().Style = yaml.SingleQuotedStyle
+	if err := value.PipeE(yaml.SetField("ref", ref)); err != nil {
+		return err
+	}
+	return yaml.WriteFile(object, openAPIPath)
+}
+
+// referencedValue returns the value of the setter referenced by the
+// substitution value, or the empty string if it doesn't reference a setter.
+func referencedValue(definitions, value *yaml.RNode) (string, error) {
+	ref := value.Field("ref")
+	if ref == nil {
+		return "", nil
+	}
+	key := strings.TrimPrefix(ref.Value.YNode().Value, fieldmeta.DefinitionsPrefix)
+	if !strings.HasPrefix(key, fieldmeta.SetterDefinitionPrefix) {
+		return "", nil
+	}
+	v, err := definitions.Pipe(yaml.Lookup(key, setters2.K8sCliExtensionKey, "setter", "value"))
+	if err != nil || v == nil {
+		return "", err
+	}
+	return v.YNode().Value, nil
+}
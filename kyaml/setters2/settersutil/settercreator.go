@@ -6,10 +6,10 @@ package settersutil
 import (
 	"io/ioutil"
 
-	"sigs.k8s.io/kustomize/kyaml/fieldmeta"
 	"sigs.k8s.io/kustomize/kyaml/kio"
-	"sigs.k8s.io/kustomize/kyaml/openapi"
+	"sigs.k8s.io/kustomize/kyaml/kio/kioutil"
 	"sigs.k8s.io/kustomize/kyaml/setters2"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
 // SetterCreator creates or updates a setter in the OpenAPI definitions, and inserts references
@@ -22,71 +22,130 @@ type SetterCreator struct {
 
 	SetBy string
 
+	// Type is the type of the setter value.  If neither Type nor SchemaPath
+	// are set, the type is inferred from the values of the matching fields --
+	// see setters2.InferType.
 	Type string
 
 	SchemaPath string
 
-	// FieldName if set will add the OpenAPI reference to fields with this name or path
-	// FieldName may be the full name of the field, full path to the field, or the path suffix.
-	// e.g. all of the following would match spec.template.spec.containers.image --
-	// [image, containers.image, spec.containers.image, template.spec.containers.image,
-	//  spec.template.spec.containers.image]
+	// Pattern if set is a regular expression which the setter value must match.
+	Pattern string
+
+	// Enum if set are the values which the setter may have.
+	Enum []string
+
+	// Kinds, if set, are the kinds of the resources whose fields may reference
+	// the setter -- e.g. Deployment.  They are recorded in the definition, so
+	// that set only sets the fields of resources of the kinds.
+	Kinds []string
+
+	// APIVersion, if set, is the apiVersion of the resources whose fields may
+	// reference the setter -- e.g. apps/v1.  It is recorded like Kinds.
+	APIVersion string
+
+	// UniqueItems requires the values of a list setter to be unique.
+	UniqueItems bool
+
+	// Unbounded allows the values of a percentage setter to exceed 100%.
+	Unbounded bool
+
+	// Force updates the definition of the setter if it already exists, rather
+	// than returning an error.  The description, type and setBy of the
+	// existing setter are kept unless Description, Type or SetBy are set.
+	Force bool
+
+	// Required marks the setter as one which the users of the package must set.
+	// FieldValue is recorded as the default of the setter -- the placeholder
+	// which the users must replace -- and so isn't validated against the schema.
+	Required bool
+
+	// FieldName if set will add the OpenAPI reference to fields with this name or path.
+	// FieldName may be the name of the field, or the full path to the field
+	// from the root of the resource, optionally with list element selectors.
 	// Optional.  If unspecified match all field names.
+	// If FieldName is a path, only the fields at that path are matched -- see
+	// setters2.Add.
 	FieldName string
 
 	// FieldValue if set will add the OpenAPI reference to fields if they have this value.
 	// Optional.  If unspecified match all field values.
+	// FieldValue is only the value of the setter if FieldName is a path.
 	FieldValue string
-}
 
-func (c SetterCreator) Create(openAPIPath, resourcesPath string) error {
-	schema, err := schemaFromFile(c.SchemaPath)
-	if err != nil {
-		return err
-	}
-	// Update the OpenAPI definitions to hace the setter
-	sd := setters2.SetterDefinition{
-		Name: c.Name, Value: c.FieldValue, Description: c.Description, SetBy: c.SetBy,
-		Type: c.Type, Schema: schema,
-	}
-	if err := sd.AddToFile(openAPIPath); err != nil {
-		return err
-	}
+	// Partial creates a setter for only the part of the values of the
+	// FieldName fields which is FieldValue, and a substitution of it into the
+	// rest of their values, which the fields reference -- see
+	// setters2.CreateSetter.
+	Partial bool
 
-	// Load the updated definitions
-	if err := openapi.AddSchemaFromFile(openAPIPath); err != nil {
-		return err
-	}
+	// PackageFileName, if set, identifies subpackages by the presence of this
+	// file.  Fields in subpackages of the resources path aren't added a
+	// reference to the setter.
+	PackageFileName string
 
+	// References are set by Create to the fields which were added a reference
+	// to the setter.
+	References []SetterReference
+}
+
+// Create creates the setter in the OpenAPI definitions, and adds references
+// to it to the matching fields of the resources in resourcesPath -- see
+// setters2.CreateSetter.
+func (c *SetterCreator) Create(openAPIPath, resourcesPath string) error {
 	// Update the resources with the setter reference
-	inout := &kio.LocalPackageReadWriter{PackagePath: resourcesPath}
-	a := &setters2.Add{
-		FieldName:  c.FieldName,
-		FieldValue: c.FieldValue,
-		Ref:        fieldmeta.DefinitionsPrefix + fieldmeta.SetterDefinitionPrefix + c.Name,
-		Type:       c.Type,
+	inout := &kio.LocalPackageReadWriter{
+		PackagePath:     resourcesPath,
+		PackageFileName: c.PackageFileName,
 	}
-	err = kio.Pipeline{
-		Inputs:  []kio.Reader{inout},
-		Filters: []kio.Filter{kio.FilterAll(a)},
-		Outputs: []kio.Writer{inout},
-	}.Execute()
-
+	nodes, err := inout.Read()
 	if err != nil {
 		return err
 	}
-
-	// if the setter is of array type write the derived list values back to
-	// openAPI definitions
-	if len(a.ListValues) > 0 {
-		sd.ListValues = a.ListValues
-		sd.Value = ""
-		if err := sd.AddToFile(openAPIPath); err != nil {
-			return err
-		}
+	// the setter is created even if there are no resources, which a
+	// kio.Pipeline would skip its filters for
+	nodes, err = c.CreateFilter(openAPIPath).Filter(nodes)
+	if err != nil || len(nodes) == 0 {
+		return err
 	}
+	return inout.Write(nodes)
+}
 
-	return nil
+// CreateFilter returns a filter which creates the setter in the OpenAPI
+// definitions, and adds references to it to the matching fields of the
+// resources it is given -- e.g. resources read from stdin.
+func (c *SetterCreator) CreateFilter(openAPIPath string) kio.Filter {
+	c.References = nil
+	return kio.FilterFunc(func(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
+		schema, err := schemaFromFile(c.SchemaPath)
+		if err != nil {
+			return nil, err
+		}
+		sd := setters2.SetterDefinition{
+			Name: c.Name, Value: c.FieldValue, Description: c.Description, SetBy: c.SetBy,
+			Type: c.Type, Schema: schema, Pattern: c.Pattern, Unbounded: c.Unbounded,
+			Enum: c.Enum, Required: c.Required, Field: c.FieldName, Force: c.Force,
+			Partial: c.Partial, UniqueItems: c.UniqueItems, Kinds: c.Kinds, APIVersion: c.APIVersion,
+		}
+		refs, err := setters2.CreateSetter(openAPIPath, nodes, sd)
+		if err != nil {
+			return nil, err
+		}
+		for _, ref := range refs {
+			meta, err := ref.Resource.GetMeta()
+			if err != nil {
+				return nil, err
+			}
+			c.References = append(c.References, SetterReference{
+				File:      meta.Annotations[kioutil.PathAnnotation],
+				Kind:      meta.Kind,
+				Name:      meta.Name,
+				Namespace: meta.Namespace,
+				Field:     ref.Field,
+			})
+		}
+		return nodes, nil
+	})
 }
 
 // schemaFromFile reads the contents from schemaPath and returns schema
@@ -0,0 +1,66 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package settersutil
+
+import (
+	"sigs.k8s.io/kustomize/kyaml/fieldmeta"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+	"sigs.k8s.io/kustomize/kyaml/setters2"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// SetterDeleter deletes a setter from the OpenAPI definitions, and removes the
+// references to it from the resource fields, leaving their values as they are.
+type SetterDeleter struct {
+	// Name is the name of the setter to delete.
+	Name string
+
+	// PackageFileName, if set, identifies subpackages by the presence of this
+	// file.  References in subpackages of the resources path aren't removed.
+	PackageFileName string
+
+	// Count is set by Delete to the number of fields whose reference to the
+	// setter was removed.
+	Count int
+}
+
+// Delete deletes the setter from the OpenAPI definitions and removes the
+// references to it from the resources.  Nothing is changed if the setter isn't
+// defined, or is referenced by a substitution.
+func (d *SetterDeleter) Delete(openAPIPath, resourcesPath string) error {
+	if err := (setters2.DeleteDefinition{Name: d.Name}).DeleteFromFile(openAPIPath); err != nil {
+		return err
+	}
+
+	inout := &kio.LocalPackageReadWriter{
+		PackagePath:     resourcesPath,
+		PackageFileName: d.PackageFileName,
+	}
+	del := &setters2.Delete{Name: d.Name}
+	err := kio.Pipeline{
+		Inputs:  []kio.Reader{inout},
+		Filters: []kio.Filter{kio.FilterAll(del)},
+		Outputs: []kio.Writer{inout},
+	}.Execute()
+	d.Count = del.Count
+	return err
+}
+
+// IsDefined returns true if the setter is defined in the OpenAPI file.
+func (d *SetterDeleter) IsDefined(openAPIPath string) (bool, error) {
+	def, err := lookupSetterDefinition(openAPIPath, d.Name)
+	return def != nil, err
+}
+
+// lookupSetterDefinition returns the definition of the setter in the OpenAPI
+// file, or nil if it isn't defined.
+func lookupSetterDefinition(openAPIPath, name string) (*yaml.RNode, error) {
+	object, err := yaml.ReadFile(openAPIPath)
+	if err != nil {
+		return nil, err
+	}
+	return object.Pipe(yaml.Lookup(
+		openapi.SupplementaryOpenAPIFieldName, "definitions", fieldmeta.SetterDefinitionPrefix+name))
+}
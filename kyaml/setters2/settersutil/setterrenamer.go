@@ -0,0 +1,74 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package settersutil
+
+import (
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/setters2"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// SetterRenamer renames a setter in the OpenAPI definitions, and changes the
+// references to it from the resource fields to the new name.
+type SetterRenamer struct {
+	// Name is the current name of the setter.
+	Name string
+
+	// NewName is the name to rename the setter to.
+	NewName string
+
+	// PackageFileName, if set, identifies subpackages by the presence of this
+	// file.  References in subpackages of the resources path aren't changed.
+	PackageFileName string
+
+	// Count is set by Rename to the number of references to the setter which
+	// were changed.
+	Count int
+}
+
+// Rename renames the setter in the OpenAPI definitions and the resources.
+// Nothing is written if the setter can't be renamed -- e.g. the new name is
+// already defined.
+func (r *SetterRenamer) Rename(openAPIPath, resourcesPath string) error {
+	if err := r.Check(openAPIPath); err != nil {
+		return err
+	}
+
+	inout := &kio.LocalPackageReadWriter{
+		PackagePath:     resourcesPath,
+		PackageFileName: r.PackageFileName,
+	}
+	rename := &setters2.Rename{Name: r.Name, NewName: r.NewName}
+	err := kio.Pipeline{
+		Inputs:  []kio.Reader{inout},
+		Filters: []kio.Filter{kio.FilterAll(rename)},
+		Outputs: []kio.Writer{inout},
+	}.Execute()
+	r.Count = rename.Count
+	if err != nil {
+		return err
+	}
+	return r.definition().RenameInFile(openAPIPath)
+}
+
+// Check returns an error if the setter can't be renamed in the OpenAPI file,
+// without changing the file.
+func (r *SetterRenamer) Check(openAPIPath string) error {
+	object, err := yaml.ReadFile(openAPIPath)
+	if err != nil {
+		return err
+	}
+	_, err = r.definition().Filter(object)
+	return err
+}
+
+// IsDefined returns true if the setter is defined in the OpenAPI file.
+func (r *SetterRenamer) IsDefined(openAPIPath string) (bool, error) {
+	def, err := lookupSetterDefinition(openAPIPath, r.Name)
+	return def != nil, err
+}
+
+func (r *SetterRenamer) definition() setters2.RenameDefinition {
+	return setters2.RenameDefinition{Name: r.Name, NewName: r.NewName}
+}
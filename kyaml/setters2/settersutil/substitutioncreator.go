@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"regexp"
 	"strings"
 
 	"github.com/go-openapi/spec"
@@ -42,9 +43,125 @@ type SubstitutionCreator struct {
 	// FieldValue if set will add the OpenAPI reference to fields if they have this value.
 	// Optional.  If unspecified match all field values.
 	FieldValue string
+
+	// CreateMissingSetters creates the setters referenced by the values which
+	// don't exist with empty values if their values can't be derived from
+	// FieldValue.  Otherwise it is an error if they can't be.  Missing setters
+	// are created either way.
+	CreateMissingSetters bool
+}
+
+// markerPattern matches the markers of a pattern -- e.g. ${my-image-setter}
+var markerPattern = regexp.MustCompile(`\$\{([^}]*)\}`)
+
+// MissingSetters returns the names of the setters referenced by the values
+// which don't exist in the openAPIPath file -- those created by Create.
+// Missing substitutions aren't included.
+func (c SubstitutionCreator) MissingSetters(openAPIPath string) ([]string, error) {
+	keys, err := c.missingDefinitions(openAPIPath)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, key := range keys {
+		if strings.HasPrefix(key, fieldmeta.SetterDefinitionPrefix) {
+			names = append(names, definitionName(key))
+		}
+	}
+	return names, nil
+}
+
+// missingDefinitions returns the keys of the definitions referenced by the
+// values which don't exist in the openAPIPath file.
+func (c SubstitutionCreator) missingDefinitions(openAPIPath string) ([]string, error) {
+	y, err := yaml.ReadFile(openAPIPath)
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	for _, value := range c.Values {
+		key := strings.TrimPrefix(value.Ref, fieldmeta.DefinitionsPrefix)
+		def, err := y.Pipe(yaml.Lookup("openAPI", "definitions", key))
+		if err != nil {
+			return nil, err
+		}
+		if def == nil {
+			missing = append(missing, key)
+		}
+	}
+	return missing, nil
+}
+
+// definitionName returns the name of the setter or substitution with the
+// definition key -- e.g. image for io.k8s.cli.setters.image
+func definitionName(key string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(key,
+		fieldmeta.SetterDefinitionPrefix), fieldmeta.SubstitutionDefinitionPrefix)
+}
+
+// Validate returns an error listing the markers of the pattern which have no
+// value, the substitutions referenced by the values which don't exist in the
+// openAPIPath file, and the setters which don't exist if their values can't be
+// derived from FieldValue to create them.  Nothing is written.
+func (c SubstitutionCreator) Validate(openAPIPath string) error {
+	markers := sets.String{}
+	for _, value := range c.Values {
+		markers.Insert(value.Marker)
+	}
+	var unvalued []string
+	for _, marker := range markerPattern.FindAllString(c.Pattern, -1) {
+		if !markers.Has(marker) {
+			unvalued = append(unvalued, marker)
+			markers.Insert(marker)
+		}
+	}
+
+	keys, err := c.missingDefinitions(openAPIPath)
+	if err != nil {
+		return err
+	}
+	var missingSetters, missingSubstitutions []string
+	for _, key := range keys {
+		if strings.HasPrefix(key, fieldmeta.SetterDefinitionPrefix) {
+			missingSetters = append(missingSetters, definitionName(key))
+		} else {
+			missingSubstitutions = append(missingSubstitutions, definitionName(key))
+		}
+	}
+	if c.CreateMissingSetters {
+		// created by Create, with empty values if they can't be derived
+		missingSetters = nil
+	} else if _, err := c.GetValuesForMarkers(); err == nil {
+		// created by Create, with the derived values
+		missingSetters = nil
+	}
+
+	var problems []string
+	if len(unvalued) > 0 {
+		problems = append(problems, fmt.Sprintf(
+			"markers %s have no values", strings.Join(unvalued, ", ")))
+	}
+	if len(missingSubstitutions) > 0 {
+		problems = append(problems, fmt.Sprintf(
+			"substitutions %s don't exist", strings.Join(missingSubstitutions, ", ")))
+	}
+	if len(missingSetters) > 0 {
+		problems = append(problems, fmt.Sprintf(
+			"setters %s don't exist and their values can't be derived from %s",
+			strings.Join(missingSetters, ", "), c.FieldValue))
+	}
+	if len(problems) > 0 {
+		return errors.Errorf("invalid substitution %s: %s",
+			c.Name, strings.Join(problems, "; "))
+	}
+	return nil
 }
 
 func (c SubstitutionCreator) Create(openAPIPath, resourcesPath string) error {
+	if err := c.Validate(openAPIPath); err != nil {
+		return err
+	}
+
 	d := setters2.SubstitutionDefinition{
 		Name:    c.Name,
 		Values:  c.Values,
@@ -130,9 +247,13 @@ func (c SubstitutionCreator) CreateSettersForSubstitution(openAPIPath string) er
 	}
 
 	m, err := c.GetValuesForMarkers()
-	if err != nil {
+	if err != nil && !c.CreateMissingSetters {
 		return err
 	}
+	if err != nil {
+		// values which can't be derived are left empty, to be set later
+		m = map[string]string{}
+	}
 
 	// for each ref in values, check if the setter or substitution already exists, if not create setter
 	for _, value := range c.Values {
@@ -154,7 +275,10 @@ func (c SubstitutionCreator) CreateSettersForSubstitution(openAPIPath string) er
 		if setterObj == nil {
 			name := strings.TrimPrefix(value.Ref, fieldmeta.DefinitionsPrefix+fieldmeta.SetterDefinitionPrefix)
 			value := m[value.Marker]
-			fmt.Printf("unable to find setter with name %s, creating new setter with value %s\n", name, value)
+			if !c.CreateMissingSetters {
+				// the created setters are otherwise reported by the caller
+				fmt.Printf("unable to find setter with name %s, creating new setter with value %s\n", name, value)
+			}
 			sd := setters2.SetterDefinition{
 				// get the setter name from ref. Ex: from #/definitions/io.k8s.cli.setters.image_setter
 				// extract image_setter
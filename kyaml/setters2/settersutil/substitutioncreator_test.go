@@ -4,6 +4,8 @@
 package settersutil
 
 import (
+	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -109,3 +111,139 @@ func TestGetValuesForMarkers(t *testing.T) {
 		})
 	}
 }
+
+func TestSubstitutionCreator_Validate(t *testing.T) {
+	var tests = []struct {
+		name                 string
+		pattern              string
+		values               []setters2.Value
+		createMissingSetters bool
+		expectedError        string
+	}{
+		{
+			name:    "valid",
+			pattern: "${image}:${tag}",
+			values: []setters2.Value{
+				{Marker: "${image}", Ref: "#/definitions/io.k8s.cli.setters.image"},
+				{Marker: "${tag}", Ref: "#/definitions/io.k8s.cli.setters.tag"},
+			},
+		},
+		{
+			name:    "missing setters",
+			pattern: "${image}:${version}-${arch}",
+			values: []setters2.Value{
+				{Marker: "${image}", Ref: "#/definitions/io.k8s.cli.setters.image"},
+				{Marker: "${version}", Ref: "#/definitions/io.k8s.cli.setters.version"},
+				{Marker: "${arch}", Ref: "#/definitions/io.k8s.cli.substitutions.arch"},
+			},
+			expectedError: "invalid substitution my-subst: substitutions arch don't exist; " +
+				"setters version don't exist and their values can't be derived from nginx:1.7.9",
+		},
+		{
+			name:    "missing setters with derived values",
+			pattern: "${image}:${version}",
+			values: []setters2.Value{
+				{Marker: "${image}", Ref: "#/definitions/io.k8s.cli.setters.image"},
+				{Marker: "${version}", Ref: "#/definitions/io.k8s.cli.setters.version"},
+			},
+		},
+		{
+			name:    "marker without value",
+			pattern: "${image}:${tag}",
+			values: []setters2.Value{
+				{Marker: "${image}", Ref: "#/definitions/io.k8s.cli.setters.image"},
+			},
+			expectedError: "invalid substitution my-subst: markers ${tag} have no values",
+		},
+		{
+			name:    "create missing setters",
+			pattern: "${image}:${version}",
+			values: []setters2.Value{
+				{Marker: "${image}", Ref: "#/definitions/io.k8s.cli.setters.image"},
+				{Marker: "${version}", Ref: "#/definitions/io.k8s.cli.setters.version"},
+			},
+			createMissingSetters: true,
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			f, err := ioutil.TempFile("", "k8s-cli-")
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			defer os.Remove(f.Name())
+			err = ioutil.WriteFile(f.Name(), []byte(`apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.image:
+      x-k8s-cli:
+        setter:
+          name: image
+          value: nginx
+    io.k8s.cli.setters.tag:
+      x-k8s-cli:
+        setter:
+          name: tag
+          value: 1.7.9
+`), 0600)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+
+			sc := SubstitutionCreator{
+				Name:                 "my-subst",
+				Pattern:              test.pattern,
+				Values:               test.values,
+				FieldValue:           "nginx:1.7.9",
+				CreateMissingSetters: test.createMissingSetters,
+			}
+			err = sc.Validate(f.Name())
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			if assert.Error(t, err) {
+				assert.Equal(t, test.expectedError, err.Error())
+			}
+		})
+	}
+}
+
+func TestSubstitutionCreator_MissingSetters(t *testing.T) {
+	f, err := ioutil.TempFile("", "k8s-cli-")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.Remove(f.Name())
+	err = ioutil.WriteFile(f.Name(), []byte(`apiVersion: v1alpha1
+kind: Example
+openAPI:
+  definitions:
+    io.k8s.cli.setters.image:
+      x-k8s-cli:
+        setter:
+          name: image
+          value: nginx
+`), 0600)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	// missing substitutions aren't setters to be created
+	sc := SubstitutionCreator{
+		Name:    "my-subst",
+		Pattern: "${image}:${tag}-${arch}",
+		Values: []setters2.Value{
+			{Marker: "${image}", Ref: "#/definitions/io.k8s.cli.setters.image"},
+			{Marker: "${tag}", Ref: "#/definitions/io.k8s.cli.setters.tag"},
+			{Marker: "${arch}", Ref: "#/definitions/io.k8s.cli.substitutions.arch"},
+		},
+	}
+	missing, err := sc.MissingSetters(f.Name())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, []string{"tag"}, missing)
+}
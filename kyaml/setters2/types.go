@@ -9,6 +9,7 @@ import (
 	"github.com/go-openapi/spec"
 	"sigs.k8s.io/kustomize/kyaml/errors"
 	"sigs.k8s.io/kustomize/kyaml/openapi"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
 type CliExtension struct {
@@ -19,8 +20,33 @@ type CliExtension struct {
 type setter struct {
 	Name       string            `yaml:"name,omitempty" json:"name,omitempty"`
 	Value      string            `yaml:"value,omitempty" json:"value,omitempty"`
-	ListValues []string          `yaml:"listValues,omitempty" json:"listValues,omitempty"`
+	ListValues ListValues        `yaml:"listValues,omitempty" json:"listValues,omitempty"`
 	EnumValues map[string]string `yaml:"enumValues,omitempty" json:"enumValues,omitempty"`
+	Kinds      []string          `yaml:"kinds,omitempty" json:"kinds,omitempty"`
+	APIVersion string            `yaml:"apiVersion,omitempty" json:"apiVersion,omitempty"`
+}
+
+// inScope returns true if the setter may set the fields of resources with
+// meta -- those of its kinds and apiVersion, if it is scoped to them.
+func (s *setter) inScope(meta yaml.ResourceMeta) bool {
+	return matchesScope(s.Kinds, s.APIVersion, meta)
+}
+
+// matchesScope returns true if the resource with meta is one of kinds, unless
+// kinds is empty, and has apiVersion, unless it is empty.
+func matchesScope(kinds []string, apiVersion string, meta yaml.ResourceMeta) bool {
+	if apiVersion != "" && meta.APIVersion != apiVersion {
+		return false
+	}
+	if len(kinds) == 0 {
+		return true
+	}
+	for _, k := range kinds {
+		if k == meta.Kind {
+			return true
+		}
+	}
+	return false
 }
 
 type substitution struct {
@@ -0,0 +1,151 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package setters2
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/fieldmeta"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// JSONSchemaDraft is the JSON Schema version of the values schema.
+const JSONSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// ValuesSchema returns a JSON Schema describing the values of the setters
+// defined in the OpenAPI file, as the properties of a single object -- e.g. so
+// that a form may collect the values to set.
+//
+// Each property has the type and constraints of the setter's schema, its
+// description, and its enum values.  The default of a property is the setter
+// default, or its current value if it has none.  Required setters are listed
+// as required properties.
+func ValuesSchema(openAPIPath string) (map[string]interface{}, error) {
+	y, err := yaml.ReadFile(openAPIPath)
+	if err != nil {
+		return nil, err
+	}
+	definitions, err := y.Pipe(yaml.Lookup(openapi.SupplementaryOpenAPIFieldName, "definitions"))
+	if err != nil {
+		return nil, err
+	}
+
+	properties := map[string]interface{}{}
+	var required []string
+	if definitions != nil {
+		err = definitions.VisitFields(func(node *yaml.MapNode) error {
+			key := node.Key.YNode().Value
+			if !strings.HasPrefix(key, fieldmeta.SetterDefinitionPrefix) {
+				return nil
+			}
+			setter, property, err := valuesSchemaProperty(node.Value)
+			if err != nil {
+				return errors.WrapPrefixf(err, "invalid definition %s", key)
+			}
+			properties[setter.Name] = property
+			if setter.Required {
+				required = append(required, setter.Name)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	schema := map[string]interface{}{
+		"$schema":    JSONSchemaDraft,
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	return schema, nil
+}
+
+// valuesSchemaProperty returns the setter definition, and the JSON Schema
+// property for its value.
+func valuesSchemaProperty(def *yaml.RNode) (SetterDefinition, map[string]interface{}, error) {
+	setter := SetterDefinition{}
+	setterNode, err := def.Pipe(yaml.Lookup(K8sCliExtensionKey, "setter"))
+	if err != nil {
+		return setter, nil, err
+	}
+	if yaml.IsEmpty(setterNode) {
+		return setter, nil, errors.Errorf("missing %s.setter", K8sCliExtensionKey)
+	}
+	s, err := setterNode.String()
+	if err != nil {
+		return setter, nil, err
+	}
+	if err := yaml.Unmarshal([]byte(s), &setter); err != nil {
+		return setter, nil, errors.Wrap(err)
+	}
+
+	// the schema of the definition, less the extension
+	s, err = def.String()
+	if err != nil {
+		return setter, nil, err
+	}
+	property := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(s), &property); err != nil {
+		return setter, nil, errors.Wrap(err)
+	}
+	delete(property, K8sCliExtensionKey)
+
+	if _, found := property["type"]; !found {
+		switch {
+		case len(setter.ListValues) > 0:
+			property["type"] = "array"
+			property["items"] = map[string]interface{}{"type": "string"}
+		default:
+			// setter values are strings unless the schema says otherwise
+			property["type"] = "string"
+		}
+	}
+	if _, found := property["enum"]; !found && len(setter.EnumValues) > 0 {
+		var enum []string
+		for k := range setter.EnumValues {
+			enum = append(enum, k)
+		}
+		sort.Strings(enum)
+		property["enum"] = enum
+	}
+
+	switch {
+	case setter.Default != "":
+		property["default"] = typedValue(setter.Default, property["type"])
+	case len(setter.ListValues) > 0:
+		property["default"] = setter.ListValues
+	case setter.Value != "":
+		property["default"] = typedValue(setter.Value, property["type"])
+	}
+	return setter, property, nil
+}
+
+// typedValue returns the setter value as the JSON Schema type t, or as a
+// string if it isn't a valid value of the type.
+func typedValue(value string, t interface{}) interface{} {
+	switch t {
+	case "integer":
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return i
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
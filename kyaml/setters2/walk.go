@@ -49,6 +49,10 @@ func acceptImpl(v visitor, object *yaml.RNode, p string, oa *openapi.ResourceSch
 			return err
 		}
 		return object.VisitFields(func(node *yaml.MapNode) error {
+			if node.Key.YNode().Value == mergeKey {
+				// merged fields are visited where their anchor is defined
+				return nil
+			}
 			// get the schema for the field and propagate it
 			oa = getSchema(node.Key, oa, node.Key.YNode().Value)
 			// Traverse each field value
@@ -70,10 +74,29 @@ func acceptImpl(v visitor, object *yaml.RNode, p string, oa *openapi.ResourceSch
 		// Visit the scalar field
 		oa = getSchema(object, oa, "")
 		return v.visitScalar(object, p, oa)
+	case yaml.AliasNode:
+		// Traverse the anchored node in place of the alias, so that references
+		// are added to the anchor and values set through the alias
+		if object.YNode().Alias == nil {
+			return nil
+		}
+		return acceptImpl(v, yaml.NewRNode(object.YNode().Alias), p, oa)
 	}
 	return nil
 }
 
+// resolveAlias returns the node anchored by node if it is an alias, or else
+// node.
+func resolveAlias(node *yaml.RNode) *yaml.RNode {
+	if node.YNode().Kind == yaml.AliasNode && node.YNode().Alias != nil {
+		return yaml.NewRNode(node.YNode().Alias)
+	}
+	return node
+}
+
+// mergeKey is the key of a YAML merge, e.g. `<<: *defaults`
+const mergeKey = "<<"
+
 // getSchema returns OpenAPI schema for an RNode or field of the
 // RNode.  It will overriding the provide schema with field specific values
 // if they are found
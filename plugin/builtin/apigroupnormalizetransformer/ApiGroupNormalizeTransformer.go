@@ -0,0 +1,192 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/resid"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/yaml"
+)
+
+// Normalize the apiVersion of resources of the same kind
+// which are drawn from bases using different apiVersions,
+// e.g. policy/v1beta1 and policy/v1 PodDisruptionBudgets.
+// The fields of well known kinds are migrated to the
+// preferred apiVersion; fields which can't be migrated
+// automatically are reported as an error.
+type plugin struct {
+	// ApiVersions maps a kind to its preferred apiVersion,
+	// e.g. PodDisruptionBudget: policy/v1.
+	ApiVersions map[string]string `json:"apiVersions,omitempty" yaml:"apiVersions,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+// migration migrates the fields of a resource of some kind
+// to the given apiVersion, returning the paths of the fields
+// which need manual migration.
+type migration func(obj map[string]interface{}, from, to string) []string
+
+// migrations are the field migrations of well known kinds.
+var migrations = map[string]migration{
+	"PodDisruptionBudget": migratePodDisruptionBudget,
+	"Ingress":             migrateIngress,
+}
+
+func (p *plugin) Config(
+	_ *resmap.PluginHelpers, c []byte) (err error) {
+	p.ApiVersions = nil
+	return yaml.Unmarshal(c, p)
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	var manual []string
+	for _, r := range m.Resources() {
+		gvk := r.GetGvk()
+		to, ok := p.ApiVersions[gvk.Kind]
+		if !ok {
+			continue
+		}
+		from := apiVersion(gvk)
+		if from == to {
+			continue
+		}
+		if fields := normalize(r, from, to); len(fields) > 0 {
+			manual = append(manual, fmt.Sprintf(
+				"%s from %s to %s: %s",
+				r.CurId(), from, to, strings.Join(fields, ", ")))
+		}
+	}
+	if len(manual) > 0 {
+		return fmt.Errorf(
+			"fields need manual migration:\n  %s",
+			strings.Join(manual, "\n  "))
+	}
+	return nil
+}
+
+// normalize sets the apiVersion of r, and migrates its
+// fields if its kind is well known.
+func normalize(r *resource.Resource, from, to string) []string {
+	gvk := r.GetGvk()
+	gvk.Group, gvk.Version = "", to
+	if i := strings.LastIndex(to, "/"); i >= 0 {
+		gvk.Group, gvk.Version = to[:i], to[i+1:]
+	}
+	var fields []string
+	if migrate, ok := migrations[gvk.Kind]; ok {
+		fields = migrate(r.Map(), from, to)
+	}
+	r.SetGvk(gvk)
+	return fields
+}
+
+// apiVersion returns the apiVersion of the gvk,
+// e.g. policy/v1.
+func apiVersion(gvk resid.Gvk) string {
+	if gvk.Group == "" {
+		return gvk.Version
+	}
+	return gvk.Group + "/" + gvk.Version
+}
+
+// isV1 reports whether apiVersion is a v1 version.
+func isV1(apiVersion string) bool {
+	return strings.HasSuffix(apiVersion, "/v1")
+}
+
+// migratePodDisruptionBudget migrates a PodDisruptionBudget
+// between policy/v1beta1 and policy/v1.  The fields are the
+// same, but an empty selector selects no pods in v1beta1
+// and all pods in v1.
+func migratePodDisruptionBudget(
+	obj map[string]interface{}, from, to string) []string {
+	if isV1(from) == isV1(to) {
+		return nil
+	}
+	spec, _ := obj["spec"].(map[string]interface{})
+	selector, _ := spec["selector"].(map[string]interface{})
+	if len(selector) == 0 {
+		return []string{"spec.selector"}
+	}
+	return nil
+}
+
+// migrateIngress migrates an Ingress from extensions/v1beta1
+// or networking.k8s.io/v1beta1 to networking.k8s.io/v1:
+// backends refer to services with a service field, the
+// default backend is named defaultBackend and paths must
+// have a pathType.  Ingresses can't be migrated back.
+func migrateIngress(
+	obj map[string]interface{}, from, to string) []string {
+	if isV1(from) == isV1(to) {
+		return nil
+	}
+	spec, ok := obj["spec"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if isV1(from) {
+		return []string{"spec"}
+	}
+	var fields []string
+	if backend, ok := spec["backend"]; ok {
+		delete(spec, "backend")
+		spec["defaultBackend"] = backend
+		fields = append(fields, migrateIngressBackend(
+			backend, "spec.defaultBackend")...)
+	}
+	rules, _ := spec["rules"].([]interface{})
+	for i := range rules {
+		rule, _ := rules[i].(map[string]interface{})
+		http, _ := rule["http"].(map[string]interface{})
+		paths, _ := http["paths"].([]interface{})
+		for j := range paths {
+			path, ok := paths[j].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if _, ok := path["pathType"]; !ok {
+				// the v1beta1 default
+				path["pathType"] = "ImplementationSpecific"
+			}
+			fields = append(fields, migrateIngressBackend(path["backend"], fmt.Sprintf(
+				"spec.rules[%d].http.paths[%d].backend", i, j))...)
+		}
+	}
+	return fields
+}
+
+// migrateIngressBackend replaces the serviceName and
+// servicePort of a v1beta1 backend with a v1 service.
+func migrateIngressBackend(in interface{}, path string) []string {
+	backend, ok := in.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	name, hasName := backend["serviceName"]
+	port, hasPort := backend["servicePort"]
+	if !hasName && !hasPort {
+		return nil
+	}
+	delete(backend, "serviceName")
+	delete(backend, "servicePort")
+	service := map[string]interface{}{"name": name}
+	switch port.(type) {
+	case string:
+		service["port"] = map[string]interface{}{"name": port}
+	case int, int32, int64, float64:
+		service["port"] = map[string]interface{}{"number": port}
+	default:
+		return []string{path + ".servicePort"}
+	}
+	backend["service"] = service
+	return nil
+}
@@ -0,0 +1,153 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestApiGroupNormalizeTransformer(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ApiGroupNormalizeTransformer")
+	defer th.Reset()
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: ApiGroupNormalizeTransformer
+metadata:
+  name: notImportantHere
+apiVersions:
+  PodDisruptionBudget: policy/v1
+`, `
+apiVersion: policy/v1beta1
+kind: PodDisruptionBudget
+metadata:
+  name: web
+spec:
+  minAvailable: 2
+  selector:
+    matchLabels:
+      app: web
+---
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: db
+spec:
+  maxUnavailable: 1
+  selector:
+    matchLabels:
+      app: db
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: web
+spec:
+  minAvailable: 2
+  selector:
+    matchLabels:
+      app: web
+---
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: db
+spec:
+  maxUnavailable: 1
+  selector:
+    matchLabels:
+      app: db
+`)
+}
+
+func TestApiGroupNormalizeTransformerIngress(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ApiGroupNormalizeTransformer")
+	defer th.Reset()
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: ApiGroupNormalizeTransformer
+metadata:
+  name: notImportantHere
+apiVersions:
+  Ingress: networking.k8s.io/v1
+`, `
+apiVersion: extensions/v1beta1
+kind: Ingress
+metadata:
+  name: web
+spec:
+  backend:
+    serviceName: default
+    servicePort: 80
+  rules:
+  - host: example.com
+    http:
+      paths:
+      - path: /
+        backend:
+          serviceName: web
+          servicePort: http
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: web
+spec:
+  defaultBackend:
+    service:
+      name: default
+      port:
+        number: 80
+  rules:
+  - host: example.com
+    http:
+      paths:
+      - backend:
+          service:
+            name: web
+            port:
+              name: http
+        path: /
+        pathType: ImplementationSpecific
+`)
+}
+
+func TestApiGroupNormalizeTransformerManualMigration(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ApiGroupNormalizeTransformer")
+	defer th.Reset()
+
+	err := th.ErrorFromLoadAndRunTransformer(`
+apiVersion: builtin
+kind: ApiGroupNormalizeTransformer
+metadata:
+  name: notImportantHere
+apiVersions:
+  PodDisruptionBudget: policy/v1
+`, `
+apiVersion: policy/v1beta1
+kind: PodDisruptionBudget
+metadata:
+  name: web
+spec:
+  minAvailable: 2
+`)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(err.Error(),
+		"from policy/v1beta1 to policy/v1: spec.selector") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Annotate each resource with a checksum of its content,
+// so that downstream tooling can detect tampering.  The
+// checksum excludes volatile fields, and its own annotation,
+// so checksumming is idempotent.  List this transformer
+// last, since later changes invalidate the checksums.
+type plugin struct {
+	// Annotation is the annotation holding the checksum.
+	Annotation string `json:"annotation,omitempty" yaml:"annotation,omitempty"`
+
+	// ExcludeFields are the paths of the fields excluded
+	// from the checksum, e.g. metadata/annotations/owner.
+	// Slashes within a field name are escaped as '\/'.
+	// Defaults to the fields set by the API server.
+	ExcludeFields []string `json:"excludeFields,omitempty" yaml:"excludeFields,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+const defaultAnnotation = "config.kubernetes.io/checksum"
+
+// defaultExcludeFields are the volatile fields set by the
+// API server.
+var defaultExcludeFields = []string{
+	"status",
+	"metadata/creationTimestamp",
+	"metadata/generation",
+	"metadata/managedFields",
+	"metadata/resourceVersion",
+	"metadata/selfLink",
+	"metadata/uid",
+}
+
+func (p *plugin) Config(
+	_ *resmap.PluginHelpers, c []byte) (err error) {
+	p.Annotation = ""
+	p.ExcludeFields = nil
+	err = yaml.Unmarshal(c, p)
+	if err != nil {
+		return err
+	}
+	if p.Annotation == "" {
+		p.Annotation = defaultAnnotation
+	}
+	if p.ExcludeFields == nil {
+		p.ExcludeFields = defaultExcludeFields
+	}
+	return nil
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	for _, r := range m.Resources() {
+		obj := r.DeepCopy().Map()
+		removeField(obj, []string{"metadata", "annotations", p.Annotation})
+		for _, path := range p.ExcludeFields {
+			removeField(obj, types.FieldSpec{Path: path}.PathSlice())
+		}
+		b, err := json.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		annotations := r.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[p.Annotation] = fmt.Sprintf("sha256:%x", sha256.Sum256(b))
+		r.SetAnnotations(annotations)
+	}
+	return nil
+}
+
+// removeField removes the field at path from obj, and any
+// maps containing it which are left empty, so that adding
+// a field then excluding it leaves the checksum unchanged.
+func removeField(obj map[string]interface{}, path []string) {
+	if len(path) == 0 {
+		return
+	}
+	if len(path) > 1 {
+		child, ok := obj[path[0]].(map[string]interface{})
+		if !ok {
+			return
+		}
+		removeField(child, path[1:])
+		if len(child) > 0 {
+			return
+		}
+	}
+	delete(obj, path[0])
+}
@@ -0,0 +1,116 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestChecksumAnnotationTransformer(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ChecksumAnnotationTransformer")
+	defer th.Reset()
+
+	config := `
+apiVersion: builtin
+kind: ChecksumAnnotationTransformer
+metadata:
+  name: notImportantHere
+`
+	expected := `
+apiVersion: v1
+data:
+  a: b
+kind: ConfigMap
+metadata:
+  annotations:
+    config.kubernetes.io/checksum: sha256:463fec9e7f075ca5eb1a707216e3dc9200994845fb22f75c60980c245565a94c
+  name: cm
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    config.kubernetes.io/checksum: sha256:07f795487a59de59f1bfc34512bce43c396954d07fe2aeb5ca51d6a8b4c54c0b
+  labels:
+    app: web
+  name: app
+  resourceVersion: "42"
+spec:
+  replicas: 3
+status:
+  readyReplicas: 3
+`
+
+	rm := th.LoadAndRunTransformer(config, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+data:
+  a: b
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  labels:
+    app: web
+  resourceVersion: "42"
+spec:
+  replicas: 3
+status:
+  readyReplicas: 3
+`)
+	th.AssertActualEqualsExpected(rm, expected)
+
+	// the checksums are stable when re-run
+	rm = th.LoadAndRunTransformer(config, expected)
+	th.AssertActualEqualsExpected(rm, expected)
+}
+
+func TestChecksumAnnotationTransformerExcludeFields(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ChecksumAnnotationTransformer")
+	defer th.Reset()
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: ChecksumAnnotationTransformer
+metadata:
+  name: notImportantHere
+annotation: example.com/checksum
+excludeFields:
+- spec/replicas
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  labels:
+    app: web
+  resourceVersion: "42"
+spec:
+  replicas: 3
+status:
+  readyReplicas: 3
+`)
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    example.com/checksum: sha256:44848ea58b572338bd670899491fe9ffb53f71a492469fcdc8163197070045f7
+  labels:
+    app: web
+  name: app
+  resourceVersion: "42"
+spec:
+  replicas: 3
+status:
+  readyReplicas: 3
+`)
+}
@@ -0,0 +1,107 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filtersutil"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// Remove the given fields from the containers of the selected
+// workloads, e.g. to produce a stripped-down local-dev variant.
+type plugin struct {
+	// Fields are the container fields to remove, e.g. resources,
+	// livenessProbe or securityContext.  Nested fields are
+	// separated by '/', e.g. resources/limits.
+	Fields []string `json:"fields,omitempty" yaml:"fields,omitempty"`
+
+	// Target selects the workloads to strip.  If unset, all
+	// resources are stripped.
+	Target *types.Selector `json:"target,omitempty" yaml:"target,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+// containerPaths are the paths to lists of containers in the
+// well known workload kinds.
+var containerPaths = [][]string{
+	{"spec", "containers"},
+	{"spec", "initContainers"},
+	{"spec", "template", "spec", "containers"},
+	{"spec", "template", "spec", "initContainers"},
+	{"spec", "jobTemplate", "spec", "template", "spec", "containers"},
+	{"spec", "jobTemplate", "spec", "template", "spec", "initContainers"},
+}
+
+func (p *plugin) Config(
+	_ *resmap.PluginHelpers, c []byte) (err error) {
+	p.Fields = nil
+	p.Target = nil
+	return yaml.Unmarshal(c, p)
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	resources := m.Resources()
+	if p.Target != nil {
+		var err error
+		resources, err = m.Select(*p.Target)
+		if err != nil {
+			return err
+		}
+	}
+	return p.strip(resources)
+}
+
+func (p *plugin) strip(resources []*resource.Resource) error {
+	for _, r := range resources {
+		err := filtersutil.ApplyToJSON(
+			kio.FilterAll(kyaml.FilterFunc(p.stripContainers)), r.Kunstructured)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *plugin) stripContainers(node *kyaml.RNode) (*kyaml.RNode, error) {
+	for _, path := range containerPaths {
+		containers, err := node.Pipe(kyaml.Lookup(path...))
+		if err != nil {
+			return nil, err
+		}
+		if containers == nil {
+			continue
+		}
+		err = containers.VisitElements(func(container *kyaml.RNode) error {
+			for _, field := range p.Fields {
+				parts := strings.Split(strings.Trim(field, "/"), "/")
+				parent, err := container.Pipe(kyaml.Lookup(parts[:len(parts)-1]...))
+				if err != nil {
+					return err
+				}
+				if parent == nil {
+					// nothing to strip
+					continue
+				}
+				if _, err := parent.Pipe(kyaml.Clear(parts[len(parts)-1])); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
+}
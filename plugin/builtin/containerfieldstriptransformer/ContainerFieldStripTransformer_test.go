@@ -0,0 +1,149 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestContainerFieldStripTransformer(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ContainerFieldStripTransformer")
+	defer th.Reset()
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: ContainerFieldStripTransformer
+metadata:
+  name: notImportantHere
+fields:
+- resources
+- livenessProbe
+- securityContext
+target:
+  name: myapp
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: myapp
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: busybox
+      containers:
+      - name: app
+        image: nginx
+        resources:
+          limits:
+            cpu: 500m
+        livenessProbe:
+          httpGet:
+            path: /healthz
+            port: 8080
+        readinessProbe:
+          httpGet:
+            path: /ready
+            port: 8080
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: otherapp
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: nginx
+        resources:
+          limits:
+            cpu: 500m
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: myapp
+spec:
+  template:
+    spec:
+      containers:
+      - image: nginx
+        name: app
+        readinessProbe:
+          httpGet:
+            path: /ready
+            port: 8080
+      initContainers:
+      - image: busybox
+        name: init
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: otherapp
+spec:
+  template:
+    spec:
+      containers:
+      - image: nginx
+        name: app
+        resources:
+          limits:
+            cpu: 500m
+`)
+}
+
+func TestContainerFieldStripTransformerNestedField(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ContainerFieldStripTransformer")
+	defer th.Reset()
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: ContainerFieldStripTransformer
+metadata:
+  name: notImportantHere
+fields:
+- resources/limits
+`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: mypod
+spec:
+  containers:
+  - name: app
+    image: nginx
+    resources:
+      limits:
+        cpu: 500m
+      requests:
+        cpu: 100m
+  - name: sidecar
+    image: envoy
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: mypod
+spec:
+  containers:
+  - image: nginx
+    name: app
+    resources:
+      requests:
+        cpu: 100m
+  - image: envoy
+    name: sidecar
+`)
+}
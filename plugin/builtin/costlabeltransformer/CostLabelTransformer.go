@@ -0,0 +1,79 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/kustomize/api/filters/labels"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filtersutil"
+	"sigs.k8s.io/yaml"
+)
+
+// Add cost allocation labels, e.g. team, cost-center and
+// environment, to the metadata of all resources.  Unlike
+// commonLabels, the labels are never added to selectors or
+// pod templates, so workloads are not affected.
+type plugin struct {
+	// Labels are the cost allocation labels to add.
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+
+	// FromEnv maps label keys to the environment variables
+	// holding their values, so values may be set per build.
+	FromEnv map[string]string `json:"fromEnv,omitempty" yaml:"fromEnv,omitempty"`
+
+	labels map[string]string
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+// metadataLabels is the only field the labels are added to.
+var metadataLabels = types.FsSlice{{
+	Path:               "metadata/labels",
+	CreateIfNotPresent: true,
+}}
+
+func (p *plugin) Config(
+	_ *resmap.PluginHelpers, c []byte) (err error) {
+	p.Labels = nil
+	p.FromEnv = nil
+	p.labels = nil
+	if err = yaml.Unmarshal(c, p); err != nil {
+		return err
+	}
+	p.labels = map[string]string{}
+	for k, v := range p.Labels {
+		p.labels[k] = v
+	}
+	for k, env := range p.FromEnv {
+		v, ok := os.LookupEnv(env)
+		if !ok {
+			return fmt.Errorf(
+				"environment variable %s for label %s is not set", env, k)
+		}
+		p.labels[k] = v
+	}
+	return nil
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	if len(p.labels) == 0 {
+		return nil
+	}
+	for _, r := range m.Resources() {
+		err := filtersutil.ApplyToJSON(labels.Filter{
+			Labels:  p.labels,
+			FsSlice: metadataLabels,
+		}, r.Kunstructured)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+const costLabelInput = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: myapp
+  labels:
+    app: myapp
+spec:
+  selector:
+    matchLabels:
+      app: myapp
+  template:
+    metadata:
+      labels:
+        app: myapp
+    spec:
+      containers:
+      - name: app
+        image: nginx
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: myapp
+spec:
+  selector:
+    app: myapp
+`
+
+func TestCostLabelTransformer(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("CostLabelTransformer")
+	defer th.Reset()
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: CostLabelTransformer
+metadata:
+  name: notImportantHere
+labels:
+  team: payments
+  cost-center: cc-42
+`, costLabelInput)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    app: myapp
+    cost-center: cc-42
+    team: payments
+  name: myapp
+spec:
+  selector:
+    matchLabels:
+      app: myapp
+  template:
+    metadata:
+      labels:
+        app: myapp
+    spec:
+      containers:
+      - image: nginx
+        name: app
+---
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    cost-center: cc-42
+    team: payments
+  name: myapp
+spec:
+  selector:
+    app: myapp
+`)
+}
+
+func TestCostLabelTransformerFromEnv(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("CostLabelTransformer")
+	defer th.Reset()
+
+	os.Setenv("KUSTOMIZE_TEST_ENVIRONMENT", "staging")
+	defer os.Unsetenv("KUSTOMIZE_TEST_ENVIRONMENT")
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: CostLabelTransformer
+metadata:
+  name: notImportantHere
+fromEnv:
+  environment: KUSTOMIZE_TEST_ENVIRONMENT
+`, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: myapp
+spec:
+  selector:
+    app: myapp
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    environment: staging
+  name: myapp
+spec:
+  selector:
+    app: myapp
+`)
+
+	err := th.ErrorFromLoadAndRunTransformer(`
+apiVersion: builtin
+kind: CostLabelTransformer
+metadata:
+  name: notImportantHere
+fromEnv:
+  environment: KUSTOMIZE_TEST_UNSET
+`, costLabelInput)
+	if err == nil || !strings.Contains(err.Error(),
+		"environment variable KUSTOMIZE_TEST_UNSET for label environment is not set") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/yaml"
+)
+
+// Make a lightweight variant of a production base for local
+// development: run a single replica of each workload, allow
+// its pods to share a node, and drop its disruption budgets.
+// Each behavior is enabled unless explicitly disabled.
+type plugin struct {
+	// SingleReplica forces the replicas of Deployments and
+	// StatefulSets to 1.
+	SingleReplica *bool `json:"singleReplica,omitempty" yaml:"singleReplica,omitempty"`
+
+	// RemoveAntiAffinity removes the pod anti-affinity rules
+	// from pod templates.
+	RemoveAntiAffinity *bool `json:"removeAntiAffinity,omitempty" yaml:"removeAntiAffinity,omitempty"`
+
+	// RemovePodDisruptionBudgets removes PodDisruptionBudgets.
+	RemovePodDisruptionBudgets *bool `json:"removePodDisruptionBudgets,omitempty" yaml:"removePodDisruptionBudgets,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+// replicatedKinds are the kinds of workloads whose
+// replicas are forced to 1.
+var replicatedKinds = map[string]bool{
+	"Deployment":  true,
+	"StatefulSet": true,
+}
+
+func (p *plugin) Config(
+	_ *resmap.PluginHelpers, c []byte) (err error) {
+	p.SingleReplica = nil
+	p.RemoveAntiAffinity = nil
+	p.RemovePodDisruptionBudgets = nil
+	return yaml.Unmarshal(c, p)
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	for _, r := range m.Resources() {
+		if r.GetKind() == "PodDisruptionBudget" {
+			if enabled(p.RemovePodDisruptionBudgets) {
+				if err := m.Remove(r.CurId()); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		spec, ok := r.Map()["spec"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if enabled(p.SingleReplica) && replicatedKinds[r.GetKind()] {
+			spec["replicas"] = int64(1)
+		}
+		if enabled(p.RemoveAntiAffinity) {
+			removeAntiAffinity(spec)
+		}
+	}
+	return nil
+}
+
+// enabled reports whether a behavior is enabled;
+// behaviors are enabled by default.
+func enabled(b *bool) bool {
+	return b == nil || *b
+}
+
+// removeAntiAffinity removes the pod anti-affinity from
+// the pod template of a workload spec, removing the
+// affinity altogether if nothing else is left.
+func removeAntiAffinity(spec map[string]interface{}) {
+	template, ok := spec["template"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	podSpec, ok := template["spec"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	affinity, ok := podSpec["affinity"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	delete(affinity, "podAntiAffinity")
+	if len(affinity) == 0 {
+		delete(podSpec, "affinity")
+	}
+}
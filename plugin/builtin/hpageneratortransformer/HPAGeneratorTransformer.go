@@ -0,0 +1,182 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Generate a HorizontalPodAutoscaler for each of the selected
+// workloads, scaling it between the configured replica counts
+// per the configured metrics.
+type plugin struct {
+	h *resmap.PluginHelpers
+
+	// MinReplicas is the lower limit for the number of replicas.
+	// If unset, the HorizontalPodAutoscaler default of 1 applies.
+	MinReplicas int64 `json:"minReplicas,omitempty" yaml:"minReplicas,omitempty"`
+
+	// MaxReplicas is the upper limit for the number of replicas.
+	MaxReplicas int64 `json:"maxReplicas,omitempty" yaml:"maxReplicas,omitempty"`
+
+	// Metrics are the autoscaling/v2beta2 metric specs used to
+	// compute the desired replica count.
+	Metrics []map[string]interface{} `json:"metrics,omitempty" yaml:"metrics,omitempty"`
+
+	// Target selects the workloads to generate autoscalers for.
+	// Only Deployments and StatefulSets are selected.  If unset,
+	// all Deployments and StatefulSets are selected.
+	Target *types.Selector `json:"target,omitempty" yaml:"target,omitempty"`
+
+	metrics string
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+// scalableKinds are the kinds of workloads which are
+// given an autoscaler.
+var scalableKinds = map[string]bool{
+	"Deployment":  true,
+	"StatefulSet": true,
+}
+
+func (p *plugin) Config(
+	h *resmap.PluginHelpers, c []byte) (err error) {
+	p.MinReplicas = 0
+	p.MaxReplicas = 0
+	p.Metrics = nil
+	p.Target = nil
+	p.metrics = ""
+	if err = yaml.Unmarshal(c, p); err != nil {
+		return err
+	}
+	if p.MaxReplicas < 1 {
+		return fmt.Errorf("maxReplicas must be at least 1")
+	}
+	if p.MinReplicas > p.MaxReplicas {
+		return fmt.Errorf(
+			"minReplicas %d must not exceed maxReplicas %d",
+			p.MinReplicas, p.MaxReplicas)
+	}
+	if len(p.Metrics) > 0 {
+		// keep the JSON so that new metrics may be created for each autoscaler
+		b, err := json.Marshal(p.Metrics)
+		if err != nil {
+			return err
+		}
+		p.metrics = string(b)
+	}
+	p.h = h
+	return nil
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	resources := m.Resources()
+	if p.Target != nil {
+		var err error
+		resources, err = m.Select(*p.Target)
+		if err != nil {
+			return err
+		}
+	}
+	scaled := scaledWorkloads(m)
+	// the kinds of the workloads generated autoscalers are named after,
+	// by namespace and name
+	generated := map[string]string{}
+	for _, r := range resources {
+		if !scalableKinds[r.GetKind()] ||
+			scaled[workloadKey(r.GetKind(), r.GetName(), r.GetNamespace())] {
+			continue
+		}
+		key := r.GetNamespace() + "|" + r.GetName()
+		if kind, found := generated[key]; found {
+			return fmt.Errorf(
+				"%s %s and %s %s in namespace %q would both get a "+
+					"HorizontalPodAutoscaler named %s",
+				kind, r.GetName(), r.GetKind(), r.GetName(),
+				r.GetNamespace(), r.GetName())
+		}
+		generated[key] = r.GetKind()
+		hpa, err := p.makeAutoscaler(r)
+		if err != nil {
+			return err
+		}
+		if err := m.Append(hpa); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scaledWorkloads returns the keys of the workloads which are
+// already the scale target of a HorizontalPodAutoscaler.
+func scaledWorkloads(m resmap.ResMap) map[string]bool {
+	scaled := map[string]bool{}
+	for _, r := range m.Resources() {
+		if r.GetKind() != "HorizontalPodAutoscaler" {
+			continue
+		}
+		kind, err := r.GetString("spec.scaleTargetRef.kind")
+		if err != nil {
+			continue
+		}
+		name, err := r.GetString("spec.scaleTargetRef.name")
+		if err != nil {
+			continue
+		}
+		scaled[workloadKey(kind, name, r.GetNamespace())] = true
+	}
+	return scaled
+}
+
+func workloadKey(kind, name, namespace string) string {
+	return kind + "|" + namespace + "|" + name
+}
+
+// makeAutoscaler returns a HorizontalPodAutoscaler scaling r.
+func (p *plugin) makeAutoscaler(r *resource.Resource) (*resource.Resource, error) {
+	metadata := map[string]interface{}{
+		"name": r.GetName(),
+	}
+	if ns := r.GetNamespace(); ns != "" {
+		metadata["namespace"] = ns
+	}
+	gvk := r.GetGvk()
+	apiVersion := gvk.Version
+	if gvk.Group != "" {
+		apiVersion = gvk.Group + "/" + gvk.Version
+	}
+	spec := map[string]interface{}{
+		"scaleTargetRef": map[string]interface{}{
+			"apiVersion": apiVersion,
+			"kind":       r.GetKind(),
+			"name":       r.GetName(),
+		},
+		"maxReplicas": p.MaxReplicas,
+	}
+	if p.MinReplicas > 0 {
+		spec["minReplicas"] = p.MinReplicas
+	}
+	if p.metrics != "" {
+		var metrics []interface{}
+		if err := json.Unmarshal([]byte(p.metrics), &metrics); err != nil {
+			return nil, err
+		}
+		spec["metrics"] = metrics
+	}
+	return p.h.ResmapFactory().RF().FromMap(map[string]interface{}{
+		"apiVersion": "autoscaling/v2beta2",
+		"kind":       "HorizontalPodAutoscaler",
+		"metadata":   metadata,
+		"spec":       spec,
+	}), nil
+}
@@ -0,0 +1,188 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestHPAGeneratorTransformer(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("HPAGeneratorTransformer")
+	defer th.Reset()
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: HPAGeneratorTransformer
+metadata:
+  name: notImportantHere
+minReplicas: 2
+maxReplicas: 10
+metrics:
+- type: Resource
+  resource:
+    name: cpu
+    target:
+      type: Utilization
+      averageUtilization: 80
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: prod
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+  namespace: prod
+---
+apiVersion: autoscaling/v1
+kind: HorizontalPodAutoscaler
+metadata:
+  name: db
+  namespace: prod
+spec:
+  maxReplicas: 3
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: StatefulSet
+    name: db
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: prod
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: prod
+spec:
+  template:
+    spec:
+      containers:
+      - image: nginx
+        name: web
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+  namespace: prod
+---
+apiVersion: autoscaling/v1
+kind: HorizontalPodAutoscaler
+metadata:
+  name: db
+  namespace: prod
+spec:
+  maxReplicas: 3
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: StatefulSet
+    name: db
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: prod
+---
+apiVersion: autoscaling/v2beta2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: web
+  namespace: prod
+spec:
+  maxReplicas: 10
+  metrics:
+  - resource:
+      name: cpu
+      target:
+        averageUtilization: 80
+        type: Utilization
+    type: Resource
+  minReplicas: 2
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: web
+`)
+}
+
+func TestHPAGeneratorTransformerReplicaBounds(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("HPAGeneratorTransformer")
+	defer th.Reset()
+
+	err := th.ErrorFromLoadAndRunTransformer(`
+apiVersion: builtin
+kind: HPAGeneratorTransformer
+metadata:
+  name: notImportantHere
+minReplicas: 5
+maxReplicas: 3
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+`)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(err.Error(),
+		"minReplicas 5 must not exceed maxReplicas 3") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestHPAGeneratorTransformerNameCollision(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("HPAGeneratorTransformer")
+	defer th.Reset()
+
+	err := th.ErrorFromLoadAndRunTransformer(`
+apiVersion: builtin
+kind: HPAGeneratorTransformer
+metadata:
+  name: notImportantHere
+maxReplicas: 3
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: prod
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: web
+  namespace: prod
+`)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(err.Error(),
+		`Deployment web and StatefulSet web in namespace "prod" would both `+
+			`get a HorizontalPodAutoscaler named web`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/yaml"
+)
+
+// Fail the build if the image of any container or init
+// container isn't in one of the allowed registries or
+// repositories.  Resources are left as they are.
+//
+// Images are compared as written, so unqualified images
+// like nginx only match a prefix like nginx.  Run the
+// ImageCanonicalizeTransformer first to compare their
+// fully qualified form, e.g. docker.io/library/nginx.
+type plugin struct {
+	// Allowed are the allowed image prefixes, e.g. gcr.io or
+	// gcr.io/my-project.  A prefix matches whole path
+	// components, so gcr.io/my doesn't allow
+	// gcr.io/my-project/app.
+	Allowed []string `json:"allowed,omitempty" yaml:"allowed,omitempty"`
+
+	// WarnOnly logs the violations instead of failing.
+	WarnOnly bool `json:"warnOnly,omitempty" yaml:"warnOnly,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	_ *resmap.PluginHelpers, c []byte) (err error) {
+	p.Allowed = nil
+	p.WarnOnly = false
+	return yaml.Unmarshal(c, p)
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	var violations []string
+	for _, r := range m.Resources() {
+		images := containerImages(r.Map())
+		sort.Strings(images)
+		for _, image := range images {
+			if !p.isAllowed(image) {
+				violations = append(violations, fmt.Sprintf(
+					"%s: image %s is not allowed", r.CurId(), image))
+			}
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	if p.WarnOnly {
+		for _, v := range violations {
+			log.Printf("Warning: %s", v)
+		}
+		return nil
+	}
+	return fmt.Errorf(
+		"images not in allowed %v:\n%s",
+		p.Allowed, strings.Join(violations, "\n"))
+}
+
+// isAllowed returns true if the repository of image, i.e.
+// the image without its tag and digest, starts with one of
+// the allowed prefixes.
+func (p *plugin) isAllowed(image string) bool {
+	repository := imageRepository(image)
+	for _, prefix := range p.Allowed {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if repository == prefix ||
+			strings.HasPrefix(repository, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// imageRepository returns image without its tag and digest.
+func imageRepository(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	// a ':' after the last '/' separates the tag, any other
+	// separates a registry port
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
+
+// containerImages returns the images of the containers and
+// init containers found anywhere in obj.
+func containerImages(obj map[string]interface{}) []string {
+	var images []string
+	for key, value := range obj {
+		switch typed := value.(type) {
+		case map[string]interface{}:
+			images = append(images, containerImages(typed)...)
+		case []interface{}:
+			isContainers := key == "containers" || key == "initContainers"
+			for i := range typed {
+				item, ok := typed[i].(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if image, ok := item["image"].(string); ok && isContainers {
+					images = append(images, image)
+				}
+				images = append(images, containerImages(item)...)
+			}
+		}
+	}
+	return images
+}
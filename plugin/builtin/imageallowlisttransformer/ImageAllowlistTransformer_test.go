@@ -0,0 +1,123 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+const imageAllowlistResources = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: gcr.io/my-project/init@sha256:24a0c4b4a4c0eb97a1aabb8e29f18e917d05abfe1b7a7c07857230879ce7d3d3
+      containers:
+      - name: app
+        image: gcr.io/my-project/app:v1
+      - name: proxy
+        image: localhost:5000/proxy:v1
+`
+
+func TestImageAllowlistTransformer(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ImageAllowlistTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: ImageAllowlistTransformer
+metadata:
+  name: notImportantHere
+allowed:
+- gcr.io/my-project/
+- localhost:5000
+`, imageAllowlistResources, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - image: gcr.io/my-project/app:v1
+        name: app
+      - image: localhost:5000/proxy:v1
+        name: proxy
+      initContainers:
+      - image: gcr.io/my-project/init@sha256:24a0c4b4a4c0eb97a1aabb8e29f18e917d05abfe1b7a7c07857230879ce7d3d3
+        name: init
+`)
+}
+
+func TestImageAllowlistTransformerDisallowed(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ImageAllowlistTransformer")
+	defer th.Reset()
+
+	err := th.ErrorFromLoadAndRunTransformer(`
+apiVersion: builtin
+kind: ImageAllowlistTransformer
+metadata:
+  name: notImportantHere
+allowed:
+- gcr.io/my
+`, imageAllowlistResources)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	for _, image := range []string{
+		"gcr.io/my-project/app:v1",
+		"localhost:5000/proxy:v1",
+	} {
+		if !strings.Contains(err.Error(), "image "+image+" is not allowed") {
+			t.Fatalf("unexpected err: %v", err)
+		}
+	}
+}
+
+func TestImageAllowlistTransformerWarnOnly(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ImageAllowlistTransformer")
+	defer th.Reset()
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: ImageAllowlistTransformer
+metadata:
+  name: notImportantHere
+allowed:
+- docker.io
+warnOnly: true
+`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: app
+spec:
+  containers:
+  - name: app
+    image: gcr.io/my-project/app:v1
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: app
+spec:
+  containers:
+  - image: gcr.io/my-project/app:v1
+    name: app
+`)
+}
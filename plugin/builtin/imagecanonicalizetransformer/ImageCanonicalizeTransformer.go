@@ -0,0 +1,82 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+)
+
+// Rewrite the images of all containers to fully qualified
+// references, per Docker's rules for the default registry
+// and namespace, e.g. nginx:1.2 becomes
+// docker.io/library/nginx:1.2.  Qualified images are left
+// as they are.
+type plugin struct{}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+const (
+	defaultRegistry  = "docker.io"
+	defaultNamespace = "library"
+)
+
+// Nothing needed for configuration.
+func (p *plugin) Config(
+	_ *resmap.PluginHelpers, _ []byte) (err error) {
+	return nil
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	for _, r := range m.Resources() {
+		canonicalizeContainers(r.Map())
+	}
+	return nil
+}
+
+// canonicalizeContainers canonicalizes the images of the
+// containers and init containers found anywhere in obj.
+func canonicalizeContainers(obj map[string]interface{}) {
+	for key, value := range obj {
+		switch typed := value.(type) {
+		case map[string]interface{}:
+			canonicalizeContainers(typed)
+		case []interface{}:
+			isContainers := key == "containers" || key == "initContainers"
+			for i := range typed {
+				item, ok := typed[i].(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if image, ok := item["image"].(string); ok && isContainers {
+					item["image"] = canonicalImage(image)
+				}
+				canonicalizeContainers(item)
+			}
+		}
+	}
+}
+
+// canonicalImage returns the fully qualified form of image.
+// As with Docker, the first component of the name is a
+// registry only if it contains a '.' or ':', or is localhost.
+func canonicalImage(image string) string {
+	name := image
+	if i := strings.Index(image, "/"); i >= 0 {
+		first := image[:i]
+		if strings.ContainsAny(first, ".:") || first == "localhost" {
+			if first != defaultRegistry && first != "index."+defaultRegistry {
+				return image
+			}
+			name = image[i+1:]
+		}
+	}
+	if !strings.Contains(name, "/") {
+		name = defaultNamespace + "/" + name
+	}
+	return defaultRegistry + "/" + name
+}
@@ -0,0 +1,99 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestImageCanonicalizeTransformer(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ImageCanonicalizeTransformer")
+	defer th.Reset()
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: ImageCanonicalizeTransformer
+metadata:
+  name: notImportantHere
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: deploy
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: busybox
+      containers:
+      - name: library
+        image: nginx:1.2
+      - name: user
+        image: myorg/app@sha256:24a0c4b4a4c0eb97a1aabb8e29f18e917d05abfe1b7a7c07857230879ce7d3d3
+      - name: shortdockerhub
+        image: docker.io/redis:6
+      - name: qualified
+        image: gcr.io/project/app:v1
+      - name: localhost
+        image: localhost/app
+      - name: port
+        image: registry:5000/app:v2
+---
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: job
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - name: job
+            image: myorg/team/job:latest
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: deploy
+spec:
+  template:
+    spec:
+      containers:
+      - image: docker.io/library/nginx:1.2
+        name: library
+      - image: docker.io/myorg/app@sha256:24a0c4b4a4c0eb97a1aabb8e29f18e917d05abfe1b7a7c07857230879ce7d3d3
+        name: user
+      - image: docker.io/library/redis:6
+        name: shortdockerhub
+      - image: gcr.io/project/app:v1
+        name: qualified
+      - image: localhost/app
+        name: localhost
+      - image: registry:5000/app:v2
+        name: port
+      initContainers:
+      - image: docker.io/library/busybox
+        name: init
+---
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: job
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - image: docker.io/myorg/team/job:latest
+            name: job
+`)
+}
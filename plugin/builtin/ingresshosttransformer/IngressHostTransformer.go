@@ -0,0 +1,107 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/yaml"
+)
+
+// Rewrite the hosts of Ingresses per environment: the hosts
+// of their rules and TLS entries, and the host of
+// OpenShift Routes.  Paths and TLS secret names are left as
+// they are, so a TLS entry keeps covering the rules it
+// covered.
+//
+// A host in Hosts is replaced by its mapping.  Other hosts
+// have Suffix appended, e.g. app becomes app.dev.example.com
+// with suffix dev.example.com.  Hosts already ending in the
+// suffix are left as they are.
+type plugin struct {
+	// Suffix is the domain appended to the hosts not in Hosts.
+	Suffix string `json:"suffix,omitempty" yaml:"suffix,omitempty"`
+
+	// Hosts maps hosts to their replacement.
+	Hosts map[string]string `json:"hosts,omitempty" yaml:"hosts,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	_ *resmap.PluginHelpers, c []byte) (err error) {
+	p.Suffix = ""
+	p.Hosts = nil
+	err = yaml.Unmarshal(c, p)
+	if err != nil {
+		return err
+	}
+	if p.Suffix == "" && len(p.Hosts) == 0 {
+		return fmt.Errorf("must specify a suffix or hosts")
+	}
+	p.Suffix = strings.Trim(p.Suffix, ".")
+	return nil
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	for _, r := range m.Resources() {
+		spec, ok := r.Map()["spec"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch r.GetKind() {
+		case "Ingress":
+			p.rewriteIngress(spec)
+		case "Route":
+			p.rewriteHost(spec, "host")
+		}
+	}
+	return nil
+}
+
+// rewriteIngress rewrites the hosts of the rules and TLS
+// entries of the Ingress spec.
+func (p *plugin) rewriteIngress(spec map[string]interface{}) {
+	rules, _ := spec["rules"].([]interface{})
+	for _, r := range rules {
+		if rule, ok := r.(map[string]interface{}); ok {
+			p.rewriteHost(rule, "host")
+		}
+	}
+	tls, _ := spec["tls"].([]interface{})
+	for _, t := range tls {
+		entry, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		hosts, _ := entry["hosts"].([]interface{})
+		for i := range hosts {
+			if host, ok := hosts[i].(string); ok {
+				hosts[i] = p.host(host)
+			}
+		}
+	}
+}
+
+// rewriteHost rewrites the host in the field of obj.
+func (p *plugin) rewriteHost(obj map[string]interface{}, field string) {
+	if host, ok := obj[field].(string); ok && host != "" {
+		obj[field] = p.host(host)
+	}
+}
+
+// host returns the replacement of host.
+func (p *plugin) host(host string) string {
+	if mapped, ok := p.Hosts[host]; ok {
+		return mapped
+	}
+	if p.Suffix == "" || strings.HasSuffix(host, "."+p.Suffix) {
+		return host
+	}
+	return host + "." + p.Suffix
+}
@@ -0,0 +1,124 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+const ingress = `
+apiVersion: networking.k8s.io/v1beta1
+kind: Ingress
+metadata:
+  name: web
+spec:
+  tls:
+  - hosts:
+    - app
+    - api
+    secretName: web-tls
+  rules:
+  - host: app
+    http:
+      paths:
+      - path: /
+        backend:
+          serviceName: app
+          servicePort: 80
+  - host: api
+    http:
+      paths:
+      - path: /v1
+        backend:
+          serviceName: api
+          servicePort: 8080
+`
+
+func TestIngressHostTransformerSuffix(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("IngressHostTransformer")
+	defer th.Reset()
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: IngressHostTransformer
+metadata:
+  name: notImportantHere
+suffix: dev.example.com
+`, ingress)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: networking.k8s.io/v1beta1
+kind: Ingress
+metadata:
+  name: web
+spec:
+  rules:
+  - host: app.dev.example.com
+    http:
+      paths:
+      - backend:
+          serviceName: app
+          servicePort: 80
+        path: /
+  - host: api.dev.example.com
+    http:
+      paths:
+      - backend:
+          serviceName: api
+          servicePort: 8080
+        path: /v1
+  tls:
+  - hosts:
+    - app.dev.example.com
+    - api.dev.example.com
+    secretName: web-tls
+`)
+}
+
+func TestIngressHostTransformerMap(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("IngressHostTransformer")
+	defer th.Reset()
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: IngressHostTransformer
+metadata:
+  name: notImportantHere
+hosts:
+  app: www.example.com
+  api: api.example.com
+`, ingress)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: networking.k8s.io/v1beta1
+kind: Ingress
+metadata:
+  name: web
+spec:
+  rules:
+  - host: www.example.com
+    http:
+      paths:
+      - backend:
+          serviceName: app
+          servicePort: 80
+        path: /
+  - host: api.example.com
+    http:
+      paths:
+      - backend:
+          serviceName: api
+          servicePort: 8080
+        path: /v1
+  tls:
+  - hosts:
+    - www.example.com
+    - api.example.com
+    secretName: web-tls
+`)
+}
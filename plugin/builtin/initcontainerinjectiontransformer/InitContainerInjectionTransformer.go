@@ -0,0 +1,172 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filtersutil"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// Inject an init container, e.g. a database migration, into the
+// pod templates of the selected workloads.
+type plugin struct {
+	// Container is the init container to inject.  It is skipped for
+	// workloads which already have an init container with its name.
+	Container map[string]interface{} `json:"container,omitempty" yaml:"container,omitempty"`
+
+	// Volumes used by Container.  Each is added to the pod spec
+	// unless a volume with the same name is already present.
+	Volumes []map[string]interface{} `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+
+	// Append adds Container after the existing init containers
+	// rather than before them.
+	Append bool `json:"append,omitempty" yaml:"append,omitempty"`
+
+	// Target selects the workloads to inject Container into.  If
+	// unset, all resources with a pod template are injected.
+	Target *types.Selector `json:"target,omitempty" yaml:"target,omitempty"`
+
+	container string
+	volumes   []string
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+// podSpecPaths are the paths to the pod spec in the well known
+// workload kinds.
+var podSpecPaths = [][]string{
+	{"spec", "template", "spec"},
+	{"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+func (p *plugin) Config(
+	_ *resmap.PluginHelpers, c []byte) (err error) {
+	p.Container = nil
+	p.Volumes = nil
+	p.Append = false
+	p.Target = nil
+	p.volumes = nil
+	if err = yaml.Unmarshal(c, p); err != nil {
+		return err
+	}
+	if name, _ := p.Container["name"].(string); name == "" {
+		return fmt.Errorf("container must have a name")
+	}
+	// keep the YAML so that a new node may be created for each workload
+	b, err := yaml.Marshal(p.Container)
+	if err != nil {
+		return err
+	}
+	p.container = string(b)
+	for i := range p.Volumes {
+		if name, _ := p.Volumes[i]["name"].(string); name == "" {
+			return fmt.Errorf("volume %d must have a name", i)
+		}
+		b, err := yaml.Marshal(p.Volumes[i])
+		if err != nil {
+			return err
+		}
+		p.volumes = append(p.volumes, string(b))
+	}
+	return nil
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	resources := m.Resources()
+	if p.Target != nil {
+		var err error
+		resources, err = m.Select(*p.Target)
+		if err != nil {
+			return err
+		}
+	}
+	return p.inject(resources)
+}
+
+func (p *plugin) inject(resources []*resource.Resource) error {
+	for _, r := range resources {
+		err := filtersutil.ApplyToJSON(
+			kio.FilterAll(kyaml.FilterFunc(p.injectPodSpecs)), r.Kunstructured)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *plugin) injectPodSpecs(node *kyaml.RNode) (*kyaml.RNode, error) {
+	for _, path := range podSpecPaths {
+		podSpec, err := node.Pipe(kyaml.Lookup(path...))
+		if err != nil {
+			return nil, err
+		}
+		if podSpec == nil {
+			continue
+		}
+		if err := p.injectPodSpec(podSpec); err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
+}
+
+func (p *plugin) injectPodSpec(podSpec *kyaml.RNode) error {
+	initContainers, err := podSpec.Pipe(
+		kyaml.LookupCreate(kyaml.SequenceNode, "initContainers"))
+	if err != nil {
+		return err
+	}
+	existing, err := initContainers.Pipe(kyaml.ElementMatcher{
+		FieldName: "name", FieldValue: p.Container["name"].(string)})
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		container, err := kyaml.Parse(p.container)
+		if err != nil {
+			return err
+		}
+		if p.Append {
+			initContainers.YNode().Content = append(
+				initContainers.YNode().Content, container.YNode())
+		} else {
+			initContainers.YNode().Content = append(
+				[]*kyaml.Node{container.YNode()}, initContainers.YNode().Content...)
+		}
+	}
+
+	if len(p.volumes) == 0 {
+		return nil
+	}
+	volumes, err := podSpec.Pipe(
+		kyaml.LookupCreate(kyaml.SequenceNode, "volumes"))
+	if err != nil {
+		return err
+	}
+	for i := range p.volumes {
+		volume, err := kyaml.Parse(p.volumes[i])
+		if err != nil {
+			return err
+		}
+		// the volume is only created if there is no volume with its name
+		_, err = volumes.Pipe(kyaml.ElementMatcher{
+			FieldName:  "name",
+			FieldValue: p.Volumes[i]["name"].(string),
+			Create:     volume,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
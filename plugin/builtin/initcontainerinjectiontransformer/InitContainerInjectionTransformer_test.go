@@ -0,0 +1,221 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+const initContainerInjectionConfig = `
+apiVersion: builtin
+kind: InitContainerInjectionTransformer
+metadata:
+  name: notImportantHere
+container:
+  name: migrate
+  image: migrate:v1
+  env:
+  - name: DB_HOST
+    value: db
+  volumeMounts:
+  - name: migrations
+    mountPath: /migrations
+volumes:
+- name: migrations
+  configMap:
+    name: migrations
+target:
+  kind: Deployment
+`
+
+func TestInitContainerInjectionTransformerPrepend(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("InitContainerInjectionTransformer")
+	defer th.Reset()
+
+	rm := th.LoadAndRunTransformer(initContainerInjectionConfig, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: myapp
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: wait
+        image: busybox
+      containers:
+      - name: app
+        image: nginx
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: myapp
+spec:
+  ports:
+  - port: 80
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: myapp
+spec:
+  template:
+    spec:
+      containers:
+      - image: nginx
+        name: app
+      initContainers:
+      - env:
+        - name: DB_HOST
+          value: db
+        image: migrate:v1
+        name: migrate
+        volumeMounts:
+        - mountPath: /migrations
+          name: migrations
+      - image: busybox
+        name: wait
+      volumes:
+      - configMap:
+          name: migrations
+        name: migrations
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: myapp
+spec:
+  ports:
+  - port: 80
+`)
+}
+
+func TestInitContainerInjectionTransformerAppend(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("InitContainerInjectionTransformer")
+	defer th.Reset()
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: InitContainerInjectionTransformer
+metadata:
+  name: notImportantHere
+container:
+  name: migrate
+  image: migrate:v1
+append: true
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: myapp
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: wait
+        image: busybox
+      containers:
+      - name: app
+        image: nginx
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: myapp
+spec:
+  template:
+    spec:
+      containers:
+      - image: nginx
+        name: app
+      initContainers:
+      - image: busybox
+        name: wait
+      - image: migrate:v1
+        name: migrate
+`)
+}
+
+func TestInitContainerInjectionTransformerIdempotent(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("InitContainerInjectionTransformer")
+	defer th.Reset()
+
+	// the init container and volume are already present, so
+	// nothing is injected
+	rm := th.LoadAndRunTransformer(initContainerInjectionConfig, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: myapp
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: migrate
+        image: migrate:v0
+      containers:
+      - name: app
+        image: nginx
+      volumes:
+      - name: migrations
+        emptyDir: {}
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: myapp
+spec:
+  template:
+    spec:
+      containers:
+      - image: nginx
+        name: app
+      initContainers:
+      - image: migrate:v0
+        name: migrate
+      volumes:
+      - emptyDir: {}
+        name: migrations
+`)
+}
+
+func TestInitContainerInjectionTransformerMissingName(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("InitContainerInjectionTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckError(`
+apiVersion: builtin
+kind: InitContainerInjectionTransformer
+metadata:
+  name: notImportantHere
+container:
+  image: migrate:v1
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: myapp
+`, func(t *testing.T, err error) {
+		if err == nil {
+			t.Fatalf("expected error")
+		}
+		if !strings.Contains(err.Error(), "container must have a name") {
+			t.Fatalf("unexpected err: %v", err)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/yaml"
+)
+
+// Generate a resource from each element of JSON files holding
+// an array of Kubernetes objects -- e.g. the output of tools
+// which emit JSON rather than YAML.
+type plugin struct {
+	h *resmap.PluginHelpers
+
+	// Files are the paths to the JSON files.
+	Files []string `json:"files,omitempty" yaml:"files,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	h *resmap.PluginHelpers, c []byte) (err error) {
+	p.Files = nil
+	if err = yaml.Unmarshal(c, p); err != nil {
+		return err
+	}
+	if len(p.Files) == 0 {
+		return fmt.Errorf("files must be specified")
+	}
+	p.h = h
+	return nil
+}
+
+func (p *plugin) Generate() (resmap.ResMap, error) {
+	m := resmap.New()
+	for _, file := range p.Files {
+		data, err := p.h.Loader().Load(file)
+		if err != nil {
+			return nil, err
+		}
+		var elements []json.RawMessage
+		if err := json.Unmarshal(data, &elements); err != nil {
+			return nil, fmt.Errorf(
+				"%s must hold a JSON array of objects: %v", file, err)
+		}
+		for i, element := range elements {
+			if err := validate(element); err != nil {
+				return nil, fmt.Errorf("%s element %d: %v", file, i, err)
+			}
+			rm, err := p.h.ResmapFactory().NewResMapFromBytes(element)
+			if err != nil {
+				return nil, fmt.Errorf("%s element %d: %v", file, i, err)
+			}
+			if err := m.AppendAll(rm); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return m, nil
+}
+
+// validate returns an error if the element isn't a Kubernetes
+// object with an apiVersion, a kind and a name.
+func validate(element json.RawMessage) error {
+	var obj struct {
+		APIVersion string `json:"apiVersion"`
+		Kind       string `json:"kind"`
+		Metadata   struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(element, &obj); err != nil {
+		return fmt.Errorf("not an object: %v", err)
+	}
+	switch {
+	case obj.APIVersion == "":
+		return fmt.Errorf("missing apiVersion")
+	case obj.Kind == "":
+		return fmt.Errorf("missing kind")
+	case obj.Metadata.Name == "":
+		return fmt.Errorf("missing metadata.name")
+	}
+	return nil
+}
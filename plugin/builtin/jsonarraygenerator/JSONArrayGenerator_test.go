@@ -0,0 +1,58 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestJSONArrayGenerator(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("JSONArrayGenerator")
+	defer th.Reset()
+
+	th.WriteF("resources.json", `[
+  {
+    "apiVersion": "v1",
+    "kind": "ConfigMap",
+    "metadata": {"name": "settings"},
+    "data": {"region": "us-east1"}
+  },
+  {
+    "apiVersion": "v1",
+    "kind": "Service",
+    "metadata": {"name": "web"},
+    "spec": {"ports": [{"port": 80}]}
+  }
+]
+`)
+
+	rm := th.LoadAndRunGenerator(`
+apiVersion: builtin
+kind: JSONArrayGenerator
+metadata:
+  name: notImportantHere
+files:
+- resources.json
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: v1
+data:
+  region: us-east1
+kind: ConfigMap
+metadata:
+  name: settings
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  ports:
+  - port: 80
+`)
+}
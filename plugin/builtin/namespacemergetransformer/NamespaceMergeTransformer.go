@@ -0,0 +1,89 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/filters/namespace"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filtersutil"
+	"sigs.k8s.io/yaml"
+)
+
+// Merge the non-cluster level resources from several namespaces
+// into one namespace, failing if resources would collide.
+type plugin struct {
+	types.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+	FieldSpecs       []types.FieldSpec `json:"fieldSpecs,omitempty" yaml:"fieldSpecs,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	_ *resmap.PluginHelpers, c []byte) (err error) {
+	p.Namespace = ""
+	p.FieldSpecs = nil
+	return yaml.Unmarshal(c, p)
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	if len(p.Namespace) == 0 {
+		return nil
+	}
+	if err := p.checkConflicts(m); err != nil {
+		return err
+	}
+	for _, r := range m.Resources() {
+		if len(r.Map()) == 0 {
+			// Don't mutate empty objects?
+			continue
+		}
+		err := filtersutil.ApplyToJSON(namespace.Filter{
+			Namespace: p.Namespace,
+			FsSlice:   p.FieldSpecs,
+		}, r.Kunstructured)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkConflicts returns an error listing the resources which
+// would have the same ID once merged into the namespace.
+func (p *plugin) checkConflicts(m resmap.ResMap) error {
+	var ids []string
+	namespaces := map[string][]string{}
+	for _, r := range m.Resources() {
+		id := r.CurId()
+		if !id.IsNamespaceableKind() {
+			// the namespace isn't changed
+			continue
+		}
+		key := id.GvknString()
+		if _, found := namespaces[key]; !found {
+			ids = append(ids, key)
+		}
+		namespaces[key] = append(namespaces[key], id.EffectiveNamespace())
+	}
+	var conflicts []string
+	for _, key := range ids {
+		if len(namespaces[key]) > 1 {
+			conflicts = append(conflicts, fmt.Sprintf(
+				"%s from namespaces [%s]",
+				key, strings.Join(namespaces[key], ", ")))
+		}
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf(
+			"merging into namespace %s produces ID conflicts:\n  %s",
+			p.Namespace, strings.Join(conflicts, "\n  "))
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+const namespaceMergeConfig = `
+apiVersion: builtin
+kind: NamespaceMergeTransformer
+metadata:
+  name: notImportantHere
+  namespace: merged
+fieldSpecs:
+- path: metadata/namespace
+  create: true
+`
+
+func TestNamespaceMergeTransformer(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("NamespaceMergeTransformer")
+	defer th.Reset()
+
+	rm := th.LoadAndRunTransformer(namespaceMergeConfig, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+  namespace: foo
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm2
+  namespace: bar
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: foo
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+  namespace: merged
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm2
+  namespace: merged
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: foo
+`)
+}
+
+func TestNamespaceMergeTransformerConflict(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("NamespaceMergeTransformer")
+	defer th.Reset()
+
+	err := th.ErrorFromLoadAndRunTransformer(namespaceMergeConfig, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: foo
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: bar
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: config
+  namespace: foo
+`)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(err.Error(),
+		"~G_v1_ConfigMap|config from namespaces [foo, bar]") {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if strings.Contains(err.Error(), "Secret") {
+		t.Fatalf("unexpected conflict: %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Apply strategic merge patches stored in a ConfigMap -- the
+// patch library, holding one patch per data key -- so that
+// patches can be managed centrally and reused.
+//
+// The ConfigMap must be one of the resources, e.g. read from
+// a base or generated, and is identified by its name before
+// any prefix, suffix or hash is added.
+type plugin struct {
+	h *resmap.PluginHelpers
+
+	// ConfigMapName is the name of the patch library.
+	ConfigMapName string `json:"configMapName,omitempty" yaml:"configMapName,omitempty"`
+
+	// ConfigMapNamespace is the namespace of the patch
+	// library, if there are several with the same name.
+	ConfigMapNamespace string `json:"configMapNamespace,omitempty" yaml:"configMapNamespace,omitempty"`
+
+	// Patches are the library patches to apply, in order.
+	Patches []libraryPatch `json:"patches,omitempty" yaml:"patches,omitempty"`
+}
+
+// libraryPatch applies the patch at a key of the library.
+type libraryPatch struct {
+	// Key is the data key of the patch in the ConfigMap.
+	Key string `json:"key,omitempty" yaml:"key,omitempty"`
+
+	// Target selects the resources to patch.  If unset,
+	// the patch applies to the resource it identifies.
+	Target *types.Selector `json:"target,omitempty" yaml:"target,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	h *resmap.PluginHelpers, c []byte) (err error) {
+	p.h = h
+	p.ConfigMapName = ""
+	p.ConfigMapNamespace = ""
+	p.Patches = nil
+	err = yaml.Unmarshal(c, p)
+	if err != nil {
+		return err
+	}
+	if p.ConfigMapName == "" {
+		return fmt.Errorf("must specify the configMapName of the patch library")
+	}
+	for _, patch := range p.Patches {
+		if patch.Key == "" {
+			return fmt.Errorf(
+				"must specify the key of each patch of patch library %s",
+				p.ConfigMapName)
+		}
+	}
+	return nil
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	data, err := p.library(m)
+	if err != nil {
+		return err
+	}
+	// check all the keys before patching anything
+	for _, patch := range p.Patches {
+		if _, found := data[patch.Key]; !found {
+			return fmt.Errorf(
+				"patch library %s has no key %s", p.ConfigMapName, patch.Key)
+		}
+	}
+	for _, patch := range p.Patches {
+		s, ok := data[patch.Key].(string)
+		if !ok {
+			return fmt.Errorf(
+				"key %s of patch library %s isn't a string",
+				patch.Key, p.ConfigMapName)
+		}
+		loaded, err := p.h.ResmapFactory().RF().FromBytes([]byte(s))
+		if err != nil {
+			return fmt.Errorf(
+				"key %s of patch library %s: %v", patch.Key, p.ConfigMapName, err)
+		}
+		if err := applyPatch(m, loaded, patch.Target); err != nil {
+			return fmt.Errorf(
+				"key %s of patch library %s: %v", patch.Key, p.ConfigMapName, err)
+		}
+	}
+	return nil
+}
+
+// library returns the data of the patch library ConfigMap.
+func (p *plugin) library(m resmap.ResMap) (map[string]interface{}, error) {
+	var found []*resource.Resource
+	for _, r := range m.Resources() {
+		id := r.OrgId()
+		if id.Kind != "ConfigMap" || id.Name != p.ConfigMapName {
+			continue
+		}
+		if p.ConfigMapNamespace != "" && id.EffectiveNamespace() != p.ConfigMapNamespace {
+			continue
+		}
+		found = append(found, r)
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("patch library %s not found", p.ConfigMapName)
+	case 1:
+		data, _ := found[0].Map()["data"].(map[string]interface{})
+		return data, nil
+	default:
+		return nil, fmt.Errorf(
+			"found %d patch libraries named %s, set configMapNamespace",
+			len(found), p.ConfigMapName)
+	}
+}
+
+// applyPatch applies the strategic merge patch to the
+// resources selected by target, or if unset, to the resource
+// identified by the patch.
+func applyPatch(
+	m resmap.ResMap, patch *resource.Resource, target *types.Selector) error {
+	if target == nil {
+		res, err := m.GetById(patch.OrgId())
+		if err != nil {
+			return err
+		}
+		return res.Patch(patch.Kunstructured)
+	}
+	resources, err := m.Select(*target)
+	if err != nil {
+		return err
+	}
+	for _, res := range resources {
+		patchCopy := patch.DeepCopy()
+		patchCopy.SetName(res.GetName())
+		patchCopy.SetNamespace(res.GetNamespace())
+		patchCopy.SetGvk(res.GetGvk())
+		if err := res.Patch(patchCopy.Kunstructured); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+const patchLibrary = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: patch-library
+data:
+  replicas.yaml: |
+    apiVersion: apps/v1
+    kind: Deployment
+    metadata:
+      name: any
+    spec:
+      replicas: 3
+  team.yaml: |
+    apiVersion: v1
+    kind: Service
+    metadata:
+      name: backend
+      labels:
+        team: payments
+`
+
+func TestPatchLibraryTransformer(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("PatchLibraryTransformer")
+	defer th.Reset()
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: PatchLibraryTransformer
+metadata:
+  name: notImportantHere
+configMapName: patch-library
+patches:
+- key: replicas.yaml
+  target:
+    kind: Deployment
+    labelSelector: tier=frontend
+- key: team.yaml
+`, patchLibrary+`
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    tier: frontend
+spec:
+  replicas: 1
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: worker
+  labels:
+    tier: backend
+spec:
+  replicas: 1
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: backend
+spec:
+  ports:
+  - port: 80
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: v1
+data:
+  replicas.yaml: |
+    apiVersion: apps/v1
+    kind: Deployment
+    metadata:
+      name: any
+    spec:
+      replicas: 3
+  team.yaml: |
+    apiVersion: v1
+    kind: Service
+    metadata:
+      name: backend
+      labels:
+        team: payments
+kind: ConfigMap
+metadata:
+  name: patch-library
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    tier: frontend
+  name: web
+spec:
+  replicas: 3
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    tier: backend
+  name: worker
+spec:
+  replicas: 1
+---
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    team: payments
+  name: backend
+spec:
+  ports:
+  - port: 80
+`)
+}
+
+func TestPatchLibraryTransformerMissingKey(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("PatchLibraryTransformer")
+	defer th.Reset()
+
+	err := th.ErrorFromLoadAndRunTransformer(`
+apiVersion: builtin
+kind: PatchLibraryTransformer
+metadata:
+  name: notImportantHere
+configMapName: patch-library
+patches:
+- key: replicas.yaml
+- key: resources.yaml
+  target:
+    kind: Deployment
+`, patchLibrary)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(err.Error(),
+		"patch library patch-library has no key resources.yaml") {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
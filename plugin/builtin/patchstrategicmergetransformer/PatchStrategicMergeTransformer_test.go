@@ -249,6 +249,90 @@ spec:
 `)
 }
 
+func TestPatchStrategicMergeTransformerNullDeletesField(t *testing.T) {
+	for _, yamlSupport := range []bool{false, true} {
+		th := kusttest_test.MakeEnhancedHarness(t).
+			PrepBuiltin("PatchStrategicMergeTransformer")
+
+		th.RunTransformerAndCheckResult(fmt.Sprintf(`
+apiVersion: builtin
+kind: PatchStrategicMergeTransformer
+metadata:
+  name: notImportantHere
+yamlSupport: %v
+patches: |-
+  apiVersion: apps/v1
+  metadata:
+    name: myDeploy
+  kind: Deployment
+  spec:
+    replica: null
+    template:
+      metadata: null
+`, yamlSupport),
+			target,
+			`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: myDeploy
+spec:
+  template:
+    spec:
+      containers:
+      - image: nginx
+        name: nginx
+`)
+		th.Reset()
+	}
+}
+
+func TestPatchStrategicMergeTransformerKeepNullValues(t *testing.T) {
+	for _, yamlSupport := range []bool{false, true} {
+		th := kusttest_test.MakeEnhancedHarness(t).
+			PrepBuiltin("PatchStrategicMergeTransformer")
+
+		th.RunTransformerAndCheckResult(fmt.Sprintf(`
+apiVersion: builtin
+kind: PatchStrategicMergeTransformer
+metadata:
+  name: notImportantHere
+yamlSupport: %v
+keepNullValues: true
+patches: |-
+  apiVersion: apps/v1
+  metadata:
+    name: myDeploy
+  kind: Deployment
+  spec:
+    replica: null
+    template:
+      metadata:
+        labels:
+          new-label: null
+`, yamlSupport),
+			target,
+			`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: myDeploy
+spec:
+  replica: null
+  template:
+    metadata:
+      labels:
+        new-label: null
+        old-label: old-value
+    spec:
+      containers:
+      - image: nginx
+        name: nginx
+`)
+		th.Reset()
+	}
+}
+
 func TestPatchStrategicMergeTransformerMultiplePatches(t *testing.T) {
 	th := kusttest_test.MakeEnhancedHarness(t).
 		PrepBuiltin("PatchStrategicMergeTransformer")
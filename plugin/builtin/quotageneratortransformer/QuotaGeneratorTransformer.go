@@ -0,0 +1,156 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/yaml"
+)
+
+// Generate a ResourceQuota with the configured hard limits,
+// and optionally a LimitRange with the configured container
+// defaults, for a namespace -- e.g. for each tenant of a
+// multi-tenant cluster.
+//
+// The ResourceQuota is named <namespace>-quota, and the
+// LimitRange <namespace>-limits.  Neither is generated if the
+// namespace already has one of the same kind.
+type plugin struct {
+	h *resmap.PluginHelpers
+
+	// Namespace is the namespace to generate the quota for.
+	// If unset, a quota is generated for each namespace of
+	// the resources.
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+
+	// Hard are the hard limits of the ResourceQuota -- e.g.
+	// requests.cpu: "4".
+	Hard map[string]interface{} `json:"hard,omitempty" yaml:"hard,omitempty"`
+
+	// LimitRange, if set, are the container defaults of the
+	// LimitRange.
+	LimitRange *limitRange `json:"limitRange,omitempty" yaml:"limitRange,omitempty"`
+}
+
+// limitRange holds the defaults of the containers of a namespace.
+type limitRange struct {
+	// Default are the default limits of the containers.
+	Default map[string]interface{} `json:"default,omitempty" yaml:"default,omitempty"`
+
+	// DefaultRequest are the default requests of the containers.
+	DefaultRequest map[string]interface{} `json:"defaultRequest,omitempty" yaml:"defaultRequest,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	h *resmap.PluginHelpers, c []byte) (err error) {
+	p.Namespace = ""
+	p.Hard = nil
+	p.LimitRange = nil
+	if err = yaml.Unmarshal(c, p); err != nil {
+		return err
+	}
+	if len(p.Hard) == 0 {
+		return fmt.Errorf("must specify the hard limits of the quota")
+	}
+	if p.LimitRange != nil &&
+		len(p.LimitRange.Default) == 0 && len(p.LimitRange.DefaultRequest) == 0 {
+		return fmt.Errorf("must specify the default or defaultRequest of the limitRange")
+	}
+	p.h = h
+	return nil
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	namespaces := []string{p.Namespace}
+	if p.Namespace == "" {
+		namespaces = resourceNamespaces(m)
+		if len(namespaces) == 0 {
+			return fmt.Errorf(
+				"no namespaced resources to generate a quota for; specify the namespace")
+		}
+	}
+	for _, ns := range namespaces {
+		if !hasKind(m, "ResourceQuota", ns) {
+			err := m.Append(p.makeResource("ResourceQuota", ns+"-quota", ns,
+				map[string]interface{}{"hard": copyLimits(p.Hard)}))
+			if err != nil {
+				return err
+			}
+		}
+		if p.LimitRange != nil && !hasKind(m, "LimitRange", ns) {
+			limit := map[string]interface{}{"type": "Container"}
+			if len(p.LimitRange.Default) > 0 {
+				limit["default"] = copyLimits(p.LimitRange.Default)
+			}
+			if len(p.LimitRange.DefaultRequest) > 0 {
+				limit["defaultRequest"] = copyLimits(p.LimitRange.DefaultRequest)
+			}
+			err := m.Append(p.makeResource("LimitRange", ns+"-limits", ns,
+				map[string]interface{}{"limits": []interface{}{limit}}))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resourceNamespaces returns the sorted namespaces of the
+// resources.
+func resourceNamespaces(m resmap.ResMap) []string {
+	found := map[string]bool{}
+	var namespaces []string
+	for _, r := range m.Resources() {
+		ns := r.GetNamespace()
+		if ns != "" && !found[ns] {
+			found[ns] = true
+			namespaces = append(namespaces, ns)
+		}
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// hasKind returns true if one of the resources in the
+// namespace is of the kind.
+func hasKind(m resmap.ResMap, kind, namespace string) bool {
+	for _, r := range m.Resources() {
+		if r.GetKind() == kind && r.GetNamespace() == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// copyLimits returns a copy of the limits, so that the
+// generated resources don't share them.
+func copyLimits(limits map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(limits))
+	for k, v := range limits {
+		c[k] = v
+	}
+	return c
+}
+
+// makeResource returns a core/v1 resource of the kind.
+func (p *plugin) makeResource(
+	kind, name, namespace string, spec map[string]interface{}) *resource.Resource {
+	return p.h.ResmapFactory().RF().FromMap(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": namespace,
+		},
+		"spec": spec,
+	})
+}
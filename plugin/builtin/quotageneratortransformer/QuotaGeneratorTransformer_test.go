@@ -0,0 +1,189 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestQuotaGeneratorTransformer(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("QuotaGeneratorTransformer")
+	defer th.Reset()
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: QuotaGeneratorTransformer
+metadata:
+  name: notImportantHere
+hard:
+  requests.cpu: "4"
+  requests.memory: 8Gi
+  limits.cpu: "8"
+  limits.memory: 16Gi
+limitRange:
+  default:
+    cpu: 500m
+    memory: 512Mi
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: tenant-a
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: tenant-b
+---
+apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: quota
+  namespace: tenant-b
+spec:
+  hard:
+    pods: "10"
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: reader
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: tenant-a
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: tenant-b
+---
+apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: quota
+  namespace: tenant-b
+spec:
+  hard:
+    pods: "10"
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: reader
+---
+apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: tenant-a-quota
+  namespace: tenant-a
+spec:
+  hard:
+    limits.cpu: "8"
+    limits.memory: 16Gi
+    requests.cpu: "4"
+    requests.memory: 8Gi
+---
+apiVersion: v1
+kind: LimitRange
+metadata:
+  name: tenant-a-limits
+  namespace: tenant-a
+spec:
+  limits:
+  - default:
+      cpu: 500m
+      memory: 512Mi
+    type: Container
+---
+apiVersion: v1
+kind: LimitRange
+metadata:
+  name: tenant-b-limits
+  namespace: tenant-b
+spec:
+  limits:
+  - default:
+      cpu: 500m
+      memory: 512Mi
+    type: Container
+`)
+}
+
+func TestQuotaGeneratorTransformerNamespace(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("QuotaGeneratorTransformer")
+	defer th.Reset()
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: QuotaGeneratorTransformer
+metadata:
+  name: notImportantHere
+namespace: tenant-c
+hard:
+  cpu: "2"
+  memory: 4Gi
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: tenant-a
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: tenant-a
+---
+apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: tenant-c-quota
+  namespace: tenant-c
+spec:
+  hard:
+    cpu: "2"
+    memory: 4Gi
+`)
+}
+
+func TestQuotaGeneratorTransformerNoHardLimits(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("QuotaGeneratorTransformer")
+	defer th.Reset()
+
+	err := th.ErrorFromLoadAndRunTransformer(`
+apiVersion: builtin
+kind: QuotaGeneratorTransformer
+metadata:
+  name: notImportantHere
+namespace: tenant-a
+`, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+`)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(err.Error(), "must specify the hard limits of the quota") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
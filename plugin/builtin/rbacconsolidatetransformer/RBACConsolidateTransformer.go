@@ -0,0 +1,203 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"sort"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+)
+
+// Consolidate the rules of Roles and ClusterRoles, which
+// accumulate duplicates when composing several bases.  The
+// lists of each rule are sorted and deduplicated, duplicate
+// rules are removed, and rules differing only in one list
+// are merged -- e.g. rules granting get and list on the same
+// resources.  The permissions granted are left unchanged.
+type plugin struct{}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+// ruleFields are the lists of a policy rule, whose cross
+// product is the set of permissions the rule grants.  Rules
+// with other fields aren't consolidated.
+var ruleFields = []string{
+	"apiGroups", "resources", "resourceNames", "nonResourceURLs", "verbs",
+}
+
+// Nothing needed for configuration.
+func (p *plugin) Config(
+	_ *resmap.PluginHelpers, _ []byte) (err error) {
+	return nil
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	for _, r := range m.Resources() {
+		if r.GetKind() != "Role" && r.GetKind() != "ClusterRole" {
+			continue
+		}
+		obj := r.Map()
+		if _, found := obj["aggregationRule"]; found {
+			// the rules are filled in by the controller manager
+			continue
+		}
+		rules, ok := obj["rules"].([]interface{})
+		if !ok {
+			continue
+		}
+		obj["rules"] = consolidate(rules)
+	}
+	return nil
+}
+
+// policyRule is a policy rule, with its lists sorted and
+// deduplicated.
+type policyRule map[string][]string
+
+// consolidate returns the rules with duplicates removed and
+// compatible rules merged, in the order they first appear.
+func consolidate(rules []interface{}) []interface{} {
+	var result []interface{}
+	var parsed []policyRule
+	var index []int
+	for _, r := range rules {
+		pr, ok := parseRule(r)
+		if !ok {
+			// leave rules which can't be reasoned about as is
+			result = append(result, r)
+			continue
+		}
+		parsed = append(parsed, pr)
+		index = append(index, len(result))
+		result = append(result, nil)
+	}
+
+	// merge until no two rules may be merged, so that the
+	// result doesn't depend on the order of the merges
+	for merged := true; merged; {
+		merged = false
+		for i := 0; i < len(parsed) && !merged; i++ {
+			for j := i + 1; j < len(parsed) && !merged; j++ {
+				if !mergeRule(parsed[i], parsed[j]) {
+					continue
+				}
+				result = append(result[:index[j]], result[index[j]+1:]...)
+				for k := j + 1; k < len(index); k++ {
+					index[k]--
+				}
+				parsed = append(parsed[:j], parsed[j+1:]...)
+				index = append(index[:j], index[j+1:]...)
+				merged = true
+			}
+		}
+	}
+	for i, pr := range parsed {
+		result[index[i]] = pr.toMap()
+	}
+	return result
+}
+
+// parseRule returns the rule, or false if it has fields
+// other than ruleFields, or values other than strings.
+func parseRule(r interface{}) (policyRule, bool) {
+	obj, ok := r.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	pr := policyRule{}
+	for k, v := range obj {
+		if !isRuleField(k) {
+			return nil, false
+		}
+		list, ok := v.([]interface{})
+		if !ok {
+			return nil, false
+		}
+		var values []string
+		for _, item := range list {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			values = append(values, s)
+		}
+		pr[k] = sortUnique(values)
+	}
+	return pr, true
+}
+
+func isRuleField(k string) bool {
+	for _, f := range ruleFields {
+		if f == k {
+			return true
+		}
+	}
+	return false
+}
+
+// sortUnique sorts the values and removes duplicates.
+func sortUnique(values []string) []string {
+	sort.Strings(values)
+	var result []string
+	for i, v := range values {
+		if i == 0 || v != values[i-1] {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// mergeRule merges other into r, returning false if the
+// rules may not be merged without changing the permissions
+// they grant.  Identical rules are merged, as are rules
+// whose lists differ in only one field, if neither list of
+// that field is empty -- an empty list of resourceNames
+// grants access to all names.
+func mergeRule(r, other policyRule) bool {
+	differing := ""
+	for _, f := range ruleFields {
+		if equalLists(r[f], other[f]) {
+			continue
+		}
+		if differing != "" || len(r[f]) == 0 || len(other[f]) == 0 {
+			return false
+		}
+		differing = f
+	}
+	if differing != "" {
+		r[differing] = sortUnique(append(r[differing], other[differing]...))
+	}
+	return true
+}
+
+func equalLists(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// toMap returns the rule as an unstructured object.
+func (r policyRule) toMap() map[string]interface{} {
+	obj := map[string]interface{}{}
+	for _, f := range ruleFields {
+		values, found := r[f]
+		if !found {
+			continue
+		}
+		list := []interface{}{}
+		for _, v := range values {
+			list = append(list, v)
+		}
+		obj[f] = list
+	}
+	return obj
+}
@@ -0,0 +1,136 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+const config = `
+apiVersion: builtin
+kind: RBACConsolidateTransformer
+metadata:
+  name: notImportantHere
+`
+
+func TestRBACConsolidateTransformerDuplicates(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("RBACConsolidateTransformer")
+	defer th.Reset()
+
+	rm := th.LoadAndRunTransformer(config, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: reader
+rules:
+- apiGroups: [""]
+  resources: [pods]
+  verbs: [get, list]
+- apiGroups: [""]
+  resources: [pods]
+  verbs: [list, get, get]
+- apiGroups: [""]
+  resources: [secrets]
+  resourceNames: [tls]
+  verbs: [get]
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: reader
+rules:
+- apiGroups:
+  - ""
+  resources:
+  - pods
+  verbs:
+  - get
+  - list
+- apiGroups:
+  - ""
+  resourceNames:
+  - tls
+  resources:
+  - secrets
+  verbs:
+  - get
+`)
+}
+
+func TestRBACConsolidateTransformerMerge(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("RBACConsolidateTransformer")
+	defer th.Reset()
+
+	rm := th.LoadAndRunTransformer(config, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: operator
+rules:
+- apiGroups: [""]
+  resources: [pods]
+  verbs: [get, list]
+- apiGroups: [apps]
+  resources: [deployments]
+  verbs: [get]
+- apiGroups: [""]
+  resources: [pods]
+  verbs: [watch]
+- apiGroups: [""]
+  resources: [services]
+  verbs: [get, list, watch]
+- apiGroups: [""]
+  resources: [secrets]
+  resourceNames: [tls]
+  verbs: [get]
+- apiGroups: [""]
+  resources: [secrets]
+  verbs: [get]
+`)
+
+	// rules differing in more than one list, or in
+	// resourceNames from an empty list, aren't merged
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: operator
+rules:
+- apiGroups:
+  - ""
+  resources:
+  - pods
+  - services
+  verbs:
+  - get
+  - list
+  - watch
+- apiGroups:
+  - apps
+  resources:
+  - deployments
+  verbs:
+  - get
+- apiGroups:
+  - ""
+  resourceNames:
+  - tls
+  resources:
+  - secrets
+  verbs:
+  - get
+- apiGroups:
+  - ""
+  resources:
+  - secrets
+  verbs:
+  - get
+`)
+}
@@ -0,0 +1,7 @@
+module sigs.k8s.io/kustomize/plugin/builtin/rbacconsolidatetransformer
+
+go 1.14
+
+require sigs.k8s.io/kustomize/api v0.4.0
+
+replace sigs.k8s.io/kustomize/api v0.4.0 => ../../../api
@@ -0,0 +1,114 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Rewrite the storage classes of PersistentVolumeClaims and
+// of StatefulSet volumeClaimTemplates, e.g. to use the
+// storage classes of a particular cluster.
+//
+// Claims without a storageClassName use the cluster's
+// default class; they are only given a class if
+// DefaultClassName is set.  Claims with an empty
+// storageClassName explicitly have no class, and are
+// never rewritten.
+type plugin struct {
+	// ClassNames maps the storage classes to rewrite to
+	// their new names.
+	ClassNames map[string]string `json:"classNames,omitempty" yaml:"classNames,omitempty"`
+
+	// ClassName is the single storage class which all
+	// claims with a storage class are rewritten to.  May not
+	// be used with ClassNames.
+	ClassName string `json:"className,omitempty" yaml:"className,omitempty"`
+
+	// DefaultClassName is the storage class given to claims
+	// without a storageClassName.
+	DefaultClassName string `json:"defaultClassName,omitempty" yaml:"defaultClassName,omitempty"`
+
+	// Target selects the resources to rewrite.  If unset,
+	// all resources are rewritten.
+	Target *types.Selector `json:"target,omitempty" yaml:"target,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	_ *resmap.PluginHelpers, c []byte) (err error) {
+	p.ClassNames = nil
+	p.ClassName = ""
+	p.DefaultClassName = ""
+	p.Target = nil
+	err = yaml.Unmarshal(c, p)
+	if err != nil {
+		return err
+	}
+	if p.ClassName != "" && len(p.ClassNames) > 0 {
+		return fmt.Errorf("className and classNames may not both be specified")
+	}
+	return nil
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	resources := m.Resources()
+	if p.Target != nil {
+		var err error
+		resources, err = m.Select(*p.Target)
+		if err != nil {
+			return err
+		}
+	}
+	for _, r := range resources {
+		spec, ok := r.Map()["spec"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch r.GetKind() {
+		case "PersistentVolumeClaim":
+			p.rewrite(spec)
+		case "StatefulSet":
+			templates, _ := spec["volumeClaimTemplates"].([]interface{})
+			for i := range templates {
+				template, _ := templates[i].(map[string]interface{})
+				if claimSpec, ok := template["spec"].(map[string]interface{}); ok {
+					p.rewrite(claimSpec)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// rewrite rewrites the storageClassName of a claim spec.
+func (p *plugin) rewrite(spec map[string]interface{}) {
+	class, found := spec["storageClassName"]
+	if !found || class == nil {
+		// the cluster default class
+		if p.DefaultClassName != "" {
+			spec["storageClassName"] = p.DefaultClassName
+		}
+		return
+	}
+	name, ok := class.(string)
+	if !ok || name == "" {
+		// explicitly no class
+		return
+	}
+	if p.ClassName != "" {
+		spec["storageClassName"] = p.ClassName
+		return
+	}
+	if newName, ok := p.ClassNames[name]; ok {
+		spec["storageClassName"] = newName
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"text/template"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/yaml"
+)
+
+// Generate one or more resources per row of a CSV file by
+// executing a Go template with the row.  The first row of
+// the file holds the column names, which are the names of
+// the template variables, e.g. {{ .name }}.
+type plugin struct {
+	h *resmap.PluginHelpers
+
+	// Template is the path to the Go template.
+	Template string `json:"template,omitempty" yaml:"template,omitempty"`
+
+	// Data is the path to the CSV file.
+	Data string `json:"data,omitempty" yaml:"data,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	h *resmap.PluginHelpers, c []byte) (err error) {
+	p.Template = ""
+	p.Data = ""
+	if err = yaml.Unmarshal(c, p); err != nil {
+		return err
+	}
+	if p.Template == "" {
+		return fmt.Errorf("template must be specified")
+	}
+	if p.Data == "" {
+		return fmt.Errorf("data must be specified")
+	}
+	p.h = h
+	return nil
+}
+
+func (p *plugin) Generate() (resmap.ResMap, error) {
+	content, err := p.h.Loader().Load(p.Template)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(p.Template).
+		Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, err
+	}
+	data, err := p.h.Loader().Load(p.Data)
+	if err != nil {
+		return nil, err
+	}
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %v", p.Data, err)
+	}
+	m := resmap.New()
+	if len(records) == 0 {
+		return m, nil
+	}
+	columns := records[0]
+	for i, record := range records[1:] {
+		// rows are numbered as in the file, after the header
+		row := i + 2
+		values := map[string]string{}
+		for j := range columns {
+			values[columns[j]] = record[j]
+		}
+		var out bytes.Buffer
+		if err := tmpl.Execute(&out, values); err != nil {
+			return nil, fmt.Errorf("%s row %d: %v", p.Data, row, err)
+		}
+		rm, err := p.h.ResmapFactory().NewResMapFromBytes(out.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%s row %d: generated invalid yaml: %v", p.Data, row, err)
+		}
+		if err := m.AppendAll(rm); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
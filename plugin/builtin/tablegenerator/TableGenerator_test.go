@@ -0,0 +1,66 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestTableGenerator(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("TableGenerator")
+	defer th.Reset()
+
+	th.WriteF("configmap.tmpl", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .name }}-config
+data:
+  region: {{ .region }}
+  replicas: "{{ .replicas }}"
+`)
+	th.WriteF("regions.csv", `name,region,replicas
+east,us-east1,3
+west,us-west1,2
+europe,europe-west1,1
+`)
+
+	rm := th.LoadAndRunGenerator(`
+apiVersion: builtin
+kind: TableGenerator
+metadata:
+  name: notImportantHere
+template: configmap.tmpl
+data: regions.csv
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: v1
+data:
+  region: us-east1
+  replicas: "3"
+kind: ConfigMap
+metadata:
+  name: east-config
+---
+apiVersion: v1
+data:
+  region: us-west1
+  replicas: "2"
+kind: ConfigMap
+metadata:
+  name: west-config
+---
+apiVersion: v1
+data:
+  region: europe-west1
+  replicas: "1"
+kind: ConfigMap
+metadata:
+  name: europe-config
+`)
+}
@@ -0,0 +1,140 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+	"reflect"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Remove the duplicate volumes of pod specs, and the
+// duplicate volumeMounts of their containers, e.g. those
+// accumulated by patches injecting sidecars which share
+// volumes.  Run it after the transformers injecting them.
+//
+// Only identical duplicates are removed.  Two volumes with
+// the same name, or two mounts of a container at the same
+// mountPath, with different definitions are an error.
+type plugin struct {
+	// Target selects the resources to dedupe.  If unset,
+	// all resources are deduped.
+	Target *types.Selector `json:"target,omitempty" yaml:"target,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	_ *resmap.PluginHelpers, c []byte) (err error) {
+	p.Target = nil
+	return yaml.Unmarshal(c, p)
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	resources := m.Resources()
+	if p.Target != nil {
+		var err error
+		resources, err = m.Select(*p.Target)
+		if err != nil {
+			return err
+		}
+	}
+	for _, r := range resources {
+		if err := dedupePodSpecs(r.Map()); err != nil {
+			return fmt.Errorf("%s: %v", r.CurId(), err)
+		}
+	}
+	return nil
+}
+
+// dedupePodSpecs searches obj for pod specs at any depth,
+// e.g. spec/template/spec, and dedupes each of them.  Pod
+// specs are the maps with a containers field.
+func dedupePodSpecs(obj map[string]interface{}) error {
+	if _, found := obj["containers"]; found {
+		return dedupePodSpec(obj)
+	}
+	for _, v := range obj {
+		switch typedV := v.(type) {
+		case map[string]interface{}:
+			if err := dedupePodSpecs(typedV); err != nil {
+				return err
+			}
+		case []interface{}:
+			for _, item := range typedV {
+				if typedItem, ok := item.(map[string]interface{}); ok {
+					if err := dedupePodSpecs(typedItem); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func dedupePodSpec(spec map[string]interface{}) error {
+	volumes, err := dedupe(spec, "volumes", "name")
+	if err != nil {
+		return err
+	}
+	if volumes != nil {
+		spec["volumes"] = volumes
+	}
+	for _, field := range []string{"containers", "initContainers"} {
+		containers, _ := spec[field].([]interface{})
+		for _, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			mounts, err := dedupe(container, "volumeMounts", "mountPath")
+			if err != nil {
+				return fmt.Errorf("container %v: %v", container["name"], err)
+			}
+			if mounts != nil {
+				container["volumeMounts"] = mounts
+			}
+		}
+	}
+	return nil
+}
+
+// dedupe returns the elements of the list field of obj
+// without the identical duplicates, keeping the first of
+// each.  Elements are identified by their key field.
+// Returns an error if two elements have the same key, but
+// differ.
+func dedupe(obj map[string]interface{}, field, key string) ([]interface{}, error) {
+	list, ok := obj[field].([]interface{})
+	if !ok {
+		return nil, nil
+	}
+	var result []interface{}
+	seen := map[string]interface{}{}
+	for _, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok || m[key] == nil {
+			result = append(result, item)
+			continue
+		}
+		id := fmt.Sprint(m[key])
+		previous, found := seen[id]
+		if !found {
+			seen[id] = item
+			result = append(result, item)
+			continue
+		}
+		if !reflect.DeepEqual(previous, item) {
+			return nil, fmt.Errorf(
+				"%s with %s %s have conflicting definitions", field, key, id)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestVolumeDedupeTransformer(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("VolumeDedupeTransformer")
+	defer th.Reset()
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: VolumeDedupeTransformer
+metadata:
+  name: notImportantHere
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: init:v1
+        volumeMounts:
+        - name: config
+          mountPath: /etc/config
+      containers:
+      - name: app
+        image: app:v1
+        volumeMounts:
+        - name: config
+          mountPath: /etc/config
+        - name: data
+          mountPath: /data
+      - name: proxy
+        image: proxy:v1
+        volumeMounts:
+        - name: config
+          mountPath: /etc/config
+        - name: config
+          mountPath: /etc/config
+      volumes:
+      - name: config
+        configMap:
+          name: app-config
+      - name: data
+        emptyDir: {}
+      - name: config
+        configMap:
+          name: app-config
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - image: app:v1
+        name: app
+        volumeMounts:
+        - mountPath: /etc/config
+          name: config
+        - mountPath: /data
+          name: data
+      - image: proxy:v1
+        name: proxy
+        volumeMounts:
+        - mountPath: /etc/config
+          name: config
+      initContainers:
+      - image: init:v1
+        name: init
+        volumeMounts:
+        - mountPath: /etc/config
+          name: config
+      volumes:
+      - configMap:
+          name: app-config
+        name: config
+      - emptyDir: {}
+        name: data
+`)
+}
+
+func TestVolumeDedupeTransformerConflict(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("VolumeDedupeTransformer")
+	defer th.Reset()
+
+	err := th.ErrorFromLoadAndRunTransformer(`
+apiVersion: builtin
+kind: VolumeDedupeTransformer
+metadata:
+  name: notImportantHere
+`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: app
+spec:
+  containers:
+  - name: app
+    image: app:v1
+  volumes:
+  - name: config
+    configMap:
+      name: app-config
+  - name: config
+    configMap:
+      name: proxy-config
+`)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(err.Error(),
+		"volumes with name config have conflicting definitions") {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}